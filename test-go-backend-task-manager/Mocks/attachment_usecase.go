@@ -0,0 +1,172 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentUsecase is an autogenerated mock type for the AttachmentUsecase type
+type AttachmentUsecase struct {
+	mock.Mock
+}
+
+// DeleteAttachment provides a mock function with given fields: cxt, taskID, attachmentID, authority
+func (_m *AttachmentUsecase) DeleteAttachment(cxt context.Context, taskID string, attachmentID string, authority domain.User) *domain.TaskError {
+	ret := _m.Called(cxt, taskID, attachmentID, authority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAttachment")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) *domain.TaskError); ok {
+		r0 = rf(cxt, taskID, attachmentID, authority)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// GetAttachments provides a mock function with given fields: cxt, taskID
+func (_m *AttachmentUsecase) GetAttachments(cxt context.Context, taskID string) ([]domain.Attachment, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachments")
+	}
+
+	var r0 []domain.Attachment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Attachment, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Attachment); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// OpenAttachment provides a mock function with given fields: cxt, taskID, attachmentID
+func (_m *AttachmentUsecase) OpenAttachment(cxt context.Context, taskID string, attachmentID string) (domain.Attachment, io.ReadCloser, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenAttachment")
+	}
+
+	var r0 domain.Attachment
+	var r1 io.ReadCloser
+	var r2 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Attachment, io.ReadCloser, *domain.TaskError)); ok {
+		return rf(cxt, taskID, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Attachment); ok {
+		r0 = rf(cxt, taskID, attachmentID)
+	} else {
+		r0 = ret.Get(0).(domain.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) io.ReadCloser); ok {
+		r1 = rf(cxt, taskID, attachmentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) *domain.TaskError); ok {
+		r2 = rf(cxt, taskID, attachmentID)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// TaskDeleted provides a mock function with given fields: cxt, task
+func (_m *AttachmentUsecase) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	ret := _m.Called(cxt, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskDeleted")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) *domain.TaskError); ok {
+		r0 = rf(cxt, task)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// UploadAttachment provides a mock function with given fields: cxt, taskID, upload
+func (_m *AttachmentUsecase) UploadAttachment(cxt context.Context, taskID string, upload domain.AttachmentUpload) (domain.Attachment, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadAttachment")
+	}
+
+	var r0 domain.Attachment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.AttachmentUpload) (domain.Attachment, *domain.TaskError)); ok {
+		return rf(cxt, taskID, upload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.AttachmentUpload) domain.Attachment); ok {
+		r0 = rf(cxt, taskID, upload)
+	} else {
+		r0 = ret.Get(0).(domain.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.AttachmentUpload) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, upload)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewAttachmentUsecase creates a new instance of AttachmentUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttachmentUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttachmentUsecase {
+	mock := &AttachmentUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
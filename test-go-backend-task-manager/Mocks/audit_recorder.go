@@ -0,0 +1,34 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRecorder is an autogenerated mock type for the AuditRecorder type
+type AuditRecorder struct {
+	mock.Mock
+}
+
+// Record provides a mock function with given fields: cxt, entry
+func (_m *AuditRecorder) Record(cxt context.Context, entry domain.AuditEntry) {
+	_m.Called(cxt, entry)
+}
+
+// NewAuditRecorder creates a new instance of AuditRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRecorder {
+	mock := &AuditRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
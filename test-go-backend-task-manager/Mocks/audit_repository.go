@@ -0,0 +1,81 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// FetchEntries provides a mock function with given fields: cxt, query
+func (_m *AuditRepository) FetchEntries(cxt context.Context, query domain.AuditQuery) ([]domain.AuditEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchEntries")
+	}
+
+	var r0 []domain.AuditEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) ([]domain.AuditEntry, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) []domain.AuditEntry); ok {
+		r0 = rf(cxt, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// InsertEntry provides a mock function with given fields: cxt, entry
+func (_m *AuditRepository) InsertEntry(cxt context.Context, entry domain.AuditEntry) *domain.TaskError {
+	ret := _m.Called(cxt, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertEntry")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditEntry) *domain.TaskError); ok {
+		r0 = rf(cxt, entry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,9 +14,9 @@ type AuthService struct {
 	mock.Mock
 }
 
-// CreateJWTToken provides a mock function with given fields: username, role, timeDuration
-func (_m *AuthService) CreateJWTToken(username string, role string, timeDuration time.Duration) (string, error) {
-	ret := _m.Called(username, role, timeDuration)
+// CreateJWTToken provides a mock function with given fields: username, role, orgID, timeDuration
+func (_m *AuthService) CreateJWTToken(username string, role string, orgID string, timeDuration time.Duration) (string, error) {
+	ret := _m.Called(username, role, orgID, timeDuration)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateJWTToken")
@@ -24,17 +24,17 @@ func (_m *AuthService) CreateJWTToken(username string, role string, timeDuration
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, time.Duration) (string, error)); ok {
-		return rf(username, role, timeDuration)
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Duration) (string, error)); ok {
+		return rf(username, role, orgID, timeDuration)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, time.Duration) string); ok {
-		r0 = rf(username, role, timeDuration)
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Duration) string); ok {
+		r0 = rf(username, role, orgID, timeDuration)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, time.Duration) error); ok {
-		r1 = rf(username, role, timeDuration)
+	if rf, ok := ret.Get(1).(func(string, string, string, time.Duration) error); ok {
+		r1 = rf(username, role, orgID, timeDuration)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,251 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentRepository is an autogenerated mock type for the CommentRepository type
+type CommentRepository struct {
+	mock.Mock
+}
+
+// ArchiveTaskComments provides a mock function with given fields: cxt, taskID
+func (_m *CommentRepository) ArchiveTaskComments(cxt context.Context, taskID string) *domain.TaskError {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchiveTaskComments")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskError); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// CountReplies provides a mock function with given fields: cxt, commentID
+func (_m *CommentRepository) CountReplies(cxt context.Context, commentID string) (int64, *domain.TaskError) {
+	ret := _m.Called(cxt, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountReplies")
+	}
+
+	var r0 int64
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, *domain.TaskError)); ok {
+		return rf(cxt, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(cxt, commentID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, commentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateComment provides a mock function with given fields: cxt, comment
+func (_m *CommentRepository) CreateComment(cxt context.Context, comment domain.Comment) (string, *domain.TaskError) {
+	ret := _m.Called(cxt, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateComment")
+	}
+
+	var r0 string
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) (string, *domain.TaskError)); ok {
+		return rf(cxt, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) string); ok {
+		r0 = rf(cxt, comment)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment) *domain.TaskError); ok {
+		r1 = rf(cxt, comment)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: cxt, commentID
+func (_m *CommentRepository) DeleteComment(cxt context.Context, commentID string) *domain.TaskError {
+	ret := _m.Called(cxt, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskError); ok {
+		r0 = rf(cxt, commentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// FetchCommentByID provides a mock function with given fields: cxt, commentID
+func (_m *CommentRepository) FetchCommentByID(cxt context.Context, commentID string) (domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchCommentByID")
+	}
+
+	var r0 domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Comment); ok {
+		r0 = rf(cxt, commentID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, commentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchComments provides a mock function with given fields: cxt, taskID
+func (_m *CommentRepository) FetchComments(cxt context.Context, taskID string) ([]domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchComments")
+	}
+
+	var r0 []domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Comment); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// MarkCommentDeleted provides a mock function with given fields: cxt, commentID
+func (_m *CommentRepository) MarkCommentDeleted(cxt context.Context, commentID string) (domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkCommentDeleted")
+	}
+
+	var r0 domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Comment); ok {
+		r0 = rf(cxt, commentID)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, commentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateCommentBody provides a mock function with given fields: cxt, commentID, body, previous
+func (_m *CommentRepository) UpdateCommentBody(cxt context.Context, commentID string, body string, previous domain.CommentRevision) (domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, commentID, body, previous)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCommentBody")
+	}
+
+	var r0 domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.CommentRevision) (domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, commentID, body, previous)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.CommentRevision) domain.Comment); ok {
+		r0 = rf(cxt, commentID, body, previous)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.CommentRevision) *domain.TaskError); ok {
+		r1 = rf(cxt, commentID, body, previous)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewCommentRepository creates a new instance of CommentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentRepository {
+	mock := &CommentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,161 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentUsecase is an autogenerated mock type for the CommentUsecase type
+type CommentUsecase struct {
+	mock.Mock
+}
+
+// AddComment provides a mock function with given fields: cxt, comment
+func (_m *CommentUsecase) AddComment(cxt context.Context, comment domain.Comment) (domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddComment")
+	}
+
+	var r0 domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) (domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Comment) domain.Comment); ok {
+		r0 = rf(cxt, comment)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Comment) *domain.TaskError); ok {
+		r1 = rf(cxt, comment)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: cxt, taskID, commentID, authorID
+func (_m *CommentUsecase) DeleteComment(cxt context.Context, taskID string, commentID string, authorID string) *domain.TaskError {
+	ret := _m.Called(cxt, taskID, commentID, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteComment")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.TaskError); ok {
+		r0 = rf(cxt, taskID, commentID, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// EditComment provides a mock function with given fields: cxt, taskID, commentID, authorID, body
+func (_m *CommentUsecase) EditComment(cxt context.Context, taskID string, commentID string, authorID string, body string) (domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, commentID, authorID, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditComment")
+	}
+
+	var r0 domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, taskID, commentID, authorID, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) domain.Comment); ok {
+		r0 = rf(cxt, taskID, commentID, authorID, body)
+	} else {
+		r0 = ret.Get(0).(domain.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, commentID, authorID, body)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: cxt, taskID
+func (_m *CommentUsecase) GetComments(cxt context.Context, taskID string) ([]domain.Comment, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetComments")
+	}
+
+	var r0 []domain.Comment
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Comment, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Comment); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// TaskDeleted provides a mock function with given fields: cxt, task
+func (_m *CommentUsecase) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	ret := _m.Called(cxt, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskDeleted")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) *domain.TaskError); ok {
+		r0 = rf(cxt, task)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// NewCommentUsecase creates a new instance of CommentUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentUsecase {
+	mock := &CommentUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,231 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProjectRepository is an autogenerated mock type for the ProjectRepository type
+type ProjectRepository struct {
+	mock.Mock
+}
+
+// CreateProject provides a mock function with given fields: cxt, project
+func (_m *ProjectRepository) CreateProject(cxt context.Context, project domain.Project) (string, *domain.TaskError) {
+	ret := _m.Called(cxt, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProject")
+	}
+
+	var r0 string
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project) (string, *domain.TaskError)); ok {
+		return rf(cxt, project)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project) string); ok {
+		r0 = rf(cxt, project)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Project) *domain.TaskError); ok {
+		r1 = rf(cxt, project)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteProject provides a mock function with given fields: cxt, ID
+func (_m *ProjectRepository) DeleteProject(cxt context.Context, ID string) *domain.TaskError {
+	ret := _m.Called(cxt, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProject")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskError); ok {
+		r0 = rf(cxt, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// FetchProjectByID provides a mock function with given fields: cxt, ID
+func (_m *ProjectRepository) FetchProjectByID(cxt context.Context, ID string) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchProjectByID")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Project); ok {
+		r0 = rf(cxt, ID)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, ID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchProjects provides a mock function with given fields: cxt, memberID
+func (_m *ProjectRepository) FetchProjects(cxt context.Context, memberID string) ([]domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchProjects")
+	}
+
+	var r0 []domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Project); ok {
+		r0 = rf(cxt, memberID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, memberID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: cxt, projectID, userID
+func (_m *ProjectRepository) RemoveMember(cxt context.Context, projectID string, userID string) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, projectID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, projectID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Project); ok {
+		r0 = rf(cxt, projectID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, projectID, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SetMember provides a mock function with given fields: cxt, projectID, member
+func (_m *ProjectRepository) SetMember(cxt context.Context, projectID string, member domain.ProjectMember) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, projectID, member)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMember")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProjectMember) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, projectID, member)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProjectMember) domain.Project); ok {
+		r0 = rf(cxt, projectID, member)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ProjectMember) *domain.TaskError); ok {
+		r1 = rf(cxt, projectID, member)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateProject provides a mock function with given fields: cxt, project
+func (_m *ProjectRepository) UpdateProject(cxt context.Context, project domain.Project) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProject")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, project)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project) domain.Project); ok {
+		r0 = rf(cxt, project)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Project) *domain.TaskError); ok {
+		r1 = rf(cxt, project)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewProjectRepository creates a new instance of ProjectRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProjectRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProjectRepository {
+	mock := &ProjectRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,261 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProjectUsecase is an autogenerated mock type for the ProjectUsecase type
+type ProjectUsecase struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: cxt, projectID, user, role
+func (_m *ProjectUsecase) Authorize(cxt context.Context, projectID string, user domain.User, role string) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, projectID, user, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User, string) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, projectID, user, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User, string) domain.Project); ok {
+		r0 = rf(cxt, projectID, user, role)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.User, string) *domain.TaskError); ok {
+		r1 = rf(cxt, projectID, user, role)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateProject provides a mock function with given fields: cxt, project, owner
+func (_m *ProjectUsecase) CreateProject(cxt context.Context, project domain.Project, owner domain.User) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, project, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProject")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project, domain.User) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, project, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project, domain.User) domain.Project); ok {
+		r0 = rf(cxt, project, owner)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Project, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, project, owner)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeleteProject provides a mock function with given fields: cxt, projectID, user
+func (_m *ProjectUsecase) DeleteProject(cxt context.Context, projectID string, user domain.User) *domain.TaskError {
+	ret := _m.Called(cxt, projectID, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProject")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) *domain.TaskError); ok {
+		r0 = rf(cxt, projectID, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// GetProject provides a mock function with given fields: cxt, projectID, user
+func (_m *ProjectUsecase) GetProject(cxt context.Context, projectID string, user domain.User) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, projectID, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProject")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, projectID, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) domain.Project); ok {
+		r0 = rf(cxt, projectID, user)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, projectID, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetProjects provides a mock function with given fields: cxt, user
+func (_m *ProjectUsecase) GetProjects(cxt context.Context, user domain.User) ([]domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProjects")
+	}
+
+	var r0 []domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) ([]domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) []domain.Project); ok {
+		r0 = rf(cxt, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: cxt, projectID, memberID, user
+func (_m *ProjectUsecase) RemoveMember(cxt context.Context, projectID string, memberID string, user domain.User) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, projectID, memberID, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, projectID, memberID, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) domain.Project); ok {
+		r0 = rf(cxt, projectID, memberID, user)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, projectID, memberID, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SetMember provides a mock function with given fields: cxt, projectID, member, user
+func (_m *ProjectUsecase) SetMember(cxt context.Context, projectID string, member domain.ProjectMember, user domain.User) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, projectID, member, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMember")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProjectMember, domain.User) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, projectID, member, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProjectMember, domain.User) domain.Project); ok {
+		r0 = rf(cxt, projectID, member, user)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ProjectMember, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, projectID, member, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateProject provides a mock function with given fields: cxt, project, user
+func (_m *ProjectUsecase) UpdateProject(cxt context.Context, project domain.Project, user domain.User) (domain.Project, *domain.TaskError) {
+	ret := _m.Called(cxt, project, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProject")
+	}
+
+	var r0 domain.Project
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project, domain.User) (domain.Project, *domain.TaskError)); ok {
+		return rf(cxt, project, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Project, domain.User) domain.Project); ok {
+		r0 = rf(cxt, project, user)
+	} else {
+		r0 = ret.Get(0).(domain.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Project, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, project, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewProjectUsecase creates a new instance of ProjectUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProjectUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProjectUsecase {
+	mock := &ProjectUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
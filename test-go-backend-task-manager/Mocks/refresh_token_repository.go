@@ -0,0 +1,131 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RefreshTokenRepository is an autogenerated mock type for the RefreshTokenRepository type
+type RefreshTokenRepository struct {
+	mock.Mock
+}
+
+// ConsumeRefreshToken provides a mock function with given fields: cxt, ID, usedAt
+func (_m *RefreshTokenRepository) ConsumeRefreshToken(cxt context.Context, ID string, usedAt time.Time) (domain.RefreshToken, *domain.UserError) {
+	ret := _m.Called(cxt, ID, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeRefreshToken")
+	}
+
+	var r0 domain.RefreshToken
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (domain.RefreshToken, *domain.UserError)); ok {
+		return rf(cxt, ID, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) domain.RefreshToken); ok {
+		r0 = rf(cxt, ID, usedAt)
+	} else {
+		r0 = ret.Get(0).(domain.RefreshToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) *domain.UserError); ok {
+		r1 = rf(cxt, ID, usedAt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateRefreshToken provides a mock function with given fields: cxt, token
+func (_m *RefreshTokenRepository) CreateRefreshToken(cxt context.Context, token domain.RefreshToken) *domain.UserError {
+	ret := _m.Called(cxt, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RefreshToken) *domain.UserError); ok {
+		r0 = rf(cxt, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// FetchRefreshToken provides a mock function with given fields: cxt, ID
+func (_m *RefreshTokenRepository) FetchRefreshToken(cxt context.Context, ID string) (domain.RefreshToken, *domain.UserError) {
+	ret := _m.Called(cxt, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRefreshToken")
+	}
+
+	var r0 domain.RefreshToken
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.RefreshToken, *domain.UserError)); ok {
+		return rf(cxt, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.RefreshToken); ok {
+		r0 = rf(cxt, ID)
+	} else {
+		r0 = ret.Get(0).(domain.RefreshToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, ID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RevokeTokenFamily provides a mock function with given fields: cxt, familyID, revokedAt
+func (_m *RefreshTokenRepository) RevokeTokenFamily(cxt context.Context, familyID string, revokedAt time.Time) *domain.UserError {
+	ret := _m.Called(cxt, familyID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeTokenFamily")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.UserError); ok {
+		r0 = rf(cxt, familyID, revokedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefreshTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RefreshTokenRepository {
+	mock := &RefreshTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,141 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReminderRepository is an autogenerated mock type for the ReminderRepository type
+type ReminderRepository struct {
+	mock.Mock
+}
+
+// FetchPreferences provides a mock function with given fields: cxt, userIDs
+func (_m *ReminderRepository) FetchPreferences(cxt context.Context, userIDs []string) ([]domain.ReminderPreference, *domain.TaskError) {
+	ret := _m.Called(cxt, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchPreferences")
+	}
+
+	var r0 []domain.ReminderPreference
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.ReminderPreference, *domain.TaskError)); ok {
+		return rf(cxt, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.ReminderPreference); ok {
+		r0 = rf(cxt, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ReminderPreference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, userIDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// MarkReminderSent provides a mock function with given fields: cxt, reminder
+func (_m *ReminderRepository) MarkReminderSent(cxt context.Context, reminder domain.Reminder) (bool, *domain.TaskError) {
+	ret := _m.Called(cxt, reminder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReminderSent")
+	}
+
+	var r0 bool
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Reminder) (bool, *domain.TaskError)); ok {
+		return rf(cxt, reminder)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Reminder) bool); ok {
+		r0 = rf(cxt, reminder)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Reminder) *domain.TaskError); ok {
+		r1 = rf(cxt, reminder)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UnmarkReminder provides a mock function with given fields: cxt, reminderID
+func (_m *ReminderRepository) UnmarkReminder(cxt context.Context, reminderID string) *domain.TaskError {
+	ret := _m.Called(cxt, reminderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnmarkReminder")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskError); ok {
+		r0 = rf(cxt, reminderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// UpsertPreference provides a mock function with given fields: cxt, preference
+func (_m *ReminderRepository) UpsertPreference(cxt context.Context, preference domain.ReminderPreference) (domain.ReminderPreference, *domain.TaskError) {
+	ret := _m.Called(cxt, preference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertPreference")
+	}
+
+	var r0 domain.ReminderPreference
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReminderPreference) (domain.ReminderPreference, *domain.TaskError)); ok {
+		return rf(cxt, preference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReminderPreference) domain.ReminderPreference); ok {
+		r0 = rf(cxt, preference)
+	} else {
+		r0 = ret.Get(0).(domain.ReminderPreference)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ReminderPreference) *domain.TaskError); ok {
+		r1 = rf(cxt, preference)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewReminderRepository creates a new instance of ReminderRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReminderRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReminderRepository {
+	mock := &ReminderRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
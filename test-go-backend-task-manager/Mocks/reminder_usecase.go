@@ -0,0 +1,123 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReminderUsecase is an autogenerated mock type for the ReminderUsecase type
+type ReminderUsecase struct {
+	mock.Mock
+}
+
+// GetPreference provides a mock function with given fields: cxt, userID
+func (_m *ReminderUsecase) GetPreference(cxt context.Context, userID string) (domain.ReminderPreference, *domain.TaskError) {
+	ret := _m.Called(cxt, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreference")
+	}
+
+	var r0 domain.ReminderPreference
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.ReminderPreference, *domain.TaskError)); ok {
+		return rf(cxt, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.ReminderPreference); ok {
+		r0 = rf(cxt, userID)
+	} else {
+		r0 = ret.Get(0).(domain.ReminderPreference)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ScanReminders provides a mock function with given fields: cxt, now
+func (_m *ReminderUsecase) ScanReminders(cxt context.Context, now time.Time) ([]domain.Reminder, *domain.TaskError) {
+	ret := _m.Called(cxt, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScanReminders")
+	}
+
+	var r0 []domain.Reminder
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Reminder, *domain.TaskError)); ok {
+		return rf(cxt, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Reminder); ok {
+		r0 = rf(cxt, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Reminder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) *domain.TaskError); ok {
+		r1 = rf(cxt, now)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdatePreference provides a mock function with given fields: cxt, preference
+func (_m *ReminderUsecase) UpdatePreference(cxt context.Context, preference domain.ReminderPreference) (domain.ReminderPreference, *domain.TaskError) {
+	ret := _m.Called(cxt, preference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePreference")
+	}
+
+	var r0 domain.ReminderPreference
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReminderPreference) (domain.ReminderPreference, *domain.TaskError)); ok {
+		return rf(cxt, preference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReminderPreference) domain.ReminderPreference); ok {
+		r0 = rf(cxt, preference)
+	} else {
+		r0 = ret.Get(0).(domain.ReminderPreference)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ReminderPreference) *domain.TaskError); ok {
+		r1 = rf(cxt, preference)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewReminderUsecase creates a new instance of ReminderUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReminderUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReminderUsecase {
+	mock := &ReminderUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
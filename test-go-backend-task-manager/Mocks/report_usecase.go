@@ -0,0 +1,89 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReportUsecase is an autogenerated mock type for the ReportUsecase type
+type ReportUsecase struct {
+	mock.Mock
+}
+
+// GetBurndown provides a mock function with given fields: cxt, query
+func (_m *ReportUsecase) GetBurndown(cxt context.Context, query domain.ReportQuery) (domain.Burndown, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBurndown")
+	}
+
+	var r0 domain.Burndown
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReportQuery) (domain.Burndown, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReportQuery) domain.Burndown); ok {
+		r0 = rf(cxt, query)
+	} else {
+		r0 = ret.Get(0).(domain.Burndown)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ReportQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetVelocity provides a mock function with given fields: cxt, query
+func (_m *ReportUsecase) GetVelocity(cxt context.Context, query domain.ReportQuery) (domain.Velocity, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVelocity")
+	}
+
+	var r0 domain.Velocity
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReportQuery) (domain.Velocity, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReportQuery) domain.Velocity); ok {
+		r0 = rf(cxt, query)
+	} else {
+		r0 = ret.Get(0).(domain.Velocity)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ReportQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewReportUsecase creates a new instance of ReportUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReportUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReportUsecase {
+	mock := &ReportUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,131 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RevisionRepository is an autogenerated mock type for the RevisionRepository type
+type RevisionRepository struct {
+	mock.Mock
+}
+
+// CreateRevision provides a mock function with given fields: cxt, revision
+func (_m *RevisionRepository) CreateRevision(cxt context.Context, revision domain.TaskRevision) *domain.TaskError {
+	ret := _m.Called(cxt, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRevision")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskRevision) *domain.TaskError); ok {
+		r0 = rf(cxt, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// DeleteRevisions provides a mock function with given fields: cxt, taskID
+func (_m *RevisionRepository) DeleteRevisions(cxt context.Context, taskID string) *domain.TaskError {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRevisions")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskError); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// FetchRevision provides a mock function with given fields: cxt, taskID, number
+func (_m *RevisionRepository) FetchRevision(cxt context.Context, taskID string, number int) (domain.TaskRevision, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRevision")
+	}
+
+	var r0 domain.TaskRevision
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (domain.TaskRevision, *domain.TaskError)); ok {
+		return rf(cxt, taskID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) domain.TaskRevision); ok {
+		r0 = rf(cxt, taskID, number)
+	} else {
+		r0 = ret.Get(0).(domain.TaskRevision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, number)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchRevisions provides a mock function with given fields: cxt, taskID
+func (_m *RevisionRepository) FetchRevisions(cxt context.Context, taskID string) ([]domain.TaskRevision, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRevisions")
+	}
+
+	var r0 []domain.TaskRevision
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.TaskRevision, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.TaskRevision); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TaskRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewRevisionRepository creates a new instance of RevisionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevisionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevisionRepository {
+	mock := &RevisionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,49 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskDeleteListener is an autogenerated mock type for the TaskDeleteListener type
+type TaskDeleteListener struct {
+	mock.Mock
+}
+
+// TaskDeleted provides a mock function with given fields: cxt, task
+func (_m *TaskDeleteListener) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	ret := _m.Called(cxt, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskDeleted")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) *domain.TaskError); ok {
+		r0 = rf(cxt, task)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// NewTaskDeleteListener creates a new instance of TaskDeleteListener. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskDeleteListener(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskDeleteListener {
+	mock := &TaskDeleteListener{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskRepository is an autogenerated mock type for the TaskRepository type
@@ -14,6 +16,126 @@ type TaskRepository struct {
 	mock.Mock
 }
 
+// AddAssignee provides a mock function with given fields: cxt, taskID, userID
+func (_m *TaskRepository) AddAssignee(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAssignee")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// AddAttachment provides a mock function with given fields: cxt, taskID, attachment
+func (_m *TaskRepository) AddAttachment(cxt context.Context, taskID string, attachment domain.Attachment) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, attachment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAttachment")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Attachment) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, attachment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Attachment) domain.Task); ok {
+		r0 = rf(cxt, taskID, attachment)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Attachment) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, attachment)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// AddDependency provides a mock function with given fields: cxt, taskID, blockerID
+func (_m *TaskRepository) AddDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDependency")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, blockerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, blockerID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, blockerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// AddWatcher provides a mock function with given fields: cxt, taskID, userID
+func (_m *TaskRepository) AddWatcher(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddWatcher")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
 // CreateTask provides a mock function with given fields: cxt, newTask
 func (_m *TaskRepository) CreateTask(cxt context.Context, newTask domain.Task) (string, *domain.TaskError) {
 	ret := _m.Called(cxt, newTask)
@@ -44,9 +166,9 @@ func (_m *TaskRepository) CreateTask(cxt context.Context, newTask domain.Task) (
 	return r0, r1
 }
 
-// DeleteTask provides a mock function with given fields: cxt, taskID
-func (_m *TaskRepository) DeleteTask(cxt context.Context, taskID string) (domain.Task, *domain.TaskError) {
-	ret := _m.Called(cxt, taskID)
+// DeleteTask provides a mock function with given fields: cxt, taskID, expectedVersion
+func (_m *TaskRepository) DeleteTask(cxt context.Context, taskID string, expectedVersion int) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, expectedVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
@@ -54,17 +176,17 @@ func (_m *TaskRepository) DeleteTask(cxt context.Context, taskID string) (domain
 
 	var r0 domain.Task
 	var r1 *domain.TaskError
-	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Task, *domain.TaskError)); ok {
-		return rf(cxt, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, expectedVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Task); ok {
-		r0 = rf(cxt, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) domain.Task); ok {
+		r0 = rf(cxt, taskID, expectedVersion)
 	} else {
 		r0 = ret.Get(0).(domain.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
-		r1 = rf(cxt, taskID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, expectedVersion)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*domain.TaskError)
@@ -106,6 +228,230 @@ func (_m *TaskRepository) FetchAllTasks(cxt context.Context) ([]domain.Task, *do
 	return r0, r1
 }
 
+// FetchChildTasks provides a mock function with given fields: cxt, parentID
+func (_m *TaskRepository) FetchChildTasks(cxt context.Context, parentID string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, parentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchChildTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, parentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Task); ok {
+		r0 = rf(cxt, parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, parentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchDeletedTasks provides a mock function with given fields: cxt, deletedBefore, limit
+func (_m *TaskRepository) FetchDeletedTasks(cxt context.Context, deletedBefore time.Time, limit int) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, deletedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchDeletedTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, deletedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []domain.Task); ok {
+		r0 = rf(cxt, deletedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) *domain.TaskError); ok {
+		r1 = rf(cxt, deletedBefore, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchDependencies provides a mock function with given fields: cxt, IDs
+func (_m *TaskRepository) FetchDependencies(cxt context.Context, IDs []string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchDependencies")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, IDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Task); ok {
+		r0 = rf(cxt, IDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, IDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchDueTasks provides a mock function with given fields: cxt, dueAfter, dueBefore, excludeStatuses
+func (_m *TaskRepository) FetchDueTasks(cxt context.Context, dueAfter time.Time, dueBefore time.Time, excludeStatuses []string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, dueAfter, dueBefore, excludeStatuses)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchDueTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, []string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, dueAfter, dueBefore, excludeStatuses)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, []string) []domain.Task); ok {
+		r0 = rf(cxt, dueAfter, dueBefore, excludeStatuses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, dueAfter, dueBefore, excludeStatuses)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchEstimatedTasks provides a mock function with given fields: cxt, query, finalStatuses
+func (_m *TaskRepository) FetchEstimatedTasks(cxt context.Context, query domain.ReportQuery, finalStatuses []string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, query, finalStatuses)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchEstimatedTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReportQuery, []string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, query, finalStatuses)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReportQuery, []string) []domain.Task); ok {
+		r0 = rf(cxt, query, finalStatuses)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ReportQuery, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, query, finalStatuses)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchSubtree provides a mock function with given fields: cxt, rootID
+func (_m *TaskRepository) FetchSubtree(cxt context.Context, rootID string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, rootID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchSubtree")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, rootID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Task); ok {
+		r0 = rf(cxt, rootID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, rootID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchTagCounts provides a mock function with given fields: cxt
+func (_m *TaskRepository) FetchTagCounts(cxt context.Context) ([]domain.TagCount, *domain.TaskError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTagCounts")
+	}
+
+	var r0 []domain.TagCount
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.TagCount, *domain.TaskError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.TagCount); ok {
+		r0 = rf(cxt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.TaskError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
 // FetchTaskByID provides a mock function with given fields: cxt, ID
 func (_m *TaskRepository) FetchTaskByID(cxt context.Context, ID string) (domain.Task, *domain.TaskError) {
 	ret := _m.Called(cxt, ID)
@@ -136,27 +482,461 @@ func (_m *TaskRepository) FetchTaskByID(cxt context.Context, ID string) (domain.
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: cxt, updateTask
-func (_m *TaskRepository) UpdateTask(cxt context.Context, updateTask domain.Task) (domain.Task, *domain.TaskError) {
-	ret := _m.Called(cxt, updateTask)
+// FetchTasks provides a mock function with given fields: cxt, query
+func (_m *TaskRepository) FetchTasks(cxt context.Context, query domain.TaskQuery) (domain.TaskPage, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateTask")
+		panic("no return value specified for FetchTasks")
 	}
 
-	var r0 domain.Task
+	var r0 domain.TaskPage
 	var r1 *domain.TaskError
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) (domain.Task, *domain.TaskError)); ok {
-		return rf(cxt, updateTask)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) (domain.TaskPage, *domain.TaskError)); ok {
+		return rf(cxt, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) domain.Task); ok {
-		r0 = rf(cxt, updateTask)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) domain.TaskPage); ok {
+		r0 = rf(cxt, query)
 	} else {
-		r0 = ret.Get(0).(domain.Task)
+		r0 = ret.Get(0).(domain.TaskPage)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Task) *domain.TaskError); ok {
-		r1 = rf(cxt, updateTask)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchTasksByIDs provides a mock function with given fields: cxt, IDs
+func (_m *TaskRepository) FetchTasksByIDs(cxt context.Context, IDs []string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTasksByIDs")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, IDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Task); ok {
+		r0 = rf(cxt, IDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, IDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// LinkNextOccurrence provides a mock function with given fields: cxt, taskID, nextID
+func (_m *TaskRepository) LinkNextOccurrence(cxt context.Context, taskID string, nextID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, nextID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkNextOccurrence")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, nextID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, nextID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, nextID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PatchTask provides a mock function with given fields: cxt, task, fields
+func (_m *TaskRepository) PatchTask(cxt context.Context, task domain.Task, fields []string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, task, fields)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task, []string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, task, fields)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task, []string) domain.Task); ok {
+		r0 = rf(cxt, task, fields)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Task, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, task, fields)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeTask provides a mock function with given fields: cxt, ID, deletedBefore
+func (_m *TaskRepository) PurgeTask(cxt context.Context, ID string, deletedBefore time.Time) *domain.TaskError {
+	ret := _m.Called(cxt, ID, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTask")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.TaskError); ok {
+		r0 = rf(cxt, ID, deletedBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// RemoveAssignee provides a mock function with given fields: cxt, taskID, userID
+func (_m *TaskRepository) RemoveAssignee(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAssignee")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveAttachment provides a mock function with given fields: cxt, taskID, attachmentID
+func (_m *TaskRepository) RemoveAttachment(cxt context.Context, taskID string, attachmentID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAttachment")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, attachmentID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, attachmentID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveDependency provides a mock function with given fields: cxt, taskID, blockerID
+func (_m *TaskRepository) RemoveDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDependency")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, blockerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, blockerID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, blockerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveWatcher provides a mock function with given fields: cxt, taskID, userID
+func (_m *TaskRepository) RemoveWatcher(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveWatcher")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, userID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ReplaceTags provides a mock function with given fields: cxt, from, to
+func (_m *TaskRepository) ReplaceTags(cxt context.Context, from []string, to string) (int64, *domain.TaskError) {
+	ret := _m.Called(cxt, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceTags")
+	}
+
+	var r0 int64
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) (int64, *domain.TaskError)); ok {
+		return rf(cxt, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) int64); ok {
+		r0 = rf(cxt, from, to)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, from, to)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RestoreTask provides a mock function with given fields: cxt, task
+func (_m *TaskRepository) RestoreTask(cxt context.Context, task domain.Task) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, task)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) domain.Task); ok {
+		r0 = rf(cxt, task)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Task) *domain.TaskError); ok {
+		r1 = rf(cxt, task)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SearchTasks provides a mock function with given fields: cxt, query, limit
+func (_m *TaskRepository) SearchTasks(cxt context.Context, query string, limit int) ([]domain.TaskSearchResult, *domain.TaskError) {
+	ret := _m.Called(cxt, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []domain.TaskSearchResult
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.TaskSearchResult, *domain.TaskError)); ok {
+		return rf(cxt, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.TaskSearchResult); ok {
+		r0 = rf(cxt, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) *domain.TaskError); ok {
+		r1 = rf(cxt, query, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UndeleteTask provides a mock function with given fields: cxt, ID
+func (_m *TaskRepository) UndeleteTask(cxt context.Context, ID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UndeleteTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Task); ok {
+		r0 = rf(cxt, ID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, ID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UnlinkDependents provides a mock function with given fields: cxt, blockerID
+func (_m *TaskRepository) UnlinkDependents(cxt context.Context, blockerID string) *domain.TaskError {
+	ret := _m.Called(cxt, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlinkDependents")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TaskError); ok {
+		r0 = rf(cxt, blockerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// UpdateTask provides a mock function with given fields: cxt, updateTask
+func (_m *TaskRepository) UpdateTask(cxt context.Context, updateTask domain.Task) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, updateTask)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, updateTask)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) domain.Task); ok {
+		r0 = rf(cxt, updateTask)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Task) *domain.TaskError); ok {
+		r1 = rf(cxt, updateTask)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateTaskTags provides a mock function with given fields: cxt, taskIDs, add, remove
+func (_m *TaskRepository) UpdateTaskTags(cxt context.Context, taskIDs []string, add []string, remove []string) (int64, *domain.TaskError) {
+	ret := _m.Called(cxt, taskIDs, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTaskTags")
+	}
+
+	var r0 int64
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string, []string) (int64, *domain.TaskError)); ok {
+		return rf(cxt, taskIDs, add, remove)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string, []string) int64); ok {
+		r0 = rf(cxt, taskIDs, add, remove)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, []string, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskIDs, add, remove)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*domain.TaskError)
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TaskUsecase is an autogenerated mock type for the TaskUsecase type
@@ -14,6 +16,66 @@ type TaskUsecase struct {
 	mock.Mock
 }
 
+// AddDependency provides a mock function with given fields: cxt, taskID, blockerID
+func (_m *TaskUsecase) AddDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddDependency")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, blockerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, blockerID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, blockerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// AssignTask provides a mock function with given fields: cxt, taskID, assigneeID, authority
+func (_m *TaskUsecase) AssignTask(cxt context.Context, taskID string, assigneeID string, authority domain.User) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, assigneeID, authority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, assigneeID, authority)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) domain.Task); ok {
+		r0 = rf(cxt, taskID, assigneeID, authority)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, assigneeID, authority)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
 // CreateTask provides a mock function with given fields: cxt, newTask
 func (_m *TaskUsecase) CreateTask(cxt context.Context, newTask domain.Task) (string, *domain.TaskError) {
 	ret := _m.Called(cxt, newTask)
@@ -44,9 +106,9 @@ func (_m *TaskUsecase) CreateTask(cxt context.Context, newTask domain.Task) (str
 	return r0, r1
 }
 
-// DeleteTask provides a mock function with given fields: cxt, taskID, authorityID
-func (_m *TaskUsecase) DeleteTask(cxt context.Context, taskID string, authorityID string) (domain.Task, *domain.TaskError) {
-	ret := _m.Called(cxt, taskID, authorityID)
+// DeleteTask provides a mock function with given fields: cxt, taskID, authority, expectedVersion
+func (_m *TaskUsecase) DeleteTask(cxt context.Context, taskID string, authority domain.User, expectedVersion int) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, authority, expectedVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteTask")
@@ -54,17 +116,47 @@ func (_m *TaskUsecase) DeleteTask(cxt context.Context, taskID string, authorityI
 
 	var r0 domain.Task
 	var r1 *domain.TaskError
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
-		return rf(cxt, taskID, authorityID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User, int) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, authority, expectedVersion)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
-		r0 = rf(cxt, taskID, authorityID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User, int) domain.Task); ok {
+		r0 = rf(cxt, taskID, authority, expectedVersion)
 	} else {
 		r0 = ret.Get(0).(domain.Task)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
-		r1 = rf(cxt, taskID, authorityID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.User, int) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, authority, expectedVersion)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// DiffRevisions provides a mock function with given fields: cxt, taskID, from, to
+func (_m *TaskUsecase) DiffRevisions(cxt context.Context, taskID string, from int, to int) (domain.RevisionDiff, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffRevisions")
+	}
+
+	var r0 domain.RevisionDiff
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (domain.RevisionDiff, *domain.TaskError)); ok {
+		return rf(cxt, taskID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) domain.RevisionDiff); ok {
+		r0 = rf(cxt, taskID, from, to)
+	} else {
+		r0 = ret.Get(0).(domain.RevisionDiff)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, from, to)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*domain.TaskError)
@@ -106,6 +198,134 @@ func (_m *TaskUsecase) GetAllTasks(cxt context.Context) ([]domain.Task, *domain.
 	return r0, r1
 }
 
+// GetChildTasks provides a mock function with given fields: cxt, taskID
+func (_m *TaskUsecase) GetChildTasks(cxt context.Context, taskID string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChildTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Task); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetDeletedTasks provides a mock function with given fields: cxt
+func (_m *TaskUsecase) GetDeletedTasks(cxt context.Context) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Task); ok {
+		r0 = rf(cxt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.TaskError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRevisions provides a mock function with given fields: cxt, taskID
+func (_m *TaskUsecase) GetRevisions(cxt context.Context, taskID string) ([]domain.TaskRevision, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevisions")
+	}
+
+	var r0 []domain.TaskRevision
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.TaskRevision, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.TaskRevision); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TaskRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTags provides a mock function with given fields: cxt
+func (_m *TaskUsecase) GetTags(cxt context.Context) ([]domain.TagCount, *domain.TaskError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTags")
+	}
+
+	var r0 []domain.TagCount
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.TagCount, *domain.TaskError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.TagCount); ok {
+		r0 = rf(cxt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.TaskError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetTaskByID provides a mock function with given fields: cxt, taskID
 func (_m *TaskUsecase) GetTaskByID(cxt context.Context, taskID string) (domain.Task, *domain.TaskError) {
 	ret := _m.Called(cxt, taskID)
@@ -136,27 +356,563 @@ func (_m *TaskUsecase) GetTaskByID(cxt context.Context, taskID string) (domain.T
 	return r0, r1
 }
 
-// UpdateTask provides a mock function with given fields: cxt, updateTask
-func (_m *TaskUsecase) UpdateTask(cxt context.Context, updateTask domain.Task) (domain.Task, *domain.TaskError) {
-	ret := _m.Called(cxt, updateTask)
+// GetTaskTree provides a mock function with given fields: cxt, taskID
+func (_m *TaskUsecase) GetTaskTree(cxt context.Context, taskID string) (domain.TaskNode, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateTask")
+		panic("no return value specified for GetTaskTree")
 	}
 
-	var r0 domain.Task
+	var r0 domain.TaskNode
 	var r1 *domain.TaskError
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) (domain.Task, *domain.TaskError)); ok {
-		return rf(cxt, updateTask)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TaskNode, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TaskNode); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		r0 = ret.Get(0).(domain.TaskNode)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) domain.Task); ok {
-		r0 = rf(cxt, updateTask)
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
 	} else {
-		r0 = ret.Get(0).(domain.Task)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domain.Task) *domain.TaskError); ok {
-		r1 = rf(cxt, updateTask)
+	return r0, r1
+}
+
+// GetTasks provides a mock function with given fields: cxt, query
+func (_m *TaskUsecase) GetTasks(cxt context.Context, query domain.TaskQuery) (domain.TaskPage, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTasks")
+	}
+
+	var r0 domain.TaskPage
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) (domain.TaskPage, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TaskQuery) domain.TaskPage); ok {
+		r0 = rf(cxt, query)
+	} else {
+		r0 = ret.Get(0).(domain.TaskPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TaskQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetWorkflow provides a mock function with given fields:
+func (_m *TaskUsecase) GetWorkflow() domain.Workflow {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflow")
+	}
+
+	var r0 domain.Workflow
+	if rf, ok := ret.Get(0).(func() domain.Workflow); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.Workflow)
+	}
+
+	return r0
+}
+
+// MergeTags provides a mock function with given fields: cxt, tags, into
+func (_m *TaskUsecase) MergeTags(cxt context.Context, tags []string, into string) (int64, *domain.TaskError) {
+	ret := _m.Called(cxt, tags, into)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeTags")
+	}
+
+	var r0 int64
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) (int64, *domain.TaskError)); ok {
+		return rf(cxt, tags, into)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) int64); ok {
+		r0 = rf(cxt, tags, into)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, tags, into)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NextStatuses provides a mock function with given fields: cxt, status
+func (_m *TaskUsecase) NextStatuses(cxt context.Context, status string) ([]string, *domain.TaskError) {
+	ret := _m.Called(cxt, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextStatuses")
+	}
+
+	var r0 []string
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, *domain.TaskError)); ok {
+		return rf(cxt, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(cxt, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, status)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PatchTask provides a mock function with given fields: cxt, taskID, patch, authority
+func (_m *TaskUsecase) PatchTask(cxt context.Context, taskID string, patch domain.TaskPatch, authority domain.User) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, patch, authority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TaskPatch, domain.User) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, patch, authority)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TaskPatch, domain.User) domain.Task); ok {
+		r0 = rf(cxt, taskID, patch, authority)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TaskPatch, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, patch, authority)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PlanTasks provides a mock function with given fields: cxt, taskIDs
+func (_m *TaskUsecase) PlanTasks(cxt context.Context, taskIDs []string) ([]domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlanTasks")
+	}
+
+	var r0 []domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Task); ok {
+		r0 = rf(cxt, taskIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskIDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PreviewOccurrences provides a mock function with given fields: cxt, taskID, count
+func (_m *TaskUsecase) PreviewOccurrences(cxt context.Context, taskID string, count int) ([]time.Time, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewOccurrences")
+	}
+
+	var r0 []time.Time
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]time.Time, *domain.TaskError)); ok {
+		return rf(cxt, taskID, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []time.Time); ok {
+		r0 = rf(cxt, taskID, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, count)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedTasks provides a mock function with given fields: cxt, deletedBefore
+func (_m *TaskUsecase) PurgeDeletedTasks(cxt context.Context, deletedBefore time.Time) (int, *domain.TaskError) {
+	ret := _m.Called(cxt, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedTasks")
+	}
+
+	var r0 int
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, *domain.TaskError)); ok {
+		return rf(cxt, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(cxt, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) *domain.TaskError); ok {
+		r1 = rf(cxt, deletedBefore)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RemoveDependency provides a mock function with given fields: cxt, taskID, blockerID
+func (_m *TaskUsecase) RemoveDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDependency")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, blockerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, blockerID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, blockerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RenameTag provides a mock function with given fields: cxt, tag, newName
+func (_m *TaskUsecase) RenameTag(cxt context.Context, tag string, newName string) (int64, *domain.TaskError) {
+	ret := _m.Called(cxt, tag, newName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameTag")
+	}
+
+	var r0 int64
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, *domain.TaskError)); ok {
+		return rf(cxt, tag, newName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(cxt, tag, newName)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, tag, newName)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RestoreDeletedTask provides a mock function with given fields: cxt, taskID
+func (_m *TaskUsecase) RestoreDeletedTask(cxt context.Context, taskID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreDeletedTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Task); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RevertTask provides a mock function with given fields: cxt, taskID, revision, authority
+func (_m *TaskUsecase) RevertTask(cxt context.Context, taskID string, revision int, authority domain.User) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, revision, authority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, domain.User) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, revision, authority)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, domain.User) domain.Task); ok {
+		r0 = rf(cxt, taskID, revision, authority)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, revision, authority)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// SearchTasks provides a mock function with given fields: cxt, query, limit
+func (_m *TaskUsecase) SearchTasks(cxt context.Context, query string, limit int) ([]domain.TaskSearchResult, *domain.TaskError) {
+	ret := _m.Called(cxt, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []domain.TaskSearchResult
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.TaskSearchResult, *domain.TaskError)); ok {
+		return rf(cxt, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.TaskSearchResult); ok {
+		r0 = rf(cxt, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) *domain.TaskError); ok {
+		r1 = rf(cxt, query, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UnassignTask provides a mock function with given fields: cxt, taskID, assigneeID, authority
+func (_m *TaskUsecase) UnassignTask(cxt context.Context, taskID string, assigneeID string, authority domain.User) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, assigneeID, authority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnassignTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, assigneeID, authority)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) domain.Task); ok {
+		r0 = rf(cxt, taskID, assigneeID, authority)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, assigneeID, authority)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UnwatchTask provides a mock function with given fields: cxt, taskID, watcherID
+func (_m *TaskUsecase) UnwatchTask(cxt context.Context, taskID string, watcherID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, watcherID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnwatchTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, watcherID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, watcherID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, watcherID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateTask provides a mock function with given fields: cxt, updateTask, authority
+func (_m *TaskUsecase) UpdateTask(cxt context.Context, updateTask domain.Task, authority domain.User) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, updateTask, authority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task, domain.User) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, updateTask, authority)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task, domain.User) domain.Task); ok {
+		r0 = rf(cxt, updateTask, authority)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Task, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, updateTask, authority)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateTaskTags provides a mock function with given fields: cxt, update
+func (_m *TaskUsecase) UpdateTaskTags(cxt context.Context, update domain.TagUpdate) (int64, *domain.TaskError) {
+	ret := _m.Called(cxt, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTaskTags")
+	}
+
+	var r0 int64
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TagUpdate) (int64, *domain.TaskError)); ok {
+		return rf(cxt, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TagUpdate) int64); ok {
+		r0 = rf(cxt, update)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TagUpdate) *domain.TaskError); ok {
+		r1 = rf(cxt, update)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// WatchTask provides a mock function with given fields: cxt, taskID, watcherID
+func (_m *TaskUsecase) WatchTask(cxt context.Context, taskID string, watcherID string) (domain.Task, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, watcherID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchTask")
+	}
+
+	var r0 domain.Task
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Task, *domain.TaskError)); ok {
+		return rf(cxt, taskID, watcherID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Task); ok {
+		r0 = rf(cxt, taskID, watcherID)
+	} else {
+		r0 = ret.Get(0).(domain.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, watcherID)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*domain.TaskError)
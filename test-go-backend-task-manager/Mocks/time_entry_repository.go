@@ -0,0 +1,205 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TimeEntryRepository is an autogenerated mock type for the TimeEntryRepository type
+type TimeEntryRepository struct {
+	mock.Mock
+}
+
+// CreateTimeEntry provides a mock function with given fields: cxt, entry
+func (_m *TimeEntryRepository) CreateTimeEntry(cxt context.Context, entry domain.TimeEntry) (string, *domain.TaskError) {
+	ret := _m.Called(cxt, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTimeEntry")
+	}
+
+	var r0 string
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeEntry) (string, *domain.TaskError)); ok {
+		return rf(cxt, entry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeEntry) string); ok {
+		r0 = rf(cxt, entry)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeEntry) *domain.TaskError); ok {
+		r1 = rf(cxt, entry)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchRunningEntry provides a mock function with given fields: cxt, userID
+func (_m *TimeEntryRepository) FetchRunningEntry(cxt context.Context, userID string) (domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRunningEntry")
+	}
+
+	var r0 domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TimeEntry); ok {
+		r0 = rf(cxt, userID)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchTimeEntries provides a mock function with given fields: cxt, query
+func (_m *TimeEntryRepository) FetchTimeEntries(cxt context.Context, query domain.TimeQuery) ([]domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTimeEntries")
+	}
+
+	var r0 []domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) ([]domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) []domain.TimeEntry); ok {
+		r0 = rf(cxt, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TimeEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchTimeReport provides a mock function with given fields: cxt, query
+func (_m *TimeEntryRepository) FetchTimeReport(cxt context.Context, query domain.TimeQuery) ([]domain.TimeReportRow, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTimeReport")
+	}
+
+	var r0 []domain.TimeReportRow
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) ([]domain.TimeReportRow, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) []domain.TimeReportRow); ok {
+		r0 = rf(cxt, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TimeReportRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// StopTaskTimers provides a mock function with given fields: cxt, taskID, end
+func (_m *TimeEntryRepository) StopTaskTimers(cxt context.Context, taskID string, end time.Time) *domain.TaskError {
+	ret := _m.Called(cxt, taskID, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopTaskTimers")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.TaskError); ok {
+		r0 = rf(cxt, taskID, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// StopTimeEntry provides a mock function with given fields: cxt, entryID, end
+func (_m *TimeEntryRepository) StopTimeEntry(cxt context.Context, entryID string, end time.Time) (domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, entryID, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopTimeEntry")
+	}
+
+	var r0 domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, entryID, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) domain.TimeEntry); ok {
+		r0 = rf(cxt, entryID, end)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) *domain.TaskError); ok {
+		r1 = rf(cxt, entryID, end)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewTimeEntryRepository creates a new instance of TimeEntryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTimeEntryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TimeEntryRepository {
+	mock := &TimeEntryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,259 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TimeUsecase is an autogenerated mock type for the TimeUsecase type
+type TimeUsecase struct {
+	mock.Mock
+}
+
+// AddTimeEntry provides a mock function with given fields: cxt, entry, user
+func (_m *TimeUsecase) AddTimeEntry(cxt context.Context, entry domain.TimeEntry, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, entry, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTimeEntry")
+	}
+
+	var r0 domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeEntry, domain.User) (domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, entry, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeEntry, domain.User) domain.TimeEntry); ok {
+		r0 = rf(cxt, entry, user)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeEntry, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, entry, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRunningTimer provides a mock function with given fields: cxt, user
+func (_m *TimeUsecase) GetRunningTimer(cxt context.Context, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRunningTimer")
+	}
+
+	var r0 domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.TimeEntry); ok {
+		r0 = rf(cxt, user)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTaskTime provides a mock function with given fields: cxt, taskID
+func (_m *TimeUsecase) GetTaskTime(cxt context.Context, taskID string) (domain.TimeSummary, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTaskTime")
+	}
+
+	var r0 domain.TimeSummary
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TimeSummary, *domain.TaskError)); ok {
+		return rf(cxt, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TimeSummary); ok {
+		r0 = rf(cxt, taskID)
+	} else {
+		r0 = ret.Get(0).(domain.TimeSummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTimeReport provides a mock function with given fields: cxt, query
+func (_m *TimeUsecase) GetTimeReport(cxt context.Context, query domain.TimeQuery) (domain.TimeReport, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimeReport")
+	}
+
+	var r0 domain.TimeReport
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) (domain.TimeReport, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) domain.TimeReport); ok {
+		r0 = rf(cxt, query)
+	} else {
+		r0 = ret.Get(0).(domain.TimeReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetUserTime provides a mock function with given fields: cxt, query
+func (_m *TimeUsecase) GetUserTime(cxt context.Context, query domain.TimeQuery) (domain.TimeSummary, *domain.TaskError) {
+	ret := _m.Called(cxt, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTime")
+	}
+
+	var r0 domain.TimeSummary
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) (domain.TimeSummary, *domain.TaskError)); ok {
+		return rf(cxt, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TimeQuery) domain.TimeSummary); ok {
+		r0 = rf(cxt, query)
+	} else {
+		r0 = ret.Get(0).(domain.TimeSummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TimeQuery) *domain.TaskError); ok {
+		r1 = rf(cxt, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// StartTimer provides a mock function with given fields: cxt, taskID, note, user
+func (_m *TimeUsecase) StartTimer(cxt context.Context, taskID string, note string, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, taskID, note, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartTimer")
+	}
+
+	var r0 domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) (domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, taskID, note, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.User) domain.TimeEntry); ok {
+		r0 = rf(cxt, taskID, note, user)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, taskID, note, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// StopTimer provides a mock function with given fields: cxt, user
+func (_m *TimeUsecase) StopTimer(cxt context.Context, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	ret := _m.Called(cxt, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopTimer")
+	}
+
+	var r0 domain.TimeEntry
+	var r1 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (domain.TimeEntry, *domain.TaskError)); ok {
+		return rf(cxt, user)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.TimeEntry); ok {
+		r0 = rf(cxt, user)
+	} else {
+		r0 = ret.Get(0).(domain.TimeEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) *domain.TaskError); ok {
+		r1 = rf(cxt, user)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.TaskError)
+		}
+	}
+
+	return r0, r1
+}
+
+// TaskDeleted provides a mock function with given fields: cxt, task
+func (_m *TimeUsecase) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	ret := _m.Called(cxt, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskDeleted")
+	}
+
+	var r0 *domain.TaskError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) *domain.TaskError); ok {
+		r0 = rf(cxt, task)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskError)
+		}
+	}
+
+	return r0
+}
+
+// NewTimeUsecase creates a new instance of TimeUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTimeUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TimeUsecase {
+	mock := &TimeUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TokenRevocationStore is an autogenerated mock type for the TokenRevocationStore type
+type TokenRevocationStore struct {
+	mock.Mock
+}
+
+// IsRevoked provides a mock function with given fields: cxt, tokenID, username, issuedAt
+func (_m *TokenRevocationStore) IsRevoked(cxt context.Context, tokenID string, username string, issuedAt time.Time) (bool, error) {
+	ret := _m.Called(cxt, tokenID, username, issuedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (bool, error)); ok {
+		return rf(cxt, tokenID, username, issuedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) bool); ok {
+		r0 = rf(cxt, tokenID, username, issuedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(cxt, tokenID, username, issuedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: cxt, tokenID, expiresAt
+func (_m *TokenRevocationStore) RevokeToken(cxt context.Context, tokenID string, expiresAt time.Time) error {
+	ret := _m.Called(cxt, tokenID, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(cxt, tokenID, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeUserTokens provides a mock function with given fields: cxt, username, revokedAt
+func (_m *TokenRevocationStore) RevokeUserTokens(cxt context.Context, username string, revokedAt time.Time) error {
+	ret := _m.Called(cxt, username, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(cxt, username, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTokenRevocationStore creates a new instance of TokenRevocationStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenRevocationStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenRevocationStore {
+	mock := &TokenRevocationStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
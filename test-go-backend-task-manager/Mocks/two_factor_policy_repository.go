@@ -0,0 +1,89 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactorPolicyRepository is an autogenerated mock type for the TwoFactorPolicyRepository type
+type TwoFactorPolicyRepository struct {
+	mock.Mock
+}
+
+// FetchTwoFactorPolicy provides a mock function with given fields: cxt
+func (_m *TwoFactorPolicyRepository) FetchTwoFactorPolicy(cxt context.Context) (domain.TwoFactorPolicy, *domain.UserError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTwoFactorPolicy")
+	}
+
+	var r0 domain.TwoFactorPolicy
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context) (domain.TwoFactorPolicy, *domain.UserError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) domain.TwoFactorPolicy); ok {
+		r0 = rf(cxt)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.UserError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateTwoFactorPolicy provides a mock function with given fields: cxt, policy
+func (_m *TwoFactorPolicyRepository) UpdateTwoFactorPolicy(cxt context.Context, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, *domain.UserError) {
+	ret := _m.Called(cxt, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTwoFactorPolicy")
+	}
+
+	var r0 domain.TwoFactorPolicy
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TwoFactorPolicy) (domain.TwoFactorPolicy, *domain.UserError)); ok {
+		return rf(cxt, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TwoFactorPolicy) domain.TwoFactorPolicy); ok {
+		r0 = rf(cxt, policy)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.TwoFactorPolicy) *domain.UserError); ok {
+		r1 = rf(cxt, policy)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactorPolicyRepository creates a new instance of TwoFactorPolicyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactorPolicyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactorPolicyRepository {
+	mock := &TwoFactorPolicyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -14,6 +16,46 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// AcceptTwoFactorStep provides a mock function with given fields: cxt, ID, step
+func (_m *UserRepository) AcceptTwoFactorStep(cxt context.Context, ID string, step int64) *domain.UserError {
+	ret := _m.Called(cxt, ID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptTwoFactorStep")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *domain.UserError); ok {
+		r0 = rf(cxt, ID, step)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// ConsumeRecoveryCode provides a mock function with given fields: cxt, ID, hash
+func (_m *UserRepository) ConsumeRecoveryCode(cxt context.Context, ID string, hash string) *domain.UserError {
+	ret := _m.Called(cxt, ID, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeRecoveryCode")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.UserError); ok {
+		r0 = rf(cxt, ID, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
 // CreateUser provides a mock function with given fields: cxt, newUser
 func (_m *UserRepository) CreateUser(cxt context.Context, newUser domain.User) (string, *domain.UserError) {
 	ret := _m.Called(cxt, newUser)
@@ -106,6 +148,68 @@ func (_m *UserRepository) FetchAllUsers(cxt context.Context) ([]domain.User, *do
 	return r0, r1
 }
 
+// FetchDeletedUsers provides a mock function with given fields: cxt, deletedBefore, limit
+func (_m *UserRepository) FetchDeletedUsers(cxt context.Context, deletedBefore time.Time, limit int) ([]domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, deletedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchDeletedUsers")
+	}
+
+	var r0 []domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]domain.User, *domain.UserError)); ok {
+		return rf(cxt, deletedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []domain.User); ok {
+		r0 = rf(cxt, deletedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) *domain.UserError); ok {
+		r1 = rf(cxt, deletedBefore, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// FetchUserByEmail provides a mock function with given fields: cxt, email
+func (_m *UserRepository) FetchUserByEmail(cxt context.Context, email string) (domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchUserByEmail")
+	}
+
+	var r0 domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, *domain.UserError)); ok {
+		return rf(cxt, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(cxt, email)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, email)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
 // FetchUserByID provides a mock function with given fields: cxt, ID
 func (_m *UserRepository) FetchUserByID(cxt context.Context, ID string) (domain.User, *domain.UserError) {
 	ret := _m.Called(cxt, ID)
@@ -136,6 +240,36 @@ func (_m *UserRepository) FetchUserByID(cxt context.Context, ID string) (domain.
 	return r0, r1
 }
 
+// FetchUserByIdentity provides a mock function with given fields: cxt, issuer, subject
+func (_m *UserRepository) FetchUserByIdentity(cxt context.Context, issuer string, subject string) (domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, issuer, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchUserByIdentity")
+	}
+
+	var r0 domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, *domain.UserError)); ok {
+		return rf(cxt, issuer, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(cxt, issuer, subject)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.UserError); ok {
+		r1 = rf(cxt, issuer, subject)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
 // FetchUserByUsername provides a mock function with given fields: cxt, username
 func (_m *UserRepository) FetchUserByUsername(cxt context.Context, username string) (domain.User, *domain.UserError) {
 	ret := _m.Called(cxt, username)
@@ -196,6 +330,126 @@ func (_m *UserRepository) FetchUserCount(cxt context.Context) (int, *domain.User
 	return r0, r1
 }
 
+// LinkIdentity provides a mock function with given fields: cxt, ID, issuer, subject
+func (_m *UserRepository) LinkIdentity(cxt context.Context, ID string, issuer string, subject string) (domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, ID, issuer, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkIdentity")
+	}
+
+	var r0 domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (domain.User, *domain.UserError)); ok {
+		return rf(cxt, ID, issuer, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) domain.User); ok {
+		r0 = rf(cxt, ID, issuer, subject)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) *domain.UserError); ok {
+		r1 = rf(cxt, ID, issuer, subject)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeUser provides a mock function with given fields: cxt, ID, deletedBefore
+func (_m *UserRepository) PurgeUser(cxt context.Context, ID string, deletedBefore time.Time) *domain.UserError {
+	ret := _m.Called(cxt, ID, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeUser")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.UserError); ok {
+		r0 = rf(cxt, ID, deletedBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// RecordTwoFactorFailure provides a mock function with given fields: cxt, ID, failedAt
+func (_m *UserRepository) RecordTwoFactorFailure(cxt context.Context, ID string, failedAt time.Time) *domain.UserError {
+	ret := _m.Called(cxt, ID, failedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordTwoFactorFailure")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.UserError); ok {
+		r0 = rf(cxt, ID, failedAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// UndeleteUser provides a mock function with given fields: cxt, ID
+func (_m *UserRepository) UndeleteUser(cxt context.Context, ID string) (domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UndeleteUser")
+	}
+
+	var r0 domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, *domain.UserError)); ok {
+		return rf(cxt, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(cxt, ID)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, ID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateTwoFactor provides a mock function with given fields: cxt, ID, twoFactor
+func (_m *UserRepository) UpdateTwoFactor(cxt context.Context, ID string, twoFactor *domain.TwoFactor) *domain.UserError {
+	ret := _m.Called(cxt, ID, twoFactor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTwoFactor")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TwoFactor) *domain.UserError); ok {
+		r0 = rf(cxt, ID, twoFactor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
 // UpdateUser provides a mock function with given fields: cxt, updateUser
 func (_m *UserRepository) UpdateUser(cxt context.Context, updateUser domain.User) (domain.User, *domain.UserError) {
 	ret := _m.Called(cxt, updateUser)
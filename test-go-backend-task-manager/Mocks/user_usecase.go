@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserUsecase is an autogenerated mock type for the UserUsecase type
@@ -14,6 +16,158 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// BeginOIDCLogin provides a mock function with given fields: cxt
+func (_m *UserUsecase) BeginOIDCLogin(cxt context.Context) (domain.OIDCLogin, *domain.UserError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginOIDCLogin")
+	}
+
+	var r0 domain.OIDCLogin
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context) (domain.OIDCLogin, *domain.UserError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) domain.OIDCLogin); ok {
+		r0 = rf(cxt)
+	} else {
+		r0 = ret.Get(0).(domain.OIDCLogin)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.UserError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CompleteOIDCLogin provides a mock function with given fields: cxt, code, state, login
+func (_m *UserUsecase) CompleteOIDCLogin(cxt context.Context, code string, state string, login domain.OIDCLogin) (domain.Session, *domain.UserError) {
+	ret := _m.Called(cxt, code, state, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteOIDCLogin")
+	}
+
+	var r0 domain.Session
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.OIDCLogin) (domain.Session, *domain.UserError)); ok {
+		return rf(cxt, code, state, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.OIDCLogin) domain.Session); ok {
+		r0 = rf(cxt, code, state, login)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.OIDCLogin) *domain.UserError); ok {
+		r1 = rf(cxt, code, state, login)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CompleteTwoFactorLogin provides a mock function with given fields: cxt, twoFactorToken, code
+func (_m *UserUsecase) CompleteTwoFactorLogin(cxt context.Context, twoFactorToken string, code string) (domain.Session, *domain.UserError) {
+	ret := _m.Called(cxt, twoFactorToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteTwoFactorLogin")
+	}
+
+	var r0 domain.Session
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Session, *domain.UserError)); ok {
+		return rf(cxt, twoFactorToken, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Session); ok {
+		r0 = rf(cxt, twoFactorToken, code)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.UserError); ok {
+		r1 = rf(cxt, twoFactorToken, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ConfirmTwoFactor provides a mock function with given fields: cxt, userID, code
+func (_m *UserUsecase) ConfirmTwoFactor(cxt context.Context, userID string, code string) ([]string, *domain.UserError) {
+	ret := _m.Called(cxt, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTwoFactor")
+	}
+
+	var r0 []string
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, *domain.UserError)); ok {
+		return rf(cxt, userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(cxt, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.UserError); ok {
+		r1 = rf(cxt, userID, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// CreateOrganization provides a mock function with given fields: cxt, newAdmin
+func (_m *UserUsecase) CreateOrganization(cxt context.Context, newAdmin domain.User) (domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, newAdmin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrganization")
+	}
+
+	var r0 domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (domain.User, *domain.UserError)); ok {
+		return rf(cxt, newAdmin)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.User); ok {
+		r0 = rf(cxt, newAdmin)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) *domain.UserError); ok {
+		r1 = rf(cxt, newAdmin)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: cxt, newUser
 func (_m *UserUsecase) CreateUser(cxt context.Context, newUser domain.User) (string, *domain.UserError) {
 	ret := _m.Called(cxt, newUser)
@@ -74,6 +228,86 @@ func (_m *UserUsecase) DeleteUser(cxt context.Context, authority domain.User, de
 	return r0, r1
 }
 
+// DisableTwoFactor provides a mock function with given fields: cxt, userID, code
+func (_m *UserUsecase) DisableTwoFactor(cxt context.Context, userID string, code string) *domain.UserError {
+	ret := _m.Called(cxt, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTwoFactor")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.UserError); ok {
+		r0 = rf(cxt, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// EnrollTwoFactor provides a mock function with given fields: cxt, userID
+func (_m *UserUsecase) EnrollTwoFactor(cxt context.Context, userID string) (domain.TwoFactorEnrollment, *domain.UserError) {
+	ret := _m.Called(cxt, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTwoFactor")
+	}
+
+	var r0 domain.TwoFactorEnrollment
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TwoFactorEnrollment, *domain.UserError)); ok {
+		return rf(cxt, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TwoFactorEnrollment); ok {
+		r0 = rf(cxt, userID)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorEnrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// EnrollTwoFactorAtLogin provides a mock function with given fields: cxt, twoFactorToken
+func (_m *UserUsecase) EnrollTwoFactorAtLogin(cxt context.Context, twoFactorToken string) (domain.TwoFactorEnrollment, *domain.UserError) {
+	ret := _m.Called(cxt, twoFactorToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTwoFactorAtLogin")
+	}
+
+	var r0 domain.TwoFactorEnrollment
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TwoFactorEnrollment, *domain.UserError)); ok {
+		return rf(cxt, twoFactorToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TwoFactorEnrollment); ok {
+		r0 = rf(cxt, twoFactorToken)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorEnrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, twoFactorToken)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetAllUser provides a mock function with given fields: cxt
 func (_m *UserUsecase) GetAllUser(cxt context.Context) ([]domain.User, *domain.UserError) {
 	ret := _m.Called(cxt)
@@ -106,6 +340,98 @@ func (_m *UserUsecase) GetAllUser(cxt context.Context) ([]domain.User, *domain.U
 	return r0, r1
 }
 
+// GetDeletedUsers provides a mock function with given fields: cxt
+func (_m *UserUsecase) GetDeletedUsers(cxt context.Context) ([]domain.User, *domain.UserError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeletedUsers")
+	}
+
+	var r0 []domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.User, *domain.UserError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.User); ok {
+		r0 = rf(cxt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.UserError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTwoFactorPolicy provides a mock function with given fields: cxt
+func (_m *UserUsecase) GetTwoFactorPolicy(cxt context.Context) (domain.TwoFactorPolicy, *domain.UserError) {
+	ret := _m.Called(cxt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactorPolicy")
+	}
+
+	var r0 domain.TwoFactorPolicy
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context) (domain.TwoFactorPolicy, *domain.UserError)); ok {
+		return rf(cxt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) domain.TwoFactorPolicy); ok {
+		r0 = rf(cxt)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *domain.UserError); ok {
+		r1 = rf(cxt)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTwoFactorStatus provides a mock function with given fields: cxt, userID
+func (_m *UserUsecase) GetTwoFactorStatus(cxt context.Context, userID string) (domain.TwoFactorStatus, *domain.UserError) {
+	ret := _m.Called(cxt, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactorStatus")
+	}
+
+	var r0 domain.TwoFactorStatus
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TwoFactorStatus, *domain.UserError)); ok {
+		return rf(cxt, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TwoFactorStatus); ok {
+		r0 = rf(cxt, userID)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetUserByID provides a mock function with given fields: cxt, userID
 func (_m *UserUsecase) GetUserByID(cxt context.Context, userID string) (domain.User, *domain.UserError) {
 	ret := _m.Called(cxt, userID)
@@ -167,22 +493,22 @@ func (_m *UserUsecase) GetUserByUsername(cxt context.Context, username string) (
 }
 
 // LoginUser provides a mock function with given fields: cxt, loggingUser
-func (_m *UserUsecase) LoginUser(cxt context.Context, loggingUser domain.User) (string, *domain.UserError) {
+func (_m *UserUsecase) LoginUser(cxt context.Context, loggingUser domain.User) (domain.Session, *domain.UserError) {
 	ret := _m.Called(cxt, loggingUser)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LoginUser")
 	}
 
-	var r0 string
+	var r0 domain.Session
 	var r1 *domain.UserError
-	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (string, *domain.UserError)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (domain.Session, *domain.UserError)); ok {
 		return rf(cxt, loggingUser)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.User) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.Session); ok {
 		r0 = rf(cxt, loggingUser)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(domain.Session)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, domain.User) *domain.UserError); ok {
@@ -196,6 +522,198 @@ func (_m *UserUsecase) LoginUser(cxt context.Context, loggingUser domain.User) (
 	return r0, r1
 }
 
+// Logout provides a mock function with given fields: cxt, refreshToken, accessToken
+func (_m *UserUsecase) Logout(cxt context.Context, refreshToken string, accessToken string) *domain.UserError {
+	ret := _m.Called(cxt, refreshToken, accessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.UserError); ok {
+		r0 = rf(cxt, refreshToken, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// PurgeDeletedUsers provides a mock function with given fields: cxt, deletedBefore
+func (_m *UserUsecase) PurgeDeletedUsers(cxt context.Context, deletedBefore time.Time) (int, *domain.UserError) {
+	ret := _m.Called(cxt, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedUsers")
+	}
+
+	var r0 int
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, *domain.UserError)); ok {
+		return rf(cxt, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(cxt, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) *domain.UserError); ok {
+		r1 = rf(cxt, deletedBefore)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RefreshSession provides a mock function with given fields: cxt, refreshToken
+func (_m *UserUsecase) RefreshSession(cxt context.Context, refreshToken string) (domain.Session, *domain.UserError) {
+	ret := _m.Called(cxt, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshSession")
+	}
+
+	var r0 domain.Session
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Session, *domain.UserError)); ok {
+		return rf(cxt, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Session); ok {
+		r0 = rf(cxt, refreshToken)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, refreshToken)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: cxt, userID, code
+func (_m *UserUsecase) RegenerateRecoveryCodes(cxt context.Context, userID string, code string) ([]string, *domain.UserError) {
+	ret := _m.Called(cxt, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 []string
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, *domain.UserError)); ok {
+		return rf(cxt, userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(cxt, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *domain.UserError); ok {
+		r1 = rf(cxt, userID, code)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ResetTwoFactor provides a mock function with given fields: cxt, authority, userID
+func (_m *UserUsecase) ResetTwoFactor(cxt context.Context, authority domain.User, userID string) *domain.UserError {
+	ret := _m.Called(cxt, authority, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetTwoFactor")
+	}
+
+	var r0 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, string) *domain.UserError); ok {
+		r0 = rf(cxt, authority, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserError)
+		}
+	}
+
+	return r0
+}
+
+// RestoreDeletedUser provides a mock function with given fields: cxt, userID
+func (_m *UserUsecase) RestoreDeletedUser(cxt context.Context, userID string) (domain.User, *domain.UserError) {
+	ret := _m.Called(cxt, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreDeletedUser")
+	}
+
+	var r0 domain.User
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, *domain.UserError)); ok {
+		return rf(cxt, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(cxt, userID)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *domain.UserError); ok {
+		r1 = rf(cxt, userID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateTwoFactorPolicy provides a mock function with given fields: cxt, authority, policy
+func (_m *UserUsecase) UpdateTwoFactorPolicy(cxt context.Context, authority domain.User, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, *domain.UserError) {
+	ret := _m.Called(cxt, authority, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTwoFactorPolicy")
+	}
+
+	var r0 domain.TwoFactorPolicy
+	var r1 *domain.UserError
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, domain.TwoFactorPolicy) (domain.TwoFactorPolicy, *domain.UserError)); ok {
+		return rf(cxt, authority, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, domain.TwoFactorPolicy) domain.TwoFactorPolicy); ok {
+		r0 = rf(cxt, authority, policy)
+	} else {
+		r0 = ret.Get(0).(domain.TwoFactorPolicy)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User, domain.TwoFactorPolicy) *domain.UserError); ok {
+		r1 = rf(cxt, authority, policy)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.UserError)
+		}
+	}
+
+	return r0, r1
+}
+
 // UpdateUser provides a mock function with given fields: cxt, userUpdate
 func (_m *UserUsecase) UpdateUser(cxt context.Context, userUpdate domain.User) (domain.User, *domain.UserError) {
 	ret := _m.Called(cxt, userUpdate)
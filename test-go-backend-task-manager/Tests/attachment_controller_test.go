@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentControllerSuite struct {
+	suite.Suite
+	attachmentUsecase *mocks.AttachmentUsecase
+	userUsecase       *mocks.UserUsecase
+	controller        controllers.AttachmentController
+	router            *gin.Engine
+	user              domain.User
+}
+
+func (suite *attachmentControllerSuite) SetupTest() {
+	suite.attachmentUsecase = new(mocks.AttachmentUsecase)
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewAttachmentController(suite.attachmentUsecase, suite.userUsecase)
+	suite.user = domain.User{ID: "user_1", Username: "abebe", Role: "user"}
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+
+	// stands in for AuthMiddleWare
+	suite.router.Use(func(cxt *gin.Context) {
+		cxt.Set(infrastructure.CONTEXT_USERNAME, "abebe")
+	})
+	suite.router.GET("/task/:id/attachments", suite.controller.GetAttachments)
+	suite.router.POST("/task/:id/attachments", suite.controller.PostAttachment)
+	suite.router.GET("/task/:id/attachments/:attachmentid", suite.controller.GetAttachment)
+	suite.router.DELETE("/task/:id/attachments/:attachmentid", suite.controller.DeleteAttachment)
+	suite.router.DELETE("/task/:id/:userid", func(cxt *gin.Context) {})
+	suite.userUsecase.On("GetUserByUsername", mock.Anything, "abebe").Return(suite.user, nil)
+}
+
+func (suite *attachmentControllerSuite) multipartRequest(field string, filename string, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("note", "ignored")
+	part, _ := writer.CreateFormFile(field, filename)
+	part.Write([]byte(content))
+	writer.Close()
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/attachments", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func (suite *attachmentControllerSuite) TestPostAttachment() {
+	var uploaded string
+	suite.attachmentUsecase.On("UploadAttachment", mock.Anything, "1", mock.MatchedBy(func(upload domain.AttachmentUpload) bool {
+		return upload.Filename == "notes.txt" && upload.UploadedBy == "user_1"
+	})).Run(func(args mock.Arguments) {
+		content, _ := io.ReadAll(args.Get(2).(domain.AttachmentUpload).Content)
+		uploaded = string(content)
+	}).Return(domain.Attachment{ID: "a1", Filename: "notes.txt"}, nil)
+
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, suite.multipartRequest("file", "notes.txt", "remember the milk"))
+
+	var attachment domain.Attachment
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &attachment))
+	suite.Equal(http.StatusCreated, resp.Code)
+	suite.Equal("a1", attachment.ID)
+	suite.Equal("remember the milk", uploaded, "the file part is streamed to the use case")
+}
+
+func (suite *attachmentControllerSuite) TestPostAttachment_MissingFile() {
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, suite.multipartRequest("document", "notes.txt", "remember the milk"))
+	suite.Equal(http.StatusBadRequest, resp.Code)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/attachments", bytes.NewBufferString(`{"file": "notes.txt"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.attachmentUsecase.AssertNotCalled(suite.T(), "UploadAttachment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *attachmentControllerSuite) TestGetAttachment() {
+	attachment := domain.Attachment{ID: "a1", Filename: "report é.pdf", ContentType: "application/pdf", Size: 8, SHA256: "abc123"}
+	suite.attachmentUsecase.On("OpenAttachment", mock.Anything, "1", "a1").Return(attachment, io.NopCloser(bytes.NewBufferString("%PDF-1.7")), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1/attachments/a1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal("%PDF-1.7", resp.Body.String())
+	suite.Equal("application/pdf", resp.Header().Get("Content-Type"))
+	suite.Equal("attachment; filename*=utf-8''report%20%C3%A9.pdf", resp.Header().Get("Content-Disposition"))
+	suite.Equal("nosniff", resp.Header().Get("X-Content-Type-Options"))
+	suite.Equal("abc123", resp.Header().Get("X-Checksum-Sha256"))
+}
+
+func (suite *attachmentControllerSuite) TestDeleteAttachment_Forbidden() {
+	suite.attachmentUsecase.On("DeleteAttachment", mock.Anything, "1", "a1", suite.user).Return(&domain.TaskError{Message: "Only the uploader or an admin can delete this attachment", Code: http.StatusForbidden})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/task/1/attachments/a1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusForbidden, resp.Code)
+	suite.attachmentUsecase.AssertCalled(suite.T(), "DeleteAttachment", mock.Anything, "1", "a1", suite.user)
+}
+
+func TestAttachmentControllerSuite(t *testing.T) {
+	suite.Run(t, new(attachmentControllerSuite))
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// start of a PNG file, enough for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type attachmentUsecaseSuite struct {
+	suite.Suite
+	taskRepository *mocks.TaskRepository
+	blobStore      infrastructure.LocalBlobStore
+	usecase        domain.AttachmentUsecase
+}
+
+func (suite *attachmentUsecaseSuite) SetupTest() {
+	suite.taskRepository = new(mocks.TaskRepository)
+	blobStore, err := infrastructure.NewLocalBlobStore(suite.T().TempDir())
+	suite.Require().Nil(err)
+	suite.blobStore = blobStore
+	attachmentUC := usecases.NewAttachmentUsecase(suite.taskRepository, suite.blobStore, time.Second*2)
+	suite.usecase = &attachmentUC
+}
+
+func (suite *attachmentUsecaseSuite) storedBlobs() []os.DirEntry {
+	entries, _ := os.ReadDir(suite.blobStore.Root)
+	return entries
+}
+
+func (suite *attachmentUsecaseSuite) TestUploadAttachment() {
+	content := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 1000)...)
+	checksum := sha256.Sum256(content)
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(domain.Task{ID: "task_1"}, nil)
+	suite.taskRepository.On("AddAttachment", mock.Anything, "task_1", mock.Anything).Return(domain.Task{}, nil)
+
+	attachment, err := suite.usecase.UploadAttachment(context.TODO(), "task_1", domain.AttachmentUpload{
+		Filename:   `C:\Users\abebe\screen shot.txt`,
+		UploadedBy: "user_1",
+		Content:    bytes.NewReader(content),
+	})
+	suite.Nil(err, "error should be nil")
+	suite.Equal("screen shot.txt", attachment.Filename, "only the base name is kept")
+	suite.Equal("image/png", attachment.ContentType, "the type comes from the content, not the name")
+	suite.Equal(int64(len(content)), attachment.Size)
+	suite.Equal(hex.EncodeToString(checksum[:]), attachment.SHA256)
+	suite.taskRepository.AssertCalled(suite.T(), "AddAttachment", mock.Anything, "task_1", attachment)
+
+	stored, errOpen := suite.blobStore.Open(context.TODO(), "task_1/"+attachment.ID)
+	suite.Require().Nil(errOpen)
+	defer stored.Close()
+	body, _ := io.ReadAll(stored)
+	suite.Equal(content, body)
+}
+
+func (suite *attachmentUsecaseSuite) TestUploadAttachment_TooLarge() {
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(domain.Task{ID: "task_1"}, nil)
+
+	_, err := suite.usecase.UploadAttachment(context.TODO(), "task_1", domain.AttachmentUpload{
+		Filename:   "huge.bin",
+		UploadedBy: "user_1",
+		Content:    io.LimitReader(zeroReader{}, usecases.MAX_ATTACHMENT_SIZE+1),
+	})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusRequestEntityTooLarge, err.Code)
+	suite.Empty(suite.storedBlobs(), "the partial blob is removed")
+	suite.taskRepository.AssertNotCalled(suite.T(), "AddAttachment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *attachmentUsecaseSuite) TestUploadAttachment_Invalid() {
+	full := make([]domain.Attachment, usecases.MAX_TASK_ATTACHMENTS)
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(domain.Task{ID: "task_1"}, nil)
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_full").Return(domain.Task{ID: "task_full", Attachments: full}, nil)
+
+	for name, upload := range map[string]struct {
+		taskID string
+		upload domain.AttachmentUpload
+	}{
+		"missing filename": {"task_1", domain.AttachmentUpload{UploadedBy: "user_1", Content: bytes.NewReader(pngHeader)}},
+		"empty file":       {"task_1", domain.AttachmentUpload{Filename: "a.png", UploadedBy: "user_1", Content: bytes.NewReader(nil)}},
+		"too many files":   {"task_full", domain.AttachmentUpload{Filename: "a.png", UploadedBy: "user_1", Content: bytes.NewReader(pngHeader)}},
+	} {
+		_, err := suite.usecase.UploadAttachment(context.TODO(), upload.taskID, upload.upload)
+		suite.NotNil(err, name)
+		suite.Equal(http.StatusBadRequest, err.Code, name)
+	}
+	suite.Empty(suite.storedBlobs())
+}
+
+func (suite *attachmentUsecaseSuite) TestOpenAttachment() {
+	attachment := domain.Attachment{ID: "a1", Filename: "notes.txt", Size: 5}
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(domain.Task{ID: "task_1", Attachments: []domain.Attachment{attachment}}, nil)
+	suite.Nil(suite.blobStore.Put(context.TODO(), "task_1/a1", bytes.NewBufferString("notes")))
+
+	opened, content, err := suite.usecase.OpenAttachment(context.TODO(), "task_1", "a1")
+	suite.Require().Nil(err)
+	defer content.Close()
+	body, _ := io.ReadAll(content)
+	suite.Equal(attachment, opened)
+	suite.Equal("notes", string(body))
+
+	_, _, err = suite.usecase.OpenAttachment(context.TODO(), "task_1", "a2")
+	suite.NotNil(err)
+	suite.Equal(http.StatusNotFound, err.Code)
+}
+
+func (suite *attachmentUsecaseSuite) TestDeleteAttachment() {
+	task := domain.Task{ID: "task_1", Attachments: []domain.Attachment{{ID: "a1", UploadedBy: "user_1"}}}
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(task, nil)
+	suite.taskRepository.On("RemoveAttachment", mock.Anything, "task_1", "a1").Return(domain.Task{ID: "task_1"}, nil)
+	suite.Nil(suite.blobStore.Put(context.TODO(), "task_1/a1", bytes.NewBufferString("notes")))
+
+	err := suite.usecase.DeleteAttachment(context.TODO(), "task_1", "a1", domain.User{ID: "user_2", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusForbidden, err.Code)
+	suite.taskRepository.AssertNotCalled(suite.T(), "RemoveAttachment", mock.Anything, mock.Anything, mock.Anything)
+
+	err = suite.usecase.DeleteAttachment(context.TODO(), "task_1", "a1", domain.User{ID: "user_2", Role: "admin"})
+	suite.Nil(err, "admins can delete any attachment")
+	suite.Empty(suite.storedBlobs())
+}
+
+func (suite *attachmentUsecaseSuite) TestTaskDeleted() {
+	for _, key := range []string{"task_1/a1", "task_1/a2", "task_2/a3"} {
+		suite.Nil(suite.blobStore.Put(context.TODO(), key, bytes.NewBufferString("x")))
+	}
+
+	err := suite.usecase.TaskDeleted(context.TODO(), domain.Task{ID: "task_1", Attachments: []domain.Attachment{{ID: "a1"}, {ID: "a2"}}})
+	suite.Nil(err, "error should be nil")
+	blobs := suite.storedBlobs()
+	suite.Equal(1, len(blobs), "only the files of the deleted task are removed")
+	suite.Equal("task_2", blobs[0].Name())
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestAttachmentUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(attachmentUsecaseSuite))
+}
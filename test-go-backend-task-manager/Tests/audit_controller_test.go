@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditControllerSuite struct {
+	suite.Suite
+	auditUsecase *mocks.AuditUsecase
+	controller   controllers.AuditController
+	router       *gin.Engine
+}
+
+func (suite *auditControllerSuite) SetupTest() {
+	suite.auditUsecase = new(mocks.AuditUsecase)
+	suite.controller = controllers.NewAuditController(suite.auditUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+	suite.router.GET("/audit", suite.controller.GetAuditEntries)
+}
+
+func (suite *auditControllerSuite) TestGetAuditEntries() {
+	since := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	query := domain.AuditQuery{Actor: "abebe", TargetType: "task", TargetID: "task_1", Since: since, Until: until, Limit: 10}
+	entries := []domain.AuditEntry{{ID: "e1", Actor: "abebe", Action: domain.AUDIT_TASK_UPDATE, TargetType: "task", TargetID: "task_1", Timestamp: since}}
+	suite.auditUsecase.On("GetEntries", mock.Anything, query).Return(entries, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/audit?actor=abebe&target_type=task&target=task_1&since=2024-01-01T00:00:00Z&until=2024-02-01T00:00:00Z&limit=10", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var auditResponse struct {
+		Entries []domain.AuditEntry `json:"entries"`
+	}
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &auditResponse))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(entries, auditResponse.Entries)
+}
+
+func (suite *auditControllerSuite) TestGetAuditEntries_InvalidQuery() {
+	for _, rawQuery := range []string{"since=yesterday", "until=2024-02-01", "limit=0"} {
+		req, _ := http.NewRequest(http.MethodGet, "/audit?"+rawQuery, nil)
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+		suite.Equal(http.StatusBadRequest, resp.Code, rawQuery)
+	}
+	suite.auditUsecase.AssertNotCalled(suite.T(), "GetEntries", mock.Anything, mock.Anything)
+}
+
+func TestAuditControllerSuite(t *testing.T) {
+	suite.Run(t, new(auditControllerSuite))
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditUsecaseSuite struct {
+	suite.Suite
+	auditRepository *mocks.AuditRepository
+	auditLog        *mocks.AuditRecorder
+	usecase         domain.AuditUsecase
+	// context of a request made by the user abebe
+	actorContext context.Context
+}
+
+func (suite *auditUsecaseSuite) SetupTest() {
+	suite.auditRepository = new(mocks.AuditRepository)
+	suite.auditLog = new(mocks.AuditRecorder)
+	auditUC := usecases.NewAuditUsecase(suite.auditRepository, time.Second*2)
+	suite.usecase = &auditUC
+	suite.actorContext = context.WithValue(context.TODO(), infrastructure.CONTEXT_USERNAME, "abebe")
+}
+
+// the entries handed to the audit log, in order
+func (suite *auditUsecaseSuite) recorded() []domain.AuditEntry {
+	entries := []domain.AuditEntry{}
+	for _, call := range suite.auditLog.Calls {
+		entries = append(entries, call.Arguments.Get(1).(domain.AuditEntry))
+	}
+	return entries
+}
+
+func (suite *auditUsecaseSuite) TestRecord_OutlivesRequest() {
+	suite.auditRepository.On("InsertEntry", mock.MatchedBy(func(cxt context.Context) bool {
+		return cxt.Err() == nil
+	}), mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AUDIT_TASK_DELETE && !entry.Timestamp.IsZero()
+	})).Return(nil)
+	cxt, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	suite.usecase.Record(cxt, domain.AuditEntry{Actor: "abebe", Action: domain.AUDIT_TASK_DELETE, TargetType: domain.AUDIT_TARGET_TASK, TargetID: "task_1"})
+	suite.auditRepository.AssertNumberOfCalls(suite.T(), "InsertEntry", 1)
+}
+
+func (suite *auditUsecaseSuite) TestGetEntries_Limits() {
+	suite.auditRepository.On("FetchEntries", mock.Anything, domain.AuditQuery{Actor: "abebe", Limit: usecases.DEFAULT_AUDIT_PAGE_LIMIT}).Return([]domain.AuditEntry{}, nil)
+	suite.auditRepository.On("FetchEntries", mock.Anything, domain.AuditQuery{Limit: usecases.MAX_AUDIT_PAGE_LIMIT}).Return([]domain.AuditEntry{}, nil)
+
+	_, err := suite.usecase.GetEntries(context.TODO(), domain.AuditQuery{Actor: "abebe"})
+	suite.Nil(err, "error should be nil")
+	_, err = suite.usecase.GetEntries(context.TODO(), domain.AuditQuery{Limit: 100000})
+	suite.Nil(err, "error should be nil")
+	suite.auditRepository.AssertNumberOfCalls(suite.T(), "FetchEntries", 2)
+}
+
+func (suite *auditUsecaseSuite) TestGetEntries_InvalidWindow() {
+	now := time.Now()
+	_, err := suite.usecase.GetEntries(context.TODO(), domain.AuditQuery{Since: now, Until: now.Add(-time.Hour)})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusBadRequest, err.Code)
+	suite.auditRepository.AssertNotCalled(suite.T(), "FetchEntries", mock.Anything, mock.Anything)
+}
+
+func (suite *auditUsecaseSuite) TestTaskMutations_Recorded() {
+	taskRepository := new(mocks.TaskRepository)
+	taskUC := usecases.NewTaskUsecase(taskRepository, time.Second*2)
+	taskUC.SetAuditLog(suite.auditLog)
+	suite.auditLog.On("Record", mock.Anything, mock.Anything).Return()
+	stored := domain.Task{ID: "task_1", UserID: "user_1", Title: "Write report", Priority: "low"}
+	updated := stored
+	updated.Priority = "high"
+	taskRepository.On("CreateTask", mock.Anything, mock.Anything).Return("task_1", nil)
+	taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(stored, nil)
+	taskRepository.On("UpdateTask", mock.Anything, mock.Anything).Return(updated, nil)
+	taskRepository.On("DeleteTask", mock.Anything, "task_1", 0).Return(updated, nil)
+
+	_, err := taskUC.CreateTask(suite.actorContext, domain.Task{UserID: "user_1", Title: "Write report", Priority: "low"})
+	suite.Nil(err, "error should be nil")
+	_, err = taskUC.UpdateTask(suite.actorContext, domain.Task{ID: "task_1", Priority: "high"}, domain.User{ID: "user_1"})
+	suite.Nil(err, "error should be nil")
+	_, err = taskUC.DeleteTask(suite.actorContext, "task_1", domain.User{ID: "user_1"}, 0)
+	suite.Nil(err, "error should be nil")
+
+	entries := suite.recorded()
+	suite.Require().Equal(3, len(entries))
+	for i, action := range []string{domain.AUDIT_TASK_CREATE, domain.AUDIT_TASK_UPDATE, domain.AUDIT_TASK_DELETE} {
+		suite.Equal(action, entries[i].Action)
+		suite.Equal("abebe", entries[i].Actor, "the actor comes from the request context")
+		suite.Equal("task_1", entries[i].TargetID)
+		suite.Equal(domain.AUDIT_TARGET_TASK, entries[i].TargetType)
+	}
+	suite.Contains(entries[0].Changes, domain.FieldChange{Field: "title", After: "Write report"})
+	suite.Equal([]domain.FieldChange{{Field: "priority", Before: "low", After: "high"}}, entries[1].Changes, "only changed fields are recorded")
+	suite.Contains(entries[2].Changes, domain.FieldChange{Field: "title", Before: "Write report"})
+}
+
+func (suite *auditUsecaseSuite) TestFailedMutation_NotRecorded() {
+	taskRepository := new(mocks.TaskRepository)
+	taskUC := usecases.NewTaskUsecase(taskRepository, time.Second*2)
+	taskUC.SetAuditLog(suite.auditLog)
+	taskRepository.On("CreateTask", mock.Anything, mock.Anything).Return("", &domain.TaskError{Message: "write failed", Code: http.StatusInternalServerError})
+
+	_, err := taskUC.CreateTask(suite.actorContext, domain.Task{UserID: "user_1", Title: "Write report"})
+	suite.NotNil(err, "error should not be nil")
+	suite.auditLog.AssertNotCalled(suite.T(), "Record", mock.Anything, mock.Anything)
+}
+
+func (suite *auditUsecaseSuite) TestUserMutations_Recorded() {
+	userRepository := new(mocks.UserRepository)
+	userUC := usecases.NewUserUsecase(userRepository, time.Second*2)
+	userUC.SetAuditLog(suite.auditLog)
+	suite.auditLog.On("Record", mock.Anything, mock.Anything).Return()
+	hashed, _ := infrastructure.HashPassword("secret")
+	stored := domain.User{ID: "user_2", Username: "kebede", Password: hashed, Role: "user"}
+	userRepository.On("FetchUserByID", mock.Anything, "user_2").Return(stored, nil)
+	userRepository.On("UpdateUser", mock.Anything, mock.Anything).Return(domain.User{ID: "user_2", Username: "kebede", Password: "changed", Role: "admin"}, nil)
+	userRepository.On("FetchUserByUsername", mock.Anything, "kebede").Return(stored, nil)
+
+	_, err := userUC.UpdateUser(suite.actorContext, domain.User{ID: "user_2", Password: "changed", Role: "admin"})
+	suite.Nil(err, "error should be nil")
+	_, err = userUC.LoginUser(context.TODO(), domain.User{Username: "kebede", Password: "wrong", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+
+	entries := suite.recorded()
+	suite.Require().Equal(2, len(entries))
+	suite.Equal(domain.AUDIT_USER_UPDATE, entries[0].Action)
+	suite.Equal([]domain.FieldChange{
+		{Field: "password", Before: usecases.REDACTED_VALUE, After: usecases.REDACTED_VALUE},
+		{Field: "role", Before: "user", After: "admin"},
+	}, entries[0].Changes, "passwords are never written to the audit log")
+	suite.Equal(domain.AUDIT_USER_LOGIN_FAILED, entries[1].Action)
+	suite.Equal("kebede", entries[1].Actor, "logins are attributed to the user logging in")
+	suite.Equal("user_2", entries[1].TargetID)
+}
+
+func (suite *auditUsecaseSuite) TestEntries_BelongToOrg() {
+	userRepository := new(mocks.UserRepository)
+	userUC := usecases.NewUserUsecase(userRepository, time.Second*2)
+	userUC.SetAuditLog(suite.auditLog)
+	taskRepository := new(mocks.TaskRepository)
+	taskUC := usecases.NewTaskUsecase(taskRepository, time.Second*2)
+	taskUC.SetAuditLog(suite.auditLog)
+	suite.auditLog.On("Record", mock.Anything, mock.Anything).Return()
+	hashed, _ := infrastructure.HashPassword("secret")
+	userRepository.On("FetchUserByUsername", mock.Anything, "kebede").Return(domain.User{ID: "user_2", Username: "kebede", Password: hashed, Role: "user", OrgID: "globex"}, nil)
+	taskRepository.On("CreateTask", mock.Anything, mock.Anything).Return("task_1", nil)
+
+	_, err := taskUC.CreateTask(context.WithValue(suite.actorContext, infrastructure.CONTEXT_ORG, "acme"), domain.Task{UserID: "user_1", Title: "Write report"})
+	suite.Nil(err, "error should be nil")
+	// logins are made without a token
+	_, errLogin := userUC.LoginUser(context.TODO(), domain.User{Username: "kebede", Password: "wrong", Role: "user"})
+	suite.NotNil(errLogin, "error should not be nil")
+
+	entries := suite.recorded()
+	suite.Require().Equal(2, len(entries))
+	suite.Equal("acme", entries[0].OrgID, "entries belong to the organization of the request")
+	suite.Equal("globex", entries[1].OrgID, "entries made without a token belong to the organization of their target")
+}
+
+func TestAuditUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(auditUsecaseSuite))
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type BlobStoreTestSuite struct {
+	suite.Suite
+	store infrastructure.LocalBlobStore
+}
+
+func (suite *BlobStoreTestSuite) SetupTest() {
+	store, err := infrastructure.NewLocalBlobStore(suite.T().TempDir())
+	suite.Require().Nil(err)
+	suite.store = store
+}
+
+func (suite *BlobStoreTestSuite) TestPutOpenDelete() {
+	suite.Nil(suite.store.Put(context.TODO(), "task_1/a1", strings.NewReader("hello")))
+
+	content, err := suite.store.Open(context.TODO(), "task_1/a1")
+	suite.Require().Nil(err)
+	body, _ := io.ReadAll(content)
+	content.Close()
+	suite.Equal("hello", string(body))
+
+	suite.Nil(suite.store.Delete(context.TODO(), "task_1/a1"))
+	_, err = suite.store.Open(context.TODO(), "task_1/a1")
+	suite.ErrorIs(err, infrastructure.ErrBlobNotFound)
+	entries, _ := os.ReadDir(suite.store.Root)
+	suite.Empty(entries, "empty task directories are removed")
+	suite.Nil(suite.store.Delete(context.TODO(), "task_1/a1"), "deleting a missing blob is not an error")
+}
+
+func (suite *BlobStoreTestSuite) TestPut_Overwrites() {
+	suite.Nil(suite.store.Put(context.TODO(), "task_1/a1", strings.NewReader("first")))
+	suite.Nil(suite.store.Put(context.TODO(), "task_1/a1", strings.NewReader("second")))
+
+	content, err := suite.store.Open(context.TODO(), "task_1/a1")
+	suite.Require().Nil(err)
+	defer content.Close()
+	body, _ := io.ReadAll(content)
+	suite.Equal("second", string(body))
+}
+
+func (suite *BlobStoreTestSuite) TestInvalidKeys() {
+	for _, key := range []string{"", "../escape", "task_1/../../escape", "/absolute", `task_1\a1`} {
+		suite.NotNil(suite.store.Put(context.TODO(), key, strings.NewReader("x")), "key %q should be rejected", key)
+		_, err := suite.store.Open(context.TODO(), key)
+		suite.NotNil(err, "key %q should be rejected", key)
+	}
+}
+
+func (suite *BlobStoreTestSuite) TestPut_CancelledContext() {
+	cxt, cancel := context.WithCancel(context.TODO())
+	cancel()
+	suite.NotNil(suite.store.Put(cxt, "task_1/a1", strings.NewReader("hello")))
+	_, err := suite.store.Open(context.TODO(), "task_1/a1")
+	suite.ErrorIs(err, infrastructure.ErrBlobNotFound, "a failed write leaves nothing behind")
+}
+
+func TestBlobStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BlobStoreTestSuite))
+}
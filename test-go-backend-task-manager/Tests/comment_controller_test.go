@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type commentControllerSuite struct {
+	suite.Suite
+	commentUsecase *mocks.CommentUsecase
+	userUsecase    *mocks.UserUsecase
+	controller     controllers.CommentController
+	router         *gin.Engine
+}
+
+func (suite *commentControllerSuite) SetupTest() {
+	suite.commentUsecase = new(mocks.CommentUsecase)
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewCommentController(suite.commentUsecase, suite.userUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+
+	// stands in for AuthMiddleWare
+	suite.router.Use(func(cxt *gin.Context) {
+		cxt.Set(infrastructure.CONTEXT_USERNAME, "abebe")
+	})
+	suite.router.GET("/task/:id/comments", suite.controller.GetComments)
+	suite.router.POST("/task/:id/comments", suite.controller.PostComment)
+	suite.router.PUT("/task/:id/comments/:commentid", suite.controller.PutComment)
+	suite.router.DELETE("/task/:id/comments/:commentid", suite.controller.DeleteComment)
+	suite.userUsecase.On("GetUserByUsername", mock.Anything, "abebe").Return(domain.User{ID: "user_1", Username: "abebe"}, nil)
+}
+
+func (suite *commentControllerSuite) TestGetComments() {
+	threads := []domain.Comment{{ID: "c1", TaskID: "1", Body: "Hi", Replies: []domain.Comment{{ID: "c2", TaskID: "1", ParentID: "c1", Body: "Hello"}}}}
+	suite.commentUsecase.On("GetComments", mock.Anything, "1").Return(threads, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1/comments", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var commentsResponse struct {
+		Comments []domain.Comment `json:"comments"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &commentsResponse)
+	suite.Nil(err)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(threads, commentsResponse.Comments)
+}
+
+func (suite *commentControllerSuite) TestPostComment() {
+	expected := domain.Comment{TaskID: "1", ParentID: "c1", AuthorID: "user_1", Body: "Agreed"}
+	suite.commentUsecase.On("AddComment", mock.Anything, expected).Return(domain.Comment{ID: "c2", TaskID: "1", ParentID: "c1", AuthorID: "user_1", Body: "Agreed"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/comments", bytes.NewBufferString(`{"body": "Agreed", "parentID": "c1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusCreated, resp.Code)
+	suite.commentUsecase.AssertCalled(suite.T(), "AddComment", mock.Anything, expected)
+}
+
+func (suite *commentControllerSuite) TestPutComment_NotAuthor() {
+	suite.commentUsecase.On("EditComment", mock.Anything, "1", "c1", "user_1", "Edited").Return(domain.Comment{}, &domain.TaskError{Message: "Only the author can change this comment", Code: http.StatusForbidden})
+
+	req, _ := http.NewRequest(http.MethodPut, "/task/1/comments/c1", bytes.NewBufferString(`{"body": "Edited"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusForbidden, resp.Code)
+}
+
+func (suite *commentControllerSuite) TestDeleteComment() {
+	suite.commentUsecase.On("DeleteComment", mock.Anything, "1", "c1", "user_1").Return(nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/task/1/comments/c1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.commentUsecase.AssertCalled(suite.T(), "DeleteComment", mock.Anything, "1", "c1", "user_1")
+}
+
+func TestCommentControllerSuite(t *testing.T) {
+	suite.Run(t, new(commentControllerSuite))
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type commentUsecaseSuite struct {
+	suite.Suite
+	commentRepository *mocks.CommentRepository
+	taskRepository    *mocks.TaskRepository
+	usecase           domain.CommentUsecase
+}
+
+func (suite *commentUsecaseSuite) SetupTest() {
+	suite.commentRepository = new(mocks.CommentRepository)
+	suite.taskRepository = new(mocks.TaskRepository)
+	commentUC := usecases.NewCommentUsecase(suite.commentRepository, suite.taskRepository, time.Second*2)
+	suite.usecase = &commentUC
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(domain.Task{ID: "task_1"}, nil)
+}
+
+func (suite *commentUsecaseSuite) TestGetComments_Threads() {
+	suite.commentRepository.On("FetchComments", mock.Anything, "task_1").Return([]domain.Comment{
+		{ID: "c1", TaskID: "task_1", Body: "Should we ship friday?"},
+		{ID: "c2", TaskID: "task_1", ParentID: "c1", Body: "Only if QA signs off"},
+		{ID: "c3", TaskID: "task_1", Body: "Blocked on the API"},
+		{ID: "c4", TaskID: "task_1", ParentID: "c2", Body: "They did"},
+	}, nil)
+
+	threads, err := suite.usecase.GetComments(context.TODO(), "task_1")
+	suite.Nil(err, "error should be nil")
+	suite.Equal(2, len(threads), "replies are nested under their parent")
+	suite.Equal("c1", threads[0].ID)
+	suite.Equal("c2", threads[0].Replies[0].ID)
+	suite.Equal("c4", threads[0].Replies[0].Replies[0].ID)
+	suite.Equal("c3", threads[1].ID)
+}
+
+func (suite *commentUsecaseSuite) TestAddComment() {
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c1").Return(domain.Comment{ID: "c1", TaskID: "task_1"}, nil)
+	suite.commentRepository.On("CreateComment", mock.Anything, mock.MatchedBy(func(comment domain.Comment) bool {
+		return comment.Body == "Agreed" && comment.ParentID == "c1" && comment.AuthorID == "user_1"
+	})).Return("c2", nil)
+
+	comment, err := suite.usecase.AddComment(context.TODO(), domain.Comment{TaskID: "task_1", ParentID: "c1", AuthorID: "user_1", Body: "  Agreed "})
+	suite.Nil(err, "error should be nil")
+	suite.Equal("c2", comment.ID)
+
+	_, err = suite.usecase.AddComment(context.TODO(), domain.Comment{TaskID: "task_1", AuthorID: "user_1", Body: "   "})
+	suite.NotNil(err, "empty comments are rejected")
+}
+
+func (suite *commentUsecaseSuite) TestAddComment_ParentOnOtherTask() {
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c9").Return(domain.Comment{ID: "c9", TaskID: "task_2"}, nil)
+
+	_, err := suite.usecase.AddComment(context.TODO(), domain.Comment{TaskID: "task_1", ParentID: "c9", AuthorID: "user_1", Body: "Hi"})
+	suite.NotNil(err, "a reply must be on the same task as its parent")
+	suite.Equal(http.StatusBadRequest, err.Code)
+	suite.commentRepository.AssertNotCalled(suite.T(), "CreateComment", mock.Anything, mock.Anything)
+}
+
+func (suite *commentUsecaseSuite) TestEditComment() {
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c1").Return(domain.Comment{ID: "c1", TaskID: "task_1", AuthorID: "user_1", Body: "Frist"}, nil)
+	suite.commentRepository.On("UpdateCommentBody", mock.Anything, "c1", "First", mock.MatchedBy(func(previous domain.CommentRevision) bool {
+		return previous.Body == "Frist"
+	})).Return(domain.Comment{ID: "c1", Body: "First", History: []domain.CommentRevision{{Body: "Frist"}}}, nil)
+
+	edited, err := suite.usecase.EditComment(context.TODO(), "task_1", "c1", "user_1", "First")
+	suite.Nil(err, "error should be nil")
+	suite.Equal("Frist", edited.History[0].Body, "the previous body is kept")
+
+	_, err = suite.usecase.EditComment(context.TODO(), "task_1", "c1", "user_2", "Hijacked")
+	suite.NotNil(err, "only the author can edit")
+	suite.Equal(http.StatusForbidden, err.Code)
+}
+
+func (suite *commentUsecaseSuite) TestDeleteComment_WithReplies() {
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c1").Return(domain.Comment{ID: "c1", TaskID: "task_1", AuthorID: "user_1"}, nil)
+	suite.commentRepository.On("CountReplies", mock.Anything, "c1").Return(int64(2), nil)
+	suite.commentRepository.On("MarkCommentDeleted", mock.Anything, "c1").Return(domain.Comment{ID: "c1", Deleted: true}, nil)
+
+	err := suite.usecase.DeleteComment(context.TODO(), "task_1", "c1", "user_1")
+	suite.Nil(err, "error should be nil")
+	suite.commentRepository.AssertNotCalled(suite.T(), "DeleteComment", mock.Anything, mock.Anything)
+}
+
+func (suite *commentUsecaseSuite) TestDeleteComment_RemovesEmptyTombstone() {
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c2").Return(domain.Comment{ID: "c2", TaskID: "task_1", ParentID: "c1", AuthorID: "user_1"}, nil)
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c1").Return(domain.Comment{ID: "c1", TaskID: "task_1", Deleted: true}, nil)
+	suite.commentRepository.On("CountReplies", mock.Anything, mock.Anything).Return(int64(0), nil)
+	suite.commentRepository.On("DeleteComment", mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.usecase.DeleteComment(context.TODO(), "task_1", "c2", "user_1")
+	suite.Nil(err, "error should be nil")
+	suite.commentRepository.AssertCalled(suite.T(), "DeleteComment", mock.Anything, "c2")
+	suite.commentRepository.AssertCalled(suite.T(), "DeleteComment", mock.Anything, "c1")
+}
+
+func (suite *commentUsecaseSuite) TestDeleteComment_NotAuthor() {
+	suite.commentRepository.On("FetchCommentByID", mock.Anything, "c1").Return(domain.Comment{ID: "c1", TaskID: "task_1", AuthorID: "user_1"}, nil)
+
+	err := suite.usecase.DeleteComment(context.TODO(), "task_1", "c1", "user_2")
+	suite.NotNil(err, "only the author can delete")
+	suite.Equal(http.StatusForbidden, err.Code)
+}
+
+func TestCommentUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(commentUsecaseSuite))
+}
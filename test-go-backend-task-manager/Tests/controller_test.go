@@ -12,6 +12,7 @@ import (
 	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -24,6 +25,7 @@ type controllerTestSuite struct {
 	controller  controllers.Controller
 	router      *gin.Engine
 	authUsecase *mocks.AuthService
+	caller      domain.User
 }
 
 type TaskResponse struct {
@@ -40,15 +42,44 @@ func (suite *controllerTestSuite) SetupTest() {
 	suite.controller = controllers.NewController(taskUC, userUC)
 	gin.SetMode(gin.TestMode)
 	suite.router = gin.Default() // Make sure router is assigned to suite.router
+	suite.caller = domain.User{ID: "user_123", Username: "johndoe", Role: "user"}
+	userUC.On("GetUserByUsername", mock.Anything, suite.caller.Username).Return(suite.caller, nil)
+	// stands in for AuthMiddleWare
+	suite.router.Use(func(cxt *gin.Context) {
+		cxt.Set(infrastructure.CONTEXT_USERNAME, suite.caller.Username)
+	})
 
 	suite.router.POST("/task", suite.controller.PostTask)
 	suite.router.PUT("/task", suite.controller.UpdateTask)
-	suite.router.DELETE("/task/:id/:userid", suite.controller.DeleteTask)
+	suite.router.PATCH("/task/:id", suite.controller.PatchTask)
+	suite.router.DELETE("/task/:id", suite.controller.DeleteTask)
+	suite.router.POST("/task/:id/assignees", suite.controller.PostTaskAssignee)
+	suite.router.DELETE("/task/:id/assignees/:userid", suite.controller.DeleteTaskAssignee)
+	suite.router.POST("/task/:id/watchers", suite.controller.PostTaskWatcher)
+	suite.router.DELETE("/task/:id/watchers", suite.controller.DeleteTaskWatcher)
+	suite.router.GET("/me/tasks", suite.controller.GetMyTasks)
 	suite.router.POST("/user/assign", suite.controller.PostUserAssign)
 	suite.router.POST("/user/register", suite.controller.PostUserRegister)
 	suite.router.POST("/user/login", suite.controller.PostUserLogin)
+	suite.router.POST("/user/refresh", suite.controller.PostUserRefresh)
+	suite.router.POST("/user/logout", suite.controller.PostUserLogout)
 	suite.router.GET("/task", suite.controller.GetTasks)
+	suite.router.GET("/task/search", suite.controller.SearchTasks)
 	suite.router.GET("/task/:id", suite.controller.GetTaskByID)
+	suite.router.GET("/workflow", suite.controller.GetWorkflow)
+	suite.router.GET("/task/:id/children", suite.controller.GetChildTasks)
+	suite.router.GET("/task/:id/tree", suite.controller.GetTaskTree)
+	suite.router.GET("/task/plan", suite.controller.GetTaskPlan)
+	suite.router.GET("/tags", suite.controller.GetTags)
+	suite.router.POST("/task/tags", suite.controller.PostTaskTags)
+	suite.router.PUT("/tags/:tag", suite.controller.PutTag)
+	suite.router.POST("/tags/merge", suite.controller.PostTagMerge)
+	suite.router.GET("/task/:id/occurrences", suite.controller.GetTaskOccurrences)
+	suite.router.POST("/task/:id/dependencies", suite.controller.PostTaskDependency)
+	suite.router.DELETE("/task/:id/dependencies/:blockerid", suite.controller.DeleteTaskDependency)
+	suite.router.GET("/task/:id/revisions", suite.controller.GetTaskRevisions)
+	suite.router.GET("/task/:id/revisions/diff", suite.controller.GetTaskRevisionDiff)
+	suite.router.POST("/task/:id/revisions/:revision/revert", suite.controller.PostTaskRevert)
 }
 
 func (suite *controllerTestSuite) TestGetAllTasks_Positive() {
@@ -77,7 +108,7 @@ func (suite *controllerTestSuite) TestGetAllTasks_Positive() {
 		},
 	}
 
-	suite.taskUsecase.On("GetAllTasks", mock.Anything).Return(tasks, nil)
+	suite.taskUsecase.On("GetTasks", mock.Anything, domain.TaskQuery{SortOrder: 1}).Return(domain.TaskPage{Tasks: tasks, Limit: 20}, nil)
 	req, _ := http.NewRequest(http.MethodGet, "/task", nil)
 	resp := httptest.NewRecorder()
 	suite.router.ServeHTTP(resp, req)
@@ -92,6 +123,467 @@ func (suite *controllerTestSuite) TestGetAllTasks_Positive() {
 
 }
 
+func (suite *controllerTestSuite) TestGetTasks_QueryParameters() {
+	expectedQuery := domain.TaskQuery{
+		Status:    "Pending",
+		Priority:  "High",
+		UserID:    "user_123",
+		DueAfter:  time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		DueBefore: time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC),
+		SortBy:    "due_date",
+		SortOrder: -1,
+		Limit:     5,
+		Cursor:    "next_page",
+	}
+	page := domain.TaskPage{Tasks: []domain.Task{{ID: "1", Title: "Task 1"}}, NextCursor: "after_task_1", Limit: 5}
+	suite.taskUsecase.On("GetTasks", mock.Anything, expectedQuery).Return(page, nil)
+
+	url := "/task?status=Pending&priority=High&userID=user_123&due_after=2024-08-01T00:00:00Z&due_before=2024-08-31T00:00:00Z&sort=due_date&order=desc&limit=5&cursor=next_page"
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var pageResponse domain.TaskPage
+	err := json.Unmarshal(resp.Body.Bytes(), &pageResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(1, len(pageResponse.Tasks))
+	suite.Equal("after_task_1", pageResponse.NextCursor)
+	suite.Equal(5, pageResponse.Limit)
+}
+
+func (suite *controllerTestSuite) TestGetTasks_InvalidParameters() {
+	for _, url := range []string{"/task?limit=zero", "/task?order=up", "/task?due_after=yesterday"} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		resp := httptest.NewRecorder()
+		suite.router.ServeHTTP(resp, req)
+
+		suite.Equal(http.StatusBadRequest, resp.Code, url)
+	}
+	suite.taskUsecase.AssertNotCalled(suite.T(), "GetTasks", mock.Anything, mock.Anything)
+}
+
+func (suite *controllerTestSuite) TestSearchTasks_Positive() {
+	results := []domain.TaskSearchResult{
+		{
+			Task:       domain.Task{ID: "1", Title: "Task 1"},
+			Score:      1.5,
+			Highlights: map[string]string{"title": "<mark>Task</mark> 1"},
+		},
+	}
+	suite.taskUsecase.On("SearchTasks", mock.Anything, "task", 10).Return(results, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/search?q=task&limit=10", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var searchResponse struct {
+		Results []domain.TaskSearchResult `json:"results"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &searchResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(results, searchResponse.Results)
+}
+
+func (suite *controllerTestSuite) TestSearchTasks_Negative() {
+	suite.taskUsecase.On("SearchTasks", mock.Anything, "", 0).Return([]domain.TaskSearchResult{}, &domain.TaskError{Message: "Search query is required", Code: http.StatusBadRequest})
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/search", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusBadRequest, resp.Code)
+}
+
+func (suite *controllerTestSuite) TestGetWorkflow() {
+	workflow := domain.Workflow{
+		InitialStatus: "todo",
+		Statuses:      []string{"todo", "done"},
+		Transitions:   []domain.WorkflowTransition{{From: "todo", To: "done"}},
+	}
+	suite.taskUsecase.On("GetWorkflow").Return(workflow)
+	suite.taskUsecase.On("NextStatuses", mock.Anything, "todo").Return([]string{"done"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/workflow?status=todo", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var workflowResponse struct {
+		Workflow domain.Workflow `json:"workflow"`
+		Next     []string        `json:"next"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &workflowResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(workflow, workflowResponse.Workflow)
+	suite.Equal([]string{"done"}, workflowResponse.Next)
+}
+
+func (suite *controllerTestSuite) TestGetChildTasks() {
+	children := []domain.Task{{ID: "2", ParentID: "1", Title: "Subtask"}}
+	suite.taskUsecase.On("GetChildTasks", mock.Anything, "1").Return(children, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1/children", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var tasksResponse TaskResponse
+	err := json.Unmarshal(resp.Body.Bytes(), &tasksResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(children, tasksResponse.Tasks)
+}
+
+func (suite *controllerTestSuite) TestGetTaskTree() {
+	tree := domain.TaskNode{
+		Task:            domain.Task{ID: "1", Title: "Task 1"},
+		PercentComplete: 50,
+		Children: []domain.TaskNode{
+			{Task: domain.Task{ID: "2", ParentID: "1", Title: "Subtask"}, PercentComplete: 50, Children: []domain.TaskNode{}},
+		},
+	}
+	suite.taskUsecase.On("GetTaskTree", mock.Anything, "1").Return(tree, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1/tree", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var treeResponse domain.TaskNode
+	err := json.Unmarshal(resp.Body.Bytes(), &treeResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(tree, treeResponse)
+}
+
+func (suite *controllerTestSuite) TestPostTaskDependency() {
+	blockedTask := domain.Task{ID: "2", Title: "Deploy", BlockedBy: []string{"1"}, Blocked: true}
+	suite.taskUsecase.On("AddDependency", mock.Anything, "2", "1").Return(blockedTask, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/2/dependencies", bytes.NewBufferString(`{"blockedBy": "1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var returnedTask domain.Task
+	err := json.Unmarshal(resp.Body.Bytes(), &returnedTask)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(blockedTask, returnedTask)
+}
+
+func (suite *controllerTestSuite) TestPostTaskDependency_Cycle() {
+	suite.taskUsecase.On("AddDependency", mock.Anything, "1", "2").Return(domain.Task{}, &domain.TaskError{Message: "Dependency would create a cycle", Code: http.StatusConflict})
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/dependencies", bytes.NewBufferString(`{"blockedBy": "2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusConflict, resp.Code)
+}
+
+func (suite *controllerTestSuite) TestDeleteTaskDependency() {
+	suite.taskUsecase.On("RemoveDependency", mock.Anything, "2", "1").Return(domain.Task{ID: "2", Title: "Deploy"}, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/task/2/dependencies/1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.taskUsecase.AssertCalled(suite.T(), "RemoveDependency", mock.Anything, "2", "1")
+}
+
+func (suite *controllerTestSuite) TestGetTaskPlan() {
+	plan := []domain.Task{{ID: "1", Title: "Build"}, {ID: "2", Title: "Deploy", BlockedBy: []string{"1"}, Blocked: true}}
+	suite.taskUsecase.On("PlanTasks", mock.Anything, []string{"2", "1"}).Return(plan, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/plan?ids=2,1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var tasksResponse TaskResponse
+	err := json.Unmarshal(resp.Body.Bytes(), &tasksResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(plan, tasksResponse.Tasks)
+}
+
+func (suite *controllerTestSuite) TestGetTaskOccurrences() {
+	occurrences := []time.Time{time.Date(2024, time.January, 8, 9, 0, 0, 0, time.UTC), time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)}
+	suite.taskUsecase.On("PreviewOccurrences", mock.Anything, "1", 2).Return(occurrences, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1/occurrences?count=2", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var occurrencesResponse struct {
+		Occurrences []time.Time `json:"occurrences"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &occurrencesResponse)
+	suite.Nil(err)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(occurrences, occurrencesResponse.Occurrences)
+
+	req, _ = http.NewRequest(http.MethodGet, "/task/1/occurrences?count=none", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+}
+
+func (suite *controllerTestSuite) TestGetTaskRevisionDiff() {
+	diff := domain.RevisionDiff{From: 1, To: 3, Changes: []domain.FieldChange{{Field: "title", Before: "Draft", After: "Final"}}}
+	suite.taskUsecase.On("DiffRevisions", mock.Anything, "1", 1, 3).Return(diff, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1/revisions/diff?from=1&to=3", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var diffResponse domain.RevisionDiff
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &diffResponse))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(diff, diffResponse)
+
+	req, _ = http.NewRequest(http.MethodGet, "/task/1/revisions/diff?from=1", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+}
+
+func (suite *controllerTestSuite) TestPostTaskRevert() {
+	reverted := domain.Task{ID: "1", Title: "Draft", Revision: 4}
+	suite.taskUsecase.On("RevertTask", mock.Anything, "1", 2, suite.caller).Return(reverted, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/revisions/2/revert", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var task domain.Task
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &task))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(reverted, task)
+
+	req, _ = http.NewRequest(http.MethodPost, "/task/1/revisions/latest/revert", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.taskUsecase.AssertNumberOfCalls(suite.T(), "RevertTask", 1)
+}
+
+func (suite *controllerTestSuite) TestGetTaskByID_ETag() {
+	suite.taskUsecase.On("GetTaskByID", mock.Anything, "1").Return(domain.Task{ID: "1", Title: "Task 1", Version: 3}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task/1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(`"3"`, resp.Header().Get("ETag"))
+}
+
+func (suite *controllerTestSuite) TestUpdateTask_IfMatch() {
+	current := domain.Task{ID: "1", UserID: "user_123", Title: "Edited elsewhere", Version: 5}
+	stale := domain.Task{ID: "1", UserID: "user_123", Title: "Stale", Version: 3}
+	suite.taskUsecase.On("UpdateTask", mock.Anything, stale, suite.caller).Return(current, &domain.TaskError{Message: "Task has been modified, it is now at version 5", Code: http.StatusPreconditionFailed})
+
+	taskJSON, _ := json.Marshal(domain.Task{ID: "1", UserID: "user_123", Title: "Stale"})
+	req, _ := http.NewRequest(http.MethodPut, "/task", bytes.NewBuffer(taskJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var conflict struct {
+		Error string      `json:"Error"`
+		Task  domain.Task `json:"task"`
+	}
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &conflict))
+	suite.Equal(http.StatusPreconditionFailed, resp.Code)
+	suite.Equal(`"5"`, resp.Header().Get("ETag"))
+	suite.Equal(current.Title, conflict.Task.Title)
+	suite.Equal(5, conflict.Task.Version)
+
+	req, _ = http.NewRequest(http.MethodPut, "/task", bytes.NewBuffer(taskJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `W/"3"`)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.taskUsecase.AssertNumberOfCalls(suite.T(), "UpdateTask", 1)
+}
+
+func (suite *controllerTestSuite) TestPatchTask() {
+	patched := domain.Task{ID: "1", UserID: "user_123", Title: "Task 1", Priority: "high", Version: 4}
+	patch := domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"priority":"high"}`), Version: 3}
+	suite.taskUsecase.On("PatchTask", mock.Anything, "1", patch, suite.caller).Return(patched, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/task/1", bytes.NewBuffer(patch.Patch))
+	req.Header.Set("Content-Type", "application/merge-patch+json; charset=utf-8")
+	req.Header.Set("If-Match", `"3"`)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var task domain.Task
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &task))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(`"4"`, resp.Header().Get("ETag"))
+	suite.Equal(patched, task)
+
+	req, _ = http.NewRequest(http.MethodPatch, "/task/1", bytes.NewBuffer(patch.Patch))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusUnsupportedMediaType, resp.Code)
+	suite.Contains(resp.Header().Get("Accept-Patch"), domain.PATCH_JSON)
+	suite.taskUsecase.AssertNumberOfCalls(suite.T(), "PatchTask", 1)
+}
+
+func (suite *controllerTestSuite) TestDeleteTask_IfMatch() {
+	deleted := domain.Task{ID: "1", UserID: "user_123", Title: "Done", Version: 2}
+	suite.taskUsecase.On("DeleteTask", mock.Anything, "1", suite.caller, 2).Return(deleted, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/task/1", nil)
+	req.Header.Set("If-Match", `"2"`)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.taskUsecase.AssertCalled(suite.T(), "DeleteTask", mock.Anything, "1", suite.caller, 2)
+}
+
+func (suite *controllerTestSuite) TestGetMyTasks() {
+	query := domain.TaskQuery{Status: "todo", AssigneeID: suite.caller.ID, SortOrder: 1}
+	suite.taskUsecase.On("GetTasks", mock.Anything, query).Return(domain.TaskPage{Tasks: []domain.Task{{ID: "1", Assignees: []string{suite.caller.ID}}}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/me/tasks?status=todo", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var page domain.TaskPage
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &page))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(1, len(page.Tasks))
+}
+
+func (suite *controllerTestSuite) TestPostTaskAssignee() {
+	assigned := domain.Task{ID: "1", UserID: "user_123", Assignees: []string{"user_456"}, Version: 2}
+	suite.userUsecase.On("GetUserByID", mock.Anything, "user_456").Return(domain.User{ID: "user_456"}, nil)
+	suite.userUsecase.On("GetUserByID", mock.Anything, "user_999").Return(domain.User{}, &domain.UserError{Message: "mongo: no documents in result", Code: http.StatusInternalServerError})
+	suite.taskUsecase.On("AssignTask", mock.Anything, "1", "user_456", suite.caller).Return(assigned, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/assignees", bytes.NewBufferString(`{"userID":"user_456"}`))
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(`"2"`, resp.Header().Get("ETag"))
+
+	req, _ = http.NewRequest(http.MethodPost, "/task/1/assignees", bytes.NewBufferString(`{"userID":"user_999"}`))
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusNotFound, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/task/1/assignees", bytes.NewBufferString(`{}`))
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.taskUsecase.AssertNumberOfCalls(suite.T(), "AssignTask", 1)
+}
+
+func (suite *controllerTestSuite) TestDeleteTaskAssignee_Forbidden() {
+	suite.taskUsecase.On("UnassignTask", mock.Anything, "1", "user_456", suite.caller).Return(domain.Task{}, &domain.TaskError{Message: "You are not authorized to update this task", Code: http.StatusForbidden})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/task/1/assignees/user_456", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusForbidden, resp.Code)
+}
+
+func (suite *controllerTestSuite) TestTaskWatchers() {
+	suite.taskUsecase.On("WatchTask", mock.Anything, "1", suite.caller.ID).Return(domain.Task{ID: "1", Watchers: []string{suite.caller.ID}}, nil)
+	suite.taskUsecase.On("UnwatchTask", mock.Anything, "1", suite.caller.ID).Return(domain.Task{ID: "1"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/watchers", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/task/1/watchers", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *controllerTestSuite) TestGetTasks_Tags() {
+	query := domain.TaskQuery{AnyTags: []string{"urgent", "bug"}, AllTags: []string{"backend"}, SortOrder: 1}
+	suite.taskUsecase.On("GetTasks", mock.Anything, query).Return(domain.TaskPage{Tasks: []domain.Task{}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/task?tags_any=urgent,bug&tags_all=backend", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.taskUsecase.AssertCalled(suite.T(), "GetTasks", mock.Anything, query)
+}
+
+func (suite *controllerTestSuite) TestGetTags() {
+	counts := []domain.TagCount{{Tag: "urgent", Count: 3}, {Tag: "backend", Count: 1}}
+	suite.taskUsecase.On("GetTags", mock.Anything).Return(counts, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/tags", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var tagsResponse struct {
+		Tags []domain.TagCount `json:"tags"`
+	}
+	err := json.Unmarshal(resp.Body.Bytes(), &tagsResponse)
+	suite.Nil(err)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(counts, tagsResponse.Tags)
+}
+
+func (suite *controllerTestSuite) TestPostTaskTags() {
+	update := domain.TagUpdate{TaskIDs: []string{"1", "2"}, Add: []string{"urgent"}, Remove: []string{"later"}}
+	suite.taskUsecase.On("UpdateTaskTags", mock.Anything, update).Return(int64(2), nil)
+
+	body := `{"taskIDs": ["1", "2"], "add": ["urgent"], "remove": ["later"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/task/tags", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.JSONEq(`{"updated": 2}`, resp.Body.String())
+}
+
+func (suite *controllerTestSuite) TestRenameAndMergeTags() {
+	suite.taskUsecase.On("RenameTag", mock.Anything, "todo", "backlog").Return(int64(4), nil)
+	suite.taskUsecase.On("MergeTags", mock.Anything, []string{"bug", "defect"}, "issue").Return(int64(5), nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/tags/todo", bytes.NewBufferString(`{"name": "backlog"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.JSONEq(`{"updated": 4}`, resp.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPost, "/tags/merge", bytes.NewBufferString(`{"tags": ["bug", "defect"], "into": "issue"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.JSONEq(`{"updated": 5}`, resp.Body.String())
+}
+
 func (suite *controllerTestSuite) TestGetTaskByID_Positive() {
 	task := domain.Task{
 		ID:          "1",
@@ -148,7 +640,7 @@ func (suite *controllerTestSuite) TestUpdateTask_Positive() {
 		UpdatedAt:   time.Now().Truncate(time.Minute),
 	}
 
-	suite.taskUsecase.On("UpdateTask", mock.Anything, updatedTask).Return(updatedTask, nil)
+	suite.taskUsecase.On("UpdateTask", mock.Anything, updatedTask, suite.caller).Return(updatedTask, nil)
 
 	taskJSON, _ := json.Marshal(updatedTask)
 	req, _ := http.NewRequest(http.MethodPut, "/task", bytes.NewBuffer(taskJSON))
@@ -210,11 +702,10 @@ func (suite *controllerTestSuite) TestUpdateTask_Negative_ValidationError() {
 
 func (suite *controllerTestSuite) TestDeleteTask_Positive() {
 	taskID := "1"
-	authorityID := "user_123"
 
 	deletedTask := domain.Task{
 		ID:          taskID,
-		UserID:      authorityID,
+		UserID:      suite.caller.ID,
 		Title:       "Task to delete",
 		Description: "Description of the task to delete",
 		Status:      "Pending",
@@ -224,9 +715,9 @@ func (suite *controllerTestSuite) TestDeleteTask_Positive() {
 		UpdatedAt:   time.Now().Truncate(time.Minute),
 	}
 
-	suite.taskUsecase.On("DeleteTask", mock.Anything, taskID, authorityID).Return(deletedTask, nil)
+	suite.taskUsecase.On("DeleteTask", mock.Anything, taskID, suite.caller, 0).Return(deletedTask, nil)
 
-	req, _ := http.NewRequest(http.MethodDelete, "/task/"+taskID+"/"+authorityID, nil)
+	req, _ := http.NewRequest(http.MethodDelete, "/task/"+taskID, nil)
 	resp := httptest.NewRecorder()
 	suite.router.ServeHTTP(resp, req)
 
@@ -242,11 +733,10 @@ func (suite *controllerTestSuite) TestDeleteTask_Positive() {
 
 func (suite *controllerTestSuite) TestDeleteTask_Negative_Error() {
 	taskID := "1"
-	authorityID := "user_123"
 
-	suite.taskUsecase.On("DeleteTask", mock.Anything, taskID, authorityID).Return(domain.Task{}, &domain.TaskError{Code: http.StatusNotFound, Message: "Task not found"})
+	suite.taskUsecase.On("DeleteTask", mock.Anything, taskID, suite.caller, 0).Return(domain.Task{}, &domain.TaskError{Code: http.StatusNotFound, Message: "Task not found"})
 
-	req, _ := http.NewRequest(http.MethodDelete, "/task/"+taskID+"/"+authorityID, nil)
+	req, _ := http.NewRequest(http.MethodDelete, "/task/"+taskID, nil)
 
 	resp := httptest.NewRecorder()
 
@@ -396,7 +886,7 @@ func (suite *controllerTestSuite) TestPostUserLogin_Success() {
 		Password: "$2a$10$hashedpassword",
 	}
 	token := "token"
-	suite.userUsecase.On("LoginUser", mock.Anything, user).Return(token, nil)
+	suite.userUsecase.On("LoginUser", mock.Anything, user).Return(domain.Session{Token: token, RefreshToken: "refresh", ExpiresIn: 900}, nil)
 
 	userJSON, _ := json.Marshal(user)
 	req, _ := http.NewRequest(http.MethodPost, "/user/login", bytes.NewBuffer(userJSON))
@@ -406,11 +896,12 @@ func (suite *controllerTestSuite) TestPostUserLogin_Success() {
 	suite.router.ServeHTTP(resp, req)
 	fmt.Println(resp.Body.String())
 
-	var regisetToken map[string]string
+	var regisetToken map[string]interface{}
 	err := json.Unmarshal(resp.Body.Bytes(), &regisetToken)
 	suite.Nil(err, "Error unmarshalling response")
 	suite.Equal(http.StatusOK, resp.Code, "Expected status code 200, but got %d", resp.Code)
 	suite.Equal(token, regisetToken["token"], "Expected token to be returned, but got %s", token)
+	suite.Equal("refresh", regisetToken["refresh_token"])
 
 }
 
@@ -431,6 +922,76 @@ func (suite *controllerTestSuite) TestPostUserLogin_Fail() {
 
 	suite.Equal("Malformed JSON", errorResponse["Error"], "Expected error message 'Malformed JSON', but got '%s'", errorResponse["Error"])
 }
+
+func (suite *controllerTestSuite) TestPostUserRefresh() {
+	session := domain.Session{Token: "access", RefreshToken: "next", ExpiresIn: 900}
+	suite.userUsecase.On("RefreshSession", mock.Anything, "current").Return(session, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/user/refresh", bytes.NewBufferString(`{"refresh_token": "current"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	var response domain.Session
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &response))
+	suite.Equal(session, response)
+}
+
+func (suite *controllerTestSuite) TestPostUserRefresh_Reused() {
+	errReuse := &domain.UserError{Message: "Refresh token has already been used, please log in again", Code: http.StatusUnauthorized}
+	suite.userUsecase.On("RefreshSession", mock.Anything, "used").Return(domain.Session{}, errReuse)
+
+	req, _ := http.NewRequest(http.MethodPost, "/user/refresh", bytes.NewBufferString(`{"refresh_token": "used"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+func (suite *controllerTestSuite) TestPostUserRefresh_MissingToken() {
+	req, _ := http.NewRequest(http.MethodPost, "/user/refresh", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.userUsecase.AssertNotCalled(suite.T(), "RefreshSession", mock.Anything, mock.Anything)
+}
+
+func (suite *controllerTestSuite) TestPostUserLogout() {
+	suite.userUsecase.On("Logout", mock.Anything, "current", "").Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/user/logout", bytes.NewBufferString(`{"refresh_token": "current"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.userUsecase.AssertCalled(suite.T(), "Logout", mock.Anything, "current", "")
+}
+
+func (suite *controllerTestSuite) TestPostUserLogout_AccessToken() {
+	suite.userUsecase.On("Logout", mock.Anything, "current", "access").Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/user/logout", bytes.NewBufferString(`{"refresh_token": "current"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer access")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.userUsecase.AssertCalled(suite.T(), "Logout", mock.Anything, "current", "access")
+
+	req, _ = http.NewRequest(http.MethodPost, "/user/logout", bytes.NewBufferString(`{"refresh_token": "current"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Basic access")
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.userUsecase.AssertNumberOfCalls(suite.T(), "Logout", 1)
+}
+
 func TestControllerSuite(t *testing.T) {
 	suite.Run(t, new(controllerTestSuite))
 }
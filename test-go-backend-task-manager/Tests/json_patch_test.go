@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONPatchTestSuite struct {
+	suite.Suite
+}
+
+const patchDocument = `{"title":"Draft","tags":["a","b"],"meta":{"owner":"abebe","a/b":1}}`
+
+func (suite *JSONPatchTestSuite) TestMergePatch() {
+	// examples from RFC 7396 appendix A
+	for _, example := range []struct{ target, patch, result string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		result, err := infrastructure.MergePatch([]byte(example.target), []byte(example.patch))
+		suite.Nil(err, "patch %s should apply", example.patch)
+		suite.JSONEq(example.result, string(result), "patch %s", example.patch)
+	}
+
+	_, err := infrastructure.MergePatch([]byte(`{}`), []byte(`{"a":`))
+	suite.True(errors.Is(err, infrastructure.ErrInvalidPatch), "malformed patches should be invalid")
+}
+
+func (suite *JSONPatchTestSuite) TestJSONPatch() {
+	for _, example := range []struct{ patch, result string }{
+		{`[{"op":"replace","path":"/title","value":"Final"}]`, `{"title":"Final","tags":["a","b"],"meta":{"owner":"abebe","a/b":1}}`},
+		{`[{"op":"add","path":"/tags/-","value":"c"},{"op":"add","path":"/tags/0","value":"z"}]`, `{"title":"Draft","tags":["z","a","b","c"],"meta":{"owner":"abebe","a/b":1}}`},
+		{`[{"op":"remove","path":"/tags/0"},{"op":"remove","path":"/meta/a~1b"}]`, `{"title":"Draft","tags":["b"],"meta":{"owner":"abebe"}}`},
+		{`[{"op":"move","from":"/meta/owner","path":"/owner"}]`, `{"title":"Draft","tags":["a","b"],"meta":{"a/b":1},"owner":"abebe"}`},
+		{`[{"op":"copy","from":"/tags","path":"/labels"},{"op":"add","path":"/labels/-","value":"c"}]`, `{"title":"Draft","tags":["a","b"],"labels":["a","b","c"],"meta":{"owner":"abebe","a/b":1}}`},
+		{`[{"op":"test","path":"/meta/a~1b","value":1.0},{"op":"add","path":"/description","value":null}]`, `{"title":"Draft","tags":["a","b"],"description":null,"meta":{"owner":"abebe","a/b":1}}`},
+	} {
+		result, err := infrastructure.JSONPatch([]byte(patchDocument), []byte(example.patch))
+		suite.Nil(err, "patch %s should apply", example.patch)
+		suite.JSONEq(example.result, string(result), "patch %s", example.patch)
+	}
+}
+
+func (suite *JSONPatchTestSuite) TestJSONPatch_Errors() {
+	for _, invalid := range []string{
+		`{"op":"add","path":"/title","value":"x"}`,
+		`[{"op":"add","path":"/title"}]`,
+		`[{"op":"remove"}]`,
+		`[{"op":"rename","path":"/title","value":"x"}]`,
+		`[{"op":"move","path":"/title"}]`,
+		`[{"op":"add","path":"title","value":"x"}]`,
+		`[{"op":"move","from":"/meta","path":"/meta/inner"}]`,
+	} {
+		_, err := infrastructure.JSONPatch([]byte(patchDocument), []byte(invalid))
+		suite.True(errors.Is(err, infrastructure.ErrInvalidPatch), "patch %s should be invalid", invalid)
+	}
+
+	for _, unapplicable := range []string{
+		`[{"op":"remove","path":"/description"}]`,
+		`[{"op":"replace","path":"/description","value":"x"}]`,
+		`[{"op":"add","path":"/tags/3","value":"x"}]`,
+		`[{"op":"add","path":"/tags/01","value":"x"}]`,
+		`[{"op":"remove","path":"/tags/-"}]`,
+		`[{"op":"add","path":"/missing/child","value":"x"}]`,
+	} {
+		_, err := infrastructure.JSONPatch([]byte(patchDocument), []byte(unapplicable))
+		suite.NotNil(err, "patch %s should not apply", unapplicable)
+		suite.False(errors.Is(err, infrastructure.ErrInvalidPatch), "patch %s is well formed", unapplicable)
+	}
+
+	// operations apply in order, a test sees the changes made before it
+	_, err := infrastructure.JSONPatch([]byte(patchDocument), []byte(`[{"op":"replace","path":"/title","value":"Final"},{"op":"test","path":"/title","value":"Draft"}]`))
+	suite.True(errors.Is(err, infrastructure.ErrPatchTestFailed), "the test should fail")
+}
+
+func TestJSONPatchTestSuite(t *testing.T) {
+	suite.Run(t, new(JSONPatchTestSuite))
+}
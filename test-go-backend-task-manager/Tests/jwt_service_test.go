@@ -1,11 +1,14 @@
 package tests
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -32,7 +35,7 @@ func (suite *JWTTestSuite) TestCreateJWTToken_Success() {
 	role := "admin"
 	duration := time.Minute * 10
 
-	token, err := infrastructure.CreateJWTToken(username, role, duration)
+	token, err := infrastructure.CreateJWTToken(username, role, "org_1", duration)
 	assert.NoError(suite.T(), err)
 	assert.NotEmpty(suite.T(), token)
 
@@ -46,6 +49,7 @@ func (suite *JWTTestSuite) TestCreateJWTToken_Success() {
 	assert.True(suite.T(), ok)
 	assert.Equal(suite.T(), username, claims["username"])
 	assert.Equal(suite.T(), role, claims["role"])
+	assert.Equal(suite.T(), "org_1", claims["org"])
 }
 
 func (suite *JWTTestSuite) TestParseJWTToken_Success() {
@@ -53,7 +57,7 @@ func (suite *JWTTestSuite) TestParseJWTToken_Success() {
 	role := "admin"
 	duration := time.Minute * 10
 
-	token, err := infrastructure.CreateJWTToken(username, role, duration)
+	token, err := infrastructure.CreateJWTToken(username, role, "", duration)
 	assert.NoError(suite.T(), err)
 
 	parsedToken, err := infrastructure.ParseJWTToken(token)
@@ -77,7 +81,7 @@ func (suite *JWTTestSuite) TestParseJWTToken_ExpiredToken() {
 	role := "admin"
 	expiredDuration := -time.Minute * 10
 
-	token, err := infrastructure.CreateJWTToken(username, role, expiredDuration)
+	token, err := infrastructure.CreateJWTToken(username, role, "", expiredDuration)
 	assert.NoError(suite.T(), err)
 
 	parsedToken, err := infrastructure.ParseJWTToken(token)
@@ -85,6 +89,78 @@ func (suite *JWTTestSuite) TestParseJWTToken_ExpiredToken() {
 	assert.False(suite.T(), parsedToken.Valid)
 }
 
+func (suite *JWTTestSuite) TestAuthMiddleWare_SetsOrg() {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var orgID interface{}
+	var scoped bool
+	router.GET("/", infrastructure.AuthMiddleWare("user"), func(cxt *gin.Context) {
+		orgID, scoped = cxt.Get(infrastructure.CONTEXT_ORG)
+	})
+
+	for _, org := range []string{"acme", ""} {
+		token, err := infrastructure.CreateJWTToken("testuser", "user", org, time.Minute*10)
+		assert.NoError(suite.T(), err)
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(suite.T(), http.StatusOK, resp.Code)
+		assert.True(suite.T(), scoped, "every authenticated request should be scoped to an organization")
+		assert.Equal(suite.T(), org, orgID)
+	}
+}
+
+func (suite *JWTTestSuite) TestTwoFactorToken() {
+	token, err := infrastructure.CreateTwoFactorToken("user_1", "org_1", time.Minute*5)
+	assert.NoError(suite.T(), err)
+	claims, err := infrastructure.ParseTwoFactorToken(token)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "user_1", claims.UserID)
+	assert.Equal(suite.T(), "org_1", claims.OrgID)
+
+	accessToken, _ := infrastructure.CreateJWTToken("testuser", "admin", "org_1", time.Minute*10)
+	_, err = infrastructure.ParseTwoFactorToken(accessToken)
+	assert.Error(suite.T(), err, "access tokens should not complete a login")
+
+	expired, _ := infrastructure.CreateTwoFactorToken("user_1", "org_1", -time.Minute)
+	_, err = infrastructure.ParseTwoFactorToken(expired)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *JWTTestSuite) TestParseAccessToken() {
+	token, err := infrastructure.CreateJWTToken("testuser", "admin", "org_1", time.Minute*10)
+	assert.NoError(suite.T(), err)
+	claims, err := infrastructure.ParseAccessToken(token)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "testuser", claims.Username)
+	assert.Equal(suite.T(), "org_1", claims.OrgID)
+	assert.NotEmpty(suite.T(), claims.ID)
+	assert.WithinDuration(suite.T(), time.Now().Add(time.Minute*10), claims.ExpiresAt.Time, time.Second*2)
+
+	twoFactorToken, _ := infrastructure.CreateTwoFactorToken("user_1", "org_1", time.Minute*5)
+	_, err = infrastructure.ParseAccessToken(twoFactorToken)
+	assert.Error(suite.T(), err, "two-factor tokens are not access tokens")
+
+	expired, _ := infrastructure.CreateJWTToken("testuser", "admin", "org_1", -time.Minute)
+	_, err = infrastructure.ParseAccessToken(expired)
+	assert.ErrorIs(suite.T(), err, infrastructure.ErrTokenExpired)
+}
+
+func (suite *JWTTestSuite) TestAuthMiddleWare_RefusesTwoFactorToken() {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", infrastructure.AuthMiddleWare("user", "admin"), func(cxt *gin.Context) {})
+
+	token, err := infrastructure.CreateTwoFactorToken("user_1", "", time.Minute*5)
+	assert.NoError(suite.T(), err)
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+}
+
 func TestJWTTestSuite(t *testing.T) {
 	suite.Run(t, new(JWTTestSuite))
 }
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyManagerTestSuite struct {
+	suite.Suite
+	dir       string
+	rsaKey    *rsa.PrivateKey
+	edKey     ed25519.PrivateKey
+	oldRSAKey *rsa.PrivateKey
+}
+
+func (suite *KeyManagerTestSuite) SetupSuite() {
+	var err error
+	suite.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().Nil(err)
+	suite.oldRSAKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().Nil(err)
+	_, suite.edKey, err = ed25519.GenerateKey(rand.Reader)
+	suite.Require().Nil(err)
+	os.Setenv("SIGNITURE_SECRET", "test_secret")
+}
+
+func (suite *KeyManagerTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	suite.writeKey("rsa-2024", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(suite.rsaKey))
+	edBytes, err := x509.MarshalPKCS8PrivateKey(suite.edKey)
+	suite.Require().Nil(err)
+	suite.writeKey("ed-2025", "PRIVATE KEY", edBytes)
+	// the private half of an old key has been destroyed, its public half still verifies
+	oldBytes, err := x509.MarshalPKIXPublicKey(&suite.oldRSAKey.PublicKey)
+	suite.Require().Nil(err)
+	suite.writeKey("rsa-2023", "PUBLIC KEY", oldBytes)
+}
+
+func (suite *KeyManagerTestSuite) TearDownTest() {
+	infrastructure.SetKeyManager(nil)
+}
+
+func (suite *KeyManagerTestSuite) writeKey(ID string, blockType string, content []byte) {
+	encoded := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content})
+	suite.Require().Nil(os.WriteFile(filepath.Join(suite.dir, ID+".pem"), encoded, 0o600))
+}
+
+func (suite *KeyManagerTestSuite) claims() infrastructure.UserClaim {
+	return infrastructure.UserClaim{
+		Username: "testuser",
+		Role:     "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
+func (suite *KeyManagerTestSuite) TestSignsWithActiveKey() {
+	for _, active := range []string{"rsa-2024", "ed-2025"} {
+		manager, err := infrastructure.LoadKeyManager(suite.dir, active, nil)
+		suite.Require().Nil(err)
+		infrastructure.SetKeyManager(manager)
+
+		token, err := infrastructure.CreateJWTToken("testuser", "user", "org_1", time.Minute)
+		suite.Require().Nil(err)
+		parsed, err := infrastructure.ParseJWTToken(token)
+		suite.Require().Nil(err)
+		suite.Equal(active, parsed.Header["kid"])
+		suite.Equal("testuser", parsed.Claims.(jwt.MapClaims)["username"])
+	}
+	manager, _ := infrastructure.LoadKeyManager(suite.dir, "ed-2025", nil)
+	token, _ := manager.Sign(suite.claims())
+	parsed, _ := manager.Parse(token)
+	suite.Equal(infrastructure.ALGORITHM_EDDSA, parsed.Method.Alg())
+}
+
+func (suite *KeyManagerTestSuite) TestVerifiesWithNonRetiredKeys() {
+	signedByOld := jwt.NewWithClaims(jwt.SigningMethodRS256, suite.claims())
+	signedByOld.Header["kid"] = "rsa-2023"
+	oldToken, err := signedByOld.SignedString(suite.oldRSAKey)
+	suite.Require().Nil(err)
+
+	manager, err := infrastructure.LoadKeyManager(suite.dir, "ed-2025", nil)
+	suite.Require().Nil(err)
+	_, err = manager.Parse(oldToken)
+	suite.Nil(err, "Tokens of keys that are not retired should verify")
+
+	manager, err = infrastructure.LoadKeyManager(suite.dir, "ed-2025", []string{"rsa-2023"})
+	suite.Require().Nil(err)
+	_, err = manager.Parse(oldToken)
+	suite.NotNil(err, "Tokens of retired keys should be refused")
+}
+
+func (suite *KeyManagerTestSuite) TestRefusesForeignTokens() {
+	manager, err := infrastructure.LoadKeyManager(suite.dir, "rsa-2024", nil)
+	suite.Require().Nil(err)
+
+	hmacToken, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+	_, err = manager.Parse(hmacToken)
+	suite.NotNil(err, "Tokens signed with the secret should be refused")
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, suite.claims())
+	unknown.Header["kid"] = "missing"
+	unknownToken, _ := unknown.SignedString(suite.rsaKey)
+	_, err = manager.Parse(unknownToken)
+	suite.NotNil(err, "Tokens naming an unknown key should be refused")
+
+	// the EdDSA key ID with a token signed by RS256
+	mismatched := jwt.NewWithClaims(jwt.SigningMethodRS256, suite.claims())
+	mismatched.Header["kid"] = "ed-2025"
+	mismatchedToken, _ := mismatched.SignedString(suite.rsaKey)
+	_, err = manager.Parse(mismatchedToken)
+	suite.NotNil(err, "The algorithm should match the key")
+}
+
+func (suite *KeyManagerTestSuite) TestLoadRefusesBadConfiguration() {
+	_, err := infrastructure.LoadKeyManager(suite.dir, "missing", nil)
+	suite.NotNil(err, "The active key should be loaded")
+	_, err = infrastructure.LoadKeyManager(suite.dir, "rsa-2023", nil)
+	suite.NotNil(err, "The active key should have a private key")
+	_, err = infrastructure.LoadKeyManager(suite.dir, "rsa-2024", []string{"rsa-2024"})
+	suite.NotNil(err, "The active key should not be retired")
+
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	_, err = infrastructure.ParseSigningKey("weak", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(weak)}))
+	suite.NotNil(err, "Short RSA keys should be refused")
+	_, err = infrastructure.ParseSigningKey("garbage", []byte("not a key"))
+	suite.NotNil(err)
+}
+
+func (suite *KeyManagerTestSuite) TestJWKSEndpoint() {
+	manager, err := infrastructure.LoadKeyManager(suite.dir, "rsa-2024", []string{"rsa-2023"})
+	suite.Require().Nil(err)
+	controller := controllers.NewJWKSController(manager)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", controller.GetJWKS)
+
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Contains(resp.Header().Get("Cache-Control"), "max-age")
+	var set infrastructure.JWKSet
+	suite.Require().Nil(json.Unmarshal(resp.Body.Bytes(), &set))
+	suite.Require().Len(set.Keys, 2, "Retired keys should not be published")
+	suite.Equal(infrastructure.JWK{KeyType: "OKP", KeyID: "ed-2025", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519",
+		X: base64.RawURLEncoding.EncodeToString(suite.edKey.Public().(ed25519.PublicKey))}, set.Keys[0])
+	rsaJWK := set.Keys[1]
+	suite.Equal("rsa-2024", rsaJWK.KeyID)
+	suite.Equal("RS256", rsaJWK.Algorithm)
+	modulus, _ := base64.RawURLEncoding.DecodeString(rsaJWK.Modulus)
+	suite.Equal(0, new(big.Int).SetBytes(modulus).Cmp(suite.rsaKey.N))
+	suite.Equal("AQAB", rsaJWK.Exponent)
+	suite.NotContains(resp.Body.String(), `"d"`, "Private parts should never be published")
+
+	empty := controllers.NewJWKSController(nil)
+	router = gin.New()
+	router.GET("/.well-known/jwks.json", empty.GetJWKS)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	suite.JSONEq(`{"keys": []}`, resp.Body.String())
+}
+
+func TestKeyManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyManagerTestSuite))
+}
@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// a local SMTP server that accepts every message and hands it to the test
+type fakeSMTPServer struct {
+	listener net.Listener
+	messages chan string
+}
+
+func newFakeSMTPServer() (*fakeSMTPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &fakeSMTPServer{listener: listener, messages: make(chan string, 10)}
+	go server.serve()
+	return server, nil
+}
+
+func (server *fakeSMTPServer) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		go server.handle(conn)
+	}
+}
+
+func (server *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost fake SMTP")
+	var envelope []string
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL", "RCPT":
+			envelope = append(envelope, line)
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			body, err := text.ReadDotLines()
+			if err != nil {
+				return
+			}
+			server.messages <- strings.Join(append(envelope, body...), "\n")
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+type NotifierTestSuite struct {
+	suite.Suite
+	server   *fakeSMTPServer
+	reminder domain.Reminder
+}
+
+func (suite *NotifierTestSuite) SetupTest() {
+	server, err := newFakeSMTPServer()
+	suite.Require().Nil(err, "fake SMTP server should start")
+	suite.server = server
+	suite.reminder = domain.Reminder{
+		ID:      "task_001:overdue:1704099600",
+		TaskID:  "task_001",
+		UserID:  "user_1",
+		Kind:    domain.REMINDER_OVERDUE,
+		Title:   "Pay rent",
+		DueDate: time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC),
+		Email:   "user1@example.com",
+	}
+}
+
+func (suite *NotifierTestSuite) TearDownTest() {
+	suite.server.listener.Close()
+}
+
+func (suite *NotifierTestSuite) TestSMTPNotifier() {
+	notifier := infrastructure.NewSMTPNotifier(suite.server.listener.Addr().String(), "tasks@example.com", "", "")
+	cxt, cancel := context.WithTimeout(context.TODO(), time.Second*5)
+	defer cancel()
+
+	err := notifier.Notify(cxt, suite.reminder)
+	suite.Nil(err, "error should be nil")
+	select {
+	case message := <-suite.server.messages:
+		suite.Contains(message, "MAIL FROM:<tasks@example.com>")
+		suite.Contains(message, "RCPT TO:<user1@example.com>")
+		suite.Contains(message, "Subject: Task overdue: Pay rent")
+	case <-time.After(time.Second * 5):
+		suite.Fail("no message was delivered")
+	}
+}
+
+func (suite *NotifierTestSuite) TestSMTPNotifier_NoEmail() {
+	notifier := infrastructure.NewSMTPNotifier(suite.server.listener.Addr().String(), "tasks@example.com", "", "")
+	suite.reminder.Email = ""
+
+	err := notifier.Notify(context.TODO(), suite.reminder)
+	suite.Nil(err, "reminders without an address are dropped")
+	suite.Empty(suite.server.messages)
+}
+
+func (suite *NotifierTestSuite) TestReminderScheduler_Stop() {
+	reminderUC := new(mocks.ReminderUsecase)
+	scanned := make(chan struct{}, 10)
+	reminderUC.On("ScanReminders", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		scanned <- struct{}{}
+	}).Return([]domain.Reminder{}, nil)
+
+	scheduler := infrastructure.NewReminderScheduler(reminderUC, time.Millisecond*10)
+	scheduler.Start(context.TODO())
+	for i := 0; i < 2; i++ {
+		select {
+		case <-scanned:
+		case <-time.After(time.Second * 5):
+			suite.FailNow("the scheduler did not scan")
+		}
+	}
+	scheduler.Stop()
+	calls := len(reminderUC.Calls)
+	time.Sleep(time.Millisecond * 50)
+	suite.Equal(calls, len(reminderUC.Calls), "no scans run after Stop returns")
+}
+
+func (suite *NotifierTestSuite) TestReminderScheduler_ContextCancelled() {
+	reminderUC := new(mocks.ReminderUsecase)
+	reminderUC.On("ScanReminders", mock.Anything, mock.Anything).Return([]domain.Reminder{}, nil)
+
+	cxt, cancel := context.WithCancel(context.TODO())
+	scheduler := infrastructure.NewReminderScheduler(reminderUC, time.Hour)
+	scheduler.Start(cxt)
+	cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		suite.Fail("the scheduler did not stop after its context was cancelled")
+	}
+}
+
+func TestNotifierTestSuite(t *testing.T) {
+	suite.Run(t, new(NotifierTestSuite))
+}
@@ -0,0 +1,360 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	STUB_CLIENT_ID     = "task-manager"
+	STUB_CLIENT_SECRET = "stub-secret"
+	STUB_REDIRECT_URL  = "http://localhost:8080/api/v1/user/oidc/callback"
+)
+
+// an OpenID Connect provider that logs everyone in as the same user
+type stubIssuer struct {
+	server *httptest.Server
+	// the published key, which also signs the ID tokens unless signingKey is set
+	key        *rsa.PrivateKey
+	signingKey *rsa.PrivateKey
+	// names itself this issuer in its metadata when set
+	claimedIssuer string
+	// changes the claims of the next ID tokens
+	tweak func(claims jwt.MapClaims)
+	mutex sync.Mutex
+	codes map[string]url.Values
+}
+
+func newStubIssuer(key *rsa.PrivateKey) *stubIssuer {
+	stub := &stubIssuer{key: key, codes: map[string]url.Values{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := stub.server.URL
+		if stub.claimedIssuer != "" {
+			issuer = stub.claimedIssuer
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                           issuer,
+			"authorization_endpoint":           stub.server.URL + "/authorize",
+			"token_endpoint":                   stub.server.URL + "/token",
+			"jwks_uri":                         stub.server.URL + "/jwks",
+			"code_challenge_methods_supported": []string{"S256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(infrastructure.JWKSet{Keys: []infrastructure.JWK{{
+			KeyType:   "RSA",
+			KeyID:     "stub-key",
+			Use:       "sig",
+			Algorithm: "RS256",
+			Modulus:   base64.RawURLEncoding.EncodeToString(stub.key.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(stub.key.E)).Bytes()),
+		}}})
+	})
+	// logs the user in at once and redirects back with a code
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("client_id") != STUB_CLIENT_ID || query.Get("code_challenge_method") != "S256" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		code := newStubCode()
+		stub.mutex.Lock()
+		stub.codes[code] = query
+		stub.mutex.Unlock()
+		http.Redirect(w, r, query.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(query.Get("state")), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		stub.mutex.Lock()
+		authorization, found := stub.codes[r.PostForm.Get("code")]
+		delete(stub.codes, r.PostForm.Get("code"))
+		stub.mutex.Unlock()
+		clientID, secret, _ := r.BasicAuth()
+		challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !found || clientID != STUB_CLIENT_ID || secret != STUB_CLIENT_SECRET ||
+			r.PostForm.Get("redirect_uri") != authorization.Get("redirect_uri") ||
+			base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":                stub.server.URL,
+			"sub":                "sso-user-1",
+			"aud":                STUB_CLIENT_ID,
+			"exp":                time.Now().Add(5 * time.Minute).Unix(),
+			"iat":                time.Now().Unix(),
+			"nonce":              authorization.Get("nonce"),
+			"email":              "John.Doe@example.com",
+			"email_verified":     true,
+			"preferred_username": "johndoe",
+		}
+		if stub.tweak != nil {
+			stub.tweak(claims)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "stub-key"
+		signingKey := stub.key
+		if stub.signingKey != nil {
+			signingKey = stub.signingKey
+		}
+		idToken, _ := token.SignedString(signingKey)
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "opaque", "token_type": "Bearer", "id_token": idToken})
+	})
+	stub.server = httptest.NewServer(mux)
+	return stub
+}
+
+func newStubCode() string {
+	code := make([]byte, 16)
+	rand.Read(code)
+	return base64.RawURLEncoding.EncodeToString(code)
+}
+
+type OIDCProviderTestSuite struct {
+	suite.Suite
+	key      *rsa.PrivateKey
+	otherKey *rsa.PrivateKey
+	stub     *stubIssuer
+	provider *infrastructure.OIDCProvider
+}
+
+func (suite *OIDCProviderTestSuite) SetupSuite() {
+	var err error
+	suite.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().Nil(err)
+	suite.otherKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().Nil(err)
+	os.Setenv("SIGNITURE_SECRET", "test_secret")
+	os.Setenv("SIGNITURE_TIME_DURATION", "900")
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *OIDCProviderTestSuite) SetupTest() {
+	suite.stub = newStubIssuer(suite.key)
+	suite.provider = suite.newProvider()
+}
+
+func (suite *OIDCProviderTestSuite) TearDownTest() {
+	suite.stub.server.Close()
+}
+
+func (suite *OIDCProviderTestSuite) newProvider() *infrastructure.OIDCProvider {
+	return infrastructure.NewOIDCProvider(infrastructure.OIDCConfig{
+		Issuer:       suite.stub.server.URL,
+		ClientID:     STUB_CLIENT_ID,
+		ClientSecret: STUB_CLIENT_SECRET,
+		RedirectURL:  STUB_REDIRECT_URL,
+	}, suite.stub.server.Client())
+}
+
+// logs in at the stub and returns the code it redirected back with
+func (suite *OIDCProviderTestSuite) authorize(nonce string, codeVerifier string) string {
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	authURL, err := suite.provider.AuthCodeURL(context.TODO(), "state_1", nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+	suite.Require().Nil(err)
+	return suite.followAuthorize(authURL).Get("code")
+}
+
+// the query the stub redirects back with
+func (suite *OIDCProviderTestSuite) followAuthorize(authURL string) url.Values {
+	client := *suite.stub.server.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Get(authURL)
+	suite.Require().Nil(err)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusFound, resp.StatusCode)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	suite.Require().Nil(err)
+	return location.Query()
+}
+
+func (suite *OIDCProviderTestSuite) TestAuthCodeURL() {
+	authURL, err := suite.provider.AuthCodeURL(context.TODO(), "state_1", "nonce_1", "challenge_1")
+	suite.Require().Nil(err)
+	parsed, _ := url.Parse(authURL)
+	suite.Equal(suite.stub.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	query := parsed.Query()
+	suite.Equal("code", query.Get("response_type"))
+	suite.Equal(STUB_CLIENT_ID, query.Get("client_id"))
+	suite.Equal(STUB_REDIRECT_URL, query.Get("redirect_uri"))
+	suite.Equal("openid email profile", query.Get("scope"))
+	suite.Equal("state_1", query.Get("state"))
+	suite.Equal("nonce_1", query.Get("nonce"))
+	suite.Equal("challenge_1", query.Get("code_challenge"))
+	suite.Equal("S256", query.Get("code_challenge_method"))
+}
+
+func (suite *OIDCProviderTestSuite) TestExchange() {
+	code := suite.authorize("nonce_1", "verifier_of_at_least_forty_three_characters_long")
+	identity, err := suite.provider.Exchange(context.TODO(), code, "verifier_of_at_least_forty_three_characters_long", "nonce_1")
+	suite.Require().Nil(err)
+	suite.Equal(domain.ExternalIdentity{
+		Issuer:        suite.stub.server.URL,
+		Subject:       "sso-user-1",
+		Email:         "John.Doe@example.com",
+		EmailVerified: true,
+		Username:      "johndoe",
+	}, identity)
+
+	_, err = suite.provider.Exchange(context.TODO(), code, "verifier_of_at_least_forty_three_characters_long", "nonce_1")
+	suite.NotNil(err, "A code should only be redeemed once")
+	suite.False(errors.Is(err, infrastructure.ErrOIDCUnavailable))
+}
+
+func (suite *OIDCProviderTestSuite) TestExchangeRefusesWrongVerifier() {
+	code := suite.authorize("nonce_1", "verifier_of_at_least_forty_three_characters_long")
+	_, err := suite.provider.Exchange(context.TODO(), code, "another_verifier_of_at_least_forty_three_chars", "nonce_1")
+	suite.NotNil(err, "The provider should refuse a code verifier that does not match the challenge")
+	suite.False(errors.Is(err, infrastructure.ErrOIDCUnavailable))
+}
+
+func (suite *OIDCProviderTestSuite) TestExchangeRefusesInvalidIDTokens() {
+	tweaks := map[string]func(claims jwt.MapClaims){
+		"wrong nonce":    func(claims jwt.MapClaims) { claims["nonce"] = "nonce_2" },
+		"wrong audience": func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+		"wrong issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":      func(claims jwt.MapClaims) { delete(claims, "exp") },
+		"no subject":     func(claims jwt.MapClaims) { delete(claims, "sub") },
+		"other party": func(claims jwt.MapClaims) {
+			claims["aud"] = []string{STUB_CLIENT_ID, "another-client"}
+			claims["azp"] = "another-client"
+		},
+		"shared audience": func(claims jwt.MapClaims) { claims["aud"] = []string{STUB_CLIENT_ID, "another-client"} },
+	}
+	for name, tweak := range tweaks {
+		suite.stub.tweak = tweak
+		code := suite.authorize("nonce_1", "verifier_of_at_least_forty_three_characters_long")
+		_, err := suite.provider.Exchange(context.TODO(), code, "verifier_of_at_least_forty_three_characters_long", "nonce_1")
+		suite.NotNil(err, name)
+		suite.False(errors.Is(err, infrastructure.ErrOIDCUnavailable), name)
+	}
+
+	// the audience may name other clients when the token was issued to us
+	suite.stub.tweak = func(claims jwt.MapClaims) {
+		claims["aud"] = []string{STUB_CLIENT_ID, "another-client"}
+		claims["azp"] = STUB_CLIENT_ID
+	}
+	code := suite.authorize("nonce_1", "verifier_of_at_least_forty_three_characters_long")
+	_, err := suite.provider.Exchange(context.TODO(), code, "verifier_of_at_least_forty_three_characters_long", "nonce_1")
+	suite.Nil(err)
+}
+
+func (suite *OIDCProviderTestSuite) TestExchangeRefusesForeignSignatures() {
+	suite.stub.signingKey = suite.otherKey
+	code := suite.authorize("nonce_1", "verifier_of_at_least_forty_three_characters_long")
+	_, err := suite.provider.Exchange(context.TODO(), code, "verifier_of_at_least_forty_three_characters_long", "nonce_1")
+	suite.NotNil(err, "Tokens that the published keys do not verify should be refused")
+	suite.False(errors.Is(err, infrastructure.ErrOIDCUnavailable))
+}
+
+func (suite *OIDCProviderTestSuite) TestDiscoveryRefusesOtherIssuer() {
+	suite.stub.claimedIssuer = "https://evil.example.com"
+	_, err := suite.provider.AuthCodeURL(context.TODO(), "state_1", "nonce_1", "challenge_1")
+	suite.NotNil(err, "The metadata should name the configured issuer")
+	suite.True(errors.Is(err, infrastructure.ErrOIDCUnavailable))
+}
+
+func (suite *OIDCProviderTestSuite) TestUnreachableProvider() {
+	suite.stub.server.Close()
+	_, err := suite.provider.AuthCodeURL(context.TODO(), "state_1", "nonce_1", "challenge_1")
+	suite.True(errors.Is(err, infrastructure.ErrOIDCUnavailable))
+}
+
+// the whole flow through the endpoints, the usecase and the provider against the stub
+func (suite *OIDCProviderTestSuite) TestLoginFlow() {
+	userRepository := new(mocks.UserRepository)
+	userUC := usecases.NewUserUsecase(userRepository, 5*time.Second)
+	userUC.SetIdentityProvider(suite.provider, "org_1")
+	controller := controllers.NewController(new(mocks.TaskUsecase), &userUC)
+	router := gin.New()
+	router.GET("/api/v1/user/oidc/login", controller.GetOIDCLogin)
+	router.GET("/api/v1/user/oidc/callback", controller.GetOIDCCallback)
+	userRepository.On("FetchUserByIdentity", mock.Anything, suite.stub.server.URL, "sso-user-1").Return(
+		domain.User{ID: "user_1", Username: "johndoe", Role: "user", OrgID: "org_1"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/user/oidc/login", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	suite.Require().Equal(http.StatusFound, resp.Code)
+	cookies := resp.Result().Cookies()
+	suite.Require().Len(cookies, 1)
+	suite.Equal(controllers.OIDC_LOGIN_COOKIE, cookies[0].Name)
+	suite.True(cookies[0].HttpOnly)
+	suite.Equal("/api/v1/user/oidc", cookies[0].Path)
+	suite.Equal(http.SameSiteLaxMode, cookies[0].SameSite)
+
+	callback := suite.followAuthorize(resp.Header().Get("Location"))
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/user/oidc/callback?"+callback.Encode(), nil)
+	req.AddCookie(cookies[0])
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	suite.Require().Equal(http.StatusOK, resp.Code, resp.Body.String())
+	var session domain.Session
+	suite.Require().Nil(json.Unmarshal(resp.Body.Bytes(), &session))
+	token, err := infrastructure.ParseJWTToken(session.Token)
+	suite.Require().Nil(err)
+	suite.Equal("johndoe", token.Claims.(jwt.MapClaims)["username"])
+	suite.Equal("org_1", token.Claims.(jwt.MapClaims)["org"])
+
+	// the login cookie is cleared, so the code cannot be replayed through it
+	cleared := resp.Result().Cookies()
+	suite.Require().Len(cleared, 1)
+	suite.True(cleared[0].MaxAge < 0)
+}
+
+func (suite *OIDCProviderTestSuite) TestCallbackRefusals() {
+	userUC := usecases.NewUserUsecase(new(mocks.UserRepository), 5*time.Second)
+	userUC.SetIdentityProvider(suite.provider, "")
+	controller := controllers.NewController(new(mocks.TaskUsecase), &userUC)
+	router := gin.New()
+	router.GET("/api/v1/user/oidc/callback", controller.GetOIDCCallback)
+
+	requests := []struct {
+		query  string
+		cookie string
+		status int
+	}{
+		{query: "code=code_1&state=state_1", status: http.StatusBadRequest},
+		{query: "code=code_1&state=state_1", cookie: "not base64!", status: http.StatusBadRequest},
+		{query: "error=access_denied&state=state_1", status: http.StatusUnauthorized},
+	}
+	for _, request := range requests {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/user/oidc/callback?"+request.query, nil)
+		if request.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: controllers.OIDC_LOGIN_COOKIE, Value: request.cookie})
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		suite.Equal(request.status, resp.Code, request.query)
+	}
+}
+
+func TestOIDCProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCProviderTestSuite))
+}
@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type oidcUsecaseSuite struct {
+	suite.Suite
+	userRepository *mocks.UserRepository
+	identities     *mocks.IdentityProvider
+	auditLog       *mocks.AuditRecorder
+	usecase        domain.UserUsecase
+	login          domain.OIDCLogin
+	identity       domain.ExternalIdentity
+	notFound       *domain.UserError
+}
+
+func (suite *oidcUsecaseSuite) SetupTest() {
+	os.Setenv("SIGNITURE_TIME_DURATION", "900")
+	os.Setenv("SIGNITURE_SECRET", "mysecretkey")
+	suite.userRepository = new(mocks.UserRepository)
+	suite.identities = new(mocks.IdentityProvider)
+	suite.auditLog = new(mocks.AuditRecorder)
+	suite.auditLog.On("Record", mock.Anything, mock.Anything).Return()
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	userUC.SetIdentityProvider(suite.identities, "org_1")
+	userUC.SetAuditLog(suite.auditLog)
+	suite.usecase = userUC
+	suite.login = domain.OIDCLogin{State: "state_1", Nonce: "nonce_1", CodeVerifier: "verifier_1"}
+	suite.identity = domain.ExternalIdentity{
+		Issuer:        "https://sso.example.com",
+		Subject:       "subject_1",
+		Email:         "John.Doe@Example.com",
+		EmailVerified: true,
+		Username:      "johndoe",
+	}
+	suite.notFound = &domain.UserError{Message: "User not found", Code: http.StatusNotFound}
+}
+
+// the identity provider vouches for the identity, which no user is linked to yet
+func (suite *oidcUsecaseSuite) exchange(identity domain.ExternalIdentity) {
+	suite.identities.On("Exchange", mock.Anything, "code_1", "verifier_1", "nonce_1").Return(identity, nil)
+	suite.userRepository.On("FetchUserByIdentity", mock.Anything, identity.Issuer, identity.Subject).Return(domain.User{}, suite.notFound)
+}
+
+// queries for the identity are scoped to the organization of the provider
+func inOrg(orgID string) interface{} {
+	return mock.MatchedBy(func(cxt context.Context) bool {
+		return cxt.Value(infrastructure.CONTEXT_ORG) == orgID
+	})
+}
+
+func (suite *oidcUsecaseSuite) TestBeginLogin() {
+	var challenge string
+	suite.identities.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		challenge = args.String(3)
+	}).Return("https://sso.example.com/authorize?state=x", nil)
+
+	login, err := suite.usecase.BeginOIDCLogin(context.TODO())
+	suite.Require().Nil(err)
+	suite.Equal("https://sso.example.com/authorize?state=x", login.URL)
+	suite.Len(login.CodeVerifier, 43, "RFC 7636 asks for 43 to 128 characters")
+	hash := sha256.Sum256([]byte(login.CodeVerifier))
+	suite.Equal(base64.RawURLEncoding.EncodeToString(hash[:]), challenge)
+	suite.identities.AssertCalled(suite.T(), "AuthCodeURL", mock.Anything, login.State, login.Nonce, challenge)
+
+	other, _ := suite.usecase.BeginOIDCLogin(context.TODO())
+	suite.NotEqual(login.State, other.State)
+	suite.NotEqual(login.Nonce, other.Nonce)
+	suite.NotEqual(login.CodeVerifier, other.CodeVerifier)
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteRefusesOtherState() {
+	_, err := suite.usecase.CompleteOIDCLogin(context.TODO(), "code_1", "state_2", suite.login)
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.identities.AssertNotCalled(suite.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteLogsInLinkedUser() {
+	suite.identities.On("Exchange", mock.Anything, "code_1", "verifier_1", "nonce_1").Return(suite.identity, nil)
+	suite.userRepository.On("FetchUserByIdentity", inOrg("org_1"), "https://sso.example.com", "subject_1").Return(
+		domain.User{ID: "user_1", Username: "jdoe", Role: "admin", OrgID: "org_1"}, nil)
+
+	session, err := suite.usecase.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+	suite.Require().Nil(err)
+	suite.NotEmpty(session.Token)
+	suite.userRepository.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+	suite.auditLog.AssertCalled(suite.T(), "Record", mock.Anything, mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AUDIT_USER_LOGIN && entry.Actor == "jdoe"
+	}))
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteLinksUserByVerifiedEmail() {
+	suite.exchange(suite.identity)
+	existing := domain.User{ID: "user_1", Username: "jdoe", Role: "user", OrgID: "org_1", Email: "john.doe@example.com"}
+	linked := existing
+	linked.IdentityIssuer, linked.IdentitySubject = "https://sso.example.com", "subject_1"
+	suite.userRepository.On("FetchUserByEmail", inOrg("org_1"), "john.doe@example.com").Return(existing, nil)
+	suite.userRepository.On("LinkIdentity", inOrg("org_1"), "user_1", "https://sso.example.com", "subject_1").Return(linked, nil)
+
+	_, err := suite.usecase.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+	suite.Require().Nil(err)
+	suite.userRepository.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+	suite.auditLog.AssertCalled(suite.T(), "Record", mock.Anything, mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AUDIT_USER_LINK && entry.TargetID == "user_1"
+	}))
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteRefusesUserLinkedElsewhere() {
+	suite.exchange(suite.identity)
+	suite.userRepository.On("FetchUserByEmail", mock.Anything, "john.doe@example.com").Return(domain.User{ID: "user_1"}, nil)
+	suite.userRepository.On("LinkIdentity", mock.Anything, "user_1", mock.Anything, mock.Anything).Return(domain.User{}, suite.notFound)
+
+	_, err := suite.usecase.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusConflict, err.Code)
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteProvisionsUser() {
+	suite.exchange(suite.identity)
+	var created domain.User
+	suite.userRepository.On("FetchUserByEmail", mock.Anything, "john.doe@example.com").Return(domain.User{}, suite.notFound)
+	suite.userRepository.On("FetchUserCount", inOrg("org_1")).Return(3, nil)
+	// the preferred username is taken by someone else, so the user is named after their email
+	suite.userRepository.On("CreateUser", inOrg("org_1"), mock.MatchedBy(func(user domain.User) bool { return user.Username == "johndoe" })).Return(
+		"", &domain.UserError{Message: "User already exists", Code: http.StatusConflict})
+	suite.userRepository.On("CreateUser", inOrg("org_1"), mock.MatchedBy(func(user domain.User) bool { return user.Username == "john.doe@example.com" })).Run(func(args mock.Arguments) {
+		created = args.Get(1).(domain.User)
+	}).Return("user_2", nil)
+
+	_, err := suite.usecase.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+	suite.Require().Nil(err)
+	suite.Equal(domain.User{
+		Username:        "john.doe@example.com",
+		Role:            "user",
+		OrgID:           "org_1",
+		Email:           "john.doe@example.com",
+		IdentityIssuer:  "https://sso.example.com",
+		IdentitySubject: "subject_1",
+	}, created)
+	suite.Empty(created.Password, "Provisioned users cannot log in with a password")
+	suite.auditLog.AssertCalled(suite.T(), "Record", mock.Anything, mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AUDIT_USER_CREATE && entry.TargetID == "user_2"
+	}))
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteNeverLinksUnverifiedEmail() {
+	identity := suite.identity
+	identity.EmailVerified = false
+	suite.exchange(identity)
+	suite.userRepository.On("FetchUserCount", mock.Anything).Return(0, nil)
+	suite.userRepository.On("CreateUser", mock.Anything, mock.Anything).Return("user_2", nil)
+
+	_, err := suite.usecase.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+	suite.Require().Nil(err)
+	suite.userRepository.AssertNotCalled(suite.T(), "FetchUserByEmail", mock.Anything, mock.Anything)
+	suite.userRepository.AssertCalled(suite.T(), "CreateUser", mock.Anything, mock.MatchedBy(func(user domain.User) bool {
+		return user.Email == "" && user.Role == "admin"
+	}))
+}
+
+func (suite *oidcUsecaseSuite) TestCompleteMapsProviderErrors() {
+	errs := map[error]int{
+		fmt.Errorf("%w: connection refused", infrastructure.ErrOIDCUnavailable): http.StatusBadGateway,
+		errors.New("Invalid ID token: the nonce does not match"):                http.StatusUnauthorized,
+	}
+	for errExchange, status := range errs {
+		identities := new(mocks.IdentityProvider)
+		identities.On("Exchange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(domain.ExternalIdentity{}, errExchange)
+		userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+		userUC.SetIdentityProvider(identities, "")
+
+		_, err := userUC.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+		suite.Require().NotNil(err)
+		suite.Equal(status, err.Code)
+	}
+}
+
+func (suite *oidcUsecaseSuite) TestWithoutProvider() {
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	_, err := userUC.BeginOIDCLogin(context.TODO())
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusNotImplemented, err.Code)
+	_, err = userUC.CompleteOIDCLogin(context.TODO(), "code_1", "state_1", suite.login)
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusNotImplemented, err.Code)
+}
+
+func TestOIDCUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(oidcUsecaseSuite))
+}
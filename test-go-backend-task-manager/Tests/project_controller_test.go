@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type projectControllerSuite struct {
+	suite.Suite
+	projectUsecase *mocks.ProjectUsecase
+	taskUsecase    *mocks.TaskUsecase
+	userUsecase    *mocks.UserUsecase
+	controller     controllers.ProjectController
+	router         *gin.Engine
+	user           domain.User
+}
+
+func (suite *projectControllerSuite) SetupTest() {
+	suite.projectUsecase = new(mocks.ProjectUsecase)
+	suite.taskUsecase = new(mocks.TaskUsecase)
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewProjectController(suite.projectUsecase, suite.userUsecase)
+	taskController := controllers.NewController(suite.taskUsecase, suite.userUsecase)
+	suite.user = domain.User{ID: "user_1", Username: "abebe", Role: "user"}
+	suite.userUsecase.On("GetUserByUsername", mock.Anything, "abebe").Return(suite.user, nil)
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+	suite.router.Use(func(cxt *gin.Context) {
+		cxt.Set(infrastructure.CONTEXT_USERNAME, "abebe")
+	})
+	suite.router.GET("/projects", suite.controller.GetProjects)
+	suite.router.POST("/projects", suite.controller.PostProject)
+	suite.router.PUT("/projects/:pid/members/:userid", suite.controller.PutProjectMember)
+	suite.router.DELETE("/projects/:pid", suite.controller.DeleteProject)
+	viewer := suite.router.Group("/projects/:pid/tasks", suite.controller.RequireProjectRole(domain.PROJECT_ROLE_VIEWER))
+	editor := suite.router.Group("/projects/:pid/tasks", suite.controller.RequireProjectRole(domain.PROJECT_ROLE_EDITOR))
+	viewer.GET("/:id", taskController.GetTaskByID)
+	editor.DELETE("/:id", taskController.DeleteTask)
+}
+
+func (suite *projectControllerSuite) TestPostProject() {
+	created := domain.Project{ID: "project_1", Name: "Launch", OwnerID: "user_1", Members: []domain.ProjectMember{{UserID: "user_1", Role: domain.PROJECT_ROLE_OWNER}}}
+	suite.projectUsecase.On("CreateProject", mock.Anything, domain.Project{Name: "Launch", Description: "Q3"}, suite.user).Return(created, nil)
+
+	body, _ := json.Marshal(map[string]string{"name": "Launch", "description": "Q3"})
+	req, _ := http.NewRequest(http.MethodPost, "/projects", bytes.NewBuffer(body))
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var project domain.Project
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &project))
+	suite.Equal(http.StatusCreated, resp.Code)
+	suite.Equal(created, project)
+}
+
+func (suite *projectControllerSuite) TestGetProjects() {
+	suite.projectUsecase.On("GetProjects", mock.Anything, suite.user).Return([]domain.Project{{ID: "project_1", Name: "Launch"}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/projects", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var projectsResponse struct {
+		Projects []domain.Project `json:"projects"`
+	}
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &projectsResponse))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(1, len(projectsResponse.Projects))
+}
+
+func (suite *projectControllerSuite) TestPutProjectMember() {
+	member := domain.ProjectMember{UserID: "user_2", Role: domain.PROJECT_ROLE_EDITOR}
+	suite.projectUsecase.On("SetMember", mock.Anything, "project_1", member, suite.user).Return(domain.Project{ID: "project_1"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/projects/project_1/members/user_2", bytes.NewBufferString(`{"role":"editor"}`))
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+}
+
+func (suite *projectControllerSuite) TestDeleteProject_NotEmpty() {
+	suite.projectUsecase.On("DeleteProject", mock.Anything, "project_1", suite.user).Return(&domain.TaskError{Message: "Project still has tasks", Code: http.StatusConflict})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/projects/project_1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusConflict, resp.Code)
+}
+
+func (suite *projectControllerSuite) TestRequireProjectRole_ScopesTasks() {
+	suite.projectUsecase.On("Authorize", mock.Anything, "project_1", suite.user, domain.PROJECT_ROLE_VIEWER).Return(domain.Project{ID: "project_1"}, nil)
+	inProject := mock.MatchedBy(func(cxt *gin.Context) bool {
+		return cxt.GetString(infrastructure.CONTEXT_PROJECT) == "project_1"
+	})
+	suite.taskUsecase.On("GetTaskByID", inProject, "task_1").Return(domain.Task{ID: "task_1", ProjectID: "project_1", Version: 2}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/projects/project_1/tasks/task_1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *projectControllerSuite) TestRequireProjectRole_Rejected() {
+	suite.projectUsecase.On("Authorize", mock.Anything, "project_1", suite.user, domain.PROJECT_ROLE_EDITOR).Return(domain.Project{}, &domain.TaskError{Message: "This requires the editor role in the project", Code: http.StatusForbidden})
+	suite.projectUsecase.On("Authorize", mock.Anything, "project_2", suite.user, domain.PROJECT_ROLE_VIEWER).Return(domain.Project{}, &domain.TaskError{Message: "Project not found", Code: http.StatusNotFound})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/projects/project_1/tasks/task_1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusForbidden, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/projects/project_2/tasks/task_1", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusNotFound, resp.Code)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "DeleteTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "GetTaskByID", mock.Anything, mock.Anything)
+}
+
+func (suite *projectControllerSuite) TestDeleteTask_OnOwnAuthority() {
+	suite.projectUsecase.On("Authorize", mock.Anything, "project_1", suite.user, domain.PROJECT_ROLE_EDITOR).Return(domain.Project{ID: "project_1"}, nil)
+	suite.taskUsecase.On("DeleteTask", mock.Anything, "task_1", suite.user, 0).Return(domain.Task{ID: "task_1"}, nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/projects/project_1/tasks/task_1", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+}
+
+func TestProjectControllerSuite(t *testing.T) {
+	suite.Run(t, new(projectControllerSuite))
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type projectUsecaseSuite struct {
+	suite.Suite
+	projectRepository *mocks.ProjectRepository
+	taskRepository    *mocks.TaskRepository
+	userRepository    *mocks.UserRepository
+	usecase           domain.ProjectUsecase
+	project           domain.Project
+	owner             domain.User
+	editor            domain.User
+	viewer            domain.User
+	stranger          domain.User
+}
+
+func (suite *projectUsecaseSuite) SetupTest() {
+	suite.projectRepository = new(mocks.ProjectRepository)
+	suite.taskRepository = new(mocks.TaskRepository)
+	suite.userRepository = new(mocks.UserRepository)
+	projectUC := usecases.NewProjectUsecase(suite.projectRepository, suite.taskRepository, suite.userRepository, time.Second*2)
+	suite.usecase = &projectUC
+
+	suite.owner = domain.User{ID: "user_1", Username: "abebe", Role: "user"}
+	suite.editor = domain.User{ID: "user_2", Username: "kebede", Role: "user"}
+	suite.viewer = domain.User{ID: "user_3", Username: "almaz", Role: "user"}
+	suite.stranger = domain.User{ID: "user_4", Username: "tigist", Role: "user"}
+	suite.project = domain.Project{ID: "project_1", Name: "Launch", OwnerID: "user_1", Members: []domain.ProjectMember{
+		{UserID: "user_1", Role: domain.PROJECT_ROLE_OWNER},
+		{UserID: "user_2", Role: domain.PROJECT_ROLE_EDITOR},
+		{UserID: "user_3", Role: domain.PROJECT_ROLE_VIEWER},
+	}}
+	suite.projectRepository.On("FetchProjectByID", mock.Anything, "project_1").Return(suite.project, nil)
+}
+
+func (suite *projectUsecaseSuite) TestAuthorize() {
+	for _, allowed := range []struct {
+		user domain.User
+		role string
+	}{
+		{suite.viewer, domain.PROJECT_ROLE_VIEWER},
+		{suite.editor, domain.PROJECT_ROLE_VIEWER},
+		{suite.editor, domain.PROJECT_ROLE_EDITOR},
+		{suite.owner, domain.PROJECT_ROLE_OWNER},
+		{domain.User{ID: "admin_1", Role: "admin"}, domain.PROJECT_ROLE_OWNER},
+	} {
+		project, err := suite.usecase.Authorize(context.TODO(), "project_1", allowed.user, allowed.role)
+		suite.Nil(err, "%s should have the %s role", allowed.user.Username, allowed.role)
+		suite.Equal(suite.project, project)
+	}
+
+	_, err := suite.usecase.Authorize(context.TODO(), "project_1", suite.viewer, domain.PROJECT_ROLE_EDITOR)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusForbidden, err.Code)
+	_, err = suite.usecase.Authorize(context.TODO(), "project_1", suite.stranger, domain.PROJECT_ROLE_VIEWER)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusNotFound, err.Code, "the project is hidden from non-members")
+}
+
+func (suite *projectUsecaseSuite) TestCreateProject() {
+	suite.projectRepository.On("CreateProject", mock.Anything, mock.MatchedBy(func(project domain.Project) bool {
+		return project.Name == "Launch" && project.OwnerID == "user_1" &&
+			len(project.Members) == 1 && project.Members[0] == domain.ProjectMember{UserID: "user_1", Role: domain.PROJECT_ROLE_OWNER}
+	})).Return("project_1", nil)
+
+	project, err := suite.usecase.CreateProject(context.TODO(), domain.Project{Name: " Launch ", OwnerID: "someone_else"}, suite.owner)
+	suite.Nil(err, "error should be nil")
+	suite.Equal("project_1", project.ID)
+
+	_, err = suite.usecase.CreateProject(context.TODO(), domain.Project{Name: "  "}, suite.owner)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusBadRequest, err.Code)
+}
+
+func (suite *projectUsecaseSuite) TestGetProjects() {
+	suite.projectRepository.On("FetchProjects", mock.Anything, "user_2").Return([]domain.Project{suite.project}, nil)
+	suite.projectRepository.On("FetchProjects", mock.Anything, "").Return([]domain.Project{suite.project, {ID: "project_2"}}, nil)
+
+	projects, err := suite.usecase.GetProjects(context.TODO(), suite.editor)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(projects))
+	projects, err = suite.usecase.GetProjects(context.TODO(), domain.User{ID: "admin_1", Role: "admin"})
+	suite.Nil(err, "error should be nil")
+	suite.Equal(2, len(projects), "admins see every project")
+}
+
+func (suite *projectUsecaseSuite) TestSetMember() {
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_4").Return(suite.stranger, nil)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_9").Return(domain.User{}, &domain.UserError{Message: "User not found", Code: http.StatusNotFound})
+	member := domain.ProjectMember{UserID: "user_4", Role: domain.PROJECT_ROLE_EDITOR}
+	suite.projectRepository.On("SetMember", mock.Anything, "project_1", member).Return(suite.project, nil)
+
+	_, err := suite.usecase.SetMember(context.TODO(), "project_1", member, suite.owner)
+	suite.Nil(err, "error should be nil")
+
+	for _, rejected := range []struct {
+		member domain.ProjectMember
+		user   domain.User
+		code   int
+	}{
+		{member, suite.editor, http.StatusForbidden},
+		{domain.ProjectMember{UserID: "user_4", Role: "admin"}, suite.owner, http.StatusBadRequest},
+		{domain.ProjectMember{UserID: "user_1", Role: domain.PROJECT_ROLE_VIEWER}, suite.owner, http.StatusConflict},
+		{domain.ProjectMember{UserID: "user_9", Role: domain.PROJECT_ROLE_VIEWER}, suite.owner, http.StatusNotFound},
+	} {
+		_, err := suite.usecase.SetMember(context.TODO(), "project_1", rejected.member, rejected.user)
+		suite.NotNil(err, "error should not be nil")
+		suite.Equal(rejected.code, err.Code, rejected.member)
+	}
+	suite.projectRepository.AssertNumberOfCalls(suite.T(), "SetMember", 1)
+}
+
+func (suite *projectUsecaseSuite) TestRemoveMember() {
+	suite.projectRepository.On("RemoveMember", mock.Anything, "project_1", mock.Anything).Return(suite.project, nil)
+
+	_, err := suite.usecase.RemoveMember(context.TODO(), "project_1", "user_3", suite.viewer)
+	suite.Nil(err, "members can leave")
+	_, err = suite.usecase.RemoveMember(context.TODO(), "project_1", "user_2", suite.owner)
+	suite.Nil(err, "owners can remove members")
+	_, err = suite.usecase.RemoveMember(context.TODO(), "project_1", "user_3", suite.editor)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusForbidden, err.Code)
+	_, err = suite.usecase.RemoveMember(context.TODO(), "project_1", "user_1", suite.owner)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusConflict, err.Code, "the owner cannot leave")
+	suite.projectRepository.AssertNumberOfCalls(suite.T(), "RemoveMember", 2)
+}
+
+func (suite *projectUsecaseSuite) TestDeleteProject() {
+	inProject := mock.MatchedBy(func(cxt context.Context) bool {
+		return cxt.Value(infrastructure.CONTEXT_PROJECT) == "project_1"
+	})
+	query := domain.TaskQuery{ProjectID: "project_1", SortOrder: 1, Limit: 1}
+	suite.taskRepository.On("FetchTasks", inProject, query).Return(domain.TaskPage{Tasks: []domain.Task{{ID: "task_1"}}}, nil).Once()
+
+	err := suite.usecase.DeleteProject(context.TODO(), "project_1", suite.owner)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(http.StatusConflict, err.Code, "projects with tasks are kept")
+
+	suite.taskRepository.On("FetchTasks", inProject, query).Return(domain.TaskPage{Tasks: []domain.Task{}}, nil)
+	suite.projectRepository.On("DeleteProject", mock.Anything, "project_1").Return(nil)
+	err = suite.usecase.DeleteProject(context.TODO(), "project_1", suite.owner)
+	suite.Nil(err, "error should be nil")
+	err = suite.usecase.DeleteProject(context.TODO(), "project_1", suite.editor)
+	suite.NotNil(err, "error should not be nil")
+	suite.projectRepository.AssertNumberOfCalls(suite.T(), "DeleteProject", 1)
+}
+
+func TestProjectUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(projectUsecaseSuite))
+}
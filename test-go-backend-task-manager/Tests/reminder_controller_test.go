@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type reminderControllerSuite struct {
+	suite.Suite
+	reminderUsecase *mocks.ReminderUsecase
+	userUsecase     *mocks.UserUsecase
+	controller      controllers.ReminderController
+	router          *gin.Engine
+}
+
+func (suite *reminderControllerSuite) SetupTest() {
+	suite.reminderUsecase = new(mocks.ReminderUsecase)
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewReminderController(suite.reminderUsecase, suite.userUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+
+	// stands in for AuthMiddleWare
+	suite.router.Use(func(cxt *gin.Context) {
+		if username := cxt.GetHeader("X-Username"); username != "" {
+			cxt.Set(infrastructure.CONTEXT_USERNAME, username)
+		}
+	})
+	suite.router.GET("/user/reminders", suite.controller.GetReminderPreference)
+	suite.router.PUT("/user/reminders", suite.controller.PutReminderPreference)
+	suite.userUsecase.On("GetUserByUsername", mock.Anything, "abebe").Return(domain.User{ID: "user_1", Username: "abebe"}, nil)
+}
+
+func (suite *reminderControllerSuite) TestGetReminderPreference() {
+	preference := domain.ReminderPreference{UserID: "user_1", Enabled: true, LeadMinutes: 60, Overdue: true}
+	suite.reminderUsecase.On("GetPreference", mock.Anything, "user_1").Return(preference, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/user/reminders", nil)
+	req.Header.Set("X-Username", "abebe")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var returned domain.ReminderPreference
+	err := json.Unmarshal(resp.Body.Bytes(), &returned)
+	suite.Nil(err)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(preference, returned)
+}
+
+func (suite *reminderControllerSuite) TestPutReminderPreference() {
+	preference := domain.ReminderPreference{UserID: "user_1", Enabled: true, LeadMinutes: 15, Email: "abebe@example.com"}
+	suite.reminderUsecase.On("UpdatePreference", mock.Anything, preference).Return(preference, nil)
+
+	// the user ID in the body is ignored in favour of the token's user
+	body := `{"userID": "someone_else", "enabled": true, "lead_minutes": 15, "email": "abebe@example.com"}`
+	req, _ := http.NewRequest(http.MethodPut, "/user/reminders", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Username", "abebe")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.reminderUsecase.AssertCalled(suite.T(), "UpdatePreference", mock.Anything, preference)
+}
+
+func (suite *reminderControllerSuite) TestGetReminderPreference_NoUser() {
+	req, _ := http.NewRequest(http.MethodGet, "/user/reminders", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+	suite.reminderUsecase.AssertNotCalled(suite.T(), "GetPreference", mock.Anything, mock.Anything)
+}
+
+func TestReminderControllerSuite(t *testing.T) {
+	suite.Run(t, new(reminderControllerSuite))
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type reminderUsecaseSuite struct {
+	suite.Suite
+	taskRepository     *mocks.TaskRepository
+	reminderRepository *mocks.ReminderRepository
+	notifier           *mocks.Notifier
+	usecase            domain.ReminderUsecase
+	now                time.Time
+}
+
+func (suite *reminderUsecaseSuite) SetupTest() {
+	suite.taskRepository = new(mocks.TaskRepository)
+	suite.reminderRepository = new(mocks.ReminderRepository)
+	suite.notifier = new(mocks.Notifier)
+	reminderUC := usecases.NewReminderUsecase(suite.taskRepository, suite.reminderRepository, suite.notifier, time.Second*2, usecases.DefaultWorkflow())
+	suite.usecase = &reminderUC
+	suite.now = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+}
+
+func (suite *reminderUsecaseSuite) expectDueTasks(tasks []domain.Task) {
+	suite.taskRepository.On("FetchDueTasks", mock.Anything, suite.now.Add(-usecases.OVERDUE_REMINDER_WINDOW), suite.now.Add(usecases.MAX_REMINDER_LEAD_MINUTES*time.Minute), []string{"done"}).Return(tasks, nil)
+}
+
+func (suite *reminderUsecaseSuite) TestScanReminders() {
+	suite.expectDueTasks([]domain.Task{
+		{ID: "overdue", UserID: "user_1", Title: "Pay rent", DueDate: suite.now.Add(-time.Hour)},
+		{ID: "soon", UserID: "user_1", Title: "Call mom", DueDate: suite.now.Add(30 * time.Minute)},
+		{ID: "later", UserID: "user_1", Title: "Renew passport", DueDate: suite.now.Add(2 * time.Hour)},
+		{ID: "quiet", UserID: "user_2", Title: "Water plants", DueDate: suite.now.Add(time.Minute)},
+		{ID: "unowned", Title: "Nobody's task", DueDate: suite.now.Add(time.Minute)},
+	})
+	suite.reminderRepository.On("FetchPreferences", mock.Anything, []string{"user_1", "user_2"}).Return([]domain.ReminderPreference{
+		{UserID: "user_1", Enabled: true, LeadMinutes: 60, Overdue: true, Email: "user1@example.com"},
+		{UserID: "user_2", Enabled: false},
+	}, nil)
+	suite.reminderRepository.On("MarkReminderSent", mock.Anything, mock.Anything).Return(true, nil)
+	suite.notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+
+	sent, err := suite.usecase.ScanReminders(context.TODO(), suite.now)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(2, len(sent), "only the overdue task and the task inside the lead time are reminded")
+	suite.Equal(domain.REMINDER_OVERDUE, sent[0].Kind)
+	suite.Equal(domain.REMINDER_DUE_SOON, sent[1].Kind)
+	suite.Equal("user1@example.com", sent[1].Email)
+	suite.notifier.AssertNumberOfCalls(suite.T(), "Notify", 2)
+}
+
+func (suite *reminderUsecaseSuite) TestScanReminders_DefaultPreference() {
+	suite.expectDueTasks([]domain.Task{{ID: "soon", UserID: "user_1", DueDate: suite.now.Add(59 * time.Minute)}})
+	suite.reminderRepository.On("FetchPreferences", mock.Anything, []string{"user_1"}).Return([]domain.ReminderPreference{}, nil)
+	suite.reminderRepository.On("MarkReminderSent", mock.Anything, mock.Anything).Return(true, nil)
+	suite.notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+
+	sent, err := suite.usecase.ScanReminders(context.TODO(), suite.now)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(sent), "users without a preference are reminded an hour ahead")
+}
+
+func (suite *reminderUsecaseSuite) TestScanReminders_SentOnce() {
+	suite.expectDueTasks([]domain.Task{{ID: "soon", UserID: "user_1", DueDate: suite.now.Add(time.Minute)}})
+	suite.reminderRepository.On("FetchPreferences", mock.Anything, []string{"user_1"}).Return([]domain.ReminderPreference{}, nil)
+	suite.reminderRepository.On("MarkReminderSent", mock.Anything, mock.Anything).Return(false, nil)
+
+	sent, err := suite.usecase.ScanReminders(context.TODO(), suite.now)
+	suite.Nil(err, "error should be nil")
+	suite.Empty(sent, "a reminder that was already sent is skipped")
+	suite.notifier.AssertNotCalled(suite.T(), "Notify", mock.Anything, mock.Anything)
+}
+
+func (suite *reminderUsecaseSuite) TestScanReminders_NotifyFailure() {
+	dueDate := suite.now.Add(time.Minute)
+	suite.expectDueTasks([]domain.Task{{ID: "soon", UserID: "user_1", DueDate: dueDate}})
+	suite.reminderRepository.On("FetchPreferences", mock.Anything, []string{"user_1"}).Return([]domain.ReminderPreference{}, nil)
+	suite.reminderRepository.On("MarkReminderSent", mock.Anything, mock.Anything).Return(true, nil)
+	suite.reminderRepository.On("UnmarkReminder", mock.Anything, mock.Anything).Return(nil)
+	suite.notifier.On("Notify", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+	sent, err := suite.usecase.ScanReminders(context.TODO(), suite.now)
+	suite.Nil(err, "a failed delivery does not fail the scan")
+	suite.Empty(sent)
+	suite.reminderRepository.AssertCalled(suite.T(), "UnmarkReminder", mock.Anything, fmt.Sprintf("soon:due_soon:%d", dueDate.Unix()))
+}
+
+func (suite *reminderUsecaseSuite) TestUpdatePreference_Negative() {
+	_, err := suite.usecase.UpdatePreference(context.TODO(), domain.ReminderPreference{UserID: "user_1", LeadMinutes: -5})
+	suite.NotNil(err, "negative lead times are rejected")
+	suite.Equal(400, err.Code)
+	suite.reminderRepository.AssertNotCalled(suite.T(), "UpsertPreference", mock.Anything, mock.Anything)
+}
+
+func (suite *reminderUsecaseSuite) TestUpdatePreference_Email() {
+	for _, invalid := range []string{"not-an-address", "user@example.com\r\nBcc: other@example.com", "a@b@c"} {
+		_, err := suite.usecase.UpdatePreference(context.TODO(), domain.ReminderPreference{UserID: "user_1", Email: invalid})
+		suite.NotNil(err, "%q should be rejected", invalid)
+		suite.Equal(400, err.Code)
+	}
+	suite.reminderRepository.AssertNotCalled(suite.T(), "UpsertPreference", mock.Anything, mock.Anything)
+
+	stored := domain.ReminderPreference{UserID: "user_1", Email: "jane@example.com"}
+	suite.reminderRepository.On("UpsertPreference", mock.Anything, stored).Return(stored, nil)
+	_, err := suite.usecase.UpdatePreference(context.TODO(), domain.ReminderPreference{UserID: "user_1", Email: "Jane Doe <jane@example.com>"})
+	suite.Nil(err, "error should be nil")
+	suite.reminderRepository.AssertCalled(suite.T(), "UpsertPreference", mock.Anything, stored)
+}
+
+func TestReminderUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(reminderUsecaseSuite))
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type reportControllerSuite struct {
+	suite.Suite
+	reportUsecase *mocks.ReportUsecase
+	controller    controllers.ReportController
+	router        *gin.Engine
+}
+
+func (suite *reportControllerSuite) SetupTest() {
+	suite.reportUsecase = new(mocks.ReportUsecase)
+	suite.controller = controllers.NewReportController(suite.reportUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+	suite.router.GET("/reports/burndown", suite.controller.GetBurndown)
+	suite.router.GET("/reports/velocity", suite.controller.GetVelocity)
+}
+
+func (suite *reportControllerSuite) TestGetBurndown() {
+	query := domain.ReportQuery{
+		Tag:  "sprint-12",
+		From: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC),
+	}
+	burndown := domain.Burndown{From: "2024-03-04", To: "2024-03-15", Unit: "points", Series: []domain.BurndownPoint{{Date: "2024-03-04", Remaining: 8, Scope: 8, Ideal: 7}}}
+	suite.reportUsecase.On("GetBurndown", mock.Anything, query).Return(burndown, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reports/burndown?tag=sprint-12&from=2024-03-04&to=2024-03-15", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var body domain.Burndown
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &body))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(burndown, body)
+
+	req, _ = http.NewRequest(http.MethodGet, "/reports/burndown?from=03/04/2024", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+}
+
+func (suite *reportControllerSuite) TestGetVelocity() {
+	query := domain.ReportQuery{Unit: "hours", IterationDays: 7, Iterations: 4}
+	suite.reportUsecase.On("GetVelocity", mock.Anything, query).Return(domain.Velocity{Unit: "hours", IterationDays: 7, Iterations: []domain.VelocityIteration{}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reports/velocity?unit=hours&iteration_days=7&iterations=4", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/reports/velocity?iterations=-1", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+}
+
+func TestReportControllerSuite(t *testing.T) {
+	suite.Run(t, new(reportControllerSuite))
+}
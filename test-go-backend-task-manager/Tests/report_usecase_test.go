@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type reportUsecaseSuite struct {
+	suite.Suite
+	taskRepository *mocks.TaskRepository
+	usecase        domain.ReportUsecase
+}
+
+func (suite *reportUsecaseSuite) SetupTest() {
+	suite.taskRepository = new(mocks.TaskRepository)
+	reportUC := usecases.NewReportUsecase(suite.taskRepository, time.Second*2, usecases.DefaultWorkflow())
+	suite.usecase = &reportUC
+}
+
+func reportDay(day int, hour int) time.Time {
+	return time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func (suite *reportUsecaseSuite) TestGetBurndown() {
+	query := domain.ReportQuery{ProjectID: "project_1", From: reportDay(4, 0), To: reportDay(8, 0)}
+	expected := query
+	expected.Unit = domain.ESTIMATE_POINTS
+	suite.taskRepository.On("FetchEstimatedTasks", mock.Anything, expected, []string{"done"}).Return([]domain.Task{
+		{ID: "t1", Estimate: 5, CreatedAt: reportDay(1, 9), CompletedAt: reportDay(5, 10)},
+		{ID: "t2", Estimate: 3, CreatedAt: reportDay(2, 9)},
+		{ID: "t3", Estimate: 2, CreatedAt: reportDay(6, 9), CompletedAt: reportDay(7, 16)},
+	}, nil)
+
+	burndown, err := suite.usecase.GetBurndown(context.TODO(), query)
+	suite.Nil(err, "error should be nil")
+	suite.Equal("2024-03-04", burndown.From)
+	suite.Equal("2024-03-07", burndown.To, "to is the last day of the burndown")
+	suite.Equal([]domain.BurndownPoint{
+		{Date: "2024-03-04", Remaining: 8, Completed: 0, Scope: 8, Ideal: 6},
+		{Date: "2024-03-05", Remaining: 3, Completed: 5, Scope: 8, Ideal: 4},
+		{Date: "2024-03-06", Remaining: 5, Completed: 5, Scope: 10, Ideal: 2},
+		{Date: "2024-03-07", Remaining: 3, Completed: 7, Scope: 10, Ideal: 0},
+	}, burndown.Series)
+}
+
+func (suite *reportUsecaseSuite) TestGetBurndown_InvalidQuery() {
+	_, err := suite.usecase.GetBurndown(context.TODO(), domain.ReportQuery{From: reportDay(8, 0), To: reportDay(4, 0)})
+	suite.NotNil(err, "from must be before to")
+	suite.Equal(http.StatusBadRequest, err.Code)
+
+	_, err = suite.usecase.GetBurndown(context.TODO(), domain.ReportQuery{From: reportDay(1, 0).AddDate(-2, 0, 0), To: reportDay(1, 0)})
+	suite.NotNil(err, "the range is limited")
+
+	_, err = suite.usecase.GetBurndown(context.TODO(), domain.ReportQuery{Unit: "days"})
+	suite.NotNil(err, "unknown units are rejected")
+	suite.taskRepository.AssertNotCalled(suite.T(), "FetchEstimatedTasks", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *reportUsecaseSuite) TestGetVelocity() {
+	query := domain.ReportQuery{Unit: domain.ESTIMATE_HOURS, To: reportDay(15, 0), IterationDays: 7, Iterations: 2}
+	expected := query
+	expected.From = reportDay(1, 0)
+	suite.taskRepository.On("FetchEstimatedTasks", mock.Anything, expected, []string{"done"}).Return([]domain.Task{
+		{ID: "t1", Estimate: 5, CompletedAt: reportDay(2, 10)},
+		{ID: "t2", Estimate: 3, CompletedAt: reportDay(7, 23)},
+		{ID: "t3", Estimate: 2, CompletedAt: reportDay(8, 0)},
+		{ID: "t4", Estimate: 8},
+	}, nil)
+
+	velocity, err := suite.usecase.GetVelocity(context.TODO(), query)
+	suite.Nil(err, "error should be nil")
+	suite.Equal([]domain.VelocityIteration{
+		{Start: "2024-03-01", End: "2024-03-07", Completed: 8, Tasks: 2},
+		{Start: "2024-03-08", End: "2024-03-14", Completed: 2, Tasks: 1},
+	}, velocity.Iterations)
+	suite.Equal(5.0, velocity.Average)
+	suite.Equal(domain.ESTIMATE_HOURS, velocity.Unit)
+
+	_, err = suite.usecase.GetVelocity(context.TODO(), domain.ReportQuery{Iterations: 100})
+	suite.NotNil(err, "the number of iterations is limited")
+}
+
+func TestReportUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(reportUsecaseSuite))
+}
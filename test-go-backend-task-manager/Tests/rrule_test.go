@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type RRuleTestSuite struct {
+	suite.Suite
+	// Monday 1 January 2024, 09:00 UTC
+	start time.Time
+}
+
+func (suite *RRuleTestSuite) SetupTest() {
+	suite.start = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+}
+
+func (suite *RRuleTestSuite) occurrences(rule string, after time.Time, n int) []string {
+	parsed, err := infrastructure.ParseRRule(rule)
+	suite.Nil(err, "rule should parse")
+	dates := []string{}
+	for _, occurrence := range parsed.After(suite.start, after, n) {
+		dates = append(dates, occurrence.Format("2006-01-02 15:04"))
+	}
+	return dates
+}
+
+func (suite *RRuleTestSuite) TestParseRRule_Invalid() {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240201",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		_, err := infrastructure.ParseRRule(rule)
+		suite.NotNil(err, "rule %q should be rejected", rule)
+	}
+}
+
+func (suite *RRuleTestSuite) TestDaily_Count() {
+	suite.Equal([]string{"2024-01-02 09:00", "2024-01-03 09:00"}, suite.occurrences("RRULE:FREQ=DAILY;COUNT=3", suite.start, 10))
+}
+
+func (suite *RRuleTestSuite) TestWeekly_IntervalByDay() {
+	suite.Equal(
+		[]string{"2024-01-03 09:00", "2024-01-15 09:00", "2024-01-17 09:00", "2024-01-29 09:00"},
+		suite.occurrences("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", suite.start, 4),
+	)
+}
+
+func (suite *RRuleTestSuite) TestWeekly_Until() {
+	suite.Equal(
+		[]string{"2024-01-08 09:00", "2024-01-15 09:00"},
+		suite.occurrences("FREQ=WEEKLY;UNTIL=20240115", suite.start, 10),
+		"a date only UNTIL includes the whole day",
+	)
+}
+
+func (suite *RRuleTestSuite) TestMonthly_OrdinalByDay() {
+	suite.Equal(
+		[]string{"2024-01-26 09:00", "2024-02-23 09:00", "2024-03-29 09:00"},
+		suite.occurrences("FREQ=MONTHLY;BYDAY=-1FR", suite.start, 3),
+	)
+}
+
+func (suite *RRuleTestSuite) TestMonthly_SkipsShortMonths() {
+	suite.start = time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+	suite.Equal(
+		[]string{"2024-03-31 09:00", "2024-05-31 09:00"},
+		suite.occurrences("FREQ=MONTHLY", suite.start, 2),
+	)
+}
+
+func (suite *RRuleTestSuite) TestAfter_CountIncludesPastOccurrences() {
+	after := time.Date(2024, time.January, 3, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]string{"2024-01-04 09:00"}, suite.occurrences("FREQ=DAILY;COUNT=4", after, 10))
+	suite.Empty(suite.occurrences("FREQ=DAILY;COUNT=3", after, 10), "the series has ended")
+}
+
+func TestRRuleTestSuite(t *testing.T) {
+	suite.Run(t, new(RRuleTestSuite))
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchServiceTestSuite struct {
+	suite.Suite
+	tasks []domain.Task
+}
+
+func (suite *SearchServiceTestSuite) SetupTest() {
+	suite.tasks = []domain.Task{
+		{ID: "task_001", Title: "Review PRs", Description: "Review the pull requests for the backend repository."},
+		{ID: "task_002", Title: "Update Documentation", Description: "Update the API documentation after the review."},
+		{ID: "task_003", Title: "Schedule dentist appointment", Description: "Call the dentist."},
+	}
+}
+
+func (suite *SearchServiceTestSuite) TestSearchTerms() {
+	suite.Equal([]string{"review", "api", "docs"}, infrastructure.SearchTerms("Review  API-docs -dentist"))
+	suite.Empty(infrastructure.SearchTerms("   "))
+}
+
+func (suite *SearchServiceTestSuite) TestSearchTasksInMemory_Ranking() {
+	results := infrastructure.SearchTasksInMemory(suite.tasks, "review", 10)
+
+	suite.Equal(2, len(results), "only matching tasks should be returned")
+	suite.Equal("task_001", results[0].Task.ID, "title matches should rank first")
+	suite.Equal("task_002", results[1].Task.ID)
+	suite.Greater(results[0].Score, results[1].Score)
+	suite.Equal("<mark>Review</mark> PRs", results[0].Highlights["title"])
+	suite.NotContains(results[1].Highlights, "title")
+}
+
+func (suite *SearchServiceTestSuite) TestParseSearchQuery() {
+	query := infrastructure.ParseSearchQuery(`Review -"pull requests" "API docs" -dentist "unterminated`)
+	suite.Equal([]string{"review", "api", "docs", "unterminated"}, query.Terms)
+	suite.Equal([]string{"api docs", "unterminated"}, query.Phrases)
+	suite.Equal([]string{"pull requests", "dentist"}, query.Excluded)
+}
+
+func (suite *SearchServiceTestSuite) TestSearchTasksInMemory_Excluded() {
+	results := infrastructure.SearchTasksInMemory(suite.tasks, "review -backend", 10)
+	suite.Equal(1, len(results), "tasks with an excluded term should not match")
+	suite.Equal("task_002", results[0].Task.ID)
+
+	results = infrastructure.SearchTasksInMemory(suite.tasks, `review -"pull requests"`, 10)
+	suite.Equal(1, len(results), "tasks with an excluded phrase should not match")
+	suite.Equal("task_002", results[0].Task.ID)
+
+	suite.Empty(infrastructure.SearchTasksInMemory(suite.tasks, "-review", 10), "exclusions alone match nothing")
+}
+
+func (suite *SearchServiceTestSuite) TestSearchTasksInMemory_Phrase() {
+	results := infrastructure.SearchTasksInMemory(suite.tasks, `"API documentation" review`, 10)
+	suite.Equal(1, len(results), "tasks without the phrase should not match")
+	suite.Equal("task_002", results[0].Task.ID)
+	suite.Empty(infrastructure.SearchTasksInMemory(suite.tasks, `"documentation API"`, 10), "the words of a phrase must be in order")
+}
+
+func (suite *SearchServiceTestSuite) TestSearchTasksInMemory_Limit() {
+	results := infrastructure.SearchTasksInMemory(suite.tasks, "review documentation", 1)
+	suite.Equal(1, len(results))
+	suite.Equal("task_002", results[0].Task.ID)
+}
+
+func (suite *SearchServiceTestSuite) TestHighlightText_Snippet() {
+	text := "Prepare the quarterly <report> for the board meeting and share the draft with the finance team before Friday."
+	snippet := infrastructure.HighlightText(text, []string{"board"}, 10)
+
+	suite.Equal("…report&gt; for the <mark>board</mark> meeting and…", snippet)
+}
+
+func TestSearchServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchServiceTestSuite))
+}
@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type sessionUsecaseSuite struct {
+	suite.Suite
+	userRepository  *mocks.UserRepository
+	tokenRepository *mocks.RefreshTokenRepository
+	auditLog        *mocks.AuditRecorder
+	usecase         domain.UserUsecase
+	user            domain.User
+}
+
+func (suite *sessionUsecaseSuite) SetupTest() {
+	os.Setenv("SIGNITURE_TIME_DURATION", "900")
+	os.Setenv("SIGNITURE_SECRET", "mysecretkey")
+	os.Unsetenv("REFRESH_TOKEN_DURATION")
+	suite.userRepository = new(mocks.UserRepository)
+	suite.tokenRepository = new(mocks.RefreshTokenRepository)
+	suite.auditLog = new(mocks.AuditRecorder)
+	suite.auditLog.On("Record", mock.Anything, mock.Anything).Return()
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	userUC.SetRefreshTokenRepository(suite.tokenRepository)
+	userUC.SetAuditLog(suite.auditLog)
+	suite.usecase = userUC
+	hashed, _ := infrastructure.HashPassword("secret")
+	suite.user = domain.User{ID: "user_1", Username: "johndoe", Password: hashed, Role: "user", OrgID: "org_1"}
+}
+
+// logs in and returns the session along with the stored refresh token
+func (suite *sessionUsecaseSuite) login() (domain.Session, domain.RefreshToken) {
+	var stored domain.RefreshToken
+	suite.userRepository.On("FetchUserByUsername", mock.Anything, "johndoe").Return(suite.user, nil).Once()
+	suite.tokenRepository.On("CreateRefreshToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(domain.RefreshToken)
+	}).Return(nil).Once()
+	session, err := suite.usecase.LoginUser(context.TODO(), domain.User{Username: "johndoe", Password: "secret", Role: "user"})
+	suite.Require().Nil(err, "error should be nil")
+	return session, stored
+}
+
+func (suite *sessionUsecaseSuite) TestLoginIssuesRefreshToken() {
+	session, stored := suite.login()
+
+	suite.NotEmpty(session.Token)
+	suite.Equal(int64(900), session.ExpiresIn)
+	suite.Len(session.RefreshToken, 64)
+	suite.NotEqual(session.RefreshToken, stored.ID, "Only the hash of the token should be stored")
+	suite.Equal(stored.ID, stored.FamilyID, "A login should start a new family")
+	suite.Equal("user_1", stored.UserID)
+	suite.Equal("org_1", stored.OrgID)
+	suite.WithinDuration(time.Now().Add(usecases.DEFAULT_REFRESH_TOKEN_DURATION), stored.ExpiresAt, time.Minute)
+}
+
+func (suite *sessionUsecaseSuite) TestRefreshRotatesToken() {
+	session, stored := suite.login()
+	var rotated domain.RefreshToken
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, stored.ID).Return(stored, nil)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(domain.User{ID: "user_1", Username: "johndoe", Role: "admin", OrgID: "org_1"}, nil)
+	suite.tokenRepository.On("ConsumeRefreshToken", mock.Anything, stored.ID, mock.Anything).Return(stored, nil)
+	suite.tokenRepository.On("CreateRefreshToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		rotated = args.Get(1).(domain.RefreshToken)
+	}).Return(nil)
+
+	refreshed, err := suite.usecase.RefreshSession(context.TODO(), session.RefreshToken)
+	suite.Nil(err, "error should be nil")
+	suite.NotEqual(session.RefreshToken, refreshed.RefreshToken, "The refresh token should be rotated")
+	suite.Equal(stored.FamilyID, rotated.FamilyID, "The new token should stay in the family")
+	suite.NotEqual(stored.ID, rotated.ID)
+	token, errParse := infrastructure.ParseJWTToken(refreshed.Token)
+	suite.Require().Nil(errParse)
+	suite.Equal("admin", token.Claims.(jwt.MapClaims)["role"], "The access token should carry the current role")
+}
+
+func (suite *sessionUsecaseSuite) TestRefreshReuseRevokesFamily() {
+	session, stored := suite.login()
+	stored.UsedAt = time.Now().Add(-time.Minute)
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, stored.ID).Return(stored, nil)
+	suite.tokenRepository.On("RevokeTokenFamily", mock.Anything, stored.FamilyID, mock.Anything).Return(nil)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil)
+
+	_, err := suite.usecase.RefreshSession(context.TODO(), session.RefreshToken)
+	suite.Require().NotNil(err, "A used token should be refused")
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.tokenRepository.AssertCalled(suite.T(), "RevokeTokenFamily", mock.Anything, stored.FamilyID, mock.Anything)
+	suite.tokenRepository.AssertNotCalled(suite.T(), "ConsumeRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	suite.auditLog.AssertCalled(suite.T(), "Record", mock.Anything, mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AUDIT_USER_TOKEN_REUSE && entry.Actor == "johndoe" && entry.TargetID == "user_1"
+	}))
+}
+
+func (suite *sessionUsecaseSuite) TestRefreshConcurrentUseRevokesFamily() {
+	session, stored := suite.login()
+	used := stored
+	used.UsedAt = time.Now()
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, stored.ID).Return(stored, nil).Once()
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, stored.ID).Return(used, nil).Once()
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil)
+	suite.tokenRepository.On("ConsumeRefreshToken", mock.Anything, stored.ID, mock.Anything).Return(domain.RefreshToken{}, &domain.UserError{Message: "Refresh token not found", Code: http.StatusNotFound})
+	suite.tokenRepository.On("RevokeTokenFamily", mock.Anything, stored.FamilyID, mock.Anything).Return(nil)
+
+	_, err := suite.usecase.RefreshSession(context.TODO(), session.RefreshToken)
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.tokenRepository.AssertCalled(suite.T(), "RevokeTokenFamily", mock.Anything, stored.FamilyID, mock.Anything)
+}
+
+func (suite *sessionUsecaseSuite) TestRefreshRejectsRevokedExpiredAndUnknown() {
+	_, stored := suite.login()
+	revoked := stored
+	revoked.RevokedAt = time.Now()
+	expired := stored
+	expired.ID = "expired"
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, mock.Anything).Return(revoked, nil).Once()
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, mock.Anything).Return(expired, nil).Once()
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, mock.Anything).Return(domain.RefreshToken{}, &domain.UserError{Message: "Refresh token not found", Code: http.StatusNotFound}).Once()
+
+	for _, message := range []string{"Refresh token has been revoked", "Refresh token has expired", "Invalid refresh token"} {
+		_, err := suite.usecase.RefreshSession(context.TODO(), "token")
+		suite.Require().NotNil(err)
+		suite.Equal(http.StatusUnauthorized, err.Code)
+		suite.Equal(message, err.Message)
+	}
+	suite.tokenRepository.AssertNotCalled(suite.T(), "RevokeTokenFamily", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *sessionUsecaseSuite) TestLogoutRevokesFamily() {
+	session, stored := suite.login()
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, stored.ID).Return(stored, nil)
+	suite.tokenRepository.On("RevokeTokenFamily", mock.Anything, stored.FamilyID, mock.Anything).Return(nil)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil)
+
+	err := suite.usecase.Logout(context.TODO(), session.RefreshToken, "")
+	suite.Nil(err, "error should be nil")
+	suite.tokenRepository.AssertExpectations(suite.T())
+	suite.auditLog.AssertCalled(suite.T(), "Record", mock.Anything, mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AUDIT_USER_LOGOUT && entry.Actor == "johndoe"
+	}))
+}
+
+func (suite *sessionUsecaseSuite) TestLogoutRevokesAccessToken() {
+	revocations := infrastructure.NewMemoryRevocationStore()
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	userUC.SetRefreshTokenRepository(suite.tokenRepository)
+	userUC.SetTokenRevocationStore(revocations)
+	suite.usecase = userUC
+	session, stored := suite.login()
+	suite.tokenRepository.On("FetchRefreshToken", mock.Anything, stored.ID).Return(stored, nil)
+	suite.tokenRepository.On("RevokeTokenFamily", mock.Anything, stored.FamilyID, mock.Anything).Return(nil)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil)
+
+	// the access token of another user is refused and nothing is revoked
+	otherToken, _ := infrastructure.CreateJWTToken("janedoe", "user", "org_1", time.Minute)
+	err := suite.usecase.Logout(context.TODO(), session.RefreshToken, otherToken)
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.tokenRepository.AssertNotCalled(suite.T(), "RevokeTokenFamily", mock.Anything, mock.Anything, mock.Anything)
+
+	err = suite.usecase.Logout(context.TODO(), session.RefreshToken, session.Token)
+	suite.Nil(err, "error should be nil")
+	claims, errParse := infrastructure.ParseAccessToken(session.Token)
+	suite.Require().Nil(errParse)
+	revoked, _ := revocations.IsRevoked(context.TODO(), claims.ID, "johndoe", time.Now().Add(time.Hour))
+	suite.True(revoked, "the access token should be revoked")
+	revoked, _ = revocations.IsRevoked(context.TODO(), "other", "johndoe", time.Now().Add(time.Hour))
+	suite.False(revoked, "only the presented access token should be revoked")
+}
+
+func (suite *sessionUsecaseSuite) TestRefreshWithoutRepository() {
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	_, err := userUC.RefreshSession(context.TODO(), "token")
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusNotImplemented, err.Code)
+}
+
+func TestSessionUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(sessionUsecaseSuite))
+}
@@ -9,10 +9,12 @@ import (
 	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	repositorie "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/repositories"
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -130,6 +132,16 @@ func (suite *testRepositorySuite) TestAddingAndRetrieving() {
 	suite.Equal(task.UserID, fetchedTask.UserID, "Task user ID should match")
 }
 
+func (suite *testRepositorySuite) TestFetchTaskByID_NotFound() {
+	_, errInvalid := suite.repository.FetchTaskByID(context.TODO(), "not-an-id")
+	suite.NotNil(errInvalid, "Error fetching a task by an invalid ID")
+	suite.Equal(400, errInvalid.Code)
+
+	_, errMissing := suite.repository.FetchTaskByID(context.TODO(), primitive.NewObjectID().Hex())
+	suite.NotNil(errMissing, "Error fetching a missing task")
+	suite.Equal(404, errMissing.Code)
+}
+
 func (suite *testRepositorySuite) TestFetchAllTasksEmptyDB() {
 	_, err := suite.repository.FetchAllTasks(context.TODO())
 	suite.Nil(err, "Error fetching all tasks from empty database")
@@ -184,6 +196,658 @@ func (suite *testRepositorySuite) TestFetchAllTasksFilledDB() {
 	suite.Equal(len(totalFetched), 2, "Total fetched tasks should be 2")
 }
 
+func (suite *testRepositorySuite) TestFetchTasksPaging() {
+	base := time.Now().Truncate(time.Millisecond)
+	for i := 0; i < 5; i++ {
+		task := domain.Task{
+			UserID:   "user789",
+			Title:    fmt.Sprintf("Task %d", i),
+			Status:   "Pending",
+			Priority: "Low",
+			DueDate:  base.Add(time.Hour * time.Duration(i)),
+		}
+		_, errInsert := suite.repository.CreateTask(context.TODO(), task)
+		suite.Nil(errInsert, "Nil inserting task")
+	}
+	_, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user012", Title: "Other user", Status: "Pending"})
+	suite.Nil(errInsert, "Nil inserting task")
+
+	query := domain.TaskQuery{UserID: "user789", SortBy: "due_date", SortOrder: -1, Limit: 2}
+	var titles []string
+	for pages := 0; pages < 5; pages++ {
+		page, errFetch := suite.repository.FetchTasks(context.TODO(), query)
+		suite.Nil(errFetch, "Nil fetching page")
+		for _, task := range page.Tasks {
+			titles = append(titles, task.Title)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	suite.Equal([]string{"Task 4", "Task 3", "Task 2", "Task 1", "Task 0"}, titles, "Pages should walk every matching task in order")
+
+	query.SortOrder = 1
+	_, errFetch := suite.repository.FetchTasks(context.TODO(), query)
+	suite.NotNil(errFetch, "Cursor should be rejected for a different sort")
+}
+
+func (suite *testRepositorySuite) TestSearchTasks() {
+	tasks := []domain.Task{
+		{UserID: "user789", Title: "Review PRs", Description: "Review the pull requests for the backend repository."},
+		{UserID: "user789", Title: "Update Documentation", Description: "Update the documentation after the review."},
+		{UserID: "user789", Title: "Schedule dentist appointment", Description: "Call the dentist."},
+	}
+	for _, task := range tasks {
+		_, errInsert := suite.repository.CreateTask(context.TODO(), task)
+		suite.Nil(errInsert, "Nil inserting task")
+	}
+
+	// without the text index the repository falls back to the in-process ranking
+	suite.repository.Collection.Indexes().DropAll(context.TODO())
+	results, errSearch := suite.repository.SearchTasks(context.TODO(), "review", 10)
+	suite.Nil(errSearch, "Nil searching without text index")
+	suite.Equal(2, len(results), "Two tasks mention review")
+	suite.Equal("Review PRs", results[0].Task.Title, "Title matches should rank first")
+	results, errSearch = suite.repository.SearchTasks(context.TODO(), "review -backend", 10)
+	suite.Nil(errSearch, "Nil searching without text index")
+	suite.Equal(1, len(results), "Excluded terms should be honoured without the text index")
+	suite.Equal("Update Documentation", results[0].Task.Title)
+
+	errIndex := infrastructure.EstablisTextIndex(suite.repository.Collection, map[string]int{
+		"title":       infrastructure.TITLE_SEARCH_WEIGHT,
+		"description": infrastructure.DESCRIPTION_SEARCH_WEIGHT,
+	})
+	suite.Nil(errIndex, "Nil creating text index")
+	results, errSearch = suite.repository.SearchTasks(context.TODO(), "review", 10)
+	suite.Nil(errSearch, "Nil searching with text index")
+	suite.Equal(2, len(results), "Two tasks mention review")
+	suite.Equal("Review PRs", results[0].Task.Title, "Title matches should rank first")
+	suite.Equal("<mark>Review</mark> PRs", results[0].Highlights["title"], "Title should be highlighted")
+}
+
+func (suite *testRepositorySuite) TestFetchSubtree() {
+	rootID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Release"})
+	suite.Nil(errInsert, "Nil inserting root task")
+	childID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Write docs", ParentID: rootID})
+	suite.Nil(errInsert, "Nil inserting child task")
+	_, errInsert = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "API docs", ParentID: childID})
+	suite.Nil(errInsert, "Nil inserting grandchild task")
+	_, errInsert = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Unrelated"})
+	suite.Nil(errInsert, "Nil inserting unrelated task")
+
+	children, errFetch := suite.repository.FetchChildTasks(context.TODO(), rootID)
+	suite.Nil(errFetch, "Nil fetching children")
+	suite.Equal(1, len(children), "Root should have a single child")
+
+	subtree, errFetch := suite.repository.FetchSubtree(context.TODO(), rootID)
+	suite.Nil(errFetch, "Nil fetching subtree")
+	suite.Equal(3, len(subtree), "Subtree should hold the root, child and grandchild")
+	suite.Equal(rootID, subtree[0].ID, "Subtree should start with the root")
+}
+
+func (suite *testRepositorySuite) TestDependencies() {
+	buildID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Build"})
+	suite.Nil(errInsert, "Nil inserting blocker")
+	deployID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Deploy"})
+	suite.Nil(errInsert, "Nil inserting dependent")
+
+	blockedTask, errAdd := suite.repository.AddDependency(context.TODO(), deployID, buildID)
+	suite.Nil(errAdd, "Nil adding dependency")
+	suite.Equal([]string{buildID}, blockedTask.BlockedBy, "Dependency should be stored on the task")
+	blockedTask, errAdd = suite.repository.AddDependency(context.TODO(), deployID, buildID)
+	suite.Nil(errAdd, "Nil adding dependency twice")
+	suite.Equal([]string{buildID}, blockedTask.BlockedBy, "Dependency should be stored once")
+
+	fetchedTasks, errFetch := suite.repository.FetchTasksByIDs(context.TODO(), []string{buildID, deployID, "invalid"})
+	suite.Nil(errFetch, "Nil fetching tasks by IDs")
+	suite.Equal(2, len(fetchedTasks), "Both tasks should be fetched")
+
+	errUnlink := suite.repository.UnlinkDependents(context.TODO(), buildID)
+	suite.Nil(errUnlink, "Nil unlinking dependents")
+	unlinkedTask, errFetch := suite.repository.FetchTaskByID(context.TODO(), deployID)
+	suite.Nil(errFetch, "Nil fetching unlinked task")
+	suite.Empty(unlinkedTask.BlockedBy, "Dependency should be removed")
+}
+
+func (suite *testRepositorySuite) TestDependencies_MissingTask() {
+	buildID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Build"})
+	suite.Nil(errInsert, "Nil inserting blocker")
+	missingID := primitive.NewObjectID().Hex()
+
+	_, errAdd := suite.repository.AddDependency(context.TODO(), missingID, buildID)
+	suite.NotNil(errAdd, "Error adding a dependency to a missing task")
+	suite.Equal(404, errAdd.Code)
+	_, errRemove := suite.repository.RemoveDependency(context.TODO(), missingID, buildID)
+	suite.NotNil(errRemove, "Error removing a dependency from a missing task")
+	suite.Equal(404, errRemove.Code)
+}
+
+func (suite *testRepositorySuite) TestRecurrenceFields() {
+	dueDate := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	insertedResult, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Water plants", DueDate: dueDate, Recurrence: "FREQ=WEEKLY", RecurrenceStart: dueDate})
+	suite.Nil(err, "Nil creating recurring task")
+
+	updatedTask, errUpdate := suite.repository.UpdateTask(context.TODO(), domain.Task{ID: insertedResult, UserID: "user789", Title: "Water plants", Status: "done"})
+	suite.Nil(errUpdate, "Nil completing recurring task")
+	suite.Equal("FREQ=WEEKLY", updatedTask.Recurrence, "Recurrence should be kept")
+	suite.True(dueDate.Equal(updatedTask.RecurrenceStart), "Recurrence start should be kept")
+
+	linkedTask, errLink := suite.repository.LinkNextOccurrence(context.TODO(), insertedResult, "next")
+	suite.Nil(errLink, "Nil linking next occurrence")
+	suite.Equal("next", linkedTask.NextOccurrenceID, "Next occurrence should be linked")
+	_, errLink = suite.repository.LinkNextOccurrence(context.TODO(), insertedResult, "other")
+	suite.NotNil(errLink, "A task is only linked once")
+	suite.Equal(409, errLink.Code)
+}
+
+func (suite *testRepositorySuite) TestFetchDueTasks() {
+	now := time.Now().Truncate(time.Millisecond)
+	soonID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Due soon", Status: "todo", DueDate: now.Add(time.Minute)})
+	suite.Nil(err, "Nil inserting task due soon")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Done", Status: "done", DueDate: now.Add(time.Minute)})
+	suite.Nil(err, "Nil inserting finished task")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Next week", Status: "todo", DueDate: now.Add(time.Hour * 24 * 7)})
+	suite.Nil(err, "Nil inserting later task")
+
+	dueTasks, errFetch := suite.repository.FetchDueTasks(context.TODO(), now.Add(-time.Hour), now.Add(time.Hour), []string{"done"})
+	suite.Nil(errFetch, "Nil fetching due tasks")
+	suite.Equal(1, len(dueTasks), "Only the open task inside the window should be fetched")
+	suite.Equal(soonID, dueTasks[0].ID)
+}
+
+func (suite *testRepositorySuite) TestTags() {
+	bugID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Fix login", Tags: []string{"bug", "backend"}})
+	suite.Nil(err, "Nil inserting bug")
+	defectID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Fix layout", Tags: []string{"defect", "frontend", "bug"}})
+	suite.Nil(err, "Nil inserting defect")
+
+	page, errFetch := suite.repository.FetchTasks(context.TODO(), domain.TaskQuery{AnyTags: []string{"backend", "frontend"}, SortOrder: 1, Limit: 10})
+	suite.Nil(errFetch, "Nil fetching any tags")
+	suite.Equal(2, len(page.Tasks), "Both tasks carry one of the tags")
+	page, errFetch = suite.repository.FetchTasks(context.TODO(), domain.TaskQuery{AllTags: []string{"bug", "frontend"}, SortOrder: 1, Limit: 10})
+	suite.Nil(errFetch, "Nil fetching all tags")
+	suite.Equal(1, len(page.Tasks), "Only one task carries both tags")
+	suite.Equal(defectID, page.Tasks[0].ID)
+
+	updated, errUpdate := suite.repository.UpdateTaskTags(context.TODO(), []string{bugID, defectID}, []string{"urgent"}, []string{"frontend"})
+	suite.Nil(errUpdate, "Nil updating tags")
+	suite.Equal(int64(2), updated)
+
+	merged, errMerge := suite.repository.ReplaceTags(context.TODO(), []string{"defect"}, "bug")
+	suite.Nil(errMerge, "Nil merging tags")
+	suite.Equal(int64(1), merged, "Only the task with the merged tag should change")
+	mergedTask, errFetch := suite.repository.FetchTaskByID(context.TODO(), defectID)
+	suite.Nil(errFetch, "Nil fetching merged task")
+	suite.Equal([]string{"bug", "urgent"}, mergedTask.Tags, "Merged tags should not repeat")
+
+	counts, errCount := suite.repository.FetchTagCounts(context.TODO())
+	suite.Nil(errCount, "Nil counting tags")
+	suite.Equal([]domain.TagCount{{Tag: "bug", Count: 2}, {Tag: "urgent", Count: 2}, {Tag: "backend", Count: 1}}, counts)
+}
+
+func (suite *testRepositorySuite) TestComments() {
+	collection := suite.repository.Collection.Database().Collection("comments_test")
+	defer collection.Drop(context.TODO())
+	commentRepository := repositorie.NewCommentRepository(collection)
+
+	rootID, err := commentRepository.CreateComment(context.TODO(), domain.Comment{TaskID: "task_1", AuthorID: "user_1", Body: "Frist", CreatedAt: time.Now()})
+	suite.Nil(err, "Nil creating comment")
+	_, err = commentRepository.CreateComment(context.TODO(), domain.Comment{TaskID: "task_1", ParentID: rootID, AuthorID: "user_2", Body: "Typo", CreatedAt: time.Now()})
+	suite.Nil(err, "Nil creating reply")
+
+	edited, err := commentRepository.UpdateCommentBody(context.TODO(), rootID, "First", domain.CommentRevision{Body: "Frist", EditedAt: time.Now()})
+	suite.Nil(err, "Nil editing comment")
+	suite.Equal("First", edited.Body)
+	suite.Equal(1, len(edited.History), "Previous body should be kept")
+
+	replies, err := commentRepository.CountReplies(context.TODO(), rootID)
+	suite.Nil(err, "Nil counting replies")
+	suite.Equal(int64(1), replies)
+
+	err = commentRepository.ArchiveTaskComments(context.TODO(), "task_1")
+	suite.Nil(err, "Nil archiving comments")
+	comments, err := commentRepository.FetchComments(context.TODO(), "task_1")
+	suite.Nil(err, "Nil fetching comments")
+	suite.Empty(comments, "Archived comments should be hidden")
+}
+
+func (suite *testRepositorySuite) TestAttachments() {
+	taskID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Screenshots"})
+	suite.Nil(errInsert, "Nil inserting task")
+	uploadedAt := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	first := domain.Attachment{ID: "a1", Filename: "before.png", ContentType: "image/png", Size: 10, SHA256: "abc", UploadedBy: "user789", UploadedAt: uploadedAt}
+	second := domain.Attachment{ID: "a2", Filename: "after.png", ContentType: "image/png", Size: 20, SHA256: "def", UploadedBy: "user789", UploadedAt: uploadedAt}
+
+	_, errAdd := suite.repository.AddAttachment(context.TODO(), taskID, first)
+	suite.Nil(errAdd, "Nil adding first attachment")
+	attachedTask, errAdd := suite.repository.AddAttachment(context.TODO(), taskID, second)
+	suite.Nil(errAdd, "Nil adding second attachment")
+	suite.Equal([]domain.Attachment{first, second}, attachedTask.Attachments, "Attachments should be kept in upload order")
+
+	detachedTask, errRemove := suite.repository.RemoveAttachment(context.TODO(), taskID, "a1")
+	suite.Nil(errRemove, "Nil removing attachment")
+	suite.Equal([]domain.Attachment{second}, detachedTask.Attachments, "Only the removed attachment should be gone")
+}
+
+func (suite *testRepositorySuite) TestAuditLog() {
+	collection := suite.repository.Collection.Database().Collection("audit_test")
+	defer collection.Drop(context.TODO())
+	auditRepository := repositorie.NewAuditRepository(collection)
+
+	start := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	for i, actor := range []string{"abebe", "kebede", "abebe"} {
+		err := auditRepository.InsertEntry(context.TODO(), domain.AuditEntry{
+			Actor:      actor,
+			Action:     domain.AUDIT_TASK_UPDATE,
+			TargetType: domain.AUDIT_TARGET_TASK,
+			TargetID:   "task_1",
+			Timestamp:  start.Add(time.Duration(i) * time.Hour),
+			Changes:    []domain.FieldChange{{Field: "tags", Before: []interface{}{"a"}, After: map[string]interface{}{"nested": true}}},
+		})
+		suite.Nil(err, "Nil inserting audit entry")
+	}
+
+	entries, err := auditRepository.FetchEntries(context.TODO(), domain.AuditQuery{Actor: "abebe", TargetID: "task_1", Limit: 10})
+	suite.Nil(err, "Nil fetching audit entries")
+	suite.Equal(2, len(entries), "Entries should be filtered by actor")
+	suite.True(entries[0].Timestamp.After(entries[1].Timestamp), "Newest entries should come first")
+	suite.Equal(map[string]interface{}{"nested": true}, map[string]interface{}(entries[0].Changes[0].After.(bson.M)), "Changed values should decode as maps")
+
+	entries, err = auditRepository.FetchEntries(context.TODO(), domain.AuditQuery{Since: start.Add(time.Hour), Until: start.Add(2 * time.Hour), Limit: 10})
+	suite.Nil(err, "Nil fetching audit window")
+	suite.Equal(1, len(entries), "Since is inclusive and until exclusive")
+	suite.Equal("kebede", entries[0].Actor)
+}
+
+func (suite *testRepositorySuite) TestRevisions() {
+	taskID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Draft"})
+	suite.Nil(errInsert, "Nil inserting task")
+	updatedTask, errUpdate := suite.repository.UpdateTask(context.TODO(), domain.Task{ID: taskID, UserID: "user789", Title: "Final", Description: "Ready"})
+	suite.Nil(errUpdate, "Nil updating task")
+	suite.Equal(2, updatedTask.Revision, "Updates should increment the revision")
+
+	restoredTask, errRestore := suite.repository.RestoreTask(context.TODO(), domain.Task{ID: taskID, UserID: "user789", Title: "Draft", Tags: []string{"draft"}})
+	suite.Nil(errRestore, "Nil restoring task")
+	suite.Equal("Draft", restoredTask.Title)
+	suite.Equal([]string{"draft"}, restoredTask.Tags, "Restoring should restore the tags")
+	suite.Empty(restoredTask.Description, "Restoring should clear fields that were empty")
+	suite.Equal(3, restoredTask.Revision, "Restoring should increment the revision")
+	_, errMissing := suite.repository.RestoreTask(context.TODO(), domain.Task{ID: primitive.NewObjectID().Hex(), UserID: "user789", Title: "Draft"})
+	suite.NotNil(errMissing, "Restoring a missing task should fail")
+	suite.Equal(404, errMissing.Code)
+
+	collection := suite.repository.Collection.Database().Collection("revisions_test")
+	defer collection.Drop(context.TODO())
+	revisionRepository := repositorie.NewRevisionRepository(collection)
+	for _, task := range []domain.Task{updatedTask, restoredTask} {
+		err := revisionRepository.CreateRevision(context.TODO(), domain.TaskRevision{TaskID: taskID, Number: task.Revision, Action: domain.REVISION_UPDATE, Task: task})
+		suite.Nil(err, "Nil creating revision")
+	}
+	errDuplicate := revisionRepository.CreateRevision(context.TODO(), domain.TaskRevision{TaskID: taskID, Number: 3, Task: restoredTask})
+	suite.NotNil(errDuplicate, "A revision number should only be stored once")
+	suite.Equal(409, errDuplicate.Code)
+
+	revisions, errFetch := revisionRepository.FetchRevisions(context.TODO(), taskID)
+	suite.Nil(errFetch, "Nil fetching revisions")
+	suite.Equal(2, len(revisions))
+	suite.Equal(3, revisions[0].Number, "Newest revisions should come first")
+	revision, errFetch := revisionRepository.FetchRevision(context.TODO(), taskID, 2)
+	suite.Nil(errFetch, "Nil fetching revision")
+	suite.Equal("Ready", revision.Task.Description)
+
+	suite.Nil(revisionRepository.DeleteRevisions(context.TODO(), taskID), "Nil deleting revisions")
+	_, errFetch = revisionRepository.FetchRevision(context.TODO(), taskID, 2)
+	suite.NotNil(errFetch, "Deleted revisions should not be found")
+	suite.Equal(404, errFetch.Code)
+}
+
+func (suite *testRepositorySuite) TestVersions() {
+	taskID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Draft"})
+	suite.Nil(errInsert, "Nil inserting task")
+	updatedTask, errUpdate := suite.repository.UpdateTask(context.TODO(), domain.Task{ID: taskID, UserID: "user789", Title: "Final", Version: 1})
+	suite.Nil(errUpdate, "Nil updating task at the current version")
+	suite.Equal(2, updatedTask.Version, "Updates should increment the version")
+
+	taggedCount, errTags := suite.repository.UpdateTaskTags(context.TODO(), []string{taskID}, []string{"urgent"}, nil)
+	suite.Nil(errTags, "Nil tagging task")
+	suite.Equal(int64(1), taggedCount)
+
+	currentTask, errStale := suite.repository.UpdateTask(context.TODO(), domain.Task{ID: taskID, UserID: "user789", Title: "Stale", Version: 2})
+	suite.NotNil(errStale, "Updating a stale version should fail")
+	suite.Equal(412, errStale.Code)
+	suite.Equal("Final", currentTask.Title, "The conflict should return the current task")
+	suite.Equal(3, currentTask.Version)
+
+	_, errStale = suite.repository.DeleteTask(context.TODO(), taskID, 2)
+	suite.NotNil(errStale, "Deleting a stale version should fail")
+	suite.Equal(412, errStale.Code)
+	_, errDelete := suite.repository.DeleteTask(context.TODO(), taskID, 3)
+	suite.Nil(errDelete, "Nil deleting task at the current version")
+	_, errGone := suite.repository.DeleteTask(context.TODO(), taskID, 3)
+	suite.NotNil(errGone, "A deleted task should not be found")
+	suite.Equal(404, errGone.Code)
+}
+
+func (suite *testRepositorySuite) TestPatchTask() {
+	taskID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Draft", Description: "Old", Priority: "Low"})
+	suite.Nil(errInsert, "Nil inserting task")
+
+	// fields that are not named are left as they are even when empty on the task
+	patchedTask, errPatch := suite.repository.PatchTask(context.TODO(), domain.Task{ID: taskID, Priority: "High", Version: 1}, []string{"priority", "description"})
+	suite.Nil(errPatch, "Nil patching task")
+	suite.Equal("High", patchedTask.Priority)
+	suite.Empty(patchedTask.Description, "Empty named fields should be removed")
+	suite.Equal("Draft", patchedTask.Title, "Fields that are not named should be kept")
+	suite.Equal(2, patchedTask.Revision)
+	suite.Equal(2, patchedTask.Version)
+
+	currentTask, errStale := suite.repository.PatchTask(context.TODO(), domain.Task{ID: taskID, Title: "Stale", Version: 1}, []string{"title"})
+	suite.NotNil(errStale, "Patching a stale version should fail")
+	suite.Equal(412, errStale.Code)
+	suite.Equal("Draft", currentTask.Title)
+
+	_, errField := suite.repository.PatchTask(context.TODO(), domain.Task{ID: taskID, Version: 2}, []string{"revision"})
+	suite.NotNil(errField, "Only task fields should be written")
+}
+
+func (suite *testRepositorySuite) TestTrash() {
+	taskID, errInsert := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user789", Title: "Draft"})
+	suite.Nil(errInsert, "Nil inserting task")
+	deletedTask, errDelete := suite.repository.DeleteTask(context.TODO(), taskID, 0)
+	suite.Nil(errDelete, "Nil deleting task")
+	suite.False(deletedTask.DeletedAt.IsZero(), "A deleted task should be marked")
+
+	_, errFetch := suite.repository.FetchTaskByID(context.TODO(), taskID)
+	suite.NotNil(errFetch, "A task in the trash should not be found")
+	suite.Equal(404, errFetch.Code)
+	trashedTasks, errTrash := suite.repository.FetchDeletedTasks(context.TODO(), time.Time{}, 10)
+	suite.Nil(errTrash, "Nil fetching the trash")
+	suite.Equal(1, len(trashedTasks))
+
+	errPurge := suite.repository.PurgeTask(context.TODO(), taskID, deletedTask.DeletedAt.Add(-time.Hour))
+	suite.NotNil(errPurge, "A task deleted after the cutoff should not be purged")
+	restoredTask, errRestore := suite.repository.UndeleteTask(context.TODO(), taskID)
+	suite.Nil(errRestore, "Nil restoring task")
+	suite.True(restoredTask.DeletedAt.IsZero(), "A restored task should not be marked")
+	suite.Equal(3, restoredTask.Version)
+
+	_, errDelete = suite.repository.DeleteTask(context.TODO(), taskID, 0)
+	suite.Nil(errDelete, "Nil deleting task")
+	suite.Nil(suite.repository.PurgeTask(context.TODO(), taskID, time.Now().Add(time.Minute)), "Nil purging task")
+	_, errRestore = suite.repository.UndeleteTask(context.TODO(), taskID)
+	suite.NotNil(errRestore, "A purged task should not be restorable")
+}
+
+func (suite *testRepositorySuite) TestProjects() {
+	collection := suite.repository.Collection.Database().Collection("projects_test")
+	defer collection.Drop(context.TODO())
+	projectRepository := repositorie.NewProjectRepository(collection)
+
+	projectID, err := projectRepository.CreateProject(context.TODO(), domain.Project{Name: "Launch", OwnerID: "user_1", Members: []domain.ProjectMember{{UserID: "user_1", Role: domain.PROJECT_ROLE_OWNER}}})
+	suite.Nil(err, "Nil creating project")
+	project, err := projectRepository.SetMember(context.TODO(), projectID, domain.ProjectMember{UserID: "user_2", Role: domain.PROJECT_ROLE_VIEWER})
+	suite.Nil(err, "Nil adding member")
+	suite.Equal(2, len(project.Members))
+	project, err = projectRepository.SetMember(context.TODO(), projectID, domain.ProjectMember{UserID: "user_2", Role: domain.PROJECT_ROLE_EDITOR})
+	suite.Nil(err, "Nil changing role")
+	suite.Equal([]domain.ProjectMember{{UserID: "user_1", Role: domain.PROJECT_ROLE_OWNER}, {UserID: "user_2", Role: domain.PROJECT_ROLE_EDITOR}}, project.Members, "A member should only be listed once")
+
+	projects, err := projectRepository.FetchProjects(context.TODO(), "user_2")
+	suite.Nil(err, "Nil fetching projects")
+	suite.Equal(1, len(projects))
+	project, err = projectRepository.RemoveMember(context.TODO(), projectID, "user_2")
+	suite.Nil(err, "Nil removing member")
+	suite.Equal(1, len(project.Members))
+	projects, _ = projectRepository.FetchProjects(context.TODO(), "user_2")
+	suite.Empty(projects, "Former members should not see the project")
+
+	suite.Nil(projectRepository.DeleteProject(context.TODO(), projectID), "Nil deleting project")
+	_, err = projectRepository.FetchProjectByID(context.TODO(), projectID)
+	suite.NotNil(err, "A deleted project should not be found")
+}
+
+func (suite *testRepositorySuite) TestProjectScope() {
+	inProject := context.WithValue(context.TODO(), infrastructure.CONTEXT_PROJECT, "project_1")
+	asUser := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "user")
+	projectTaskID, err := suite.repository.CreateTask(inProject, domain.Task{UserID: "user_1", Title: "Scoped"})
+	suite.Nil(err, "Nil creating task")
+	looseTaskID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Loose"})
+	suite.Nil(err, "Nil creating task")
+
+	projectTask, err := suite.repository.FetchTaskByID(context.TODO(), projectTaskID)
+	suite.Nil(err, "Nil fetching task")
+	suite.Equal("project_1", projectTask.ProjectID, "Tasks created in a project should belong to it")
+	_, err = suite.repository.FetchTaskByID(inProject, looseTaskID)
+	suite.NotNil(err, "Tasks of other projects should not be reachable")
+	_, err = suite.repository.FetchTaskByID(asUser, projectTaskID)
+	suite.NotNil(err, "Users should not reach project tasks outside of the project")
+	_, err = suite.repository.UpdateTask(asUser, domain.Task{ID: projectTaskID, Title: "Renamed"})
+	suite.NotNil(err, "Users should not change project tasks outside of the project")
+	_, err = suite.repository.AddDependency(context.TODO(), projectTaskID, looseTaskID)
+	suite.Nil(err, "Nil adding dependency")
+	dependencies, err := suite.repository.FetchDependencies(asUser, []string{projectTaskID})
+	suite.Nil(err, "Nil fetching dependencies")
+	suite.Equal([]domain.Task{{ID: projectTaskID, BlockedBy: []string{looseTaskID}}}, dependencies, "Cycle checks should follow tasks the caller cannot see")
+
+	page, err := suite.repository.FetchTasks(inProject, domain.TaskQuery{SortOrder: 1, Limit: 10})
+	suite.Nil(err, "Nil fetching tasks")
+	suite.Equal(1, len(page.Tasks))
+	suite.Equal(projectTaskID, page.Tasks[0].ID)
+	page, err = suite.repository.FetchTasks(context.TODO(), domain.TaskQuery{SortOrder: 1, Limit: 10})
+	suite.Nil(err, "Nil fetching tasks")
+	suite.Equal(2, len(page.Tasks), "Admins and the schedulers should reach every task")
+}
+
+func (suite *testRepositorySuite) TestTenantScope() {
+	inAcme := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "acme")
+	inDefault := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "")
+	acmeTaskID, err := suite.repository.CreateTask(inAcme, domain.Task{UserID: "user_1", Title: "Acme", OrgID: "globex"})
+	suite.Nil(err, "Nil creating task")
+	defaultTaskID, err := suite.repository.CreateTask(inDefault, domain.Task{UserID: "user_2", Title: "Default"})
+	suite.Nil(err, "Nil creating task")
+
+	acmeTask, err := suite.repository.FetchTaskByID(inAcme, acmeTaskID)
+	suite.Nil(err, "Nil fetching task")
+	suite.Equal("acme", acmeTask.OrgID, "Tasks should belong to the organization of the request")
+	_, err = suite.repository.FetchTaskByID(inAcme, defaultTaskID)
+	suite.NotNil(err, "Tasks of other organizations should not be reachable")
+	_, err = suite.repository.FetchTaskByID(inDefault, acmeTaskID)
+	suite.NotNil(err, "Tasks of other organizations should not be reachable")
+	_, err = suite.repository.DeleteTask(inDefault, acmeTaskID, 0)
+	suite.NotNil(err, "Tasks of other organizations should not be deleted")
+
+	page, err := suite.repository.FetchTasks(inAcme, domain.TaskQuery{SortOrder: 1, Limit: 10})
+	suite.Nil(err, "Nil fetching tasks")
+	suite.Equal(1, len(page.Tasks))
+	suite.Equal(acmeTaskID, page.Tasks[0].ID)
+	_, err = suite.repository.DeleteTask(inAcme, acmeTaskID, 0)
+	suite.Nil(err, "Nil deleting task")
+	deleted, err := suite.repository.FetchDeletedTasks(inDefault, time.Time{}, 10)
+	suite.Nil(err, "Nil fetching trash")
+	suite.Empty(deleted, "The trash of other organizations should not be reachable")
+	page, err = suite.repository.FetchTasks(context.TODO(), domain.TaskQuery{SortOrder: 1, Limit: 10})
+	suite.Nil(err, "Nil fetching tasks")
+	suite.Equal(1, len(page.Tasks), "The schedulers should reach every organization")
+
+	users := repositorie.NewUserRepository(suite.repository.Collection.Database().Collection("users_tenant_test"))
+	defer users.Collection.Drop(context.TODO())
+	_, errUser := users.CreateUser(context.TODO(), domain.User{Username: "abebe", Password: "secret", OrgID: "acme"})
+	suite.Nil(errUser, "Nil creating user")
+	count, errUser := users.FetchUserCount(inAcme)
+	suite.Nil(errUser, "Nil counting users")
+	suite.Equal(1, count)
+	count, _ = users.FetchUserCount(inDefault)
+	suite.Equal(0, count, "Users of other organizations should not be counted")
+	_, errUser = users.FetchUserByUsername(inDefault, "abebe")
+	suite.NotNil(errUser, "Users of other organizations should not be reachable")
+	user, errUser := users.FetchUserByUsername(context.TODO(), "abebe")
+	suite.Nil(errUser, "Logins should find the user in any organization")
+	suite.Equal("acme", user.OrgID)
+}
+
+func (suite *testRepositorySuite) TestTenantScope_AuditAndRevisions() {
+	inAcme := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "acme")
+	inDefault := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "")
+
+	audits := repositorie.NewAuditRepository(suite.repository.Collection.Database().Collection("audit_tenant_test"))
+	defer audits.Collection.Drop(context.TODO())
+	suite.Nil(audits.InsertEntry(inAcme, domain.AuditEntry{Actor: "abebe", Action: domain.AUDIT_TASK_UPDATE, OrgID: "globex"}))
+	// entries recorded without a token keep the organization of their target
+	suite.Nil(audits.InsertEntry(context.TODO(), domain.AuditEntry{Actor: "kebede", Action: domain.AUDIT_USER_LOGIN, OrgID: "acme"}))
+	suite.Nil(audits.InsertEntry(inDefault, domain.AuditEntry{Actor: "almaz", Action: domain.AUDIT_TASK_UPDATE}))
+	entries, err := audits.FetchEntries(inAcme, domain.AuditQuery{Limit: 10})
+	suite.Nil(err, "Nil fetching audit entries")
+	suite.Equal(2, len(entries), "Only the entries of the organization should be reachable")
+	for _, entry := range entries {
+		suite.Equal("acme", entry.OrgID)
+	}
+	entries, _ = audits.FetchEntries(inDefault, domain.AuditQuery{Limit: 10})
+	suite.Equal(1, len(entries))
+	suite.Equal("almaz", entries[0].Actor)
+
+	revisions := repositorie.NewRevisionRepository(suite.repository.Collection.Database().Collection("revision_tenant_test"))
+	defer revisions.Collection.Drop(context.TODO())
+	suite.Nil(revisions.CreateRevision(inAcme, domain.TaskRevision{TaskID: "task_1", Number: 1, Task: domain.Task{Title: "Acme"}}))
+	_, errFetch := revisions.FetchRevision(inDefault, "task_1", 1)
+	suite.NotNil(errFetch, "Revisions of other organizations should not be reachable")
+	fetched, _ := revisions.FetchRevisions(inDefault, "task_1")
+	suite.Empty(fetched)
+	suite.Nil(revisions.DeleteRevisions(inDefault, "task_1"))
+	fetched, _ = revisions.FetchRevisions(inAcme, "task_1")
+	suite.Equal(1, len(fetched), "Revisions of other organizations should not be deleted")
+}
+
+func (suite *testRepositorySuite) TestAssigneesAndWatchers() {
+	taskID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Shared"})
+	suite.Nil(err, "Nil creating task")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Solo"})
+	suite.Nil(err, "Nil creating task")
+
+	task, err := suite.repository.AddAssignee(context.TODO(), taskID, "user_2")
+	suite.Nil(err, "Nil assigning")
+	task, err = suite.repository.AddAssignee(context.TODO(), taskID, "user_2")
+	suite.Nil(err, "Nil assigning again")
+	suite.Equal([]string{"user_2"}, task.Assignees, "An assignee should only be listed once")
+	task, err = suite.repository.AddWatcher(context.TODO(), taskID, "user_3")
+	suite.Nil(err, "Nil watching")
+	suite.Equal([]string{"user_3"}, task.Watchers)
+
+	page, err := suite.repository.FetchTasks(context.TODO(), domain.TaskQuery{AssigneeID: "user_2", SortOrder: 1, Limit: 10})
+	suite.Nil(err, "Nil fetching tasks")
+	suite.Equal(1, len(page.Tasks))
+	suite.Equal(taskID, page.Tasks[0].ID)
+
+	task, err = suite.repository.RemoveAssignee(context.TODO(), taskID, "user_2")
+	suite.Nil(err, "Nil unassigning")
+	suite.Empty(task.Assignees)
+	task, err = suite.repository.RemoveWatcher(context.TODO(), taskID, "user_3")
+	suite.Nil(err, "Nil unwatching")
+	suite.Empty(task.Watchers)
+}
+
+func (suite *testRepositorySuite) TestTimeEntries() {
+	collection := suite.repository.Collection.Database().Collection("time_entries_test")
+	defer collection.Drop(context.TODO())
+	err := infrastructure.EstablisPartialUniqueIndex(collection, "userID", bson.D{{"running", true}})
+	suite.Nil(err, "Nil creating index")
+	timeRepository := repositorie.NewTimeEntryRepository(collection)
+	day := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	runningID, errCreate := timeRepository.CreateTimeEntry(context.TODO(), domain.TimeEntry{UserID: "user_1", TaskID: "task_1", ProjectID: "project_1", Start: day, Running: true})
+	suite.Nil(errCreate, "Nil starting timer")
+	_, errCreate = timeRepository.CreateTimeEntry(context.TODO(), domain.TimeEntry{UserID: "user_1", TaskID: "task_2", Start: day, Running: true})
+	suite.NotNil(errCreate, "A user should only run one timer")
+	_, errCreate = timeRepository.CreateTimeEntry(context.TODO(), domain.TimeEntry{UserID: "user_1", TaskID: "task_1", ProjectID: "project_1", Start: day.Add(-time.Hour), End: day.Add(-30 * time.Minute), Manual: true})
+	suite.Nil(errCreate, "Nil adding entry")
+	_, errCreate = timeRepository.CreateTimeEntry(context.TODO(), domain.TimeEntry{UserID: "user_2", TaskID: "task_1", ProjectID: "project_1", Start: day.Add(24 * time.Hour), End: day.Add(25 * time.Hour), Manual: true})
+	suite.Nil(errCreate, "Nil adding entry")
+
+	running, errFetch := timeRepository.FetchRunningEntry(context.TODO(), "user_1")
+	suite.Nil(errFetch, "Nil fetching timer")
+	suite.Equal(runningID, running.ID)
+	stopped, errStop := timeRepository.StopTimeEntry(context.TODO(), runningID, day.Add(2*time.Hour))
+	suite.Nil(errStop, "Nil stopping timer")
+	suite.False(stopped.Running)
+	_, errStop = timeRepository.StopTimeEntry(context.TODO(), runningID, day.Add(3*time.Hour))
+	suite.NotNil(errStop, "A stopped timer should not be stopped again")
+
+	entries, errFetch := timeRepository.FetchTimeEntries(context.TODO(), domain.TimeQuery{UserID: "user_1"})
+	suite.Nil(errFetch, "Nil fetching entries")
+	suite.Equal(2, len(entries))
+	suite.True(entries[0].Manual, "Entries should be sorted by start")
+
+	rows, errReport := timeRepository.FetchTimeReport(context.TODO(), domain.TimeQuery{ProjectID: "project_1"})
+	suite.Nil(errReport, "Nil fetching report")
+	suite.Equal([]domain.TimeReportRow{
+		{ProjectID: "project_1", UserID: "user_1", Day: "2026-03-02", Seconds: 9000},
+		{ProjectID: "project_1", UserID: "user_2", Day: "2026-03-03", Seconds: 3600},
+	}, rows)
+}
+
+func (suite *testRepositorySuite) TestRefreshTokens() {
+	collection := suite.repository.Collection.Database().Collection("refresh_tokens_test")
+	defer collection.Drop(context.TODO())
+	tokenRepository := repositorie.NewRefreshTokenRepository(collection)
+	now := time.Now()
+	first := domain.RefreshToken{ID: "first", FamilyID: "first", UserID: "user_1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	second := domain.RefreshToken{ID: "second", FamilyID: "first", UserID: "user_1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := domain.RefreshToken{ID: "other", FamilyID: "other", UserID: "user_1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	expired := domain.RefreshToken{ID: "expired", FamilyID: "expired", UserID: "user_1", CreatedAt: now, ExpiresAt: now.Add(-time.Second)}
+	for _, token := range []domain.RefreshToken{first, second, other, expired} {
+		suite.Nil(tokenRepository.CreateRefreshToken(context.TODO(), token), "Nil creating token")
+	}
+
+	consumed, errConsume := tokenRepository.ConsumeRefreshToken(context.TODO(), "first", now)
+	suite.Nil(errConsume, "Nil consuming token")
+	suite.False(consumed.UsedAt.IsZero())
+	_, errConsume = tokenRepository.ConsumeRefreshToken(context.TODO(), "first", now)
+	suite.NotNil(errConsume, "A token should only be consumed once")
+	_, errConsume = tokenRepository.ConsumeRefreshToken(context.TODO(), "expired", now)
+	suite.NotNil(errConsume, "An expired token should not be consumed")
+
+	suite.Nil(tokenRepository.RevokeTokenFamily(context.TODO(), "first", now), "Nil revoking family")
+	revoked, errFetch := tokenRepository.FetchRefreshToken(context.TODO(), "second")
+	suite.Nil(errFetch, "Nil fetching token")
+	suite.False(revoked.RevokedAt.IsZero(), "Every token of the family should be revoked")
+	_, errConsume = tokenRepository.ConsumeRefreshToken(context.TODO(), "second", now)
+	suite.NotNil(errConsume, "A revoked token should not be consumed")
+	untouched, errFetch := tokenRepository.FetchRefreshToken(context.TODO(), "other")
+	suite.Nil(errFetch, "Nil fetching token")
+	suite.True(untouched.RevokedAt.IsZero(), "Other families should stay valid")
+	_, errFetch = tokenRepository.FetchRefreshToken(context.TODO(), "missing")
+	suite.NotNil(errFetch, "Unknown tokens should not be found")
+}
+
+func (suite *testRepositorySuite) TestEstimatedTasks() {
+	from := time.Now().Add(-24 * time.Hour)
+	openID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Open", Status: "todo", Estimate: 3, EstimateUnit: "points"})
+	suite.Nil(err, "Nil creating task")
+	doneID, err := suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Done", Status: "done", Estimate: 5, EstimateUnit: "points", CompletedAt: time.Now()})
+	suite.Nil(err, "Nil creating task")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Done long ago", Status: "done", Estimate: 8, EstimateUnit: "points", CompletedAt: from.Add(-time.Hour)})
+	suite.Nil(err, "Nil creating task")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Done before completion times", Status: "done", Estimate: 8, EstimateUnit: "points"})
+	suite.Nil(err, "Nil creating task")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Hours", Status: "todo", Estimate: 2, EstimateUnit: "hours"})
+	suite.Nil(err, "Nil creating task")
+	_, err = suite.repository.CreateTask(context.TODO(), domain.Task{UserID: "user_1", Title: "Unsized", Status: "todo"})
+	suite.Nil(err, "Nil creating task")
+
+	tasks, err := suite.repository.FetchEstimatedTasks(context.TODO(), domain.ReportQuery{Unit: "points", From: from, To: time.Now().Add(time.Hour)}, []string{"done"})
+	suite.Nil(err, "Nil fetching tasks")
+	IDs := []string{}
+	for _, task := range tasks {
+		IDs = append(IDs, task.ID)
+		suite.False(task.CreatedAt.IsZero(), "Tasks without a creation time should get the time of their ID")
+	}
+	suite.ElementsMatch([]string{openID, doneID}, IDs)
+
+	updated, err := suite.repository.UpdateTask(context.TODO(), domain.Task{ID: doneID, Status: "in_progress"})
+	suite.Nil(err, "Nil updating task")
+	suite.True(updated.CompletedAt.IsZero(), "A status without a completion time should clear it")
+}
+
 func (suite *testRepositorySuite) TestUpdatingTask() {
 	// Create an initial task
 	initialTask := domain.Task{
@@ -239,7 +903,7 @@ func (suite *testRepositorySuite) TestDeletingTask() {
 	suite.Nil(err, "Nil creating initial task")
 
 	// Perform deletion
-	fetchedTask, errDelete := suite.repository.DeleteTask(context.TODO(), insertedResult)
+	fetchedTask, errDelete := suite.repository.DeleteTask(context.TODO(), insertedResult, 0)
 	suite.Nil(errDelete, "Nil deleting task")
 
 	// Check if the task ID matches
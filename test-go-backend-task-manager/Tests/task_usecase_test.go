@@ -2,11 +2,14 @@ package tests
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
 	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -16,6 +19,7 @@ type taskUsecaseSuite struct {
 	suite.Suite
 	repositorie *mocks.TaskRepository
 	usecase     domain.TaskUsecase
+	admin       domain.User
 }
 
 func (suite *taskUsecaseSuite) SetupTest() {
@@ -23,6 +27,7 @@ func (suite *taskUsecaseSuite) SetupTest() {
 	taskUC := usecases.NewTaskUsecase(repo, time.Second*2)
 	suite.usecase = &taskUC
 	suite.repositorie = repo
+	suite.admin = domain.User{ID: "admin_1", Username: "admin", Role: "admin"}
 }
 
 func (suite *taskUsecaseSuite) TestGetAllTasks() {
@@ -69,6 +74,62 @@ func (suite *taskUsecaseSuite) TestGetAllTasks() {
 	suite.Equal(tasks, fetchedTasks, "users should be equal")
 }
 
+func (suite *taskUsecaseSuite) TestGetTasks_Defaults() {
+	page := domain.TaskPage{Tasks: []domain.Task{{ID: "task_001", Title: "Complete Go project"}}, Limit: usecases.DEFAULT_TASK_PAGE_LIMIT}
+	suite.repositorie.On("FetchTasks", mock.Anything, domain.TaskQuery{Limit: usecases.DEFAULT_TASK_PAGE_LIMIT, SortOrder: 1}).Return(page, nil)
+
+	fetchedPage, err := suite.usecase.GetTasks(context.TODO(), domain.TaskQuery{})
+	suite.Nil(err, "error should be nil")
+	suite.Equal(page, fetchedPage, "pages should be equal")
+}
+
+func (suite *taskUsecaseSuite) TestGetTasks_LimitCapped() {
+	query := domain.TaskQuery{SortBy: "due_date", SortOrder: -1, Limit: usecases.MAX_TASK_PAGE_LIMIT}
+	suite.repositorie.On("FetchTasks", mock.Anything, query).Return(domain.TaskPage{Limit: query.Limit}, nil)
+
+	query.Limit = usecases.MAX_TASK_PAGE_LIMIT * 10
+	_, err := suite.usecase.GetTasks(context.TODO(), query)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *taskUsecaseSuite) TestGetTasks_Negative() {
+	invalidQueries := []domain.TaskQuery{
+		{SortBy: "password"},
+		{SortOrder: 2},
+		{DueAfter: time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC), DueBefore: time.Date(2024, 8, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, query := range invalidQueries {
+		_, err := suite.usecase.GetTasks(context.TODO(), query)
+		suite.NotNil(err, "error should not be nil for an invalid query")
+		if err != nil {
+			suite.Equal(400, err.Code, "error code should be 400")
+		}
+	}
+	suite.repositorie.AssertNotCalled(suite.T(), "FetchTasks", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestSearchTasks() {
+	tasks := []domain.Task{
+		{ID: "task_001", Title: "Complete Go project", Description: "Finish writing the Go code and add tests."},
+		{ID: "task_002", Title: "Review PRs", Description: "Review the pull requests for the Go backend."},
+	}
+	suite.repositorie.On("SearchTasks", mock.Anything, "go tests", usecases.DEFAULT_TASK_PAGE_LIMIT).Return(
+		func(cxt context.Context, query string, limit int) ([]domain.TaskSearchResult, *domain.TaskError) {
+			return infrastructure.SearchTasksInMemory(tasks, query, limit), nil
+		})
+
+	results, err := suite.usecase.SearchTasks(context.TODO(), "  go tests ", 0)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(2, len(results), "both tasks mention go")
+	suite.Equal("task_001", results[0].Task.ID, "the task matching both terms should rank first")
+}
+
+func (suite *taskUsecaseSuite) TestSearchTasks_EmptyQuery() {
+	_, err := suite.usecase.SearchTasks(context.TODO(), "   ", 10)
+	suite.NotNil(err, "error should not be nil for an empty query")
+	suite.repositorie.AssertNotCalled(suite.T(), "SearchTasks", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (suite *taskUsecaseSuite) TestGetTaskByID() {
 	tasks := domain.Task{
 		ID:          "task_001",
@@ -94,7 +155,7 @@ func (suite *taskUsecaseSuite) TestCreateTask_Positive() {
 		UserID:      "user_123",
 		Title:       "Complete Go project",
 		Description: "Finish writing the Go code and add tests.",
-		Status:      "In Progress",
+		Status:      "in_progress",
 		Priority:    "High",
 		DueDate:     time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC),
 		CreatedAt:   time.Now(),
@@ -107,6 +168,40 @@ func (suite *taskUsecaseSuite) TestCreateTask_Positive() {
 	suite.Equal(ID, fetchID, "users should be equal")
 }
 
+func (suite *taskUsecaseSuite) TestCreateTask_DefaultStatus() {
+	task := domain.Task{UserID: "user_123", Title: "Complete Go project"}
+	created := task
+	created.Status = usecases.STATUS_TODO
+	suite.repositorie.On("CreateTask", mock.Anything, created).Return("task_001", nil)
+
+	_, err := suite.usecase.CreateTask(context.TODO(), task)
+	suite.Nil(err, "error should be nil")
+	suite.repositorie.AssertCalled(suite.T(), "CreateTask", mock.Anything, created)
+}
+
+func (suite *taskUsecaseSuite) TestCreateTask_IgnoresServerOwnedFields() {
+	task := domain.Task{
+		UserID:           "user_123",
+		Title:            "Complete Go project",
+		Attachments:      []domain.Attachment{{ID: "a1", Filename: "forged.pdf", SHA256: "abc123", Size: 8, UploadedBy: "user_456"}},
+		NextOccurrenceID: "task_999",
+	}
+	created := domain.Task{UserID: "user_123", Title: "Complete Go project", Status: usecases.STATUS_TODO}
+	suite.repositorie.On("CreateTask", mock.Anything, created).Return("task_001", nil)
+
+	_, err := suite.usecase.CreateTask(context.TODO(), task)
+	suite.Nil(err, "error should be nil")
+	suite.repositorie.AssertCalled(suite.T(), "CreateTask", mock.Anything, created)
+}
+
+func (suite *taskUsecaseSuite) TestCreateTask_UnknownStatus() {
+	task := domain.Task{UserID: "user_123", Title: "Complete Go project", Status: "Someday"}
+
+	_, err := suite.usecase.CreateTask(context.TODO(), task)
+	suite.NotNil(err, "error should not be nil for a status outside the workflow")
+	suite.Equal(400, err.Code)
+}
+
 func (suite *taskUsecaseSuite) TestCreateTask_Negative() {
 	ID := "task_001"
 	tasks := domain.Task{
@@ -131,19 +226,641 @@ func (suite *taskUsecaseSuite) TestUpdateTask() {
 		UserID:      "user_123",
 		Title:       "",
 		Description: "Finish writing the Go code and add tests.",
-		Status:      "In Progress",
+		Status:      "in_progress",
 		Priority:    "High",
 		DueDate:     time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, tasks.ID).Return(domain.Task{ID: tasks.ID, Status: "todo"}, nil)
 	suite.repositorie.On("UpdateTask", mock.Anything, tasks).Return(tasks, nil)
 
-	fetchedTask, err := suite.usecase.UpdateTask(context.TODO(), tasks)
+	fetchedTask, err := suite.usecase.UpdateTask(context.TODO(), tasks, suite.admin)
 	suite.Nil(err, "error should be nil")
 	suite.Equal(tasks, fetchedTask, "tasks should be equal")
 }
 
+func (suite *taskUsecaseSuite) TestUpdateTask_IllegalTransition() {
+	task := domain.Task{ID: "task_001", UserID: "user_123", Title: "Complete Go project", Status: "done"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, task.ID).Return(domain.Task{ID: task.ID, Status: "todo"}, nil)
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	_, err := suite.usecase.UpdateTask(adminContext, task, suite.admin)
+	suite.NotNil(err, "todo cannot jump straight to done")
+	suite.Equal(409, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_TransitionRoles() {
+	task := domain.Task{ID: "task_001", UserID: "user_123", Title: "Complete Go project", Status: "done"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, task.ID).Return(domain.Task{ID: task.ID, Status: "review"}, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, task.ID).Return([]domain.Task{}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, mock.MatchedBy(func(closed domain.Task) bool {
+		return closed.Status == "done" && !closed.CompletedAt.IsZero()
+	})).Return(task, nil)
+
+	userContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "user")
+	_, err := suite.usecase.UpdateTask(userContext, task, suite.admin)
+	suite.NotNil(err, "only admins may close a task")
+	suite.Equal(409, err.Code)
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	_, err = suite.usecase.UpdateTask(adminContext, task, suite.admin)
+	suite.Nil(err, "admins may close a reviewed task")
+}
+
+func (suite *taskUsecaseSuite) TestNextStatuses() {
+	userContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "user")
+	next, err := suite.usecase.NextStatuses(userContext, "review")
+	suite.Nil(err, "error should be nil")
+	suite.Equal([]string{"in_progress"}, next)
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	next, err = suite.usecase.NextStatuses(adminContext, "review")
+	suite.Nil(err, "error should be nil")
+	suite.Equal([]string{"in_progress", "done"}, next)
+
+	_, err = suite.usecase.NextStatuses(adminContext, "Someday")
+	suite.NotNil(err, "error should not be nil for an unknown status")
+}
+
+func (suite *taskUsecaseSuite) TestValidateWorkflow() {
+	suite.Nil(usecases.ValidateWorkflow(usecases.DefaultWorkflow()), "default workflow should be valid")
+
+	broken := usecases.DefaultWorkflow()
+	broken.Transitions = append(broken.Transitions, domain.WorkflowTransition{From: "done", To: "archived"})
+	suite.NotNil(usecases.ValidateWorkflow(broken), "transitions to undeclared statuses should be rejected")
+}
+
+func (suite *taskUsecaseSuite) TestGetTaskTree() {
+	subtree := []domain.Task{
+		{ID: "root", Title: "Release", Status: "in_progress"},
+		{ID: "a", ParentID: "root", Title: "Write code", Status: "done"},
+		{ID: "b", ParentID: "root", Title: "Write docs", Status: "in_progress"},
+		{ID: "b1", ParentID: "b", Title: "API docs", Status: "done"},
+		{ID: "b2", ParentID: "b", Title: "User guide", Status: "todo"},
+	}
+	suite.repositorie.On("FetchSubtree", mock.Anything, "root").Return(subtree, nil)
+
+	tree, err := suite.usecase.GetTaskTree(context.TODO(), "root")
+	suite.Nil(err, "error should be nil")
+	suite.Equal("root", tree.Task.ID)
+	suite.Equal(2, len(tree.Children))
+	suite.Equal(100.0, tree.Children[0].PercentComplete, "a finished leaf is complete")
+	suite.Equal(50.0, tree.Children[1].PercentComplete, "half of the docs subtasks are done")
+	suite.Equal(75.0, tree.PercentComplete, "the root averages its children")
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_OpenSubtasks() {
+	task := domain.Task{ID: "root", UserID: "user_123", Title: "Release", Status: "done"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, task.ID).Return(domain.Task{ID: task.ID, Status: "review"}, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, task.ID).Return([]domain.Task{{ID: "a", ParentID: "root", Status: "todo"}}, nil)
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	_, err := suite.usecase.UpdateTask(adminContext, task, suite.admin)
+	suite.NotNil(err, "a parent cannot be done while a subtask is open")
+	suite.Equal(409, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_ParentCycle() {
+	task := domain.Task{ID: "root", UserID: "user_123", Title: "Release", ParentID: "b1"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, task.ID).Return(domain.Task{ID: task.ID, UserID: "user_123"}, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "b1").Return(domain.Task{ID: "b1", ParentID: "b"}, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "b").Return(domain.Task{ID: "b", ParentID: "root"}, nil)
+
+	_, err := suite.usecase.UpdateTask(context.TODO(), task, suite.admin)
+	suite.NotNil(err, "a task cannot be moved under its own subtask")
+	suite.Equal(400, err.Code)
+}
+
+func (suite *taskUsecaseSuite) TestCreateTask_MissingParent() {
+	task := domain.Task{UserID: "user_123", Title: "Write docs", ParentID: "missing"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "missing").Return(domain.Task{}, &domain.TaskError{Message: "mongo: no documents in result", Code: 500})
+
+	_, err := suite.usecase.CreateTask(context.TODO(), task)
+	suite.NotNil(err, "the parent task has to exist")
+	suite.Equal(400, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestAddDependency() {
+	task := domain.Task{ID: "b", Title: "Deploy", Status: "todo"}
+	blocker := domain.Task{ID: "a", Title: "Build", Status: "in_progress"}
+	suite.repositorie.On("FetchTasksByIDs", mock.Anything, []string{"b", "a"}).Return([]domain.Task{task, blocker}, nil)
+	suite.repositorie.On("FetchTasksByIDs", mock.Anything, []string{"a"}).Return([]domain.Task{blocker}, nil)
+	suite.repositorie.On("FetchDependencies", mock.Anything, []string{"a"}).Return([]domain.Task{{ID: "a"}}, nil)
+	blockedTask := task
+	blockedTask.BlockedBy = []string{"a"}
+	suite.repositorie.On("AddDependency", mock.Anything, "b", "a").Return(blockedTask, nil)
+
+	updatedTask, err := suite.usecase.AddDependency(context.TODO(), "b", "a")
+	suite.Nil(err, "error should be nil")
+	suite.Equal([]string{"a"}, updatedTask.BlockedBy)
+	suite.True(updatedTask.Blocked, "the task waits on an open blocker")
+}
+
+func (suite *taskUsecaseSuite) TestAddDependency_Cycle() {
+	// c is blocked by b which is blocked by a, so a cannot be blocked by c
+	suite.repositorie.On("FetchTasksByIDs", mock.Anything, []string{"a", "c"}).Return([]domain.Task{{ID: "a"}, {ID: "c", BlockedBy: []string{"b"}}}, nil)
+	suite.repositorie.On("FetchDependencies", mock.Anything, []string{"c"}).Return([]domain.Task{{ID: "c", BlockedBy: []string{"b"}}}, nil)
+	suite.repositorie.On("FetchDependencies", mock.Anything, []string{"b"}).Return([]domain.Task{{ID: "b", BlockedBy: []string{"a"}}}, nil)
+
+	_, err := suite.usecase.AddDependency(context.TODO(), "a", "c")
+	suite.NotNil(err, "error should not be nil for a dependency cycle")
+	suite.Equal(409, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "AddDependency", mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = suite.usecase.AddDependency(context.TODO(), "a", "a")
+	suite.NotNil(err, "a task cannot block itself")
+}
+
+func (suite *taskUsecaseSuite) TestAddDependency_CycleThroughHiddenTask() {
+	// b is in a project the caller is not a member of, it only shows up in the dependencies
+	suite.repositorie.On("FetchTasksByIDs", mock.Anything, []string{"a", "c"}).Return([]domain.Task{{ID: "a"}, {ID: "c", BlockedBy: []string{"b"}}}, nil)
+	suite.repositorie.On("FetchDependencies", mock.Anything, []string{"c"}).Return([]domain.Task{{ID: "c", BlockedBy: []string{"b"}}}, nil)
+	suite.repositorie.On("FetchDependencies", mock.Anything, []string{"b"}).Return([]domain.Task{{ID: "b", BlockedBy: []string{"a"}}}, nil)
+
+	asUser := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "user")
+	_, err := suite.usecase.AddDependency(asUser, "a", "c")
+	suite.NotNil(err, "error should not be nil for a dependency cycle")
+	suite.Equal(409, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "FetchTasksByIDs", mock.Anything, []string{"b"})
+	suite.repositorie.AssertNotCalled(suite.T(), "AddDependency", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestPlanTasks() {
+	tasks := []domain.Task{
+		{ID: "deploy", Status: "todo", BlockedBy: []string{"build", "test"}},
+		{ID: "test", Status: "todo", BlockedBy: []string{"build"}},
+		{ID: "build", Status: "done"},
+		{ID: "docs", Status: "todo", BlockedBy: []string{"external"}},
+	}
+	suite.repositorie.On("FetchTasksByIDs", mock.Anything, []string{"deploy", "test", "build", "docs"}).Return(tasks, nil)
+	suite.repositorie.On("FetchTasksByIDs", mock.Anything, []string{"build", "test", "external"}).Return([]domain.Task{tasks[1], tasks[2], {ID: "external", Status: "review"}}, nil)
+
+	plan, err := suite.usecase.PlanTasks(context.TODO(), []string{"deploy", "test", "build", "docs"})
+	suite.Nil(err, "error should be nil")
+	order := []string{}
+	for _, task := range plan {
+		order = append(order, task.ID)
+	}
+	suite.Equal([]string{"build", "test", "deploy", "docs"}, order, "blockers come before the tasks they block")
+	suite.False(plan[1].Blocked, "test only waits on a finished build")
+	suite.True(plan[2].Blocked, "deploy waits on the open test task")
+	suite.True(plan[3].Blocked, "docs waits on an open task outside the plan")
+}
+
+func (suite *taskUsecaseSuite) TestCreateTask_Recurrence() {
+	dueDate := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	task := domain.Task{UserID: "user_123", Title: "Water plants", DueDate: dueDate, Recurrence: "FREQ=WEEKLY;BYDAY=MO"}
+	suite.repositorie.On("CreateTask", mock.Anything, mock.MatchedBy(func(created domain.Task) bool {
+		return created.RecurrenceStart.Equal(dueDate)
+	})).Return("task_001", nil)
+
+	_, err := suite.usecase.CreateTask(context.TODO(), task)
+	suite.Nil(err, "the series should start at the due date")
+
+	task.DueDate = time.Time{}
+	_, err = suite.usecase.CreateTask(context.TODO(), task)
+	suite.NotNil(err, "a recurring task needs a due date")
+
+	task.DueDate = dueDate
+	task.Recurrence = "FREQ=FORTNIGHTLY"
+	_, err = suite.usecase.CreateTask(context.TODO(), task)
+	suite.NotNil(err, "an invalid rule should be rejected")
+	suite.Equal(400, err.Code)
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_CompletesRecurringTask() {
+	dueDate := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	current := domain.Task{ID: "task_001", UserID: "user_123", ProjectID: "project_1", Title: "Water plants", Status: "review", DueDate: dueDate, Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH", RecurrenceStart: dueDate}
+	completed := current
+	completed.Status = "done"
+	linked := completed
+	linked.NextOccurrenceID = "task_002"
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, current.ID).Return([]domain.Task{}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, mock.Anything).Return(completed, nil)
+	suite.repositorie.On("CreateTask", mock.Anything, mock.MatchedBy(func(next domain.Task) bool {
+		return next.DueDate.Equal(dueDate.AddDate(0, 0, 3)) && next.Status == "todo" && next.Title == current.Title && next.RecurrenceStart.Equal(dueDate) && next.ProjectID == current.ProjectID
+	})).Return("task_002", nil)
+	suite.repositorie.On("LinkNextOccurrence", mock.Anything, current.ID, "task_002").Return(linked, nil)
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	returnedTask, err := suite.usecase.UpdateTask(adminContext, domain.Task{ID: current.ID, Status: "done", NextOccurrenceID: "task_999"}, suite.admin)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(linked, returnedTask)
+	suite.repositorie.AssertCalled(suite.T(), "UpdateTask", mock.Anything, mock.MatchedBy(func(update domain.Task) bool {
+		return update.Status == "done" && update.NextOccurrenceID == "" && !update.CompletedAt.IsZero()
+	}))
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_OccurrenceCreatedLikeAnyTask() {
+	revisions, auditLog := new(mocks.RevisionRepository), new(mocks.AuditRecorder)
+	taskUC := usecases.NewTaskUsecase(suite.repositorie, time.Second*2)
+	taskUC.SetRevisionRepository(revisions)
+	taskUC.SetAuditLog(auditLog)
+	dueDate := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Water plants", Status: "review", Tags: []string{"garden"}, Assignees: []string{"user_456"}, Watchers: []string{"user_789"}, DueDate: dueDate, Recurrence: "FREQ=WEEKLY", RecurrenceStart: dueDate, Revision: 2}
+	completed := current
+	completed.Status, completed.Revision = "done", 3
+	linked := completed
+	linked.NextOccurrenceID, linked.Revision = "task_002", 4
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, current.ID).Return([]domain.Task{}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, mock.Anything).Return(completed, nil)
+	suite.repositorie.On("CreateTask", mock.Anything, mock.Anything).Return("task_002", nil)
+	suite.repositorie.On("LinkNextOccurrence", mock.Anything, current.ID, "task_002").Return(linked, nil)
+	revisions.On("CreateRevision", mock.Anything, mock.Anything).Return(nil)
+	auditLog.On("Record", mock.Anything, mock.Anything).Return()
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	_, err := taskUC.UpdateTask(adminContext, domain.Task{ID: current.ID, Status: "done"}, suite.admin)
+	suite.Nil(err, "error should be nil")
+	suite.repositorie.AssertCalled(suite.T(), "CreateTask", mock.Anything, mock.MatchedBy(func(next domain.Task) bool {
+		return slices.Equal(next.Tags, current.Tags) && slices.Equal(next.Assignees, current.Assignees) && slices.Equal(next.Watchers, current.Watchers)
+	}))
+	revisions.AssertCalled(suite.T(), "CreateRevision", mock.Anything, mock.MatchedBy(func(revision domain.TaskRevision) bool {
+		return revision.TaskID == "task_002" && revision.Number == 1 && revision.Action == domain.REVISION_CREATE && slices.Equal(revision.Task.Tags, current.Tags)
+	}))
+	auditLog.AssertCalled(suite.T(), "Record", mock.Anything, mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.TargetID == "task_002" && entry.Action == domain.AUDIT_TASK_CREATE
+	}))
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_RecurringConflict() {
+	dueDate := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	current := domain.Task{ID: "task_001", Title: "Water plants", Status: "review", DueDate: dueDate, Recurrence: "FREQ=WEEKLY", RecurrenceStart: dueDate, Version: 3}
+	conflict := &domain.TaskError{Message: "Task has been modified, it is now at version 4", Code: 412}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, current.ID).Return([]domain.Task{}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, mock.Anything).Return(current, conflict)
+
+	// a retry after the conflict must not leave another occurrence behind either
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	for i := 0; i < 2; i++ {
+		_, err := suite.usecase.UpdateTask(adminContext, domain.Task{ID: current.ID, Status: "done", Version: 3}, suite.admin)
+		suite.NotNil(err, "error should not be nil")
+		suite.Equal(412, err.Code)
+	}
+	suite.repositorie.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+	suite.repositorie.AssertNotCalled(suite.T(), "LinkNextOccurrence", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_RecurringAlreadyLinked() {
+	dueDate := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	current := domain.Task{ID: "task_001", Title: "Water plants", Status: "review", DueDate: dueDate, Recurrence: "FREQ=WEEKLY", RecurrenceStart: dueDate}
+	completed := current
+	completed.Status = "done"
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, current.ID).Return([]domain.Task{}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, mock.Anything).Return(completed, nil)
+	suite.repositorie.On("CreateTask", mock.Anything, mock.Anything).Return("task_002", nil)
+	suite.repositorie.On("LinkNextOccurrence", mock.Anything, current.ID, "task_002").Return(domain.Task{}, &domain.TaskError{Message: "Task already has a next occurrence", Code: 409})
+	suite.repositorie.On("DeleteTask", mock.Anything, "task_002", 0).Return(domain.Task{}, nil)
+	suite.repositorie.On("PurgeTask", mock.Anything, "task_002", time.Time{}).Return(nil)
+
+	// another completion linked its occurrence first, this one is discarded
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	returnedTask, err := suite.usecase.UpdateTask(adminContext, domain.Task{ID: current.ID, Status: "done"}, suite.admin)
+	suite.Nil(err, "the completion should stand")
+	suite.Equal(completed, returnedTask)
+	suite.repositorie.AssertCalled(suite.T(), "PurgeTask", mock.Anything, "task_002", time.Time{})
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_RecurringSeriesEnded() {
+	dueDate := time.Date(2024, time.January, 3, 9, 0, 0, 0, time.UTC)
+	current := domain.Task{ID: "task_001", Title: "Water plants", Status: "review", DueDate: dueDate, Recurrence: "FREQ=DAILY;COUNT=3", RecurrenceStart: dueDate.AddDate(0, 0, -2)}
+	completed := current
+	completed.Status = "done"
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("FetchChildTasks", mock.Anything, current.ID).Return([]domain.Task{}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, mock.Anything).Return(completed, nil)
+
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	_, err := suite.usecase.UpdateTask(adminContext, domain.Task{ID: current.ID, Status: "done"}, suite.admin)
+	suite.Nil(err, "error should be nil")
+	suite.repositorie.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+	suite.repositorie.AssertNotCalled(suite.T(), "LinkNextOccurrence", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestPreviewOccurrences() {
+	dueDate := time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)
+	task := domain.Task{ID: "task_001", DueDate: dueDate, Recurrence: "FREQ=MONTHLY", RecurrenceStart: dueDate.AddDate(0, -1, 0)}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, task.ID).Return(task, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "task_002").Return(domain.Task{ID: "task_002"}, nil)
+
+	occurrences, err := suite.usecase.PreviewOccurrences(context.TODO(), task.ID, 2)
+	suite.Nil(err, "error should be nil")
+	suite.Equal([]time.Time{dueDate.AddDate(0, 1, 0), dueDate.AddDate(0, 2, 0)}, occurrences)
+
+	_, err = suite.usecase.PreviewOccurrences(context.TODO(), "task_002", 2)
+	suite.NotNil(err, "a task without a rule has no occurrences")
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTaskTags() {
+	suite.repositorie.On("UpdateTaskTags", mock.Anything, []string{"a", "b"}, []string{"urgent", "backend"}, []string{"later"}).Return(int64(2), nil)
+
+	updated, err := suite.usecase.UpdateTaskTags(context.TODO(), domain.TagUpdate{
+		TaskIDs: []string{"a", "b", "a"},
+		Add:     []string{" Urgent", "backend", "urgent"},
+		Remove:  []string{"LATER"},
+	})
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(2), updated)
+
+	_, err = suite.usecase.UpdateTaskTags(context.TODO(), domain.TagUpdate{TaskIDs: []string{"a"}, Add: []string{"urgent"}, Remove: []string{"Urgent"}})
+	suite.NotNil(err, "a tag cannot be added and removed at once")
+	suite.Equal(400, err.Code)
+}
+
+func (suite *taskUsecaseSuite) TestMergeTags() {
+	suite.repositorie.On("ReplaceTags", mock.Anything, []string{"bug", "defect"}, "issue").Return(int64(3), nil)
+	suite.repositorie.On("ReplaceTags", mock.Anything, []string{"todo"}, "backlog").Return(int64(1), nil)
+
+	merged, err := suite.usecase.MergeTags(context.TODO(), []string{"Bug", "defect"}, "Issue")
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(3), merged)
+
+	renamed, err := suite.usecase.RenameTag(context.TODO(), "todo", "backlog")
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(1), renamed)
+
+	_, err = suite.usecase.RenameTag(context.TODO(), "todo", " ")
+	suite.NotNil(err, "a tag cannot be renamed to nothing")
+}
+
+func (suite *taskUsecaseSuite) TestGetTasks_Tags() {
+	query := domain.TaskQuery{AnyTags: []string{"urgent", "bug"}, AllTags: []string{"backend"}, SortOrder: 1, Limit: usecases.DEFAULT_TASK_PAGE_LIMIT}
+	suite.repositorie.On("FetchTasks", mock.Anything, query).Return(domain.TaskPage{Tasks: []domain.Task{}}, nil)
+
+	_, err := suite.usecase.GetTasks(context.TODO(), domain.TaskQuery{AnyTags: []string{"Urgent", " bug"}, AllTags: []string{"BACKEND"}})
+	suite.Nil(err, "tag filters should be normalized")
+}
+
+func (suite *taskUsecaseSuite) TestCreateTask_TooManyTags() {
+	tags := []string{}
+	for i := 0; i <= usecases.MAX_TASK_TAGS; i++ {
+		tags = append(tags, fmt.Sprintf("tag%d", i))
+	}
+	_, err := suite.usecase.CreateTask(context.TODO(), domain.Task{UserID: "user_123", Title: "Tagged", Tags: tags})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(400, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestDeleteTask_MovesToTrash() {
+	task := domain.Task{ID: "task_001", UserID: "user_123", Title: "Discussed"}
+	trashed := task
+	trashed.DeletedAt = time.Now()
+	suite.repositorie.On("FetchTaskByID", mock.Anything, task.ID).Return(task, nil)
+	suite.repositorie.On("DeleteTask", mock.Anything, task.ID, 0).Return(trashed, nil)
+	listener := new(mocks.TaskDeleteListener)
+
+	taskUC := usecases.NewTaskUsecase(suite.repositorie, time.Second*2)
+	taskUC.AddDeleteListener(listener)
+	deletedTask, err := taskUC.DeleteTask(context.TODO(), task.ID, domain.User{ID: "user_123"}, 0)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(trashed, deletedTask)
+	listener.AssertNotCalled(suite.T(), "TaskDeleted", mock.Anything, mock.Anything)
+	suite.repositorie.AssertNotCalled(suite.T(), "UnlinkDependents", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestRestoreDeletedTask() {
+	restored := domain.Task{ID: "task_001", UserID: "user_123", Title: "Discussed", Version: 3}
+	suite.repositorie.On("UndeleteTask", mock.Anything, restored.ID).Return(restored, nil)
+	suite.repositorie.On("UndeleteTask", mock.Anything, "task_404").Return(domain.Task{}, &domain.TaskError{Message: "Task not found in the trash", Code: 404})
+
+	task, err := suite.usecase.RestoreDeletedTask(context.TODO(), restored.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(restored, task)
+	_, err = suite.usecase.RestoreDeletedTask(context.TODO(), "task_404")
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(404, err.Code)
+}
+
+func (suite *taskUsecaseSuite) TestPurgeDeletedTasks() {
+	revisions := new(mocks.RevisionRepository)
+	listener := new(mocks.TaskDeleteListener)
+	taskUC := usecases.NewTaskUsecase(suite.repositorie, time.Second*2)
+	taskUC.SetRevisionRepository(revisions)
+	taskUC.AddDeleteListener(listener)
+	cutoff := time.Now().Add(-time.Hour)
+	expired := []domain.Task{
+		{ID: "task_001", UserID: "user_123", Title: "Old", DeletedAt: cutoff.Add(-time.Hour)},
+		{ID: "task_002", UserID: "user_123", Title: "Stuck", DeletedAt: cutoff.Add(-time.Minute)},
+		{ID: "task_003", UserID: "user_123", Title: "Older", DeletedAt: cutoff.Add(-2 * time.Hour)},
+	}
+	suite.repositorie.On("FetchDeletedTasks", mock.Anything, cutoff, usecases.PURGE_BATCH_SIZE).Return(expired, nil)
+	for _, task := range expired {
+		revisions.On("DeleteRevisions", mock.Anything, task.ID).Return(nil)
+		suite.repositorie.On("UnlinkDependents", mock.Anything, task.ID).Return(nil)
+	}
+	listener.On("TaskDeleted", mock.Anything, expired[0]).Return(nil)
+	listener.On("TaskDeleted", mock.Anything, expired[1]).Return(&domain.TaskError{Message: "blob store unavailable", Code: 500})
+	listener.On("TaskDeleted", mock.Anything, expired[2]).Return(nil)
+	suite.repositorie.On("PurgeTask", mock.Anything, mock.Anything, cutoff).Return(nil)
+
+	purged, err := taskUC.PurgeDeletedTasks(context.TODO(), cutoff)
+	suite.NotNil(err, "the failed task should be reported")
+	suite.Equal(500, err.Code)
+	suite.Equal(2, purged, "the other tasks are still purged")
+	suite.repositorie.AssertCalled(suite.T(), "PurgeTask", mock.Anything, "task_001", cutoff)
+	suite.repositorie.AssertCalled(suite.T(), "PurgeTask", mock.Anything, "task_003", cutoff)
+	suite.repositorie.AssertNotCalled(suite.T(), "PurgeTask", mock.Anything, "task_002", cutoff)
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_VersionConflict() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Edited elsewhere", Version: 4}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+
+	returnedTask, err := suite.usecase.UpdateTask(context.TODO(), domain.Task{ID: current.ID, UserID: "user_123", Title: "Stale", Version: 3}, domain.User{ID: "user_123", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(412, err.Code)
+	suite.Equal(current, returnedTask, "the conflict should carry the current task")
+	suite.repositorie.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestDeleteTask_VersionConflict() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Edited elsewhere", Version: 4}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+
+	returnedTask, err := suite.usecase.DeleteTask(context.TODO(), current.ID, domain.User{ID: "user_123", Role: "user"}, 3)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(412, err.Code)
+	suite.Equal(current, returnedTask, "the conflict should carry the current task")
+	suite.repositorie.AssertNotCalled(suite.T(), "DeleteTask", mock.Anything, mock.Anything, mock.Anything)
+
+	suite.repositorie.On("DeleteTask", mock.Anything, current.ID, 4).Return(current, nil)
+	_, err = suite.usecase.DeleteTask(context.TODO(), current.ID, domain.User{ID: "user_123", Role: "user"}, 4)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *taskUsecaseSuite) TestPatchTask_MergePatch() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Description: "Old", Priority: "Low", Version: 2}
+	patched := current
+	patched.Description, patched.Priority, patched.Tags = "", "high", []string{"urgent"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("PatchTask", mock.Anything, patched, []string{"tags", "description", "priority"}).Return(patched, nil)
+
+	patch := domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"description":null,"priority":"high","tags":[" Urgent "],"title":"Draft"}`)}
+	returnedTask, err := suite.usecase.PatchTask(context.TODO(), current.ID, patch, suite.admin)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(patched, returnedTask)
+}
+
+func (suite *taskUsecaseSuite) TestPatchTask_JSONPatch() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Tags: []string{"a"}, Revision: 3, Version: 3}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+
+	for _, rejected := range []struct {
+		patch string
+		code  int
+	}{
+		{`[{"op":"replace","path":"/revision","value":1}]`, 422},
+		{`[{"op":"remove","path":"/title"}]`, 400},
+		{`[{"op":"remove","path":"/description"}]`, 422},
+		{`[{"op":"test","path":"/tags/0","value":"b"}]`, 409},
+		{`[{"op":"add","path":"/tags","value":{"a":1}}]`, 422},
+		{`{"title":"Final"}`, 400},
+	} {
+		_, err := suite.usecase.PatchTask(context.TODO(), current.ID, domain.TaskPatch{Format: domain.PATCH_JSON, Patch: []byte(rejected.patch)}, suite.admin)
+		suite.NotNil(err, "patch %s should be rejected", rejected.patch)
+		suite.Equal(rejected.code, err.Code, rejected.patch)
+	}
+	suite.repositorie.AssertNotCalled(suite.T(), "PatchTask", mock.Anything, mock.Anything, mock.Anything)
+
+	// a patch that changes nothing is not written
+	returnedTask, err := suite.usecase.PatchTask(context.TODO(), current.ID, domain.TaskPatch{Format: domain.PATCH_JSON, Patch: []byte(`[{"op":"test","path":"/tags/0","value":"a"}]`)}, suite.admin)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(current, returnedTask)
+	suite.repositorie.AssertNotCalled(suite.T(), "PatchTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestPatchTask_Conflicts() {
+	stale := domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Version: 2}
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Description: "Edited elsewhere", Version: 3}
+	conflict := &domain.TaskError{Message: "Task has been modified, it is now at version 3", Code: 412}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, stale.ID).Return(stale, nil).Once()
+	suite.repositorie.On("FetchTaskByID", mock.Anything, stale.ID).Return(current, nil)
+	suite.repositorie.On("PatchTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool { return task.Version == 2 }), []string{"priority"}).Return(current, conflict)
+	patched := current
+	patched.Priority = "high"
+	suite.repositorie.On("PatchTask", mock.Anything, patched, []string{"priority"}).Return(patched, nil)
+
+	// without an expected version the patch is applied again to the task as it is now
+	patch := domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"priority":"high"}`)}
+	returnedTask, err := suite.usecase.PatchTask(context.TODO(), stale.ID, patch, suite.admin)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(patched, returnedTask)
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "PatchTask", 2)
+
+	patch.Version = 2
+	returnedTask, err = suite.usecase.PatchTask(context.TODO(), stale.ID, patch, suite.admin)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(412, err.Code)
+	suite.Equal(current, returnedTask, "the conflict should carry the current task")
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "PatchTask", 2)
+}
+
+// a task use case that keeps revisions in the returned mock repository
+func (suite *taskUsecaseSuite) revisionedUsecase() (domain.TaskUsecase, *mocks.RevisionRepository) {
+	revisions := new(mocks.RevisionRepository)
+	taskUC := usecases.NewTaskUsecase(suite.repositorie, time.Second*2)
+	taskUC.SetRevisionRepository(revisions)
+	return &taskUC, revisions
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_RecordsRevision() {
+	taskUC, revisions := suite.revisionedUsecase()
+	updated := domain.Task{ID: "task_001", UserID: "user_123", Title: "Renamed", Revision: 3}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "task_001").Return(domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Revision: 2}, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, domain.Task{ID: "task_001", Title: "Renamed"}).Return(updated, nil)
+	revisions.On("CreateRevision", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := taskUC.UpdateTask(context.TODO(), domain.Task{ID: "task_001", Title: "Renamed"}, suite.admin)
+	suite.Nil(err, "error should be nil")
+	revisions.AssertCalled(suite.T(), "CreateRevision", mock.Anything, mock.MatchedBy(func(revision domain.TaskRevision) bool {
+		return revision.TaskID == "task_001" && revision.Number == 3 && revision.Action == domain.REVISION_UPDATE && revision.Task.Title == updated.Title
+	}))
+}
+
+func (suite *taskUsecaseSuite) TestDiffRevisions() {
+	taskUC, revisions := suite.revisionedUsecase()
+	revisions.On("FetchRevision", mock.Anything, "task_001", 1).Return(domain.TaskRevision{Number: 1, Task: domain.Task{ID: "task_001", Title: "Draft", Priority: "low", Revision: 1}}, nil)
+	revisions.On("FetchRevision", mock.Anything, "task_001", 3).Return(domain.TaskRevision{Number: 3, Task: domain.Task{ID: "task_001", Title: "Final", Description: "Ready", Priority: "low", Revision: 3}}, nil)
+
+	diff, err := taskUC.DiffRevisions(context.TODO(), "task_001", 1, 3)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(domain.RevisionDiff{From: 1, To: 3, Changes: []domain.FieldChange{
+		{Field: "description", After: "Ready"},
+		{Field: "title", Before: "Draft", After: "Final"},
+	}}, diff, "the revision number itself is not a change")
+
+	revisions.On("FetchRevision", mock.Anything, "task_001", 9).Return(domain.TaskRevision{}, &domain.TaskError{Message: "Revision 9 not found", Code: 404})
+	_, err = taskUC.DiffRevisions(context.TODO(), "task_001", 1, 9)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(404, err.Code)
+}
+
+func (suite *taskUsecaseSuite) TestRevertTask() {
+	taskUC, revisions := suite.revisionedUsecase()
+	old := domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Status: usecases.STATUS_TODO, Revision: 1}
+	current := domain.Task{ID: "task_001", UserID: "user_123", Title: "Final", Description: "Ready", Status: usecases.STATUS_IN_PROGRESS, Revision: 2}
+	reverted := old
+	reverted.Revision = 3
+	revisions.On("FetchRevision", mock.Anything, "task_001", 1).Return(domain.TaskRevision{TaskID: "task_001", Number: 1, Task: old}, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "task_001").Return(current, nil)
+	suite.repositorie.On("RestoreTask", mock.Anything, old).Return(reverted, nil)
+	revisions.On("CreateRevision", mock.Anything, mock.Anything).Return(nil)
+
+	task, err := taskUC.RevertTask(context.TODO(), "task_001", 1, domain.User{ID: "user_123", Role: "user"})
+	suite.Nil(err, "error should be nil")
+	suite.Equal(reverted, task)
+	revisions.AssertCalled(suite.T(), "CreateRevision", mock.Anything, mock.MatchedBy(func(revision domain.TaskRevision) bool {
+		return revision.Number == 3 && revision.Action == domain.REVISION_REVERT && revision.RevertedFrom == 1
+	}))
+}
+
+func (suite *taskUsecaseSuite) TestRevertTask_ParentGone() {
+	taskUC, revisions := suite.revisionedUsecase()
+	revisions.On("FetchRevision", mock.Anything, "task_001", 1).Return(domain.TaskRevision{Number: 1, Task: domain.Task{ID: "task_001", Title: "Draft", ParentID: "deleted_parent"}}, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "task_001").Return(domain.Task{ID: "task_001", Title: "Final"}, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "deleted_parent").Return(domain.Task{}, &domain.TaskError{Message: "not found", Code: 404})
+
+	_, err := taskUC.RevertTask(context.TODO(), "task_001", 1, suite.admin)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(400, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "RestoreTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestRevertTask_CheckedLikeUpdate() {
+	taskUC, revisions := suite.revisionedUsecase()
+	current := domain.Task{ID: "task_001", UserID: "user_123", Assignees: []string{"user_456"}, Title: "Final", Status: usecases.STATUS_REVIEW, Revision: 3}
+	revisions.On("FetchRevision", mock.Anything, "task_001", 1).Return(domain.TaskRevision{Number: 1, Task: domain.Task{ID: "task_001", UserID: "user_456", Title: "Draft", Status: usecases.STATUS_REVIEW}}, nil)
+	revisions.On("FetchRevision", mock.Anything, "task_001", 2).Return(domain.TaskRevision{Number: 2, Task: domain.Task{ID: "task_001", UserID: "user_123", Title: "Draft", Status: usecases.STATUS_TODO}}, nil)
+	suite.repositorie.On("FetchTaskByID", mock.Anything, "task_001").Return(current, nil)
+
+	_, err := taskUC.RevertTask(context.TODO(), "task_001", 2, domain.User{ID: "user_789", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code, "only those who may edit the task revert it")
+	projectEditor := context.WithValue(context.TODO(), infrastructure.CONTEXT_PROJECT, "project_1")
+	_, err = taskUC.RevertTask(projectEditor, "task_001", 1, domain.User{ID: "user_456", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code, "an assignee should not revert the owner to themselves")
+	_, err = taskUC.RevertTask(context.TODO(), "task_001", 2, domain.User{ID: "user_123", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(409, err.Code, "the status should only move along the workflow")
+	suite.repositorie.AssertNotCalled(suite.T(), "RestoreTask", mock.Anything, mock.Anything)
+}
+
+func (suite *taskUsecaseSuite) TestRevisions_Disabled() {
+	_, err := suite.usecase.GetRevisions(context.TODO(), "task_001")
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(501, err.Code)
+}
+
 func (suite *taskUsecaseSuite) TestDeleteTask_Positive() {
 	authorityUser := domain.User{
 		ID:       "user_123",
@@ -163,12 +880,13 @@ func (suite *taskUsecaseSuite) TestDeleteTask_Positive() {
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	suite.repositorie.On("DeleteTask", mock.Anything, tasks.ID).Return(tasks, nil)
+	suite.repositorie.On("DeleteTask", mock.Anything, tasks.ID, 0).Return(tasks, nil)
 	suite.repositorie.On("FetchTaskByID", mock.Anything, tasks.ID).Return(tasks, nil)
 
-	fetchedTask, err := suite.usecase.DeleteTask(context.TODO(), tasks.ID, authorityUser)
+	fetchedTask, err := suite.usecase.DeleteTask(context.TODO(), tasks.ID, authorityUser, 0)
 	suite.Nil(err, "error should be nil")
 	suite.Equal(tasks, fetchedTask, "tasks should be equal")
+	suite.repositorie.AssertNotCalled(suite.T(), "UnlinkDependents", mock.Anything, tasks.ID)
 }
 
 func (suite *taskUsecaseSuite) TestDeleteTask_Negative() {
@@ -176,7 +894,7 @@ func (suite *taskUsecaseSuite) TestDeleteTask_Negative() {
 		ID:       "user_124",
 		Username: "johndoe",
 		Password: "password123",
-		Role:     "admin",
+		Role:     "user",
 	}
 
 	tasks := domain.Task{
@@ -190,13 +908,150 @@ func (suite *taskUsecaseSuite) TestDeleteTask_Negative() {
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	suite.repositorie.On("DeleteTask", mock.Anything, tasks.ID).Return(tasks, nil)
+	suite.repositorie.On("DeleteTask", mock.Anything, tasks.ID, 0).Return(tasks, nil)
 	suite.repositorie.On("FetchTaskByID", mock.Anything, tasks.ID).Return(tasks, nil)
 
-	_, err := suite.usecase.DeleteTask(context.TODO(), tasks.ID, authorityUser)
+	_, err := suite.usecase.DeleteTask(context.TODO(), tasks.ID, authorityUser, 0)
 	suite.NotNil(err, "error should not be nil as the user is not authorized to delete the task")
 }
 
+func (suite *taskUsecaseSuite) TestUpdateTask_OwnerOrAssigneeOrAdmin() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Assignees: []string{"user_456"}, Title: "Shared"}
+	update := domain.Task{ID: "task_001", UserID: "user_123", Title: "Renamed"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, update).Return(update, nil)
+
+	for _, allowed := range []domain.User{{ID: "user_123", Role: "user"}, {ID: "user_456", Role: "user"}, suite.admin} {
+		_, err := suite.usecase.UpdateTask(context.TODO(), update, allowed)
+		suite.Nil(err, "%s should be allowed to update the task", allowed.ID)
+	}
+	stranger := domain.User{ID: "user_789", Role: "user"}
+	_, err := suite.usecase.UpdateTask(context.TODO(), update, stranger)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code)
+	_, err = suite.usecase.PatchTask(context.TODO(), current.ID, domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"title":"Mine"}`)}, stranger)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code)
+	_, err = suite.usecase.DeleteTask(context.TODO(), current.ID, stranger, 0)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code)
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "UpdateTask", 3)
+}
+
+func (suite *taskUsecaseSuite) TestUpdateTask_OnlyOwnerChangesOwner() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Assignees: []string{"user_456"}, Title: "Shared"}
+	handover := domain.Task{ID: "task_001", UserID: "user_456", Title: "Shared"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, handover).Return(handover, nil)
+
+	assignee := domain.User{ID: "user_456", Role: "user"}
+	_, err := suite.usecase.UpdateTask(context.TODO(), handover, assignee)
+	suite.NotNil(err, "an assignee should not take the task over")
+	suite.Equal(403, err.Code)
+	_, err = suite.usecase.PatchTask(context.TODO(), current.ID, domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"userID":"user_456"}`)}, assignee)
+	suite.NotNil(err, "an assignee should not take the task over")
+	suite.Equal(403, err.Code)
+	projectEditor := context.WithValue(context.TODO(), infrastructure.CONTEXT_PROJECT, "project_1")
+	_, err = suite.usecase.UpdateTask(projectEditor, domain.Task{ID: "task_001", UserID: "user_789", Title: "Shared"}, domain.User{ID: "user_789", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything)
+	suite.repositorie.AssertNotCalled(suite.T(), "PatchTask", mock.Anything, mock.Anything, mock.Anything)
+
+	for _, allowed := range []domain.User{{ID: "user_123", Role: "user"}, suite.admin} {
+		_, err := suite.usecase.UpdateTask(context.TODO(), handover, allowed)
+		suite.Nil(err, "%s should be allowed to hand the task over", allowed.ID)
+	}
+}
+
+func (suite *taskUsecaseSuite) TestAssignTask() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Assignees: []string{"user_456"}, Title: "Shared"}
+	assigned := current
+	assigned.Assignees = []string{"user_456", "user_789"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("AddAssignee", mock.Anything, current.ID, "user_789").Return(assigned, nil)
+	suite.repositorie.On("RemoveAssignee", mock.Anything, current.ID, "user_456").Return(domain.Task{ID: current.ID}, nil)
+
+	task, err := suite.usecase.AssignTask(context.TODO(), current.ID, "user_789", domain.User{ID: "user_456", Role: "user"})
+	suite.Nil(err, "assignees may assign others")
+	suite.Equal(assigned, task)
+	task, err = suite.usecase.AssignTask(context.TODO(), current.ID, "user_456", suite.admin)
+	suite.Nil(err, "assigning an assignee again is not an error")
+	suite.Equal(current, task)
+	_, err = suite.usecase.AssignTask(context.TODO(), current.ID, "user_789", domain.User{ID: "user_789", Role: "user"})
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(403, err.Code, "users cannot assign themselves")
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "AddAssignee", 1)
+
+	_, err = suite.usecase.UnassignTask(context.TODO(), current.ID, "user_456", domain.User{ID: "user_456", Role: "user"})
+	suite.Nil(err, "assignees may unassign themselves")
+	_, err = suite.usecase.UnassignTask(context.TODO(), current.ID, "user_789", suite.admin)
+	suite.NotNil(err, "error should not be nil")
+	suite.Equal(404, err.Code)
+}
+
+func (suite *taskUsecaseSuite) TestWatchTask() {
+	current := domain.Task{ID: "task_001", UserID: "user_123", Watchers: []string{"user_456"}, Title: "Shared"}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("AddWatcher", mock.Anything, current.ID, "user_789").Return(current, nil)
+	suite.repositorie.On("RemoveWatcher", mock.Anything, current.ID, "user_456").Return(current, nil)
+
+	_, err := suite.usecase.WatchTask(context.TODO(), current.ID, "user_789")
+	suite.Nil(err, "error should be nil")
+	_, err = suite.usecase.WatchTask(context.TODO(), current.ID, "user_456")
+	suite.Nil(err, "error should be nil")
+	_, err = suite.usecase.UnwatchTask(context.TODO(), current.ID, "user_456")
+	suite.Nil(err, "error should be nil")
+	_, err = suite.usecase.UnwatchTask(context.TODO(), current.ID, "user_789")
+	suite.Nil(err, "error should be nil")
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "AddWatcher", 1)
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "RemoveWatcher", 1)
+}
+
+func (suite *taskUsecaseSuite) TestTaskEstimates() {
+	suite.repositorie.On("CreateTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+		return task.Estimate == 3 && task.EstimateUnit == domain.ESTIMATE_POINTS
+	})).Return("task_001", nil)
+	_, err := suite.usecase.CreateTask(context.TODO(), domain.Task{UserID: "user_123", Title: "Sized", Estimate: 3})
+	suite.Nil(err, "an estimate without a unit is in points")
+	_, err = suite.usecase.CreateTask(context.TODO(), domain.Task{UserID: "user_123", Title: "Sized", Estimate: 3, EstimateUnit: "days"})
+	suite.NotNil(err, "unknown units are rejected")
+	suite.Equal(400, err.Code)
+
+	current := domain.Task{ID: "task_002", UserID: "user_123", Title: "Sized", Estimate: 4, EstimateUnit: domain.ESTIMATE_HOURS}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	suite.repositorie.On("UpdateTask", mock.Anything, domain.Task{ID: current.ID, Estimate: 6, EstimateUnit: domain.ESTIMATE_HOURS}).Return(current, nil)
+	_, err = suite.usecase.UpdateTask(context.TODO(), domain.Task{ID: current.ID, Estimate: 6}, suite.admin)
+	suite.Nil(err, "a new estimate keeps the unit of the current one")
+	_, err = suite.usecase.UpdateTask(context.TODO(), domain.Task{ID: current.ID, Estimate: -1}, suite.admin)
+	suite.NotNil(err, "negative estimates are rejected")
+}
+
+func (suite *taskUsecaseSuite) TestCompletionTime() {
+	completedAt := time.Date(2024, time.March, 4, 15, 0, 0, 0, time.UTC)
+	suite.repositorie.On("CreateTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+		return task.Status == "done" && !task.CompletedAt.IsZero()
+	})).Return("task_001", nil)
+	_, err := suite.usecase.CreateTask(context.TODO(), domain.Task{UserID: "user_123", Title: "Done already", Status: "done", CompletedAt: completedAt})
+	suite.Nil(err, "error should be nil")
+	suite.repositorie.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.MatchedBy(func(task domain.Task) bool {
+		return task.CompletedAt.Equal(completedAt)
+	}))
+
+	current := domain.Task{ID: "task_002", UserID: "user_123", Title: "Shipped", Status: "done", CompletedAt: completedAt, Version: 5}
+	suite.repositorie.On("FetchTaskByID", mock.Anything, current.ID).Return(current, nil)
+	reopened := current
+	reopened.Status, reopened.CompletedAt = "in_progress", time.Time{}
+	suite.repositorie.On("PatchTask", mock.Anything, reopened, []string{"status", "completed_at"}).Return(reopened, nil)
+	adminContext := context.WithValue(context.TODO(), infrastructure.CONTEXT_ROLE, "admin")
+	_, err = suite.usecase.PatchTask(adminContext, current.ID, domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"status":"in_progress"}`)}, suite.admin)
+	suite.Nil(err, "reopening a task clears its completion time")
+
+	_, err = suite.usecase.PatchTask(adminContext, current.ID, domain.TaskPatch{Format: domain.PATCH_MERGE, Patch: []byte(`{"completed_at":"2024-01-01T00:00:00Z"}`)}, suite.admin)
+	suite.NotNil(err, "the completion time cannot be patched")
+	suite.Equal(422, err.Code)
+}
+
 func TestTaskUsecaseSuite(t *testing.T) {
 	suite.Run(t, new(taskUsecaseSuite))
 }
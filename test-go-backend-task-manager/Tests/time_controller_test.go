@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type timeControllerSuite struct {
+	suite.Suite
+	timeUsecase *mocks.TimeUsecase
+	userUsecase *mocks.UserUsecase
+	controller  controllers.TimeController
+	router      *gin.Engine
+	user        domain.User
+}
+
+func (suite *timeControllerSuite) SetupTest() {
+	suite.timeUsecase = new(mocks.TimeUsecase)
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewTimeController(suite.timeUsecase, suite.userUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+
+	// stands in for AuthMiddleWare
+	suite.router.Use(func(cxt *gin.Context) {
+		cxt.Set(infrastructure.CONTEXT_USERNAME, "abebe")
+	})
+	suite.router.POST("/task/:id/timer", suite.controller.PostTimer)
+	suite.router.GET("/timer", suite.controller.GetTimer)
+	suite.router.POST("/timer/stop", suite.controller.PostTimerStop)
+	suite.router.POST("/task/:id/time", suite.controller.PostTimeEntry)
+	suite.router.GET("/me/time", suite.controller.GetMyTime)
+	suite.router.GET("/time/report", suite.controller.GetTimeReport)
+	suite.user = domain.User{ID: "user_1", Username: "abebe"}
+	suite.userUsecase.On("GetUserByUsername", mock.Anything, "abebe").Return(suite.user, nil)
+}
+
+func (suite *timeControllerSuite) TestPostTimer() {
+	suite.timeUsecase.On("StartTimer", mock.Anything, "1", "", suite.user).Return(domain.TimeEntry{ID: "entry_1", TaskID: "1", Running: true}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/timer", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusCreated, resp.Code, "the note is optional")
+}
+
+func (suite *timeControllerSuite) TestPostTimer_AlreadyRunning() {
+	suite.timeUsecase.On("StartTimer", mock.Anything, "1", "review", suite.user).Return(domain.TimeEntry{}, &domain.TaskError{Message: "A timer is already running", Code: http.StatusConflict})
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/timer", bytes.NewBufferString(`{"note": "review"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusConflict, resp.Code)
+}
+
+func (suite *timeControllerSuite) TestPostTimerStop() {
+	suite.timeUsecase.On("StopTimer", mock.Anything, suite.user).Return(domain.TimeEntry{ID: "entry_1", Seconds: 60}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/timer/stop", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var entry domain.TimeEntry
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &entry))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(int64(60), entry.Seconds)
+}
+
+func (suite *timeControllerSuite) TestPostTimeEntry() {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	expected := domain.TimeEntry{TaskID: "1", Start: start, End: start.Add(time.Hour), Note: "pairing"}
+	suite.timeUsecase.On("AddTimeEntry", mock.Anything, expected, suite.user).Return(domain.TimeEntry{ID: "entry_2", Seconds: 3600}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/task/1/time", bytes.NewBufferString(`{"start": "2026-03-02T09:00:00Z", "end": "2026-03-02T10:00:00Z", "note": "pairing"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusCreated, resp.Code)
+	suite.timeUsecase.AssertCalled(suite.T(), "AddTimeEntry", mock.Anything, expected, suite.user)
+}
+
+func (suite *timeControllerSuite) TestGetMyTime() {
+	query := domain.TimeQuery{UserID: "user_1", From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	suite.timeUsecase.On("GetUserTime", mock.Anything, query).Return(domain.TimeSummary{Entries: []domain.TimeEntry{}, Seconds: 7200}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/me/time?from=2026-03-01T00:00:00Z", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.timeUsecase.AssertCalled(suite.T(), "GetUserTime", mock.Anything, query)
+
+	req, _ = http.NewRequest(http.MethodGet, "/me/time?from=yesterday", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+}
+
+func (suite *timeControllerSuite) TestGetTimeReport() {
+	query := domain.TimeQuery{ProjectID: "project_1", TimeZone: "Africa/Addis_Ababa"}
+	report := domain.TimeReport{TimeZone: "Africa/Addis_Ababa", Rows: []domain.TimeReportRow{{ProjectID: "project_1", UserID: "user_1", Day: "2026-03-02", Seconds: 3600}}, Seconds: 3600}
+	suite.timeUsecase.On("GetTimeReport", mock.Anything, query).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/time/report?projectID=project_1&tz=Africa/Addis_Ababa", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var body domain.TimeReport
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &body))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(report.Rows, body.Rows)
+}
+
+func TestTimeControllerSuite(t *testing.T) {
+	suite.Run(t, new(timeControllerSuite))
+}
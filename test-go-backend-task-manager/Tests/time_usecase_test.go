@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type timeUsecaseSuite struct {
+	suite.Suite
+	timeRepository *mocks.TimeEntryRepository
+	taskRepository *mocks.TaskRepository
+	usecase        domain.TimeUsecase
+	user           domain.User
+}
+
+func (suite *timeUsecaseSuite) SetupTest() {
+	suite.timeRepository = new(mocks.TimeEntryRepository)
+	suite.taskRepository = new(mocks.TaskRepository)
+	timeUC := usecases.NewTimeUsecase(suite.timeRepository, suite.taskRepository, time.Second*2)
+	suite.usecase = &timeUC
+	suite.user = domain.User{ID: "user_1", Username: "abebe", Role: "user"}
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_1").Return(domain.Task{ID: "task_1", UserID: "user_1", ProjectID: "project_1"}, nil)
+	suite.taskRepository.On("FetchTaskByID", mock.Anything, "task_2").Return(domain.Task{ID: "task_2", UserID: "user_2"}, nil)
+}
+
+func (suite *timeUsecaseSuite) TestStartTimer() {
+	suite.timeRepository.On("FetchRunningEntry", mock.Anything, "user_1").Return(domain.TimeEntry{}, &domain.TaskError{Message: "No timer is running", Code: http.StatusNotFound})
+	suite.timeRepository.On("CreateTimeEntry", mock.Anything, mock.MatchedBy(func(entry domain.TimeEntry) bool {
+		return entry.TaskID == "task_1" && entry.UserID == "user_1" && entry.ProjectID == "project_1" && entry.Running && entry.Note == "triage"
+	})).Return("entry_1", nil)
+
+	entry, err := suite.usecase.StartTimer(context.TODO(), "task_1", " triage ", suite.user)
+	suite.Nil(err, "error should be nil")
+	suite.Equal("entry_1", entry.ID)
+	suite.False(entry.Start.IsZero(), "the timer starts now")
+}
+
+func (suite *timeUsecaseSuite) TestStartTimer_AlreadyRunning() {
+	suite.timeRepository.On("FetchRunningEntry", mock.Anything, "user_1").Return(domain.TimeEntry{ID: "entry_1", TaskID: "task_1", Running: true}, nil)
+
+	_, err := suite.usecase.StartTimer(context.TODO(), "task_1", "", suite.user)
+	suite.NotNil(err, "only one timer runs at a time")
+	suite.Equal(http.StatusConflict, err.Code)
+	suite.timeRepository.AssertNotCalled(suite.T(), "CreateTimeEntry", mock.Anything, mock.Anything)
+}
+
+func (suite *timeUsecaseSuite) TestStartTimer_NotAuthorized() {
+	_, err := suite.usecase.StartTimer(context.TODO(), "task_2", "", suite.user)
+	suite.NotNil(err, "time is only tracked on tasks the user may edit")
+	suite.Equal(http.StatusForbidden, err.Code)
+}
+
+func (suite *timeUsecaseSuite) TestStopTimer() {
+	start := time.Now().Add(-90 * time.Minute)
+	suite.timeRepository.On("FetchRunningEntry", mock.Anything, "user_1").Return(domain.TimeEntry{ID: "entry_1", Start: start, Running: true}, nil)
+	suite.timeRepository.On("StopTimeEntry", mock.Anything, "entry_1", mock.AnythingOfType("time.Time")).Return(domain.TimeEntry{ID: "entry_1", Start: start, End: start.Add(90 * time.Minute)}, nil)
+
+	entry, err := suite.usecase.StopTimer(context.TODO(), suite.user)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(5400), entry.Seconds)
+}
+
+func (suite *timeUsecaseSuite) TestAddTimeEntry() {
+	end := time.Now().Add(-time.Hour)
+	start := end.Add(-30 * time.Minute)
+	suite.timeRepository.On("CreateTimeEntry", mock.Anything, mock.MatchedBy(func(entry domain.TimeEntry) bool {
+		return entry.Manual && !entry.Running && entry.UserID == "user_1" && entry.Start.Equal(start)
+	})).Return("entry_2", nil)
+
+	entry, err := suite.usecase.AddTimeEntry(context.TODO(), domain.TimeEntry{TaskID: "task_1", Start: start, End: end}, suite.user)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(1800), entry.Seconds)
+
+	_, err = suite.usecase.AddTimeEntry(context.TODO(), domain.TimeEntry{TaskID: "task_1", Start: end, End: start}, suite.user)
+	suite.NotNil(err, "end must be after start")
+	suite.Equal(http.StatusBadRequest, err.Code)
+
+	_, err = suite.usecase.AddTimeEntry(context.TODO(), domain.TimeEntry{TaskID: "task_1", Start: end.Add(-25 * time.Hour), End: end}, suite.user)
+	suite.NotNil(err, "entries longer than a day are rejected")
+
+	_, err = suite.usecase.AddTimeEntry(context.TODO(), domain.TimeEntry{TaskID: "task_1", Start: start, End: time.Now().Add(time.Hour)}, suite.user)
+	suite.NotNil(err, "entries cannot end in the future")
+}
+
+func (suite *timeUsecaseSuite) TestGetTaskTime() {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	suite.timeRepository.On("FetchTimeEntries", mock.Anything, domain.TimeQuery{TaskID: "task_1"}).Return([]domain.TimeEntry{
+		{ID: "entry_1", Start: start, End: start.Add(time.Hour)},
+		{ID: "entry_2", Start: start.Add(2 * time.Hour), End: start.Add(150 * time.Minute)},
+		{ID: "entry_3", Start: start.Add(3 * time.Hour), Running: true},
+	}, nil)
+
+	summary, err := suite.usecase.GetTaskTime(context.TODO(), "task_1")
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(5400), summary.Seconds, "running timers are not counted")
+	suite.Equal(int64(0), summary.Entries[2].Seconds)
+}
+
+func (suite *timeUsecaseSuite) TestGetTimeReport() {
+	query := domain.TimeQuery{From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+	expected := query
+	expected.TimeZone = "UTC"
+	suite.timeRepository.On("FetchTimeReport", mock.Anything, expected).Return([]domain.TimeReportRow{
+		{ProjectID: "project_1", UserID: "user_1", Day: "2026-03-02", Seconds: 3600},
+		{ProjectID: "project_1", UserID: "user_2", Day: "2026-03-02", Seconds: 1800},
+	}, nil)
+
+	report, err := suite.usecase.GetTimeReport(context.TODO(), query)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(5400), report.Seconds)
+	suite.Equal(2, len(report.Rows))
+
+	_, err = suite.usecase.GetTimeReport(context.TODO(), domain.TimeQuery{TimeZone: "Mars/Olympus_Mons"})
+	suite.NotNil(err, "unknown time zones are rejected")
+	suite.Equal(http.StatusBadRequest, err.Code)
+
+	_, err = suite.usecase.GetTimeReport(context.TODO(), domain.TimeQuery{From: query.To, To: query.From})
+	suite.NotNil(err, "from must be before to")
+}
+
+func (suite *timeUsecaseSuite) TestTaskDeleted_StopsTimers() {
+	suite.timeRepository.On("StopTaskTimers", mock.Anything, "task_1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := suite.usecase.TaskDeleted(context.TODO(), domain.Task{ID: "task_1"})
+	suite.Nil(err, "error should be nil")
+	suite.timeRepository.AssertCalled(suite.T(), "StopTaskTimers", mock.Anything, "task_1", mock.AnythingOfType("time.Time"))
+}
+
+func TestTimeUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(timeUsecaseSuite))
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenRevocationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TokenRevocationTestSuite) SetupSuite() {
+	os.Setenv("SIGNITURE_SECRET", "test_secret")
+	gin.SetMode(gin.TestMode)
+}
+
+// the status of a request made with the token through the middleware
+func (suite *TokenRevocationTestSuite) request(revocations infrastructure.TokenRevocationStore, token string) int {
+	router := gin.New()
+	router.GET("/", infrastructure.AuthMiddleWareWithRevocations(revocations, "user"), func(cxt *gin.Context) {
+		cxt.Status(http.StatusOK)
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp.Code
+}
+
+func (suite *TokenRevocationTestSuite) tokenID(token string) string {
+	parsed, err := infrastructure.ParseJWTToken(token)
+	suite.Require().Nil(err)
+	return parsed.Claims.(jwt.MapClaims)["jti"].(string)
+}
+
+func (suite *TokenRevocationTestSuite) TestTokensHaveUniqueIDs() {
+	first, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+	second, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+	suite.NotEmpty(suite.tokenID(first))
+	suite.NotEqual(suite.tokenID(first), suite.tokenID(second))
+}
+
+func (suite *TokenRevocationTestSuite) TestMiddlewareRefusesRevokedToken() {
+	store := infrastructure.NewMemoryRevocationStore()
+	revoked, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+	valid, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+	suite.Nil(store.RevokeToken(context.TODO(), suite.tokenID(revoked), time.Now().Add(time.Minute)))
+
+	suite.Equal(http.StatusUnauthorized, suite.request(store, revoked))
+	suite.Equal(http.StatusOK, suite.request(store, valid))
+}
+
+func (suite *TokenRevocationTestSuite) TestMiddlewareRefusesTokensOfRevokedUser() {
+	store := infrastructure.NewMemoryRevocationStore()
+	token, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+	other, _ := infrastructure.CreateJWTToken("otheruser", "user", "", time.Minute)
+	suite.Nil(store.RevokeUserTokens(context.TODO(), "testuser", time.Now()))
+
+	suite.Equal(http.StatusUnauthorized, suite.request(store, token))
+	suite.Equal(http.StatusOK, suite.request(store, other))
+
+	// tokens issued after the revocation are accepted
+	revoked, err := store.IsRevoked(context.TODO(), "", "testuser", time.Now().Add(time.Second))
+	suite.Nil(err)
+	suite.False(revoked)
+}
+
+func (suite *TokenRevocationTestSuite) TestMiddlewareFailsClosed() {
+	store := new(mocks.TokenRevocationStore)
+	store.On("IsRevoked", mock.Anything, mock.Anything, "testuser", mock.Anything).Return(false, errors.New("store down"))
+	token, _ := infrastructure.CreateJWTToken("testuser", "user", "", time.Minute)
+
+	suite.Equal(http.StatusInternalServerError, suite.request(store, token))
+}
+
+func (suite *TokenRevocationTestSuite) TestCachedStore() {
+	store := new(mocks.TokenRevocationStore)
+	cache := infrastructure.NewCachedRevocationStore(store, time.Hour)
+	issuedAt := time.Now().Truncate(time.Second)
+	store.On("IsRevoked", mock.Anything, "token_1", "testuser", issuedAt).Return(false, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		revoked, err := cache.IsRevoked(context.TODO(), "token_1", "testuser", issuedAt)
+		suite.Nil(err)
+		suite.False(revoked)
+	}
+	store.AssertNumberOfCalls(suite.T(), "IsRevoked", 1)
+
+	// revoking through the cache drops the cached answers
+	store.On("RevokeUserTokens", mock.Anything, "testuser", mock.Anything).Return(nil)
+	suite.Nil(cache.RevokeUserTokens(context.TODO(), "testuser", time.Now()))
+	store.On("IsRevoked", mock.Anything, "token_1", "testuser", issuedAt).Return(true, nil).Once()
+	revoked, err := cache.IsRevoked(context.TODO(), "token_1", "testuser", issuedAt)
+	suite.Nil(err)
+	suite.True(revoked)
+	store.AssertNumberOfCalls(suite.T(), "IsRevoked", 2)
+}
+
+func (suite *TokenRevocationTestSuite) TestCachedStoreExpires() {
+	store := new(mocks.TokenRevocationStore)
+	cache := infrastructure.NewCachedRevocationStore(store, time.Millisecond)
+	issuedAt := time.Now().Truncate(time.Second)
+	store.On("IsRevoked", mock.Anything, "token_1", "testuser", mock.Anything).Return(false, nil).Once()
+	store.On("IsRevoked", mock.Anything, "token_1", "testuser", mock.Anything).Return(true, nil).Once()
+
+	revoked, _ := cache.IsRevoked(context.TODO(), "token_1", "testuser", issuedAt)
+	suite.False(revoked)
+	time.Sleep(5 * time.Millisecond)
+	revoked, _ = cache.IsRevoked(context.TODO(), "token_1", "testuser", issuedAt)
+	suite.True(revoked, "revocations made elsewhere should be seen once the cached answer expires")
+}
+
+func TestTokenRevocationTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenRevocationTestSuite))
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPTestSuite struct {
+	suite.Suite
+	// the SHA-1 secret of the test vectors in RFC 6238
+	secret string
+}
+
+func (suite *TOTPTestSuite) SetupTest() {
+	suite.secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+}
+
+// the last six digits of the eight digit codes in appendix B of RFC 6238
+func (suite *TOTPTestSuite) TestRFC6238Vectors() {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := infrastructure.TOTPCode(suite.secret, infrastructure.TOTPStep(time.Unix(unix, 0)))
+		suite.Require().NoError(err)
+		suite.Equal(expected, code, "T=%d", unix)
+	}
+}
+
+func (suite *TOTPTestSuite) TestMatchAllowsOneStepOfSkew() {
+	at := time.Unix(1111111111, 0)
+	step := infrastructure.TOTPStep(at)
+	for _, offset := range []int64{-1, 0, 1} {
+		code, _ := infrastructure.TOTPCode(suite.secret, step+offset)
+		matched, ok := infrastructure.MatchTOTP(suite.secret, code, at)
+		suite.True(ok)
+		suite.Equal(step+offset, matched, "the step of the code should be returned so that it is not accepted twice")
+	}
+	for _, offset := range []int64{-2, 2} {
+		code, _ := infrastructure.TOTPCode(suite.secret, step+offset)
+		_, ok := infrastructure.MatchTOTP(suite.secret, code, at)
+		suite.False(ok)
+	}
+}
+
+func (suite *TOTPTestSuite) TestMatchRefusesMalformedCodes() {
+	at := time.Unix(59, 0)
+	code, _ := infrastructure.TOTPCode(suite.secret, infrastructure.TOTPStep(at))
+	_, ok := infrastructure.MatchTOTP(suite.secret, code[:3]+" "+code[3:], at)
+	suite.True(ok, "spaces should be ignored")
+	for _, malformed := range []string{"", "28708", "2870820", "abcdef"} {
+		_, ok := infrastructure.MatchTOTP(suite.secret, malformed, at)
+		suite.False(ok, malformed)
+	}
+	_, ok = infrastructure.MatchTOTP("not base32!", code, at)
+	suite.False(ok)
+}
+
+func (suite *TOTPTestSuite) TestSecretAndURI() {
+	secret := infrastructure.NewTOTPSecret()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	suite.Require().NoError(err)
+	suite.Len(key, infrastructure.TOTP_SECRET_BYTES)
+	suite.NotEqual(secret, infrastructure.NewTOTPSecret())
+
+	uri, err := url.Parse(infrastructure.TOTPURI("Task Manager", "john doe", secret))
+	suite.Require().NoError(err)
+	suite.Equal("otpauth", uri.Scheme)
+	suite.Equal("totp", uri.Host)
+	suite.Equal("/Task Manager:john doe", uri.Path)
+	suite.False(strings.Contains(uri.RawQuery, "+"), "spaces in the issuer should be escaped for authenticator apps")
+	query := uri.Query()
+	suite.Equal(secret, query.Get("secret"))
+	suite.Equal("Task Manager", query.Get("issuer"))
+	suite.Equal("6", query.Get("digits"))
+	suite.Equal("30", query.Get("period"))
+}
+
+func TestTOTPTestSuite(t *testing.T) {
+	suite.Run(t, new(TOTPTestSuite))
+}
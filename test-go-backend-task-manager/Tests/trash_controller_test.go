@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type trashControllerSuite struct {
+	suite.Suite
+	taskUsecase *mocks.TaskUsecase
+	userUsecase *mocks.UserUsecase
+	controller  controllers.TrashController
+	router      *gin.Engine
+}
+
+func (suite *trashControllerSuite) SetupTest() {
+	suite.taskUsecase = new(mocks.TaskUsecase)
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewTrashController(suite.taskUsecase, suite.userUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+	suite.router.GET("/trash", suite.controller.GetTrash)
+	suite.router.POST("/trash/tasks/:id/restore", suite.controller.PostTaskRestore)
+	suite.router.POST("/trash/users/:id/restore", suite.controller.PostUserRestore)
+}
+
+func (suite *trashControllerSuite) TestGetTrash() {
+	deletedAt := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []domain.Task{{ID: "task_1", UserID: "user_1", Title: "Old", DeletedAt: deletedAt}}
+	suite.taskUsecase.On("GetDeletedTasks", mock.Anything).Return(tasks, nil)
+	suite.userUsecase.On("GetDeletedUsers", mock.Anything).Return([]domain.User{{ID: "user_2", Username: "kebede", Password: "hash", DeletedAt: deletedAt}}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/trash", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var trashResponse struct {
+		Tasks []domain.Task `json:"tasks"`
+		Users []domain.User `json:"users"`
+	}
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &trashResponse))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(tasks, trashResponse.Tasks)
+	suite.Equal([]domain.User{{ID: "user_2", Username: "kebede", DeletedAt: deletedAt}}, trashResponse.Users, "password hashes are not listed")
+}
+
+func (suite *trashControllerSuite) TestPostTaskRestore() {
+	suite.taskUsecase.On("RestoreDeletedTask", mock.Anything, "task_1").Return(domain.Task{ID: "task_1", Title: "Old", Version: 3}, nil)
+	suite.taskUsecase.On("RestoreDeletedTask", mock.Anything, "task_2").Return(domain.Task{}, &domain.TaskError{Message: "Task not found in the trash", Code: http.StatusNotFound})
+
+	req, _ := http.NewRequest(http.MethodPost, "/trash/tasks/task_1/restore", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(`"3"`, resp.Header().Get("ETag"))
+
+	req, _ = http.NewRequest(http.MethodPost, "/trash/tasks/task_2/restore", nil)
+	resp = httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	suite.Equal(http.StatusNotFound, resp.Code)
+}
+
+func (suite *trashControllerSuite) TestPostUserRestore() {
+	suite.userUsecase.On("RestoreDeletedUser", mock.Anything, "user_2").Return(domain.User{ID: "user_2", Username: "kebede", Password: "hash"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/trash/users/user_2/restore", nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+
+	var restoredUser domain.User
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &restoredUser))
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal(domain.User{ID: "user_2", Username: "kebede"}, restoredUser)
+}
+
+func TestTrashControllerSuite(t *testing.T) {
+	suite.Run(t, new(trashControllerSuite))
+}
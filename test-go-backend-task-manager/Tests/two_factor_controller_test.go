@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type twoFactorControllerSuite struct {
+	suite.Suite
+	userUsecase *mocks.UserUsecase
+	controller  controllers.TwoFactorController
+	router      *gin.Engine
+}
+
+func (suite *twoFactorControllerSuite) SetupTest() {
+	suite.userUsecase = new(mocks.UserUsecase)
+	suite.controller = controllers.NewTwoFactorController(suite.userUsecase)
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.Default()
+
+	// stands in for AuthMiddleWare
+	suite.router.Use(func(cxt *gin.Context) {
+		if username := cxt.GetHeader("X-Username"); username != "" {
+			cxt.Set(infrastructure.CONTEXT_USERNAME, username)
+		}
+	})
+	suite.router.POST("/user/login/2fa", suite.controller.PostTwoFactorLogin)
+	suite.router.GET("/user/2fa", suite.controller.GetTwoFactor)
+	suite.router.POST("/user/2fa/confirm", suite.controller.PostTwoFactorConfirm)
+	suite.router.POST("/user/2fa/disable", suite.controller.PostTwoFactorDisable)
+	suite.router.PUT("/user/2fa/policy", suite.controller.PutTwoFactorPolicy)
+	suite.router.DELETE("/user/:id/2fa", suite.controller.DeleteUserTwoFactor)
+	suite.userUsecase.On("GetUserByUsername", mock.Anything, "abebe").Return(domain.User{ID: "user_1", Username: "abebe", Role: "admin"}, nil)
+}
+
+func (suite *twoFactorControllerSuite) request(method string, path string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Username", "abebe")
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *twoFactorControllerSuite) TestPostTwoFactorLogin() {
+	suite.userUsecase.On("CompleteTwoFactorLogin", mock.Anything, "token_1", "123456").Return(domain.Session{Token: "access", ExpiresIn: 900}, nil)
+
+	resp := suite.request(http.MethodPost, "/user/login/2fa", `{"two_factor_token": "token_1", "code": "123456"}`)
+	suite.Equal(http.StatusOK, resp.Code)
+	var session map[string]interface{}
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &session))
+	suite.Equal("access", session["token"])
+	suite.NotContains(session, "two_factor_token")
+}
+
+func (suite *twoFactorControllerSuite) TestPostTwoFactorLogin_MissingCode() {
+	resp := suite.request(http.MethodPost, "/user/login/2fa", `{"two_factor_token": "token_1"}`)
+	suite.Equal(http.StatusBadRequest, resp.Code)
+	suite.userUsecase.AssertNotCalled(suite.T(), "CompleteTwoFactorLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *twoFactorControllerSuite) TestPostTwoFactorLogin_InvalidCode() {
+	suite.userUsecase.On("CompleteTwoFactorLogin", mock.Anything, "token_1", "000000").Return(
+		domain.Session{}, &domain.UserError{Message: "Invalid two-factor code", Code: http.StatusUnauthorized})
+
+	resp := suite.request(http.MethodPost, "/user/login/2fa", `{"two_factor_token": "token_1", "code": "000000"}`)
+	suite.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+func (suite *twoFactorControllerSuite) TestGetTwoFactor() {
+	status := domain.TwoFactorStatus{Enabled: true, Required: true, RecoveryCodesRemaining: 8}
+	suite.userUsecase.On("GetTwoFactorStatus", mock.Anything, "user_1").Return(status, nil)
+
+	resp := suite.request(http.MethodGet, "/user/2fa", "")
+	suite.Equal(http.StatusOK, resp.Code)
+	var returned domain.TwoFactorStatus
+	suite.Nil(json.Unmarshal(resp.Body.Bytes(), &returned))
+	suite.Equal(status, returned)
+}
+
+func (suite *twoFactorControllerSuite) TestPostTwoFactorConfirm() {
+	suite.userUsecase.On("ConfirmTwoFactor", mock.Anything, "user_1", "123456").Return([]string{"abcde-12345"}, nil)
+
+	resp := suite.request(http.MethodPost, "/user/2fa/confirm", `{"code": "123456"}`)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.JSONEq(`{"recovery_codes": ["abcde-12345"]}`, resp.Body.String())
+}
+
+func (suite *twoFactorControllerSuite) TestPostTwoFactorDisable_Required() {
+	suite.userUsecase.On("DisableTwoFactor", mock.Anything, "user_1", "123456").Return(
+		&domain.UserError{Message: "Two-factor authentication is required for your role", Code: http.StatusForbidden})
+
+	resp := suite.request(http.MethodPost, "/user/2fa/disable", `{"code": "123456"}`)
+	suite.Equal(http.StatusForbidden, resp.Code)
+}
+
+func (suite *twoFactorControllerSuite) TestPutTwoFactorPolicy() {
+	policy := domain.TwoFactorPolicy{RequiredRoles: []string{"admin"}}
+	suite.userUsecase.On("UpdateTwoFactorPolicy", mock.Anything, mock.MatchedBy(func(user domain.User) bool { return user.ID == "user_1" }), policy).Return(policy, nil)
+
+	resp := suite.request(http.MethodPut, "/user/2fa/policy", `{"required_roles": ["admin"]}`)
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.JSONEq(`{"required_roles": ["admin"]}`, resp.Body.String())
+}
+
+func (suite *twoFactorControllerSuite) TestDeleteUserTwoFactor() {
+	suite.userUsecase.On("ResetTwoFactor", mock.Anything, mock.Anything, "user_2").Return(nil)
+
+	resp := suite.request(http.MethodDelete, "/user/user_2/2fa", "")
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.userUsecase.AssertCalled(suite.T(), "ResetTwoFactor", mock.Anything, mock.MatchedBy(func(user domain.User) bool { return user.ID == "user_1" }), "user_2")
+}
+
+func TestTwoFactorControllerSuite(t *testing.T) {
+	suite.Run(t, new(twoFactorControllerSuite))
+}
@@ -0,0 +1,286 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	mocks "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/Mocks"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type twoFactorUsecaseSuite struct {
+	suite.Suite
+	userRepository *mocks.UserRepository
+	policies       *mocks.TwoFactorPolicyRepository
+	auditLog       *mocks.AuditRecorder
+	usecase        domain.UserUsecase
+	user           domain.User
+	secret         string
+}
+
+func (suite *twoFactorUsecaseSuite) SetupTest() {
+	os.Setenv("SIGNITURE_TIME_DURATION", "900")
+	os.Setenv("SIGNITURE_SECRET", "mysecretkey")
+	suite.userRepository = new(mocks.UserRepository)
+	suite.policies = new(mocks.TwoFactorPolicyRepository)
+	suite.auditLog = new(mocks.AuditRecorder)
+	suite.auditLog.On("Record", mock.Anything, mock.Anything).Return()
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	userUC.SetTwoFactorPolicyRepository(suite.policies)
+	userUC.SetAuditLog(suite.auditLog)
+	suite.usecase = userUC
+	suite.secret = infrastructure.NewTOTPSecret()
+	hashed, _ := infrastructure.HashPassword("secret")
+	suite.user = domain.User{ID: "user_1", Username: "johndoe", Password: hashed, Role: "admin", OrgID: "org_1"}
+}
+
+func (suite *twoFactorUsecaseSuite) requireRoles(roles ...string) {
+	suite.policies.On("FetchTwoFactorPolicy", inOrg("org_1")).Return(domain.TwoFactorPolicy{OrgID: "org_1", RequiredRoles: roles}, nil)
+}
+
+func (suite *twoFactorUsecaseSuite) enabled(recoveryCodes ...string) domain.User {
+	user := suite.user
+	user.TwoFactor = &domain.TwoFactor{Secret: suite.secret, Enabled: true, RecoveryCodes: recoveryCodes}
+	return user
+}
+
+func (suite *twoFactorUsecaseSuite) currentCode() string {
+	code, err := infrastructure.TOTPCode(suite.secret, infrastructure.TOTPStep(time.Now()))
+	suite.Require().NoError(err)
+	return code
+}
+
+// logs in with the password and returns the two-factor token of the login
+func (suite *twoFactorUsecaseSuite) login(user domain.User) domain.Session {
+	suite.userRepository.On("FetchUserByUsername", mock.Anything, "johndoe").Return(user, nil).Once()
+	session, err := suite.usecase.LoginUser(context.TODO(), domain.User{Username: "johndoe", Password: "secret", Role: "admin"})
+	suite.Require().Nil(err)
+	suite.Require().NotEmpty(session.TwoFactorToken)
+	return session
+}
+
+func (suite *twoFactorUsecaseSuite) audited(action string) bool {
+	for _, call := range suite.auditLog.Calls {
+		if call.Arguments.Get(1).(domain.AuditEntry).Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (suite *twoFactorUsecaseSuite) TestLoginAsksForSecondFactor() {
+	suite.requireRoles()
+	session := suite.login(suite.enabled())
+
+	suite.Empty(session.Token, "no access token should be issued before the second factor")
+	suite.False(session.TwoFactorEnrollmentRequired)
+	suite.Equal(int64(usecases.TWO_FACTOR_TOKEN_DURATION/time.Second), session.ExpiresIn)
+	suite.False(suite.audited(domain.AUDIT_USER_LOGIN), "the login is only audited once it completes")
+}
+
+func (suite *twoFactorUsecaseSuite) TestLoginWithoutSecondFactor() {
+	suite.requireRoles("user")
+	suite.userRepository.On("FetchUserByUsername", mock.Anything, "johndoe").Return(suite.user, nil)
+
+	session, err := suite.usecase.LoginUser(context.TODO(), domain.User{Username: "johndoe", Password: "secret", Role: "admin"})
+	suite.Require().Nil(err)
+	suite.NotEmpty(session.Token)
+	suite.Empty(session.TwoFactorToken)
+}
+
+func (suite *twoFactorUsecaseSuite) TestCompleteLoginWithCode() {
+	suite.requireRoles()
+	user := suite.enabled()
+	session := suite.login(user)
+	var accepted int64
+	suite.userRepository.On("FetchUserByID", inOrg("org_1"), "user_1").Return(user, nil)
+	suite.userRepository.On("AcceptTwoFactorStep", mock.Anything, "user_1", mock.Anything).Run(func(args mock.Arguments) {
+		accepted = args.Get(2).(int64)
+	}).Return(nil)
+
+	completed, err := suite.usecase.CompleteTwoFactorLogin(context.TODO(), session.TwoFactorToken, suite.currentCode())
+	suite.Require().Nil(err)
+	suite.NotEmpty(completed.Token)
+	suite.Empty(completed.RecoveryCodes)
+	suite.InDelta(infrastructure.TOTPStep(time.Now()), accepted, 1)
+	suite.True(suite.audited(domain.AUDIT_USER_LOGIN))
+}
+
+func (suite *twoFactorUsecaseSuite) TestCompleteLoginRefusesReplayedCode() {
+	suite.requireRoles()
+	user := suite.enabled()
+	session := suite.login(user)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(user, nil)
+	suite.userRepository.On("AcceptTwoFactorStep", mock.Anything, "user_1", mock.Anything).Return(&domain.UserError{Message: "User not found", Code: http.StatusNotFound})
+	suite.userRepository.On("RecordTwoFactorFailure", mock.Anything, "user_1", mock.Anything).Return(nil)
+
+	_, err := suite.usecase.CompleteTwoFactorLogin(context.TODO(), session.TwoFactorToken, suite.currentCode())
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.userRepository.AssertCalled(suite.T(), "RecordTwoFactorFailure", mock.Anything, "user_1", mock.Anything)
+}
+
+func (suite *twoFactorUsecaseSuite) TestCompleteLoginWithRecoveryCode() {
+	suite.requireRoles()
+	hash := sha256.Sum256([]byte("abcde12345"))
+	user := suite.enabled(hex.EncodeToString(hash[:]))
+	session := suite.login(user)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(user, nil)
+	suite.userRepository.On("ConsumeRecoveryCode", mock.Anything, "user_1", hex.EncodeToString(hash[:])).Return(nil)
+
+	completed, err := suite.usecase.CompleteTwoFactorLogin(context.TODO(), session.TwoFactorToken, " ABCDE-12345 ")
+	suite.Require().Nil(err)
+	suite.NotEmpty(completed.Token)
+	suite.True(suite.audited(domain.AUDIT_USER_2FA_RECOVERY))
+}
+
+func (suite *twoFactorUsecaseSuite) TestCompleteLoginLocksAfterFailures() {
+	suite.requireRoles()
+	user := suite.enabled()
+	user.TwoFactor.Failures = usecases.MAX_TWO_FACTOR_FAILURES
+	user.TwoFactor.FailedAt = time.Now().Add(-time.Minute)
+	session := suite.login(user)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(user, nil)
+
+	_, err := suite.usecase.CompleteTwoFactorLogin(context.TODO(), session.TwoFactorToken, suite.currentCode())
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusTooManyRequests, err.Code)
+	suite.userRepository.AssertNotCalled(suite.T(), "AcceptTwoFactorStep", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *twoFactorUsecaseSuite) TestCompleteLoginRefusesAccessToken() {
+	token, _ := infrastructure.CreateJWTToken("johndoe", "admin", "org_1", time.Minute)
+	_, err := suite.usecase.CompleteTwoFactorLogin(context.TODO(), token, "123456")
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+}
+
+func (suite *twoFactorUsecaseSuite) TestRequiredEnrollmentAtLogin() {
+	suite.requireRoles("admin")
+	session := suite.login(suite.user)
+	suite.True(session.TwoFactorEnrollmentRequired)
+
+	var stored *domain.TwoFactor
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil).Once()
+	suite.userRepository.On("UpdateTwoFactor", mock.Anything, "user_1", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(2).(*domain.TwoFactor)
+	}).Return(nil)
+	enrollment, err := suite.usecase.EnrollTwoFactorAtLogin(context.TODO(), session.TwoFactorToken)
+	suite.Require().Nil(err)
+	suite.Equal(stored.Secret, enrollment.Secret)
+	suite.False(stored.Enabled, "the enrollment should wait for its first code")
+	suite.True(strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+
+	pending := suite.user
+	pending.TwoFactor = stored
+	suite.secret = stored.Secret
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(pending, nil)
+	suite.userRepository.On("AcceptTwoFactorStep", mock.Anything, "user_1", mock.Anything).Return(nil)
+	completed, err := suite.usecase.CompleteTwoFactorLogin(context.TODO(), session.TwoFactorToken, suite.currentCode())
+	suite.Require().Nil(err)
+	suite.NotEmpty(completed.Token)
+	suite.Len(completed.RecoveryCodes, usecases.RECOVERY_CODE_COUNT)
+	suite.True(stored.Enabled)
+	suite.Len(stored.RecoveryCodes, usecases.RECOVERY_CODE_COUNT)
+	suite.NotContains(stored.RecoveryCodes, completed.RecoveryCodes[0], "only the hashes of the recovery codes should be stored")
+	suite.True(suite.audited(domain.AUDIT_USER_2FA_ENABLE))
+}
+
+func (suite *twoFactorUsecaseSuite) TestConfirmRefusesWrongCode() {
+	pending := suite.user
+	pending.TwoFactor = &domain.TwoFactor{Secret: suite.secret}
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(pending, nil)
+	suite.userRepository.On("RecordTwoFactorFailure", mock.Anything, "user_1", mock.Anything).Return(nil)
+
+	_, err := suite.usecase.ConfirmTwoFactor(context.TODO(), "user_1", "000000x")
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.userRepository.AssertNotCalled(suite.T(), "UpdateTwoFactor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *twoFactorUsecaseSuite) TestEnrollRefusesEnabled() {
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.enabled(), nil)
+
+	_, err := suite.usecase.EnrollTwoFactor(context.TODO(), "user_1")
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusConflict, err.Code)
+}
+
+func (suite *twoFactorUsecaseSuite) TestDisableRefusedWhenRequired() {
+	suite.requireRoles("admin")
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.enabled(), nil)
+
+	err := suite.usecase.DisableTwoFactor(context.TODO(), "user_1", suite.currentCode())
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusForbidden, err.Code)
+	suite.userRepository.AssertNotCalled(suite.T(), "UpdateTwoFactor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *twoFactorUsecaseSuite) TestDisable() {
+	suite.requireRoles("user")
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.enabled(), nil)
+	suite.userRepository.On("AcceptTwoFactorStep", mock.Anything, "user_1", mock.Anything).Return(nil)
+	suite.userRepository.On("UpdateTwoFactor", mock.Anything, "user_1", (*domain.TwoFactor)(nil)).Return(nil)
+
+	err := suite.usecase.DisableTwoFactor(context.TODO(), "user_1", suite.currentCode())
+	suite.Require().Nil(err)
+	suite.True(suite.audited(domain.AUDIT_USER_2FA_DISABLE))
+}
+
+func (suite *twoFactorUsecaseSuite) TestUpdatePolicy() {
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil)
+	suite.policies.On("FetchTwoFactorPolicy", mock.Anything).Return(domain.TwoFactorPolicy{RequiredRoles: []string{}}, nil)
+	suite.policies.On("UpdateTwoFactorPolicy", mock.Anything, domain.TwoFactorPolicy{RequiredRoles: []string{"admin"}}).Return(
+		domain.TwoFactorPolicy{OrgID: "org_1", RequiredRoles: []string{"admin"}}, nil)
+
+	_, err := suite.usecase.UpdateTwoFactorPolicy(context.TODO(), suite.user, domain.TwoFactorPolicy{RequiredRoles: []string{"owner"}})
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusBadRequest, err.Code)
+
+	policy, err := suite.usecase.UpdateTwoFactorPolicy(context.TODO(), suite.user, domain.TwoFactorPolicy{RequiredRoles: []string{"admin", "admin"}})
+	suite.Require().Nil(err)
+	suite.Equal([]string{"admin"}, policy.RequiredRoles)
+	suite.True(suite.audited(domain.AUDIT_TWO_FACTOR_POLICY))
+}
+
+func (suite *twoFactorUsecaseSuite) TestUpdatePolicyRequiresAdmin() {
+	user := suite.user
+	user.Role = "user"
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(user, nil)
+
+	_, err := suite.usecase.UpdateTwoFactorPolicy(context.TODO(), user, domain.TwoFactorPolicy{RequiredRoles: []string{}})
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	suite.policies.AssertNotCalled(suite.T(), "UpdateTwoFactorPolicy", mock.Anything, mock.Anything)
+}
+
+func (suite *twoFactorUsecaseSuite) TestRefreshRefusedUntilEnrolled() {
+	tokens := new(mocks.RefreshTokenRepository)
+	userUC := usecases.NewUserUsecase(suite.userRepository, time.Second*2)
+	userUC.SetRefreshTokenRepository(tokens)
+	userUC.SetTwoFactorPolicyRepository(suite.policies)
+	suite.requireRoles("admin")
+	tokens.On("FetchRefreshToken", mock.Anything, mock.Anything).Return(domain.RefreshToken{
+		ID: "token_1", FamilyID: "token_1", UserID: "user_1", OrgID: "org_1", ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	suite.userRepository.On("FetchUserByID", mock.Anything, "user_1").Return(suite.user, nil)
+
+	_, err := userUC.RefreshSession(context.TODO(), "refresh_1")
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusUnauthorized, err.Code)
+	tokens.AssertNotCalled(suite.T(), "CreateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestTwoFactorUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(twoFactorUsecaseSuite))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
@@ -135,7 +137,61 @@ func (suite *userRepositorySuite) TestUserCreation() {
 		Role:     "admin",
 	}
 	_, errInsert = suite.repository.CreateUser(context.TODO(), user2)
-	suite.NotNil(errInsert, errInsert)
+	suite.Require().NotNil(errInsert, errInsert)
+	suite.Equal(http.StatusConflict, errInsert.Code, "Duplicate usernames should conflict")
+}
+
+func (suite *userRepositorySuite) TestLinkIdentity() {
+	insertedUser, errInsert := suite.repository.CreateUser(context.TODO(), domain.User{Username: "johndoe", Role: "user", Email: "john@example.com"})
+	suite.Require().Nil(errInsert)
+
+	_, errFetch := suite.repository.FetchUserByIdentity(context.TODO(), "https://issuer.example.com", "subject_1")
+	suite.Require().NotNil(errFetch)
+	suite.Equal(http.StatusNotFound, errFetch.Code)
+	byEmail, errFetch := suite.repository.FetchUserByEmail(context.TODO(), "john@example.com")
+	suite.Require().Nil(errFetch)
+	suite.Equal(insertedUser, byEmail.ID)
+
+	linked, errLink := suite.repository.LinkIdentity(context.TODO(), insertedUser, "https://issuer.example.com", "subject_1")
+	suite.Require().Nil(errLink)
+	suite.Equal("subject_1", linked.IdentitySubject)
+	byIdentity, errFetch := suite.repository.FetchUserByIdentity(context.TODO(), "https://issuer.example.com", "subject_1")
+	suite.Require().Nil(errFetch)
+	suite.Equal(insertedUser, byIdentity.ID)
+
+	_, errLink = suite.repository.LinkIdentity(context.TODO(), insertedUser, "https://issuer.example.com", "subject_2")
+	suite.Require().NotNil(errLink, "A linked user should not be linked to another identity")
+	suite.Equal(http.StatusNotFound, errLink.Code)
+}
+
+func (suite *userRepositorySuite) TestTwoFactor() {
+	insertedUser, errInsert := suite.repository.CreateUser(context.TODO(), domain.User{Username: "johndoe", Role: "user"})
+	suite.Require().Nil(errInsert)
+	errUpdate := suite.repository.UpdateTwoFactor(context.TODO(), insertedUser, &domain.TwoFactor{Secret: "SECRET", Enabled: true, LastStep: 10, RecoveryCodes: []string{"hash_1", "hash_2"}})
+	suite.Require().Nil(errUpdate)
+
+	suite.Nil(suite.repository.RecordTwoFactorFailure(context.TODO(), insertedUser, time.Now()))
+	suite.Nil(suite.repository.AcceptTwoFactorStep(context.TODO(), insertedUser, 11))
+	errAccept := suite.repository.AcceptTwoFactorStep(context.TODO(), insertedUser, 11)
+	suite.Require().NotNil(errAccept, "A code should only be accepted once")
+	suite.Equal(http.StatusNotFound, errAccept.Code)
+	errAccept = suite.repository.AcceptTwoFactorStep(context.TODO(), insertedUser, 10)
+	suite.Require().NotNil(errAccept, "A code older than the last accepted one should be refused")
+
+	suite.Nil(suite.repository.ConsumeRecoveryCode(context.TODO(), insertedUser, "hash_1"))
+	errConsume := suite.repository.ConsumeRecoveryCode(context.TODO(), insertedUser, "hash_1")
+	suite.Require().NotNil(errConsume, "A recovery code should only be used once")
+	suite.Equal(http.StatusNotFound, errConsume.Code)
+
+	user, _ := suite.repository.FetchUserByID(context.TODO(), insertedUser)
+	suite.Require().NotNil(user.TwoFactor)
+	suite.Equal(int64(11), user.TwoFactor.LastStep)
+	suite.Equal(0, user.TwoFactor.Failures)
+	suite.Equal([]string{"hash_2"}, user.TwoFactor.RecoveryCodes)
+
+	suite.Nil(suite.repository.UpdateTwoFactor(context.TODO(), insertedUser, nil))
+	user, _ = suite.repository.FetchUserByID(context.TODO(), insertedUser)
+	suite.Nil(user.TwoFactor)
 }
 
 func (suite *userRepositorySuite) TestUpdateUser() {
@@ -2,7 +2,9 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"strconv"
@@ -13,6 +15,7 @@ import (
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -127,6 +130,77 @@ func (suite *userUsecaseSuite) TestCreateUser_Negative() {
 	suite.Empty(fetchID, "fetchID should be empty when an error occurs")
 }
 
+func (suite *userUsecaseSuite) TestCreateUser_RegistrationIgnoresOrg() {
+	var createdOrg interface{}
+	var created domain.User
+	suite.repositorie.On("FetchUserCount", mock.Anything).Return(3, nil)
+	suite.repositorie.On("CreateUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		createdOrg = args.Get(0).(context.Context).Value(infrastructure.CONTEXT_ORG)
+		created = args.Get(1).(domain.User)
+	}).Return("1", nil)
+
+	_, err := suite.usecase.CreateUser(context.TODO(), domain.User{Username: "johndoe", Password: "password123", OrgID: "acme", Role: "admin"})
+	suite.Require().Nil(err)
+	suite.Empty(created.OrgID, "the organization is stamped by the repository from the context")
+	suite.Equal("", createdOrg, "registering should join the default organization, never the one named in the request")
+	suite.Equal("user", created.Role)
+}
+
+func (suite *userUsecaseSuite) TestCreateUser_OnlyFirstRegistrationIsAdmin() {
+	roles := []string{}
+	suite.repositorie.On("FetchUserCount", mock.Anything).Return(0, nil).Once()
+	suite.repositorie.On("FetchUserCount", mock.Anything).Return(1, nil).Once()
+	suite.repositorie.On("CreateUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		roles = append(roles, args.Get(1).(domain.User).Role)
+	}).Return("1", nil).Twice()
+
+	_, err := suite.usecase.CreateUser(context.TODO(), domain.User{Username: "johndoe", Password: "password123"})
+	suite.Require().Nil(err)
+	_, err = suite.usecase.CreateUser(context.TODO(), domain.User{Username: "janedoe", Password: "password123"})
+	suite.Require().Nil(err)
+	suite.Equal([]string{"admin", "user"}, roles, "only the first user of the deployment should become an admin")
+}
+
+func (suite *userUsecaseSuite) TestCreateUser_AdminAddsToOwnOrg() {
+	var createdOrg interface{}
+	suite.repositorie.On("CreateUser", mock.Anything, mock.MatchedBy(func(user domain.User) bool {
+		return user.OrgID == "" && user.Role == "user"
+	})).Run(func(args mock.Arguments) {
+		createdOrg = args.Get(0).(context.Context).Value(infrastructure.CONTEXT_ORG)
+	}).Return("1", nil)
+
+	cxt := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "org_1")
+	_, err := suite.usecase.CreateUser(cxt, domain.User{Username: "johndoe", Password: "password123", OrgID: "acme"})
+	suite.Require().Nil(err)
+	suite.Equal("org_1", createdOrg)
+}
+
+func (suite *userUsecaseSuite) TestCreateOrganization() {
+	var createdOrg interface{}
+	suite.repositorie.On("CreateUser", mock.Anything, mock.MatchedBy(func(user domain.User) bool {
+		return user.OrgID == "" && user.Role == "admin"
+	})).Run(func(args mock.Arguments) {
+		createdOrg = args.Get(0).(context.Context).Value(infrastructure.CONTEXT_ORG)
+	}).Return("1", nil)
+
+	cxt := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "")
+	admin, err := suite.usecase.CreateOrganization(cxt, domain.User{Username: "johndoe", Password: "password123", OrgID: "acme"})
+	suite.Require().Nil(err)
+	suite.Len(admin.OrgID, 32)
+	suite.Equal(admin.OrgID, createdOrg)
+	suite.Equal("admin", admin.Role)
+	suite.Empty(admin.Password)
+	suite.repositorie.AssertNotCalled(suite.T(), "FetchUserCount", mock.Anything)
+}
+
+func (suite *userUsecaseSuite) TestCreateOrganization_OtherOrganization() {
+	cxt := context.WithValue(context.TODO(), infrastructure.CONTEXT_ORG, "org_1")
+	_, err := suite.usecase.CreateOrganization(cxt, domain.User{Username: "johndoe", Password: "password123"})
+	suite.Require().NotNil(err)
+	suite.Equal(http.StatusForbidden, err.Code)
+	suite.repositorie.AssertNotCalled(suite.T(), "CreateUser", mock.Anything, mock.Anything)
+}
+
 func (suite *userUsecaseSuite) TestUpdateUser() {
 	user := domain.User{
 		Username: "johndoe",
@@ -161,6 +235,45 @@ func (suite *userUsecaseSuite) TestDeleteUser_Positive() {
 	suite.Equal(deleteUser, deletedUser, "users should be equal")
 }
 
+func (suite *userUsecaseSuite) TestUpdateUser_RevokesTokensOnRoleChange() {
+	revocations := new(mocks.TokenRevocationStore)
+	userUC := usecases.NewUserUsecase(suite.repositorie, time.Second*2)
+	userUC.SetTokenRevocationStore(revocations)
+	current := domain.User{ID: "1", Username: "janedoe", Role: "user"}
+	promoted := domain.User{ID: "1", Username: "janedoe", Role: "admin"}
+	suite.repositorie.On("FetchUserByID", mock.Anything, "1").Return(current, nil)
+	suite.repositorie.On("UpdateUser", mock.Anything, mock.Anything).Return(promoted, nil)
+	revocations.On("RevokeUserTokens", mock.Anything, "janedoe", mock.Anything).Return(nil).Once()
+
+	_, err := userUC.UpdateUser(context.TODO(), promoted)
+	suite.Nil(err, "error should be nil")
+	_, err = userUC.UpdateUser(context.TODO(), current)
+	suite.Nil(err, "error should be nil")
+	revocations.AssertNumberOfCalls(suite.T(), "RevokeUserTokens", 1)
+
+	suite.repositorie.On("FetchUserByID", mock.Anything, "2").Return(domain.User{ID: "2", Username: "kebede", Role: "user"}, nil)
+	revocations.On("RevokeUserTokens", mock.Anything, "kebede", mock.Anything).Return(errors.New("store down"))
+	_, err = userUC.UpdateUser(context.TODO(), domain.User{ID: "2", Username: "kebede", Role: "admin"})
+	suite.NotNil(err, "the role should not change while the old tokens stay valid")
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "UpdateUser", 2)
+}
+
+func (suite *userUsecaseSuite) TestDeleteUser_RevokesTokens() {
+	revocations := new(mocks.TokenRevocationStore)
+	userUC := usecases.NewUserUsecase(suite.repositorie, time.Second*2)
+	userUC.SetTokenRevocationStore(revocations)
+	authorityUser := domain.User{ID: "2", Username: "johndoe", Role: "admin"}
+	deleteUser := domain.User{ID: "1", Username: "janedoe", Role: "user"}
+	suite.repositorie.On("FetchUserByID", mock.Anything, authorityUser.ID).Return(authorityUser, nil)
+	suite.repositorie.On("FetchUserByID", mock.Anything, deleteUser.ID).Return(deleteUser, nil)
+	suite.repositorie.On("DeleteUser", mock.Anything, deleteUser.ID).Return(deleteUser, nil)
+	revocations.On("RevokeUserTokens", mock.Anything, "janedoe", mock.Anything).Return(nil)
+
+	_, errDelete := userUC.DeleteUser(context.TODO(), authorityUser, deleteUser.ID)
+	suite.Nil(errDelete, "error should be nil")
+	revocations.AssertExpectations(suite.T())
+}
+
 func (suite *userUsecaseSuite) TestDeleteUserSelfDeletion() {
 	authorityUser := domain.User{
 		ID:       "1",
@@ -228,15 +341,22 @@ func (suite *userUsecaseSuite) TestLoginUser() {
 
 	timeDurationEnv, err := strconv.ParseInt(os.Getenv("SIGNITURE_TIME_DURATION"), 10, 64)
 	suite.Nil(err, "parsing SIGNITURE_TIME_DURATION should not fail")
-	expectedToken, err := infrastructure.CreateJWTToken(user.Username, user.Role, time.Duration(timeDurationEnv)*time.Second)
+	expectedToken, err := infrastructure.CreateJWTToken(user.Username, user.Role, user.OrgID, time.Duration(timeDurationEnv)*time.Second)
 	suite.Nil(err, "JWT token creation should not fail")
 	suite.NotNil(expectedToken, "expectedToken should not be nil")
 
-	suite.repositorie.On("CreateJWTToken", user.Username, user.Role, time.Duration(timeDurationEnv)*time.Second).Return(expectedToken, nil)
-	token, err := suite.usecase.LoginUser(context.TODO(), user)
+	suite.repositorie.On("CreateJWTToken", user.Username, user.Role, user.OrgID, time.Duration(timeDurationEnv)*time.Second).Return(expectedToken, nil)
+	session, err := suite.usecase.LoginUser(context.TODO(), user)
 
 	suite.Nil(err, "error should be nil")
-	suite.Equal(expectedToken, token, "tokens should be equal")
+	parsedToken, errParse := infrastructure.ParseJWTToken(session.Token)
+	suite.Nil(errParse, "the token should be valid")
+	claims := parsedToken.Claims.(jwt.MapClaims)
+	suite.Equal(user.Username, claims["username"], "the token should name the user")
+	suite.Equal(user.Role, claims["role"], "the token should carry the role")
+	suite.NotEmpty(claims["jti"], "the token should have an ID")
+	suite.Equal(int64(3600), session.ExpiresIn)
+	suite.Empty(session.RefreshToken, "No refresh token without a refresh token repository")
 }
 
 func (suite *userUsecaseSuite) TestLoginUserUsernameNotExist() {
@@ -265,11 +385,11 @@ func (suite *userUsecaseSuite) TestLoginUserUsernameNotExist() {
 
 	timeDurationEnv, err := strconv.ParseInt(os.Getenv("SIGNITURE_TIME_DURATION"), 10, 64)
 	suite.Nil(err, "parsing SIGNITURE_TIME_DURATION should not fail")
-	expectedToken, err := infrastructure.CreateJWTToken(user.Username, user.Role, time.Duration(timeDurationEnv)*time.Second)
+	expectedToken, err := infrastructure.CreateJWTToken(user.Username, user.Role, user.OrgID, time.Duration(timeDurationEnv)*time.Second)
 	suite.Nil(err, "JWT token creation should not fail")
 	suite.NotNil(expectedToken, "expectedToken should not be nil")
 
-	suite.repositorie.On("CreateJWTToken", user.Username, user.Role, time.Duration(timeDurationEnv)*time.Second).Return(expectedToken, nil)
+	suite.repositorie.On("CreateJWTToken", user.Username, user.Role, user.OrgID, time.Duration(timeDurationEnv)*time.Second).Return(expectedToken, nil)
 	_, err = suite.usecase.LoginUser(context.TODO(), user)
 
 	suite.NotNil(err, "error should be nil")
@@ -302,13 +422,35 @@ func (suite *userUsecaseSuite) TestLoginUserValidation_Negative() {
 	timeDurationEnv, err := strconv.ParseInt(os.Getenv("SIGNITURE_TIME_DURATION"), 10, 64)
 	suite.Nil(err, "parsing SIGNITURE_TIME_DURATION should not fail")
 	expectedToken := "some.jwt.token"
-	suite.authService.On("CreateJWTToken", user.Username, user.Role, time.Duration(timeDurationEnv)*time.Second).Return(expectedToken, nil)
+	suite.authService.On("CreateJWTToken", user.Username, user.Role, user.OrgID, time.Duration(timeDurationEnv)*time.Second).Return(expectedToken, nil)
 
 	_, errLogin := suite.usecase.LoginUser(context.TODO(), user)
 
 	suite.NotNil(errLogin, "error should be not nil")
 }
 
+func (suite *userUsecaseSuite) TestRestoreDeletedUser() {
+	restored := domain.User{ID: "1", Username: "johndoe", Role: "user"}
+	suite.repositorie.On("UndeleteUser", mock.Anything, "1").Return(restored, nil)
+
+	user, err := suite.usecase.RestoreDeletedUser(context.TODO(), "1")
+	suite.Nil(err, "error should be nil")
+	suite.Equal(restored, user)
+}
+
+func (suite *userUsecaseSuite) TestPurgeDeletedUsers() {
+	cutoff := time.Now().Add(-time.Hour)
+	expired := []domain.User{{ID: "1", Username: "johndoe"}, {ID: "2", Username: "janedoe"}}
+	suite.repositorie.On("FetchDeletedUsers", mock.Anything, cutoff, usecases.PURGE_BATCH_SIZE).Return(expired, nil)
+	suite.repositorie.On("PurgeUser", mock.Anything, "1", cutoff).Return(&domain.UserError{Message: "User not found in the trash", Code: 404})
+	suite.repositorie.On("PurgeUser", mock.Anything, "2", cutoff).Return(nil)
+
+	purged, err := suite.usecase.PurgeDeletedUsers(context.TODO(), cutoff)
+	suite.NotNil(err, "the failed user should be reported")
+	suite.Equal(1, purged, "the other users are still purged")
+	suite.repositorie.AssertNumberOfCalls(suite.T(), "PurgeUser", 2)
+}
+
 func TestUserUsecaseSuite(t *testing.T) {
 	suite.Run(t, new(userUsecaseSuite))
 }
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+// multipart form field that carries the uploaded file
+const ATTACHMENT_FORM_FIELD = "file"
+
+type AttachmentController struct {
+	AttachmentUsecase domain.AttachmentUsecase
+	UserUsecase       domain.UserUsecase
+}
+
+func NewAttachmentController(attachmentUC domain.AttachmentUsecase, userUC domain.UserUsecase) AttachmentController {
+	return AttachmentController{
+		AttachmentUsecase: attachmentUC,
+		UserUsecase:       userUC,
+	}
+}
+
+func (controller *AttachmentController) GetAttachments(cxt *gin.Context) {
+	attachments, err := controller.AttachmentUsecase.GetAttachments(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// reads the multipart body as a stream so large files are never buffered in memory
+func (controller *AttachmentController) PostAttachment(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	reader, err := cxt.Request.MultipartReader()
+	if err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Expected a multipart/form-data body"})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid multipart body"})
+			return
+		}
+		if part.FormName() != ATTACHMENT_FORM_FIELD {
+			part.Close()
+			continue
+		}
+		attachment, errUpload := controller.AttachmentUsecase.UploadAttachment(cxt, cxt.Param("id"), domain.AttachmentUpload{
+			Filename:   part.FileName(),
+			UploadedBy: user.ID,
+			Content:    part,
+		})
+		part.Close()
+		if errUpload != nil {
+			cxt.JSON(errUpload.Code, gin.H{"Error": errUpload.Error()})
+			return
+		}
+		cxt.JSON(http.StatusCreated, attachment)
+		return
+	}
+	cxt.JSON(http.StatusBadRequest, gin.H{"Error": "The " + ATTACHMENT_FORM_FIELD + " field is required"})
+}
+
+// serves the file as a download, browsers are told not to render or re-sniff it
+func (controller *AttachmentController) GetAttachment(cxt *gin.Context) {
+	attachment, content, err := controller.AttachmentUsecase.OpenAttachment(cxt, cxt.Param("id"), cxt.Param("attachmentid"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	defer content.Close()
+	cxt.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   strconv.Quote(attachment.SHA256),
+		"X-Checksum-Sha256":      attachment.SHA256,
+	})
+}
+
+func (controller *AttachmentController) DeleteAttachment(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	if err := controller.AttachmentUsecase.DeleteAttachment(cxt, cxt.Param("id"), cxt.Param("attachmentid"), user); err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"message": "Attachment deleted"})
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	AuditUsecase domain.AuditUsecase
+}
+
+func NewAuditController(auditUC domain.AuditUsecase) AuditController {
+	return AuditController{
+		AuditUsecase: auditUC,
+	}
+}
+
+func (controller *AuditController) GetAuditEntries(cxt *gin.Context) {
+	query, errQuery := parseAuditQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	entries, err := controller.AuditUsecase.GetEntries(cxt, query)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+func parseAuditQuery(cxt *gin.Context) (domain.AuditQuery, *domain.TaskError) {
+	query := domain.AuditQuery{
+		Actor:      cxt.Query("actor"),
+		TargetType: cxt.Query("target_type"),
+		TargetID:   cxt.Query("target"),
+	}
+	if since := cxt.Query("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return domain.AuditQuery{}, &domain.TaskError{Message: "since must be an RFC3339 timestamp", Code: http.StatusBadRequest}
+		}
+		query.Since = parsed
+	}
+	if until := cxt.Query("until"); until != "" {
+		parsed, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return domain.AuditQuery{}, &domain.TaskError{Message: "until must be an RFC3339 timestamp", Code: http.StatusBadRequest}
+		}
+		query.Until = parsed
+	}
+	if limit := cxt.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 {
+			return domain.AuditQuery{}, &domain.TaskError{Message: "limit must be a positive integer", Code: http.StatusBadRequest}
+		}
+		query.Limit = parsed
+	}
+	return query, nil
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentController struct {
+	CommentUsecase domain.CommentUsecase
+	UserUsecase    domain.UserUsecase
+}
+
+func NewCommentController(commentUC domain.CommentUsecase, userUC domain.UserUsecase) CommentController {
+	return CommentController{
+		CommentUsecase: commentUC,
+		UserUsecase:    userUC,
+	}
+}
+
+type commentRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parentID"`
+}
+
+func (controller *CommentController) GetComments(cxt *gin.Context) {
+	comments, err := controller.CommentUsecase.GetComments(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+func (controller *CommentController) PostComment(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request commentRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	comment, err := controller.CommentUsecase.AddComment(cxt, domain.Comment{
+		TaskID:   cxt.Param("id"),
+		ParentID: request.ParentID,
+		AuthorID: user.ID,
+		Body:     request.Body,
+	})
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusCreated, comment)
+}
+
+func (controller *CommentController) PutComment(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request commentRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	comment, err := controller.CommentUsecase.EditComment(cxt, cxt.Param("id"), cxt.Param("commentid"), user.ID, request.Body)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, comment)
+}
+
+func (controller *CommentController) DeleteComment(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	if err := controller.CommentUsecase.DeleteComment(cxt, cxt.Param("id"), cxt.Param("commentid"), user.ID); err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
@@ -1,14 +1,31 @@
 package controllers
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
 )
 
+// largest accepted patch document
+const MAX_PATCH_SIZE = 1 << 20
+
+// keeps the login at the identity provider until it redirects back
+const OIDC_LOGIN_COOKIE = "oidc_login"
+
+// seconds a user has to log in at the identity provider
+const OIDC_LOGIN_MAX_AGE = 600
+
 type Controller struct {
 	TaskUsecase domain.TaskUsecase
 	UserUsecase domain.UserUsecase
@@ -23,12 +40,50 @@ func NewController(taskUC domain.TaskUsecase, userUC domain.UserUsecase) Control
 }
 
 func (controller *Controller) GetTasks(cxt *gin.Context) {
-	tasks, err := controller.TaskUsecase.GetAllTasks(cxt)
+	query, errQuery := parseTaskQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	page, err := controller.TaskUsecase.GetTasks(cxt, query)
 	if err != nil {
 		cxt.JSON(err.Code, gin.H{"Error": err.Message})
 		return
 	}
-	cxt.JSON(http.StatusOK, gin.H{"tasks": tasks})
+	cxt.JSON(http.StatusOK, page)
+}
+
+func (controller *Controller) SearchTasks(cxt *gin.Context) {
+	limit := 0
+	if rawLimit := cxt.Query("limit"); rawLimit != "" {
+		parsed, errLimit := strconv.Atoi(rawLimit)
+		if errLimit != nil || parsed < 1 {
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	results, err := controller.TaskUsecase.SearchTasks(cxt, cxt.Query("q"), limit)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+func (controller *Controller) GetWorkflow(cxt *gin.Context) {
+	workflow := controller.TaskUsecase.GetWorkflow()
+	status := cxt.Query("status")
+	if status == "" {
+		cxt.JSON(http.StatusOK, gin.H{"workflow": workflow})
+		return
+	}
+	next, err := controller.TaskUsecase.NextStatuses(cxt, status)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"workflow": workflow, "next": next})
 }
 
 func (controller *Controller) GetTaskByID(cxt *gin.Context) {
@@ -38,9 +93,281 @@ func (controller *Controller) GetTaskByID(cxt *gin.Context) {
 		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
 		return
 	}
+	cxt.Header("ETag", taskETag(task))
+	cxt.JSON(http.StatusOK, task)
+}
+
+func (controller *Controller) GetChildTasks(cxt *gin.Context) {
+	children, err := controller.TaskUsecase.GetChildTasks(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"tasks": children})
+}
+
+func (controller *Controller) GetTaskTree(cxt *gin.Context) {
+	tree, err := controller.TaskUsecase.GetTaskTree(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, tree)
+}
+
+func (controller *Controller) GetTags(cxt *gin.Context) {
+	tags, err := controller.TaskUsecase.GetTags(cxt)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+func (controller *Controller) PostTaskTags(cxt *gin.Context) {
+	var update domain.TagUpdate
+	if err := cxt.ShouldBindJSON(&update); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	updated, err := controller.TaskUsecase.UpdateTaskTags(cxt, update)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (controller *Controller) PutTag(cxt *gin.Context) {
+	var rename struct {
+		Name string `json:"name"`
+	}
+	if err := cxt.ShouldBindJSON(&rename); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	updated, err := controller.TaskUsecase.RenameTag(cxt, cxt.Param("tag"), rename.Name)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (controller *Controller) PostTagMerge(cxt *gin.Context) {
+	var merge struct {
+		Tags []string `json:"tags"`
+		Into string   `json:"into"`
+	}
+	if err := cxt.ShouldBindJSON(&merge); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	updated, err := controller.TaskUsecase.MergeTags(cxt, merge.Tags, merge.Into)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (controller *Controller) GetTaskOccurrences(cxt *gin.Context) {
+	count := 0
+	if rawCount := cxt.Query("count"); rawCount != "" {
+		parsed, errCount := strconv.Atoi(rawCount)
+		if errCount != nil || parsed < 1 {
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "count must be a positive integer"})
+			return
+		}
+		count = parsed
+	}
+	occurrences, err := controller.TaskUsecase.PreviewOccurrences(cxt, cxt.Param("id"), count)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"occurrences": occurrences})
+}
+
+func (controller *Controller) GetTaskRevisions(cxt *gin.Context) {
+	revisions, err := controller.TaskUsecase.GetRevisions(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+func (controller *Controller) GetTaskRevisionDiff(cxt *gin.Context) {
+	from, errFrom := strconv.Atoi(cxt.Query("from"))
+	to, errTo := strconv.Atoi(cxt.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "from and to must be revision numbers"})
+		return
+	}
+	diff, err := controller.TaskUsecase.DiffRevisions(cxt, cxt.Param("id"), from, to)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, diff)
+}
+
+func (controller *Controller) PostTaskRevert(cxt *gin.Context) {
+	revision, errRevision := strconv.Atoi(cxt.Param("revision"))
+	if errRevision != nil || revision < 1 {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "revision must be a revision number"})
+		return
+	}
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	task, err := controller.TaskUsecase.RevertTask(cxt, cxt.Param("id"), revision, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
 	cxt.JSON(http.StatusOK, task)
 }
 
+func (controller *Controller) PostTaskDependency(cxt *gin.Context) {
+	var dependency struct {
+		BlockedBy string `json:"blockedBy"`
+	}
+	if err := cxt.ShouldBindJSON(&dependency); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	if dependency.BlockedBy == "" {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Missing required fields", "Missing": []string{"blockedBy"}})
+		return
+	}
+	updatedTask, err := controller.TaskUsecase.AddDependency(cxt, cxt.Param("id"), dependency.BlockedBy)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, updatedTask)
+}
+
+func (controller *Controller) DeleteTaskDependency(cxt *gin.Context) {
+	updatedTask, err := controller.TaskUsecase.RemoveDependency(cxt, cxt.Param("id"), cxt.Param("blockerid"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, updatedTask)
+}
+
+func (controller *Controller) GetTaskPlan(cxt *gin.Context) {
+	taskIDs := []string{}
+	for _, ID := range strings.Split(cxt.Query("ids"), ",") {
+		if ID = strings.TrimSpace(ID); ID != "" {
+			taskIDs = append(taskIDs, ID)
+		}
+	}
+	plan, err := controller.TaskUsecase.PlanTasks(cxt, taskIDs)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"tasks": plan})
+}
+
+// the tasks assigned to the caller, filtered, sorted and paged like GET /task
+func (controller *Controller) GetMyTasks(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	query, errQuery := parseTaskQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	query.AssigneeID = user.ID
+	page, err := controller.TaskUsecase.GetTasks(cxt, query)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Message})
+		return
+	}
+	cxt.JSON(http.StatusOK, page)
+}
+
+func (controller *Controller) PostTaskAssignee(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var assignee struct {
+		UserID string `json:"userID"`
+	}
+	if err := cxt.ShouldBindJSON(&assignee); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	if assignee.UserID == "" {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Missing required fields", "Missing": []string{"userID"}})
+		return
+	}
+	if _, err := controller.UserUsecase.GetUserByID(cxt, assignee.UserID); err != nil {
+		cxt.JSON(http.StatusNotFound, gin.H{"Error": "User not found"})
+		return
+	}
+	updatedTask, err := controller.TaskUsecase.AssignTask(cxt, cxt.Param("id"), assignee.UserID, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.Header("ETag", taskETag(updatedTask))
+	cxt.JSON(http.StatusOK, updatedTask)
+}
+
+func (controller *Controller) DeleteTaskAssignee(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	updatedTask, err := controller.TaskUsecase.UnassignTask(cxt, cxt.Param("id"), cxt.Param("userid"), user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.Header("ETag", taskETag(updatedTask))
+	cxt.JSON(http.StatusOK, updatedTask)
+}
+
+// the caller starts watching the task
+func (controller *Controller) PostTaskWatcher(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	updatedTask, err := controller.TaskUsecase.WatchTask(cxt, cxt.Param("id"), user.ID)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.Header("ETag", taskETag(updatedTask))
+	cxt.JSON(http.StatusOK, updatedTask)
+}
+
+// the caller stops watching the task
+func (controller *Controller) DeleteTaskWatcher(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	updatedTask, err := controller.TaskUsecase.UnwatchTask(cxt, cxt.Param("id"), user.ID)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.Header("ETag", taskETag(updatedTask))
+	cxt.JSON(http.StatusOK, updatedTask)
+}
+
 func (controller *Controller) UpdateTask(cxt *gin.Context) {
 	var updatedTask domain.Task
 	if err := cxt.ShouldBindJSON(&updatedTask); err != nil {
@@ -69,21 +396,86 @@ func (controller *Controller) UpdateTask(cxt *gin.Context) {
 		return
 	}
 
-	returnedTask, err := controller.TaskUsecase.UpdateTask(cxt, updatedTask)
+	// under /projects/:pid/tasks/:id the task is named by the path
+	if taskID := cxt.Param("id"); taskID != "" {
+		updatedTask.ID = taskID
+	}
+
+	// the version in the If-Match header takes precedence over the one in the body
+	expectedVersion, errVersion := parseIfMatch(cxt)
+	if errVersion != nil {
+		cxt.JSON(errVersion.Code, gin.H{"Error": errVersion.Error()})
+		return
+	}
+	if expectedVersion != 0 {
+		updatedTask.Version = expectedVersion
+	}
+
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	returnedTask, err := controller.TaskUsecase.UpdateTask(cxt, updatedTask, user)
 	if err != nil {
-		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		respondTaskError(cxt, returnedTask, err)
 		return
 	}
 
+	cxt.Header("ETag", taskETag(returnedTask))
 	cxt.JSON(http.StatusOK, returnedTask)
 }
 
+// partial update in either patch format, chosen by the Content-Type of the request
+func (controller *Controller) PatchTask(cxt *gin.Context) {
+	format := cxt.ContentType()
+	if format != domain.PATCH_MERGE && format != domain.PATCH_JSON {
+		cxt.Header("Accept-Patch", domain.PATCH_MERGE+", "+domain.PATCH_JSON)
+		cxt.JSON(http.StatusUnsupportedMediaType, gin.H{"Error": "Content-Type must be " + domain.PATCH_MERGE + " or " + domain.PATCH_JSON})
+		return
+	}
+	expectedVersion, errVersion := parseIfMatch(cxt)
+	if errVersion != nil {
+		cxt.JSON(errVersion.Code, gin.H{"Error": errVersion.Error()})
+		return
+	}
+	patch, err := io.ReadAll(http.MaxBytesReader(cxt.Writer, cxt.Request.Body, MAX_PATCH_SIZE))
+	if err != nil {
+		var errTooLarge *http.MaxBytesError
+		if errors.As(err, &errTooLarge) {
+			cxt.JSON(http.StatusRequestEntityTooLarge, gin.H{"Error": "Patch is too large"})
+			return
+		}
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	patchedTask, errPatch := controller.TaskUsecase.PatchTask(cxt, cxt.Param("id"), domain.TaskPatch{Format: format, Patch: patch, Version: expectedVersion}, user)
+	if errPatch != nil {
+		respondTaskError(cxt, patchedTask, errPatch)
+		return
+	}
+	cxt.Header("ETag", taskETag(patchedTask))
+	cxt.JSON(http.StatusOK, patchedTask)
+}
+
 func (controller *Controller) DeleteTask(cxt *gin.Context) {
 	taskID := cxt.Param("id")
-	authorityID := cxt.Param("userid")
-	deletedTask, err := controller.TaskUsecase.DeleteTask(cxt, taskID, authorityID)
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	expectedVersion, errVersion := parseIfMatch(cxt)
+	if errVersion != nil {
+		cxt.JSON(errVersion.Code, gin.H{"Error": errVersion.Error()})
+		return
+	}
+	deletedTask, err := controller.TaskUsecase.DeleteTask(cxt, taskID, user, expectedVersion)
 	if err != nil {
-		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		respondTaskError(cxt, deletedTask, err)
 		return
 	}
 	cxt.JSON(http.StatusOK, deletedTask)
@@ -164,6 +556,32 @@ func (controller *Controller) PostUserRegister(cxt *gin.Context) {
 	cxt.JSON(http.StatusAccepted, result)
 }
 
+// founds a new organization, the body is its first admin
+func (controller *Controller) PostOrganization(cxt *gin.Context) {
+	var newAdmin domain.User
+	if err := cxt.ShouldBind(&newAdmin); err != nil {
+		switch e := err.(type) {
+		case *json.SyntaxError:
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Malformed JSON"})
+		case *json.UnmarshalTypeError:
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Mismatched format"})
+		case validator.ValidationErrors:
+			missingRequireds := []string{}
+			for _, fieldError := range e {
+				missingRequireds = append(missingRequireds, fieldError.Field())
+			}
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Missing required fields", "Missing": missingRequireds})
+		}
+		return
+	}
+	result, err := controller.UserUsecase.CreateOrganization(cxt, newAdmin)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusCreated, result)
+}
+
 func (controller *Controller) PostUserLogin(cxt *gin.Context) {
 	var loggingUser domain.User
 	if err := cxt.ShouldBind(&loggingUser); err != nil {
@@ -181,10 +599,197 @@ func (controller *Controller) PostUserLogin(cxt *gin.Context) {
 		}
 		return
 	}
-	token, err := controller.UserUsecase.LoginUser(cxt, loggingUser)
+	session, err := controller.UserUsecase.LoginUser(cxt, loggingUser)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, session)
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (controller *Controller) PostUserRefresh(cxt *gin.Context) {
+	var request refreshTokenRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "refresh_token is required"})
+		return
+	}
+	session, err := controller.UserUsecase.RefreshSession(cxt, request.RefreshToken)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, session)
+}
+
+// the access token in the optional Authorization header is revoked along with the session
+func (controller *Controller) PostUserLogout(cxt *gin.Context) {
+	var request refreshTokenRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "refresh_token is required"})
+		return
+	}
+	accessToken := ""
+	if authHeader := cxt.GetHeader("Authorization"); authHeader != "" {
+		authTokens := strings.Split(authHeader, " ")
+		if len(authTokens) != 2 || authTokens[0] != "Bearer" {
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid authorization header"})
+			return
+		}
+		accessToken = authTokens[1]
+	}
+	if err := controller.UserUsecase.Logout(cxt, request.RefreshToken, accessToken); err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// redirects to the identity provider, keeping the login in a cookie for the callback
+func (controller *Controller) GetOIDCLogin(cxt *gin.Context) {
+	login, err := controller.UserUsecase.BeginOIDCLogin(cxt)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	encoded, errEncode := json.Marshal(login)
+	if errEncode != nil {
+		cxt.JSON(http.StatusInternalServerError, gin.H{"Error": errEncode.Error()})
+		return
+	}
+	setOIDCLoginCookie(cxt, base64.RawURLEncoding.EncodeToString(encoded), OIDC_LOGIN_MAX_AGE)
+	cxt.Redirect(http.StatusFound, login.URL)
+}
+
+// the identity provider redirects back here with a code, which is exchanged for a session
+func (controller *Controller) GetOIDCCallback(cxt *gin.Context) {
+	cookie, errCookie := cxt.Cookie(OIDC_LOGIN_COOKIE)
+	// a login is only completed once
+	setOIDCLoginCookie(cxt, "", -1)
+	if providerError := cxt.Query("error"); providerError != "" {
+		cxt.JSON(http.StatusUnauthorized, gin.H{"Error": strings.TrimSpace("The identity provider refused the login: " + providerError + " " + cxt.Query("error_description"))})
+		return
+	}
+	var login domain.OIDCLogin
+	decoded, errDecode := base64.RawURLEncoding.DecodeString(cookie)
+	if errCookie != nil || errDecode != nil || json.Unmarshal(decoded, &login) != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "No login in progress, please log in again"})
+		return
+	}
+	code := cxt.Query("code")
+	if code == "" {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "code is required"})
+		return
+	}
+	session, err := controller.UserUsecase.CompleteOIDCLogin(cxt, code, cxt.Query("state"), login)
 	if err != nil {
 		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
 		return
 	}
-	cxt.JSON(http.StatusOK, gin.H{"token": token})
+	cxt.JSON(http.StatusOK, session)
+}
+
+// the cookie is only sent to the login and callback endpoints, and along with the redirect of the provider
+func setOIDCLoginCookie(cxt *gin.Context, value string, maxAge int) {
+	secure := cxt.Request.TLS != nil || cxt.GetHeader("X-Forwarded-Proto") == "https"
+	cxt.SetSameSite(http.SameSiteLaxMode)
+	cxt.SetCookie(OIDC_LOGIN_COOKIE, value, maxAge, path.Dir(cxt.Request.URL.Path), "", secure, true)
+}
+
+// reads the filtering, sorting and paging parameters of GET /task
+func parseTaskQuery(cxt *gin.Context) (domain.TaskQuery, *domain.TaskError) {
+	query := domain.TaskQuery{
+		Status:    cxt.Query("status"),
+		Priority:  cxt.Query("priority"),
+		UserID:    cxt.Query("userID"),
+		ProjectID: cxt.Query("projectID"),
+		SortBy:    cxt.Query("sort"),
+		Cursor:    cxt.Query("cursor"),
+	}
+	if dueAfter := cxt.Query("due_after"); dueAfter != "" {
+		parsed, err := time.Parse(time.RFC3339, dueAfter)
+		if err != nil {
+			return domain.TaskQuery{}, &domain.TaskError{Message: "due_after must be an RFC3339 timestamp", Code: http.StatusBadRequest}
+		}
+		query.DueAfter = parsed
+	}
+	if dueBefore := cxt.Query("due_before"); dueBefore != "" {
+		parsed, err := time.Parse(time.RFC3339, dueBefore)
+		if err != nil {
+			return domain.TaskQuery{}, &domain.TaskError{Message: "due_before must be an RFC3339 timestamp", Code: http.StatusBadRequest}
+		}
+		query.DueBefore = parsed
+	}
+	if anyTags := cxt.Query("tags_any"); anyTags != "" {
+		query.AnyTags = strings.Split(anyTags, ",")
+	}
+	if allTags := cxt.Query("tags_all"); allTags != "" {
+		query.AllTags = strings.Split(allTags, ",")
+	}
+	switch cxt.Query("order") {
+	case "", "asc":
+		query.SortOrder = 1
+	case "desc":
+		query.SortOrder = -1
+	default:
+		return domain.TaskQuery{}, &domain.TaskError{Message: "order must be asc or desc", Code: http.StatusBadRequest}
+	}
+	if limit := cxt.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 {
+			return domain.TaskQuery{}, &domain.TaskError{Message: "limit must be a positive integer", Code: http.StatusBadRequest}
+		}
+		query.Limit = parsed
+	}
+	return query, nil
+}
+
+// the version of the task as a strong entity tag, tasks written before versioning are at version 0
+func taskETag(task domain.Task) string {
+	return strconv.Quote(strconv.Itoa(task.Version))
+}
+
+// the version the request expects the task to be at, 0 when it has no If-Match header or matches any version
+func parseIfMatch(cxt *gin.Context) (int, *domain.TaskError) {
+	ifMatch := strings.TrimSpace(cxt.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err != nil || !strings.HasPrefix(ifMatch, `"`) {
+		return 0, &domain.TaskError{Message: "If-Match must be a single ETag returned for the task", Code: http.StatusBadRequest}
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 0 {
+		return 0, &domain.TaskError{Message: "If-Match must be a single ETag returned for the task", Code: http.StatusBadRequest}
+	}
+	return version, nil
+}
+
+// a version conflict comes with the task as it is now so the client can merge and retry
+func respondTaskError(cxt *gin.Context, currentTask domain.Task, err *domain.TaskError) {
+	if err.Code == http.StatusPreconditionFailed {
+		cxt.Header("ETag", taskETag(currentTask))
+		cxt.JSON(err.Code, gin.H{"Error": err.Error(), "task": currentTask})
+		return
+	}
+	cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+}
+
+// the user behind the token, writes the error response when it cannot be found
+func currentUser(cxt *gin.Context, userUC domain.UserUsecase) (domain.User, bool) {
+	username := cxt.GetString(infrastructure.CONTEXT_USERNAME)
+	if username == "" {
+		cxt.JSON(http.StatusUnauthorized, gin.H{"Error": "Invalid token, Username of the user is not found"})
+		return domain.User{}, false
+	}
+	user, err := userUC.GetUserByUsername(cxt, username)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return domain.User{}, false
+	}
+	return user, true
 }
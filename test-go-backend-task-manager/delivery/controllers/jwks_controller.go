@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+)
+
+// how long other services may cache the published keys, in seconds
+const JWKS_MAX_AGE = "300"
+
+type JWKSController struct {
+	Keys *infrastructure.KeyManager
+}
+
+func NewJWKSController(keys *infrastructure.KeyManager) JWKSController {
+	return JWKSController{
+		Keys: keys,
+	}
+}
+
+// the public keys that verify our tokens, the set is empty while tokens are signed with SIGNITURE_SECRET
+func (controller *JWKSController) GetJWKS(cxt *gin.Context) {
+	set := infrastructure.JWKSet{Keys: []infrastructure.JWK{}}
+	if controller.Keys != nil {
+		set = controller.Keys.JWKS()
+	}
+	cxt.Header("Cache-Control", "public, max-age="+JWKS_MAX_AGE)
+	cxt.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectController struct {
+	ProjectUsecase domain.ProjectUsecase
+	UserUsecase    domain.UserUsecase
+}
+
+func NewProjectController(projectUC domain.ProjectUsecase, userUC domain.UserUsecase) ProjectController {
+	return ProjectController{
+		ProjectUsecase: projectUC,
+		UserUsecase:    userUC,
+	}
+}
+
+type projectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// lets the request through only when the caller has at least the role in the project named by
+// the :pid parameter. the handlers after it, and the task queries they make, only reach the tasks
+// of that project.
+func (controller *ProjectController) RequireProjectRole(role string) gin.HandlerFunc {
+	return func(cxt *gin.Context) {
+		user, ok := currentUser(cxt, controller.UserUsecase)
+		if !ok {
+			cxt.Abort()
+			return
+		}
+		project, err := controller.ProjectUsecase.Authorize(cxt, cxt.Param("pid"), user, role)
+		if err != nil {
+			cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+			cxt.Abort()
+			return
+		}
+		cxt.Set(infrastructure.CONTEXT_PROJECT, project.ID)
+		cxt.Next()
+	}
+}
+
+func (controller *ProjectController) GetProjects(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	projects, err := controller.ProjectUsecase.GetProjects(cxt, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+func (controller *ProjectController) GetProject(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	project, err := controller.ProjectUsecase.GetProject(cxt, cxt.Param("pid"), user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, project)
+}
+
+func (controller *ProjectController) PostProject(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request projectRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	project, err := controller.ProjectUsecase.CreateProject(cxt, domain.Project{Name: request.Name, Description: request.Description}, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusCreated, project)
+}
+
+func (controller *ProjectController) PutProject(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request projectRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	project, err := controller.ProjectUsecase.UpdateProject(cxt, domain.Project{ID: cxt.Param("pid"), Name: request.Name, Description: request.Description}, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, project)
+}
+
+func (controller *ProjectController) DeleteProject(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	if err := controller.ProjectUsecase.DeleteProject(cxt, cxt.Param("pid"), user); err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"message": "Project deleted"})
+}
+
+func (controller *ProjectController) PutProjectMember(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request struct {
+		Role string `json:"role"`
+	}
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	member := domain.ProjectMember{UserID: cxt.Param("userid"), Role: request.Role}
+	project, err := controller.ProjectUsecase.SetMember(cxt, cxt.Param("pid"), member, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, project)
+}
+
+func (controller *ProjectController) DeleteProjectMember(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	project, err := controller.ProjectUsecase.RemoveMember(cxt, cxt.Param("pid"), cxt.Param("userid"), user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, project)
+}
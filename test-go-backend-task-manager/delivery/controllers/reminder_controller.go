@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderController struct {
+	ReminderUsecase domain.ReminderUsecase
+	UserUsecase     domain.UserUsecase
+}
+
+func NewReminderController(reminderUC domain.ReminderUsecase, userUC domain.UserUsecase) ReminderController {
+	return ReminderController{
+		ReminderUsecase: reminderUC,
+		UserUsecase:     userUC,
+	}
+}
+
+func (controller *ReminderController) GetReminderPreference(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	preference, err := controller.ReminderUsecase.GetPreference(cxt, user.ID)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, preference)
+}
+
+func (controller *ReminderController) PutReminderPreference(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var preference domain.ReminderPreference
+	if err := cxt.ShouldBindJSON(&preference); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	// users can only change their own preference
+	preference.UserID = user.ID
+	updated, err := controller.ReminderUsecase.UpdatePreference(cxt, preference)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, updated)
+}
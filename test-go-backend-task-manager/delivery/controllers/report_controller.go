@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	ReportUsecase domain.ReportUsecase
+}
+
+func NewReportController(reportUC domain.ReportUsecase) ReportController {
+	return ReportController{
+		ReportUsecase: reportUC,
+	}
+}
+
+func (controller *ReportController) GetBurndown(cxt *gin.Context) {
+	query, errQuery := parseReportQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	burndown, err := controller.ReportUsecase.GetBurndown(cxt, query)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, burndown)
+}
+
+func (controller *ReportController) GetVelocity(cxt *gin.Context) {
+	query, errQuery := parseReportQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	for param, value := range map[string]*int{"iteration_days": &query.IterationDays, "iterations": &query.Iterations} {
+		if raw := cxt.Query(param); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 {
+				cxt.JSON(http.StatusBadRequest, gin.H{"Error": param + " must be a positive integer"})
+				return
+			}
+			*value = parsed
+		}
+	}
+	velocity, err := controller.ReportUsecase.GetVelocity(cxt, query)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, velocity)
+}
+
+// from and to are days in UTC, both included in the report
+func parseReportQuery(cxt *gin.Context) (domain.ReportQuery, *domain.TaskError) {
+	query := domain.ReportQuery{
+		ProjectID:  cxt.Query("projectID"),
+		Tag:        cxt.Query("tag"),
+		AssigneeID: cxt.Query("assignee"),
+		Unit:       cxt.Query("unit"),
+	}
+	if from := cxt.Query("from"); from != "" {
+		parsed, err := time.Parse(domain.REPORT_DAY_FORMAT, from)
+		if err != nil {
+			return domain.ReportQuery{}, &domain.TaskError{Message: "from must be a date such as 2024-08-20", Code: http.StatusBadRequest}
+		}
+		query.From = parsed
+	}
+	if to := cxt.Query("to"); to != "" {
+		parsed, err := time.Parse(domain.REPORT_DAY_FORMAT, to)
+		if err != nil {
+			return domain.ReportQuery{}, &domain.TaskError{Message: "to must be a date such as 2024-08-20", Code: http.StatusBadRequest}
+		}
+		query.To = parsed.AddDate(0, 0, 1)
+	}
+	return query, nil
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type TimeController struct {
+	TimeUsecase domain.TimeUsecase
+	UserUsecase domain.UserUsecase
+}
+
+func NewTimeController(timeUC domain.TimeUsecase, userUC domain.UserUsecase) TimeController {
+	return TimeController{
+		TimeUsecase: timeUC,
+		UserUsecase: userUC,
+	}
+}
+
+type timeEntryRequest struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Note  string    `json:"note"`
+}
+
+// starts a timer on the task, the body with a note is optional
+func (controller *TimeController) PostTimer(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request timeEntryRequest
+	if cxt.Request.ContentLength != 0 {
+		if err := cxt.ShouldBindJSON(&request); err != nil {
+			cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+			return
+		}
+	}
+	entry, err := controller.TimeUsecase.StartTimer(cxt, cxt.Param("id"), request.Note, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusCreated, entry)
+}
+
+func (controller *TimeController) GetTimer(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	entry, err := controller.TimeUsecase.GetRunningTimer(cxt, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, entry)
+}
+
+func (controller *TimeController) PostTimerStop(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	entry, err := controller.TimeUsecase.StopTimer(cxt, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, entry)
+}
+
+func (controller *TimeController) PostTimeEntry(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request timeEntryRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	entry, err := controller.TimeUsecase.AddTimeEntry(cxt, domain.TimeEntry{
+		TaskID: cxt.Param("id"),
+		Start:  request.Start,
+		End:    request.End,
+		Note:   request.Note,
+	}, user)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusCreated, entry)
+}
+
+func (controller *TimeController) GetTaskTime(cxt *gin.Context) {
+	summary, err := controller.TimeUsecase.GetTaskTime(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, summary)
+}
+
+func (controller *TimeController) GetMyTime(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	query, errQuery := parseTimeQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	query.UserID = user.ID
+	summary, err := controller.TimeUsecase.GetUserTime(cxt, query)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, summary)
+}
+
+func (controller *TimeController) GetTimeReport(cxt *gin.Context) {
+	query, errQuery := parseTimeQuery(cxt)
+	if errQuery != nil {
+		cxt.JSON(errQuery.Code, gin.H{"Error": errQuery.Error()})
+		return
+	}
+	query.ProjectID = cxt.Query("projectID")
+	query.UserID = cxt.Query("userID")
+	query.TimeZone = cxt.Query("tz")
+	report, err := controller.TimeUsecase.GetTimeReport(cxt, query)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, report)
+}
+
+func parseTimeQuery(cxt *gin.Context) (domain.TimeQuery, *domain.TaskError) {
+	query := domain.TimeQuery{}
+	if from := cxt.Query("from"); from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return domain.TimeQuery{}, &domain.TaskError{Message: "from must be an RFC3339 timestamp", Code: http.StatusBadRequest}
+		}
+		query.From = parsed
+	}
+	if to := cxt.Query("to"); to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return domain.TimeQuery{}, &domain.TaskError{Message: "to must be an RFC3339 timestamp", Code: http.StatusBadRequest}
+		}
+		query.To = parsed
+	}
+	return query, nil
+}
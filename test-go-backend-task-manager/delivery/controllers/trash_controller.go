@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct {
+	TaskUsecase domain.TaskUsecase
+	UserUsecase domain.UserUsecase
+}
+
+func NewTrashController(taskUC domain.TaskUsecase, userUC domain.UserUsecase) TrashController {
+	return TrashController{
+		TaskUsecase: taskUC,
+		UserUsecase: userUC,
+	}
+}
+
+func (controller *TrashController) GetTrash(cxt *gin.Context) {
+	tasks, errTasks := controller.TaskUsecase.GetDeletedTasks(cxt)
+	if errTasks != nil {
+		cxt.JSON(errTasks.Code, gin.H{"Error": errTasks.Error()})
+		return
+	}
+	users, errUsers := controller.UserUsecase.GetDeletedUsers(cxt)
+	if errUsers != nil {
+		cxt.JSON(errUsers.Code, gin.H{"Error": errUsers.Error()})
+		return
+	}
+	for i := range users {
+		users[i].Password = ""
+	}
+	cxt.JSON(http.StatusOK, gin.H{"tasks": tasks, "users": users})
+}
+
+func (controller *TrashController) PostTaskRestore(cxt *gin.Context) {
+	restoredTask, err := controller.TaskUsecase.RestoreDeletedTask(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.Header("ETag", taskETag(restoredTask))
+	cxt.JSON(http.StatusOK, restoredTask)
+}
+
+func (controller *TrashController) PostUserRestore(cxt *gin.Context) {
+	restoredUser, err := controller.UserUsecase.RestoreDeletedUser(cxt, cxt.Param("id"))
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	restoredUser.Password = ""
+	cxt.JSON(http.StatusOK, restoredUser)
+}
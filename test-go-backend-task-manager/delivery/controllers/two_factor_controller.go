@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	UserUsecase domain.UserUsecase
+}
+
+func NewTwoFactorController(userUC domain.UserUsecase) TwoFactorController {
+	return TwoFactorController{
+		UserUsecase: userUC,
+	}
+}
+
+type twoFactorLoginRequest struct {
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	Code           string `json:"code"`
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// the second step of a login that answered with a two_factor_token
+func (controller *TwoFactorController) PostTwoFactorLogin(cxt *gin.Context) {
+	var request twoFactorLoginRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil || request.Code == "" {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "two_factor_token and code are required"})
+		return
+	}
+	session, err := controller.UserUsecase.CompleteTwoFactorLogin(cxt, request.TwoFactorToken, request.Code)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, session)
+}
+
+func (controller *TwoFactorController) PostTwoFactorLoginEnroll(cxt *gin.Context) {
+	var request twoFactorLoginRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "two_factor_token is required"})
+		return
+	}
+	enrollment, err := controller.UserUsecase.EnrollTwoFactorAtLogin(cxt, request.TwoFactorToken)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, enrollment)
+}
+
+func (controller *TwoFactorController) GetTwoFactor(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	status, err := controller.UserUsecase.GetTwoFactorStatus(cxt, user.ID)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, status)
+}
+
+func (controller *TwoFactorController) PostTwoFactorEnroll(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	enrollment, err := controller.UserUsecase.EnrollTwoFactor(cxt, user.ID)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, enrollment)
+}
+
+func (controller *TwoFactorController) PostTwoFactorConfirm(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request twoFactorCodeRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "code is required"})
+		return
+	}
+	recoveryCodes, err := controller.UserUsecase.ConfirmTwoFactor(cxt, user.ID, request.Code)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
+
+func (controller *TwoFactorController) PostTwoFactorDisable(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	// an enrollment that was never confirmed is cancelled without a code
+	var request struct {
+		Code string `json:"code"`
+	}
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	if err := controller.UserUsecase.DisableTwoFactor(cxt, user.ID, request.Code); err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func (controller *TwoFactorController) PostRecoveryCodes(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var request twoFactorCodeRequest
+	if err := cxt.ShouldBindJSON(&request); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "code is required"})
+		return
+	}
+	recoveryCodes, err := controller.UserUsecase.RegenerateRecoveryCodes(cxt, user.ID, request.Code)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
+
+func (controller *TwoFactorController) DeleteUserTwoFactor(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	if err := controller.UserUsecase.ResetTwoFactor(cxt, user, cxt.Param("id")); err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset"})
+}
+
+func (controller *TwoFactorController) GetTwoFactorPolicy(cxt *gin.Context) {
+	policy, err := controller.UserUsecase.GetTwoFactorPolicy(cxt)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, policy)
+}
+
+func (controller *TwoFactorController) PutTwoFactorPolicy(cxt *gin.Context) {
+	user, ok := currentUser(cxt, controller.UserUsecase)
+	if !ok {
+		return
+	}
+	var policy domain.TwoFactorPolicy
+	if err := cxt.ShouldBindJSON(&policy); err != nil {
+		cxt.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid request payload"})
+		return
+	}
+	updated, err := controller.UserUsecase.UpdateTwoFactorPolicy(cxt, user, policy)
+	if err != nil {
+		cxt.JSON(err.Code, gin.H{"Error": err.Error()})
+		return
+	}
+	cxt.JSON(http.StatusOK, updated)
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	route "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/routers"
@@ -19,5 +22,20 @@ func main() {
 	if err != nil {
 		log.Println("Error", err)
 	}
-	route.Run(port, *database, time.Second, router, os.Getenv("DB_USER_COLLECTION_NAME"), os.Getenv("DB_TASK_COLLECTION_NAME"))
+
+	cxt, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	scheduler := route.NewReminderScheduler(*database, os.Getenv("DB_TASK_COLLECTION_NAME"))
+	scheduler.Start(cxt)
+	purger := route.NewTrashPurger(*database, os.Getenv("DB_USER_COLLECTION_NAME"), os.Getenv("DB_TASK_COLLECTION_NAME"))
+	purger.Start(cxt)
+
+	go func() {
+		route.Run(port, *database, time.Second, router, os.Getenv("DB_USER_COLLECTION_NAME"), os.Getenv("DB_TASK_COLLECTION_NAME"))
+		stop()
+	}()
+	<-cxt.Done()
+	log.Println("Shutting down, waiting for the reminder scheduler and the trash purger")
+	scheduler.Stop()
+	purger.Stop()
 }
@@ -0,0 +1,28 @@
+package route
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// the keys in the .pem files of JWT_KEYS_DIR, signing with JWT_ACTIVE_KEY and refusing the
+// comma separated JWT_RETIRED_KEYS. nil when JWT_KEYS_DIR is unset, tokens are then signed with
+// SIGNITURE_SECRET. keys that are set but cannot be loaded stop the server rather than fall back.
+func loadKeyManager() *infrastructure.KeyManager {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		return nil
+	}
+	retired := []string{}
+	if retiredKeys := os.Getenv("JWT_RETIRED_KEYS"); retiredKeys != "" {
+		retired = strings.Split(retiredKeys, ",")
+	}
+	manager, err := infrastructure.LoadKeyManager(dir, os.Getenv("JWT_ACTIVE_KEY"), retired)
+	if err != nil {
+		log.Fatal("Error loading the signing keys: ", err)
+	}
+	return manager
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// the OpenID Connect provider at OIDC_ISSUER, nil when it is unset and single sign-on is disabled.
+// the provider is only contacted on the first login, so the server starts while it is unreachable.
+func newIdentityProvider() *infrastructure.OIDCProvider {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil
+	}
+	config := infrastructure.OIDCConfig{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+	if config.ClientID == "" || config.RedirectURL == "" {
+		log.Fatal("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+	}
+	return infrastructure.NewOIDCProvider(config, nil)
+}
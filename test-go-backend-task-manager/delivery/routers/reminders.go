@@ -0,0 +1,46 @@
+package route
+
+import (
+	"os"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	repositorie "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/repositories"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const DEFAULT_REMINDER_INTERVAL = time.Minute
+
+// builds the reminder scheduler over the task collection, it scans every REMINDER_INTERVAL
+func NewReminderScheduler(database mongo.Database, taskcollection string) *infrastructure.ReminderScheduler {
+	reminderUsecase := newReminderUsecase(database, database.Collection(taskcollection), loadWorkflow())
+	interval := durationFromEnv("REMINDER_INTERVAL", DEFAULT_REMINDER_INTERVAL)
+	return infrastructure.NewReminderScheduler(reminderUsecase, interval)
+}
+
+func newReminderUsecase(database mongo.Database, collectionTask *mongo.Collection, workflow domain.Workflow) domain.ReminderUsecase {
+	taskRepository := repositorie.NewTaskRepository(collectionTask)
+	reminderRepository := repositorie.NewReminderRepository(
+		database.Collection(envOrDefault("DB_REMINDER_PREFERENCE_COLLECTION_NAME", "reminder_preferences")),
+		database.Collection(envOrDefault("DB_REMINDER_COLLECTION_NAME", "reminders")),
+	)
+	reminderUsecase := usecases.NewReminderUsecase(&taskRepository, &reminderRepository, reminderNotifier(), time.Second*30, workflow)
+	return &reminderUsecase
+}
+
+// emails reminders when SMTP_ADDR is set, logs them otherwise
+func reminderNotifier() domain.Notifier {
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		return infrastructure.NewSMTPNotifier(addr, os.Getenv("SMTP_FROM"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+	}
+	return infrastructure.NewLogNotifier()
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const DEFAULT_REVOCATION_CACHE_TTL = 10 * time.Second
+
+// the store of revoked tokens behind a cache that keeps answers for TOKEN_REVOCATION_CACHE_TTL.
+// TOKEN_REVOCATION_STORE=memory keeps revocations in memory, which only suits a single server.
+func newRevocationStore(database mongo.Database) infrastructure.TokenRevocationStore {
+	var store infrastructure.TokenRevocationStore
+	if os.Getenv("TOKEN_REVOCATION_STORE") == "memory" {
+		store = infrastructure.NewMemoryRevocationStore()
+	} else {
+		collection := database.Collection(envOrDefault("DB_REVOKED_TOKEN_COLLECTION_NAME", "revoked_tokens"))
+		// revoked tokens are removed once they would have expired anyway
+		if err := infrastructure.EstablisTTLIndex(collection, "expires_at"); err != nil {
+			log.Println("Error", err)
+		}
+		store = infrastructure.NewMongoRevocationStore(collection)
+	}
+	return infrastructure.NewCachedRevocationStore(store, durationFromEnv("TOKEN_REVOCATION_CACHE_TTL", DEFAULT_REVOCATION_CACHE_TTL))
+}
@@ -2,22 +2,30 @@ package route
 
 import (
 	"log"
+	"os"
 	"strconv"
 	"time"
 
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/delivery/controllers"
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	repositorie "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/repositories"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func Run(port int, database mongo.Database, timeout time.Duration, router *gin.Engine, usercollection string, taskcollection string) {
 	public := router.Group("/api/v1")
 	private := router.Group("/api/v1")
-	private.Use(infrastructure.AuthMiddleWare("admin"))
-	public.Use(infrastructure.AuthMiddleWare("user", "admin"))
+	keyManager := loadKeyManager()
+	infrastructure.SetKeyManager(keyManager)
+	jwksController := controllers.NewJWKSController(keyManager)
+	router.GET("/.well-known/jwks.json", jwksController.GetJWKS)
+	revocations := newRevocationStore(database)
+	private.Use(infrastructure.AuthMiddleWareWithRevocations(revocations, "admin"))
+	public.Use(infrastructure.AuthMiddleWareWithRevocations(revocations, "user", "admin"))
 	open := router.Group("/api/v1")
 	CollectionUser := database.Collection(usercollection)
 	CollectionTask := database.Collection(taskcollection)
@@ -25,23 +33,280 @@ func Run(port int, database mongo.Database, timeout time.Duration, router *gin.E
 	if err != nil {
 		log.Println("Error", err)
 	}
+	err = infrastructure.EstablisTextIndex(CollectionTask, map[string]int{
+		"title":       infrastructure.TITLE_SEARCH_WEIGHT,
+		"description": infrastructure.DESCRIPTION_SEARCH_WEIGHT,
+	})
+	if err != nil {
+		log.Println("Error", err)
+	}
+	err = infrastructure.EstablisIndex(CollectionTask, "parentID")
+	if err != nil {
+		log.Println("Error", err)
+	}
+	err = infrastructure.EstablisIndex(CollectionTask, "blockedBy")
+	if err != nil {
+		log.Println("Error", err)
+	}
+
+	err = infrastructure.EstablisIndex(CollectionTask, "due_date")
+	if err != nil {
+		log.Println("Error", err)
+	}
+	// tags is an array so this is a multikey index
+	err = infrastructure.EstablisIndex(CollectionTask, "tags")
+	if err != nil {
+		log.Println("Error", err)
+	}
+	err = infrastructure.EstablisIndex(CollectionTask, "assignees")
+	if err != nil {
+		log.Println("Error", err)
+	}
+	err = infrastructure.EstablisIndex(CollectionTask, "completed_at")
+	if err != nil {
+		log.Println("Error", err)
+	}
+	for _, collection := range []*mongo.Collection{CollectionTask, CollectionUser} {
+		if err := infrastructure.EstablisIndex(collection, "deleted_at"); err != nil {
+			log.Println("Error", err)
+		}
+	}
 
 	taskRepository := repositorie.NewTaskRepository(CollectionTask)
-	taskUsecase := usecases.NewTaskUsecase(&taskRepository, time.Second*5)
+	workflow := loadWorkflow()
+	taskUsecase := usecases.NewTaskUsecaseWithWorkflow(&taskRepository, time.Second*5, workflow)
 	userRepository := repositorie.NewUserRepository(CollectionUser)
 	userUsecase := usecases.NewUserUsecase(&userRepository, time.Second*5)
 	controller := controllers.NewController(&taskUsecase, &userUsecase)
+	CollectionAudit := database.Collection(envOrDefault("DB_AUDIT_COLLECTION_NAME", "audit_log"))
+	for _, field := range []string{"actor", "target", "timestamp"} {
+		if err := infrastructure.EstablisIndex(CollectionAudit, field); err != nil {
+			log.Println("Error", err)
+		}
+	}
+	auditRepository := repositorie.NewAuditRepository(CollectionAudit)
+	auditUsecase := usecases.NewAuditUsecase(&auditRepository, time.Second*5)
+	auditController := controllers.NewAuditController(&auditUsecase)
+	taskUsecase.SetAuditLog(&auditUsecase)
+	CollectionRevision := database.Collection(envOrDefault("DB_REVISION_COLLECTION_NAME", "task_revisions"))
+	if err := infrastructure.EstablisIndex(CollectionRevision, "taskID"); err != nil {
+		log.Println("Error", err)
+	}
+	revisionRepository := repositorie.NewRevisionRepository(CollectionRevision)
+	taskUsecase.SetRevisionRepository(&revisionRepository)
+	userUsecase.SetAuditLog(&auditUsecase)
+	CollectionRefreshToken := database.Collection(envOrDefault("DB_REFRESH_TOKEN_COLLECTION_NAME", "refresh_tokens"))
+	if err := infrastructure.EstablisIndex(CollectionRefreshToken, "familyID"); err != nil {
+		log.Println("Error", err)
+	}
+	// expired tokens are removed by MongoDB
+	if err := infrastructure.EstablisTTLIndex(CollectionRefreshToken, "expires_at"); err != nil {
+		log.Println("Error", err)
+	}
+	refreshTokenRepository := repositorie.NewRefreshTokenRepository(CollectionRefreshToken)
+	userUsecase.SetRefreshTokenRepository(&refreshTokenRepository)
+	userUsecase.SetTokenRevocationStore(revocations)
+	// users logging in through the identity provider are found by their identity or their email
+	err = infrastructure.EstablisPartialUniqueIndex(CollectionUser, "oidc_subject", bson.D{{"oidc_subject", bson.D{{"$exists", true}}}})
+	if err != nil {
+		log.Println("Error", err)
+	}
+	err = infrastructure.EstablisPartialUniqueIndex(CollectionUser, "email", bson.D{{"email", bson.D{{"$exists", true}}}})
+	if err != nil {
+		log.Println("Error", err)
+	}
+	if identityProvider := newIdentityProvider(); identityProvider != nil {
+		userUsecase.SetIdentityProvider(identityProvider, os.Getenv("OIDC_ORG_ID"))
+	}
+	twoFactorPolicyRepository := repositorie.NewTwoFactorPolicyRepository(database.Collection(envOrDefault("DB_TWO_FACTOR_POLICY_COLLECTION_NAME", "two_factor_policies")))
+	userUsecase.SetTwoFactorPolicyRepository(&twoFactorPolicyRepository)
+	twoFactorController := controllers.NewTwoFactorController(&userUsecase)
+	CollectionComment := database.Collection(envOrDefault("DB_COMMENT_COLLECTION_NAME", "comments"))
+	for _, field := range []string{"taskID", "parentID"} {
+		if err := infrastructure.EstablisIndex(CollectionComment, field); err != nil {
+			log.Println("Error", err)
+		}
+	}
+	commentRepository := repositorie.NewCommentRepository(CollectionComment)
+	commentUsecase := usecases.NewCommentUsecase(&commentRepository, &taskRepository, time.Second*5)
+	commentController := controllers.NewCommentController(&commentUsecase, &userUsecase)
+	taskUsecase.AddDeleteListener(&commentUsecase)
+	blobStore, err := infrastructure.NewLocalBlobStore(envOrDefault("ATTACHMENT_DIR", "attachments"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentUsecase := usecases.NewAttachmentUsecase(&taskRepository, blobStore, time.Second*5)
+	attachmentController := controllers.NewAttachmentController(&attachmentUsecase, &userUsecase)
+	taskUsecase.AddDeleteListener(&attachmentUsecase)
+	CollectionTime := database.Collection(envOrDefault("DB_TIME_COLLECTION_NAME", "time_entries"))
+	for _, field := range []string{"taskID", "projectID", "start"} {
+		if err := infrastructure.EstablisIndex(CollectionTime, field); err != nil {
+			log.Println("Error", err)
+		}
+	}
+	// at most one running timer per user
+	err = infrastructure.EstablisPartialUniqueIndex(CollectionTime, "userID", bson.D{{"running", true}})
+	if err != nil {
+		log.Println("Error", err)
+	}
+	timeRepository := repositorie.NewTimeEntryRepository(CollectionTime)
+	timeUsecase := usecases.NewTimeUsecase(&timeRepository, &taskRepository, time.Second*5)
+	timeController := controllers.NewTimeController(&timeUsecase, &userUsecase)
+	taskUsecase.AddDeleteListener(&timeUsecase)
+	reminderUsecase := newReminderUsecase(database, CollectionTask, workflow)
+	reminderController := controllers.NewReminderController(reminderUsecase, &userUsecase)
+	trashController := controllers.NewTrashController(&taskUsecase, &userUsecase)
+	reportUsecase := usecases.NewReportUsecase(&taskRepository, time.Second*5, workflow)
+	reportController := controllers.NewReportController(&reportUsecase)
+	if err := infrastructure.EstablisIndex(CollectionTask, "projectID"); err != nil {
+		log.Println("Error", err)
+	}
+	CollectionProject := database.Collection(envOrDefault("DB_PROJECT_COLLECTION_NAME", "projects"))
+	if err := infrastructure.EstablisIndex(CollectionProject, "members.userID"); err != nil {
+		log.Println("Error", err)
+	}
+	projectRepository := repositorie.NewProjectRepository(CollectionProject)
+	projectUsecase := usecases.NewProjectUsecase(&projectRepository, &taskRepository, &userRepository, time.Second*5)
+	projectController := controllers.NewProjectController(&projectUsecase, &userUsecase)
 
 	private.POST("/task", controller.PostTask)
-	private.PUT("/task", controller.UpdateTask)
-	private.DELETE("/task/:id/:userid", controller.DeleteTask)
+	private.POST("/task/:id/dependencies", controller.PostTaskDependency)
+	private.DELETE("/task/:id/dependencies/:blockerid", controller.DeleteTaskDependency)
+	private.POST("/task/tags", controller.PostTaskTags)
+	private.PUT("/tags/:tag", controller.PutTag)
+	private.POST("/tags/merge", controller.PostTagMerge)
+	private.POST("/task/:id/revisions/:revision/revert", controller.PostTaskRevert)
+	// adds a user to the admin's organization, self-registration joins the default one
+	private.POST("/user", controller.PostUserRegister)
+	private.POST("/org", controller.PostOrganization)
 	private.POST("/user/assign", controller.PostUserAssign)
+	private.GET("/audit", auditController.GetAuditEntries)
+	private.GET("/trash", trashController.GetTrash)
+	private.POST("/trash/tasks/:id/restore", trashController.PostTaskRestore)
+	private.POST("/trash/users/:id/restore", trashController.PostUserRestore)
+	private.GET("/time/report", timeController.GetTimeReport)
+	private.GET("/user/2fa/policy", twoFactorController.GetTwoFactorPolicy)
+	private.PUT("/user/2fa/policy", twoFactorController.PutTwoFactorPolicy)
+	private.DELETE("/user/:id/2fa", twoFactorController.DeleteUserTwoFactor)
 
 	open.POST("/user/register", controller.PostUserRegister)
 	open.POST("/user/login", controller.PostUserLogin)
+	open.POST("/user/refresh", controller.PostUserRefresh)
+	open.POST("/user/logout", controller.PostUserLogout)
+	open.GET("/user/oidc/login", controller.GetOIDCLogin)
+	open.GET("/user/oidc/callback", controller.GetOIDCCallback)
+	open.POST("/user/login/2fa", twoFactorController.PostTwoFactorLogin)
+	open.POST("/user/login/2fa/enroll", twoFactorController.PostTwoFactorLoginEnroll)
 	public.GET("/task", controller.GetTasks)
+	public.GET("/task/search", controller.SearchTasks)
+	public.GET("/task/plan", controller.GetTaskPlan)
 	public.GET("/task/:id", controller.GetTaskByID)
+	public.GET("/task/:id/children", controller.GetChildTasks)
+	public.GET("/task/:id/tree", controller.GetTaskTree)
+	public.GET("/task/:id/occurrences", controller.GetTaskOccurrences)
+	public.GET("/task/:id/revisions", controller.GetTaskRevisions)
+	public.GET("/task/:id/revisions/diff", controller.GetTaskRevisionDiff)
+	// owners, assignees and admins may change a task, the usecase checks who the caller is
+	public.PUT("/task", controller.UpdateTask)
+	public.PATCH("/task/:id", controller.PatchTask)
+	public.DELETE("/task/:id", controller.DeleteTask)
+	public.POST("/task/:id/assignees", controller.PostTaskAssignee)
+	public.DELETE("/task/:id/assignees/:userid", controller.DeleteTaskAssignee)
+	public.POST("/task/:id/watchers", controller.PostTaskWatcher)
+	public.DELETE("/task/:id/watchers", controller.DeleteTaskWatcher)
+	public.GET("/me/tasks", controller.GetMyTasks)
+	public.GET("/workflow", controller.GetWorkflow)
+	public.GET("/tags", controller.GetTags)
+	public.GET("/task/:id/comments", commentController.GetComments)
+	public.POST("/task/:id/comments", commentController.PostComment)
+	public.PUT("/task/:id/comments/:commentid", commentController.PutComment)
+	public.DELETE("/task/:id/comments/:commentid", commentController.DeleteComment)
+	public.GET("/task/:id/attachments", attachmentController.GetAttachments)
+	public.POST("/task/:id/attachments", attachmentController.PostAttachment)
+	public.GET("/task/:id/attachments/:attachmentid", attachmentController.GetAttachment)
+	public.DELETE("/task/:id/attachments/:attachmentid", attachmentController.DeleteAttachment)
+	public.GET("/task/:id/time", timeController.GetTaskTime)
+	public.POST("/task/:id/time", timeController.PostTimeEntry)
+	public.POST("/task/:id/timer", timeController.PostTimer)
+	public.GET("/timer", timeController.GetTimer)
+	public.POST("/timer/stop", timeController.PostTimerStop)
+	public.GET("/me/time", timeController.GetMyTime)
+	public.GET("/reports/burndown", reportController.GetBurndown)
+	public.GET("/reports/velocity", reportController.GetVelocity)
+	public.GET("/user/reminders", reminderController.GetReminderPreference)
+	public.PUT("/user/reminders", reminderController.PutReminderPreference)
+	public.GET("/user/2fa", twoFactorController.GetTwoFactor)
+	public.POST("/user/2fa/enroll", twoFactorController.PostTwoFactorEnroll)
+	public.POST("/user/2fa/confirm", twoFactorController.PostTwoFactorConfirm)
+	public.POST("/user/2fa/disable", twoFactorController.PostTwoFactorDisable)
+	public.POST("/user/2fa/recovery-codes", twoFactorController.PostRecoveryCodes)
+
+	public.GET("/projects", projectController.GetProjects)
+	public.POST("/projects", projectController.PostProject)
+	public.GET("/projects/:pid", projectController.GetProject)
+	public.PUT("/projects/:pid", projectController.PutProject)
+	public.DELETE("/projects/:pid", projectController.DeleteProject)
+	public.PUT("/projects/:pid/members/:userid", projectController.PutProjectMember)
+	public.DELETE("/projects/:pid/members/:userid", projectController.DeleteProjectMember)
+
+	// the task endpoints again, scoped to one project and open to its members by their project role
+	projectViewer := public.Group("/projects/:pid/tasks", projectController.RequireProjectRole(domain.PROJECT_ROLE_VIEWER))
+	projectEditor := public.Group("/projects/:pid/tasks", projectController.RequireProjectRole(domain.PROJECT_ROLE_EDITOR))
+	projectViewer.GET("", controller.GetTasks)
+	projectViewer.GET("/search", controller.SearchTasks)
+	projectViewer.GET("/plan", controller.GetTaskPlan)
+	projectViewer.GET("/tags", controller.GetTags)
+	projectViewer.GET("/:id", controller.GetTaskByID)
+	projectViewer.GET("/:id/children", controller.GetChildTasks)
+	projectViewer.GET("/:id/tree", controller.GetTaskTree)
+	projectViewer.GET("/:id/occurrences", controller.GetTaskOccurrences)
+	projectViewer.GET("/:id/revisions", controller.GetTaskRevisions)
+	projectViewer.GET("/:id/revisions/diff", controller.GetTaskRevisionDiff)
+	projectViewer.GET("/:id/comments", commentController.GetComments)
+	projectViewer.GET("/:id/attachments", attachmentController.GetAttachments)
+	projectViewer.GET("/:id/attachments/:attachmentid", attachmentController.GetAttachment)
+	projectViewer.GET("/:id/time", timeController.GetTaskTime)
+	projectViewer.POST("/:id/watchers", controller.PostTaskWatcher)
+	projectViewer.DELETE("/:id/watchers", controller.DeleteTaskWatcher)
+	projectEditor.POST("", controller.PostTask)
+	projectEditor.PUT("/:id", controller.UpdateTask)
+	projectEditor.PATCH("/:id", controller.PatchTask)
+	projectEditor.DELETE("/:id", controller.DeleteTask)
+	projectEditor.POST("/tags", controller.PostTaskTags)
+	projectEditor.POST("/:id/dependencies", controller.PostTaskDependency)
+	projectEditor.DELETE("/:id/dependencies/:blockerid", controller.DeleteTaskDependency)
+	projectEditor.POST("/:id/revisions/:revision/revert", controller.PostTaskRevert)
+	projectEditor.POST("/:id/comments", commentController.PostComment)
+	projectEditor.PUT("/:id/comments/:commentid", commentController.PutComment)
+	projectEditor.DELETE("/:id/comments/:commentid", commentController.DeleteComment)
+	projectEditor.POST("/:id/attachments", attachmentController.PostAttachment)
+	projectEditor.DELETE("/:id/attachments/:attachmentid", attachmentController.DeleteAttachment)
+	projectEditor.POST("/:id/assignees", controller.PostTaskAssignee)
+	projectEditor.DELETE("/:id/assignees/:userid", controller.DeleteTaskAssignee)
+	projectEditor.POST("/:id/time", timeController.PostTimeEntry)
+	projectEditor.POST("/:id/timer", timeController.PostTimer)
+
+	projectReports := public.Group("/projects/:pid/reports", projectController.RequireProjectRole(domain.PROJECT_ROLE_VIEWER))
+	projectReports.GET("/burndown", reportController.GetBurndown)
+	projectReports.GET("/velocity", reportController.GetVelocity)
 
 	router.Run("localhost:" + strconv.Itoa(port))
 	log.Println("Server is running on port:", port)
 }
+
+// the workflow in TASK_WORKFLOW_FILE, or the default one when it is unset or invalid
+func loadWorkflow() domain.Workflow {
+	workflow := usecases.DefaultWorkflow()
+	if workflowFile := os.Getenv("TASK_WORKFLOW_FILE"); workflowFile != "" {
+		loaded, err := infrastructure.LoadWorkflow(workflowFile)
+		if err == nil {
+			err = usecases.ValidateWorkflow(loaded)
+		}
+		if err != nil {
+			log.Println("Error", err, "- falling back to the default workflow")
+		} else {
+			workflow = loaded
+		}
+	}
+	return workflow
+}
@@ -0,0 +1,61 @@
+package route
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	repositorie "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/repositories"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/usecases"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	DEFAULT_TRASH_RETENTION      = 30 * 24 * time.Hour
+	DEFAULT_TRASH_PURGE_INTERVAL = time.Hour
+)
+
+// builds the purger that empties the trash of records older than TRASH_RETENTION, it runs every
+// TRASH_PURGE_INTERVAL. the task use case gets the same cleanup as the one serving the api so that
+// revisions, comments and attachments go together with the task.
+func NewTrashPurger(database mongo.Database, usercollection string, taskcollection string) *infrastructure.TrashPurger {
+	taskRepository := repositorie.NewTaskRepository(database.Collection(taskcollection))
+	taskUsecase := usecases.NewTaskUsecaseWithWorkflow(&taskRepository, time.Second*30, loadWorkflow())
+	userRepository := repositorie.NewUserRepository(database.Collection(usercollection))
+	userUsecase := usecases.NewUserUsecase(&userRepository, time.Second*30)
+
+	auditRepository := repositorie.NewAuditRepository(database.Collection(envOrDefault("DB_AUDIT_COLLECTION_NAME", "audit_log")))
+	auditUsecase := usecases.NewAuditUsecase(&auditRepository, time.Second*5)
+	taskUsecase.SetAuditLog(&auditUsecase)
+	userUsecase.SetAuditLog(&auditUsecase)
+	revisionRepository := repositorie.NewRevisionRepository(database.Collection(envOrDefault("DB_REVISION_COLLECTION_NAME", "task_revisions")))
+	taskUsecase.SetRevisionRepository(&revisionRepository)
+	commentRepository := repositorie.NewCommentRepository(database.Collection(envOrDefault("DB_COMMENT_COLLECTION_NAME", "comments")))
+	commentUsecase := usecases.NewCommentUsecase(&commentRepository, &taskRepository, time.Second*5)
+	taskUsecase.AddDeleteListener(&commentUsecase)
+	blobStore, err := infrastructure.NewLocalBlobStore(envOrDefault("ATTACHMENT_DIR", "attachments"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentUsecase := usecases.NewAttachmentUsecase(&taskRepository, blobStore, time.Second*5)
+	taskUsecase.AddDeleteListener(&attachmentUsecase)
+
+	retention := durationFromEnv("TRASH_RETENTION", DEFAULT_TRASH_RETENTION)
+	interval := durationFromEnv("TRASH_PURGE_INTERVAL", DEFAULT_TRASH_PURGE_INTERVAL)
+	return infrastructure.NewTrashPurger(&taskUsecase, &userUsecase, retention, interval)
+}
+
+// a positive duration such as 720h from the environment, fallback when it is unset or invalid
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil || parsed <= 0 {
+		log.Println("Error", "invalid", key, raw, "- using", fallback)
+		return fallback
+	}
+	return parsed
+}
@@ -2,20 +2,447 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
 // task struc
 type Task struct {
-	ID          string    `json:"id,omitempty" bson:"_id,omitempty"`
-	UserID      string    `json:"userID" bson:"userID" validate:"required"`
-	Title       string    `json:"title" bson:"title"  validate:"required"`
-	Description string    `json:"description,omitempty" bson:"description,omitempty"`
-	Status      string    `json:"status,omitempty" bson:"status,omitempty"`
-	Priority    string    `json:"priority,omitempty" bson:"priority,omitempty"`
-	DueDate     time.Time `json:"due_date,omitempty" bson:"due_date,omitempty"`
-	CreatedAt   time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	ID     string `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID string `json:"userID" bson:"userID" validate:"required"`
+	// the organization the task belongs to, set from the token of the request that created it
+	OrgID     string `json:"orgID,omitempty" bson:"orgID,omitempty"`
+	ProjectID string `json:"projectID,omitempty" bson:"projectID,omitempty"`
+	// besides the owner in UserID, assignees may edit and delete the task
+	Assignees   []string     `json:"assignees,omitempty" bson:"assignees,omitempty"`
+	Watchers    []string     `json:"watchers,omitempty" bson:"watchers,omitempty"`
+	ParentID    string       `json:"parentID,omitempty" bson:"parentID,omitempty"`
+	BlockedBy   []string     `json:"blockedBy,omitempty" bson:"blockedBy,omitempty"`
+	Tags        []string     `json:"tags,omitempty" bson:"tags,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Blocked     bool         `json:"blocked,omitempty" bson:"-"`
+	Title       string       `json:"title" bson:"title"  validate:"required"`
+	Description string       `json:"description,omitempty" bson:"description,omitempty"`
+	Status      string       `json:"status,omitempty" bson:"status,omitempty"`
+	Priority    string       `json:"priority,omitempty" bson:"priority,omitempty"`
+	DueDate     time.Time    `json:"due_date,omitempty" bson:"due_date,omitempty"`
+	// the size of the task in EstimateUnit, story points unless another unit is given
+	Estimate     float64 `json:"estimate,omitempty" bson:"estimate,omitempty"`
+	EstimateUnit string  `json:"estimate_unit,omitempty" bson:"estimate_unit,omitempty"`
+	// set when the task enters a final status of the workflow and cleared when it leaves them
+	CompletedAt time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	// RFC 5545 RRULE, the series starts at RecurrenceStart
+	Recurrence       string    `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+	RecurrenceStart  time.Time `json:"recurrence_start,omitempty" bson:"recurrence_start,omitempty"`
+	NextOccurrenceID string    `json:"nextOccurrenceID,omitempty" bson:"nextOccurrenceID,omitempty"`
+	// incremented by every update, the matching snapshot is kept as a TaskRevision
+	Revision int `json:"revision,omitempty" bson:"revision,omitempty"`
+	// incremented by every write, updates and deletes that name a version only apply to that version
+	Version   int       `json:"version,omitempty" bson:"version,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	// set while the task is in the trash, such tasks are hidden from every query but the trash
+	DeletedAt time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+// units of task estimates
+const (
+	ESTIMATE_POINTS = "points"
+	ESTIMATE_HOURS  = "hours"
+)
+
+// task with its subtasks, percent complete is rolled up from the leaves
+type TaskNode struct {
+	Task            Task       `json:"task"`
+	PercentComplete float64    `json:"percent_complete"`
+	Children        []TaskNode `json:"children"`
+}
+
+// task query structs
+
+type TaskQuery struct {
+	Status    string
+	Priority  string
+	UserID    string
+	ProjectID string
+	// tasks the user is one of the assignees of
+	AssigneeID string
+	DueAfter   time.Time
+	DueBefore  time.Time
+	// tasks with at least one of AnyTags and every one of AllTags
+	AnyTags   []string
+	AllTags   []string
+	SortBy    string
+	SortOrder int
+	Limit     int
+	Cursor    string
+}
+
+type TaskPage struct {
+	Tasks      []Task `json:"tasks"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Limit      int    `json:"limit"`
+}
+
+type TaskSearchResult struct {
+	Task       Task              `json:"task"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// tag structs
+
+type TagCount struct {
+	Tag   string `json:"tag" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
+
+// tags added to and removed from a set of tasks at once
+type TagUpdate struct {
+	TaskIDs []string `json:"taskIDs"`
+	Add     []string `json:"add"`
+	Remove  []string `json:"remove"`
+}
+
+// report structs
+
+// the days of reports are dates in UTC
+const REPORT_DAY_FORMAT = "2006-01-02"
+
+// filters for the burndown and velocity reports, only tasks estimated in Unit are counted.
+// the reports cover the days from From (inclusive) to To (exclusive).
+type ReportQuery struct {
+	ProjectID  string
+	Tag        string
+	AssigneeID string
+	Unit       string
+	From       time.Time
+	To         time.Time
+	// the length and number of the iterations of the velocity report, which end at To
+	IterationDays int
+	Iterations    int
+}
+
+// the estimates at the end of a day: still open, completed since the start of the burndown,
+// and of every task created by then. Ideal falls in a straight line to zero on the last day.
+type BurndownPoint struct {
+	Date      string  `json:"date"`
+	Remaining float64 `json:"remaining"`
+	Completed float64 `json:"completed"`
+	Scope     float64 `json:"scope"`
+	Ideal     float64 `json:"ideal"`
+}
+
+type Burndown struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Unit   string          `json:"unit"`
+	Series []BurndownPoint `json:"series"`
+}
+
+// the estimates of the tasks completed from Start to End, both inclusive
+type VelocityIteration struct {
+	Start     string  `json:"start"`
+	End       string  `json:"end"`
+	Completed float64 `json:"completed"`
+	Tasks     int     `json:"tasks"`
+}
+
+type Velocity struct {
+	Unit          string              `json:"unit"`
+	IterationDays int                 `json:"iteration_days"`
+	Iterations    []VelocityIteration `json:"iterations"`
+	Average       float64             `json:"average"`
+}
+
+// workflow structs
+
+type WorkflowTransition struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+type Workflow struct {
+	InitialStatus string               `json:"initial_status"`
+	FinalStatuses []string             `json:"final_statuses"`
+	Statuses      []string             `json:"statuses"`
+	Transitions   []WorkflowTransition `json:"transitions"`
+}
+
+// project structs
+
+// roles of project members, each role may do everything the ones before it may
+const (
+	PROJECT_ROLE_VIEWER = "viewer"
+	PROJECT_ROLE_EDITOR = "editor"
+	PROJECT_ROLE_OWNER  = "owner"
+)
+
+type ProjectMember struct {
+	UserID string `json:"userID" bson:"userID"`
+	Role   string `json:"role" bson:"role"`
+}
+
+// a group of tasks that only the members of the project can reach, the owner is always a member
+type Project struct {
+	ID          string          `json:"id,omitempty" bson:"_id,omitempty"`
+	Name        string          `json:"name" bson:"name"`
+	Description string          `json:"description,omitempty" bson:"description,omitempty"`
+	OwnerID     string          `json:"ownerID" bson:"ownerID"`
+	OrgID       string          `json:"orgID,omitempty" bson:"orgID,omitempty"`
+	Members     []ProjectMember `json:"members" bson:"members"`
+	CreatedAt   time.Time       `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// reminder structs
+
+// how and when a user is reminded about their tasks
+type ReminderPreference struct {
+	UserID      string `json:"userID" bson:"_id"`
+	Enabled     bool   `json:"enabled" bson:"enabled"`
+	LeadMinutes int    `json:"lead_minutes" bson:"lead_minutes"`
+	Overdue     bool   `json:"overdue" bson:"overdue"`
+	Email       string `json:"email,omitempty" bson:"email,omitempty"`
+}
+
+// kinds of reminder events
+const (
+	REMINDER_DUE_SOON = "due_soon"
+	REMINDER_OVERDUE  = "overdue"
+)
+
+// a reminder event, the ID identifies the task, kind and due date so it is sent once
+type Reminder struct {
+	ID      string    `json:"id" bson:"_id"`
+	TaskID  string    `json:"taskID" bson:"taskID"`
+	UserID  string    `json:"userID" bson:"userID"`
+	Kind    string    `json:"kind" bson:"kind"`
+	Title   string    `json:"title" bson:"title"`
+	DueDate time.Time `json:"due_date" bson:"due_date"`
+	Email   string    `json:"email,omitempty" bson:"email,omitempty"`
+	SentAt  time.Time `json:"sent_at" bson:"sent_at"`
+}
+
+// delivers reminder events
+type Notifier interface {
+	Notify(cxt context.Context, reminder Reminder) error
+}
+
+// patch structs
+
+// media types of the accepted partial task updates
+const (
+	PATCH_MERGE = "application/merge-patch+json"
+	PATCH_JSON  = "application/json-patch+json"
+)
+
+// a partial update of a task, Patch is a document in the Format media type
+type TaskPatch struct {
+	Format string
+	Patch  []byte
+	// the version the task is expected to be at, 0 skips the check
+	Version int
+}
+
+// revision structs
+
+// how a task revision came about
+const (
+	REVISION_CREATE = "create"
+	REVISION_UPDATE = "update"
+	REVISION_REVERT = "revert"
+)
+
+// the task as it was after one of its changes, numbered from 1 on creation
+type TaskRevision struct {
+	ID     string `json:"-" bson:"_id"`
+	TaskID string `json:"taskID" bson:"taskID"`
+	Number int    `json:"revision" bson:"revision"`
+	Action string `json:"action" bson:"action"`
+	// the revision whose content a revert restored
+	RevertedFrom int       `json:"reverted_from,omitempty" bson:"reverted_from,omitempty"`
+	Author       string    `json:"author,omitempty" bson:"author,omitempty"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	Task         Task      `json:"task" bson:"task"`
+	OrgID        string    `json:"-" bson:"orgID,omitempty"`
+}
+
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// attachment structs
+
+// metadata of a file attached to a task, the content itself is kept in the blob store
+type Attachment struct {
+	ID          string    `json:"id" bson:"id"`
+	Filename    string    `json:"filename" bson:"filename"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	SHA256      string    `json:"sha256" bson:"sha256"`
+	UploadedBy  string    `json:"uploadedBy" bson:"uploadedBy"`
+	UploadedAt  time.Time `json:"uploaded_at" bson:"uploaded_at"`
+}
+
+type AttachmentUpload struct {
+	Filename   string
+	UploadedBy string
+	Content    io.Reader
+}
+
+// comment structs
+
+type Comment struct {
+	ID       string `json:"id,omitempty" bson:"_id,omitempty"`
+	TaskID   string `json:"taskID" bson:"taskID"`
+	ParentID string `json:"parentID,omitempty" bson:"parentID,omitempty"`
+	AuthorID string `json:"authorID" bson:"authorID"`
+	Body     string `json:"body" bson:"body"`
+	// earlier bodies of an edited comment, oldest first
+	History []CommentRevision `json:"history,omitempty" bson:"history,omitempty"`
+	// a deleted comment that still has replies is kept with an empty body
+	Deleted   bool      `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	Archived  bool      `json:"-" bson:"archived,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	Replies   []Comment `json:"replies,omitempty" bson:"-"`
+}
+
+type CommentRevision struct {
+	Body     string    `json:"body" bson:"body"`
+	EditedAt time.Time `json:"edited_at" bson:"edited_at"`
+}
+
+// time tracking structs
+
+// time a user spent on a task, either tracked with a timer or entered by hand.
+// End is unset while the timer is running, a user runs at most one timer at a time.
+type TimeEntry struct {
+	ID     string `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID string `json:"userID" bson:"userID"`
+	TaskID string `json:"taskID" bson:"taskID"`
+	// copied from the task so the report can group by project
+	ProjectID string    `json:"projectID,omitempty" bson:"projectID,omitempty"`
+	OrgID     string    `json:"orgID,omitempty" bson:"orgID,omitempty"`
+	Start     time.Time `json:"start" bson:"start"`
+	End       time.Time `json:"end,omitempty" bson:"end,omitempty"`
+	Note      string    `json:"note,omitempty" bson:"note,omitempty"`
+	Running   bool      `json:"running,omitempty" bson:"running,omitempty"`
+	Manual    bool      `json:"manual,omitempty" bson:"manual,omitempty"`
+	// the length of a stopped entry, running entries have none yet
+	Seconds   int64     `json:"seconds" bson:"-"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// filters for time entries, zero values match everything.
+// entries are matched by their start from From (inclusive) to To (exclusive).
+type TimeQuery struct {
+	TaskID    string
+	UserID    string
+	ProjectID string
+	From      time.Time
+	To        time.Time
+	// the IANA time zone the report splits days in, UTC when empty
+	TimeZone string
+}
+
+// the entries matching a query and the seconds of the stopped ones
+type TimeSummary struct {
+	Entries []TimeEntry `json:"entries"`
+	Seconds int64       `json:"seconds"`
+}
+
+// the time one user spent on one project in one day, entries of tasks outside projects have no project
+type TimeReportRow struct {
+	ProjectID string `json:"projectID" bson:"projectID"`
+	UserID    string `json:"userID" bson:"userID"`
+	Day       string `json:"day" bson:"day"`
+	Seconds   int64  `json:"seconds" bson:"seconds"`
+}
+
+type TimeReport struct {
+	From     time.Time       `json:"from,omitempty"`
+	To       time.Time       `json:"to,omitempty"`
+	TimeZone string          `json:"time_zone"`
+	Rows     []TimeReportRow `json:"rows"`
+	Seconds  int64           `json:"seconds"`
+}
+
+// cleans up what belongs to a task once the task has been purged from the trash
+type TaskDeleteListener interface {
+	TaskDeleted(cxt context.Context, task Task) *TaskError
+}
+
+// audit structs
+
+// audited actions
+const (
+	AUDIT_TASK_CREATE       = "task.create"
+	AUDIT_TASK_UPDATE       = "task.update"
+	AUDIT_TASK_DELETE       = "task.delete"
+	AUDIT_TASK_REVERT       = "task.revert"
+	AUDIT_TASK_RESTORE      = "task.restore"
+	AUDIT_TASK_PURGE        = "task.purge"
+	AUDIT_USER_CREATE       = "user.create"
+	AUDIT_USER_UPDATE       = "user.update"
+	AUDIT_USER_DELETE       = "user.delete"
+	AUDIT_USER_RESTORE      = "user.restore"
+	AUDIT_USER_PURGE        = "user.purge"
+	AUDIT_USER_LOGIN        = "user.login"
+	AUDIT_USER_LOGIN_FAILED = "user.login_failed"
+	AUDIT_USER_LOGOUT       = "user.logout"
+	// a refresh token was presented again after it had been rotated
+	AUDIT_USER_TOKEN_REUSE = "user.token_reuse"
+	// an existing user logged in through the identity provider for the first time
+	AUDIT_USER_LINK        = "user.link"
+	AUDIT_USER_2FA_ENABLE  = "user.2fa_enable"
+	AUDIT_USER_2FA_DISABLE = "user.2fa_disable"
+	// a login completed with a recovery code instead of a one-time password
+	AUDIT_USER_2FA_RECOVERY       = "user.2fa_recovery"
+	AUDIT_USER_2FA_RECOVERY_CODES = "user.2fa_recovery_codes"
+	AUDIT_TWO_FACTOR_POLICY       = "two_factor_policy.update"
+)
+
+// kinds of audit targets
+const (
+	AUDIT_TARGET_TASK = "task"
+	AUDIT_TARGET_USER = "user"
+	// the target ID is the organization of the policy
+	AUDIT_TARGET_TWO_FACTOR_POLICY = "two_factor_policy"
+)
+
+// a change made to a task or user, entries are only ever appended to the audit log
+type AuditEntry struct {
+	ID         string        `json:"id,omitempty" bson:"_id,omitempty"`
+	Actor      string        `json:"actor" bson:"actor"`
+	Action     string        `json:"action" bson:"action"`
+	TargetType string        `json:"target_type" bson:"target_type"`
+	TargetID   string        `json:"target" bson:"target"`
+	Timestamp  time.Time     `json:"timestamp" bson:"timestamp"`
+	Changes    []FieldChange `json:"changes,omitempty" bson:"changes,omitempty"`
+	OrgID      string        `json:"orgID,omitempty" bson:"orgID,omitempty"`
+}
+
+// a field whose value differs between two versions of a record, a missing side means the field was unset
+type FieldChange struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// filters for the audit log, zero values match everything.
+// entries are returned newest first from Since (inclusive) to Until (exclusive).
+type AuditQuery struct {
+	Actor      string
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
 }
 
 // user structs
@@ -25,6 +452,100 @@ type User struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	Role     string `json:"role,omitempty"`
+	// the organization the user belongs to, users only ever see the users and tasks of their own
+	OrgID string `json:"orgID,omitempty" bson:"orgID,omitempty"`
+	// set while the user is in the trash, such users cannot log in
+	DeletedAt time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// only set by admins or by the identity provider, accounts are linked to the provider by it
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+	// the account at the identity provider the user logs in with, never taken from a request
+	IdentityIssuer  string `json:"-" bson:"oidc_issuer,omitempty"`
+	IdentitySubject string `json:"-" bson:"oidc_subject,omitempty"`
+	// the second factor, nil until the user enrolls
+	TwoFactor *TwoFactor `json:"-" bson:"two_factor,omitempty"`
+}
+
+// a user's time-based one-time password. the secret and the recovery codes never leave the server
+// once the enrollment is confirmed.
+type TwoFactor struct {
+	// base32, as in the otpauth URI
+	Secret string `bson:"secret"`
+	// false while the enrollment waits for its first code
+	Enabled bool `bson:"enabled"`
+	// the time step of the last accepted code, each code is only accepted once
+	LastStep int64 `bson:"last_step"`
+	// the SHA-256 hashes of the recovery codes that have not been used
+	RecoveryCodes []string `bson:"recovery_codes,omitempty"`
+	// wrong codes since the last accepted one, too many lock the second factor for a while
+	Failures int       `bson:"failures"`
+	FailedAt time.Time `bson:"failed_at,omitempty"`
+}
+
+// what an authenticator app needs to generate the user's codes
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled bool `json:"enabled"`
+	// the policy of the organization requires a second factor for the user's role
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// the roles of an organization whose users need a second factor to log in
+type TwoFactorPolicy struct {
+	OrgID         string   `json:"-" bson:"_id"`
+	RequiredRoles []string `json:"required_roles" bson:"required_roles"`
+}
+
+// a user as vouched for by the ID token of an OpenID Connect provider
+type ExternalIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	// the preferred_username claim, new users are named after it
+	Username string
+}
+
+// a login started at the identity provider. the client keeps it until the provider redirects back,
+// only URL is meant for the provider.
+type OIDCLogin struct {
+	URL          string `json:"-"`
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// the tokens handed out on login and on every refresh. the access token is a short lived JWT,
+// the refresh token an opaque string that can be exchanged once for a new session.
+type Session struct {
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// seconds until the access token expires
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+	// handed out instead of the tokens while the login waits for the second factor
+	TwoFactorToken string `json:"two_factor_token,omitempty"`
+	// the user has to enroll a second factor before the login can complete
+	TwoFactorEnrollmentRequired bool `json:"two_factor_enrollment_required,omitempty"`
+	// only handed out by the login that confirms an enrollment
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// a refresh token as stored on the server, only the hash of the token itself is kept.
+// the tokens rotated from the same login form a family that is revoked as a whole.
+type RefreshToken struct {
+	ID        string    `bson:"_id"`
+	FamilyID  string    `bson:"familyID"`
+	UserID    string    `bson:"userID"`
+	OrgID     string    `bson:"orgID,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	// set once the token has been exchanged, presenting it again revokes the family
+	UsedAt    time.Time `bson:"used_at,omitempty"`
+	RevokedAt time.Time `bson:"revoked_at,omitempty"`
 }
 
 // error structs
@@ -50,19 +571,191 @@ func (taskerr *TaskError) Error() string {
 // task repository struct
 type TaskRepository interface {
 	FetchAllTasks(cxt context.Context) ([]Task, *TaskError)
+	FetchTasks(cxt context.Context, query TaskQuery) (TaskPage, *TaskError)
+	SearchTasks(cxt context.Context, query string, limit int) ([]TaskSearchResult, *TaskError)
+	FetchChildTasks(cxt context.Context, parentID string) ([]Task, *TaskError)
+	FetchSubtree(cxt context.Context, rootID string) ([]Task, *TaskError)
+	FetchTasksByIDs(cxt context.Context, IDs []string) ([]Task, *TaskError)
+	// the blockedBy lists of the tasks across the organization, whatever project or caller
+	FetchDependencies(cxt context.Context, IDs []string) ([]Task, *TaskError)
+	AddDependency(cxt context.Context, taskID string, blockerID string) (Task, *TaskError)
+	RemoveDependency(cxt context.Context, taskID string, blockerID string) (Task, *TaskError)
+	AddAssignee(cxt context.Context, taskID string, userID string) (Task, *TaskError)
+	RemoveAssignee(cxt context.Context, taskID string, userID string) (Task, *TaskError)
+	AddWatcher(cxt context.Context, taskID string, userID string) (Task, *TaskError)
+	RemoveWatcher(cxt context.Context, taskID string, userID string) (Task, *TaskError)
+	UnlinkDependents(cxt context.Context, blockerID string) *TaskError
 	FetchTaskByID(cxt context.Context, ID string) (Task, *TaskError)
 	CreateTask(cxt context.Context, newTask Task) (string, *TaskError)
 	UpdateTask(cxt context.Context, updateTask Task) (Task, *TaskError)
-	DeleteTask(cxt context.Context, taskID string) (Task, *TaskError)
+	LinkNextOccurrence(cxt context.Context, taskID string, nextID string) (Task, *TaskError)
+	DeleteTask(cxt context.Context, taskID string, expectedVersion int) (Task, *TaskError)
+	FetchDueTasks(cxt context.Context, dueAfter time.Time, dueBefore time.Time, excludeStatuses []string) ([]Task, *TaskError)
+	FetchTagCounts(cxt context.Context) ([]TagCount, *TaskError)
+	UpdateTaskTags(cxt context.Context, taskIDs []string, add []string, remove []string) (int64, *TaskError)
+	ReplaceTags(cxt context.Context, from []string, to string) (int64, *TaskError)
+	RestoreTask(cxt context.Context, task Task) (Task, *TaskError)
+	PatchTask(cxt context.Context, task Task, fields []string) (Task, *TaskError)
+	FetchDeletedTasks(cxt context.Context, deletedBefore time.Time, limit int) ([]Task, *TaskError)
+	UndeleteTask(cxt context.Context, ID string) (Task, *TaskError)
+	PurgeTask(cxt context.Context, ID string, deletedBefore time.Time) *TaskError
+	AddAttachment(cxt context.Context, taskID string, attachment Attachment) (Task, *TaskError)
+	RemoveAttachment(cxt context.Context, taskID string, attachmentID string) (Task, *TaskError)
+	FetchEstimatedTasks(cxt context.Context, query ReportQuery, finalStatuses []string) ([]Task, *TaskError)
+}
+
+// project repository interface
+type ProjectRepository interface {
+	FetchProjects(cxt context.Context, memberID string) ([]Project, *TaskError)
+	FetchProjectByID(cxt context.Context, ID string) (Project, *TaskError)
+	CreateProject(cxt context.Context, project Project) (string, *TaskError)
+	UpdateProject(cxt context.Context, project Project) (Project, *TaskError)
+	SetMember(cxt context.Context, projectID string, member ProjectMember) (Project, *TaskError)
+	RemoveMember(cxt context.Context, projectID string, userID string) (Project, *TaskError)
+	DeleteProject(cxt context.Context, ID string) *TaskError
+}
+
+// project use case interface, every method acts on behalf of the given user
+type ProjectUsecase interface {
+	GetProjects(cxt context.Context, user User) ([]Project, *TaskError)
+	GetProject(cxt context.Context, projectID string, user User) (Project, *TaskError)
+	CreateProject(cxt context.Context, project Project, owner User) (Project, *TaskError)
+	UpdateProject(cxt context.Context, project Project, user User) (Project, *TaskError)
+	DeleteProject(cxt context.Context, projectID string, user User) *TaskError
+	SetMember(cxt context.Context, projectID string, member ProjectMember, user User) (Project, *TaskError)
+	RemoveMember(cxt context.Context, projectID string, memberID string, user User) (Project, *TaskError)
+	Authorize(cxt context.Context, projectID string, user User, role string) (Project, *TaskError)
+}
+
+// reminder repository interface
+type ReminderRepository interface {
+	FetchPreferences(cxt context.Context, userIDs []string) ([]ReminderPreference, *TaskError)
+	UpsertPreference(cxt context.Context, preference ReminderPreference) (ReminderPreference, *TaskError)
+	MarkReminderSent(cxt context.Context, reminder Reminder) (bool, *TaskError)
+	UnmarkReminder(cxt context.Context, reminderID string) *TaskError
+}
+
+// reminder use case interface
+type ReminderUsecase interface {
+	GetPreference(cxt context.Context, userID string) (ReminderPreference, *TaskError)
+	UpdatePreference(cxt context.Context, preference ReminderPreference) (ReminderPreference, *TaskError)
+	ScanReminders(cxt context.Context, now time.Time) ([]Reminder, *TaskError)
 }
 
 // task use case interface
 type TaskUsecase interface {
 	GetAllTasks(cxt context.Context) ([]Task, *TaskError)
+	GetTasks(cxt context.Context, query TaskQuery) (TaskPage, *TaskError)
+	SearchTasks(cxt context.Context, query string, limit int) ([]TaskSearchResult, *TaskError)
 	GetTaskByID(cxt context.Context, taskID string) (Task, *TaskError)
+	GetChildTasks(cxt context.Context, taskID string) ([]Task, *TaskError)
+	GetTaskTree(cxt context.Context, taskID string) (TaskNode, *TaskError)
+	AddDependency(cxt context.Context, taskID string, blockerID string) (Task, *TaskError)
+	RemoveDependency(cxt context.Context, taskID string, blockerID string) (Task, *TaskError)
+	PlanTasks(cxt context.Context, taskIDs []string) ([]Task, *TaskError)
 	CreateTask(cxt context.Context, newTask Task) (string, *TaskError)
-	UpdateTask(cxt context.Context, updateTask Task) (Task, *TaskError)
-	DeleteTask(cxt context.Context, taskID string, authorityID string) (Task, *TaskError)
+	UpdateTask(cxt context.Context, updateTask Task, authority User) (Task, *TaskError)
+	PatchTask(cxt context.Context, taskID string, patch TaskPatch, authority User) (Task, *TaskError)
+	DeleteTask(cxt context.Context, taskID string, authority User, expectedVersion int) (Task, *TaskError)
+	AssignTask(cxt context.Context, taskID string, assigneeID string, authority User) (Task, *TaskError)
+	UnassignTask(cxt context.Context, taskID string, assigneeID string, authority User) (Task, *TaskError)
+	WatchTask(cxt context.Context, taskID string, watcherID string) (Task, *TaskError)
+	UnwatchTask(cxt context.Context, taskID string, watcherID string) (Task, *TaskError)
+	GetWorkflow() Workflow
+	NextStatuses(cxt context.Context, status string) ([]string, *TaskError)
+	PreviewOccurrences(cxt context.Context, taskID string, count int) ([]time.Time, *TaskError)
+	GetTags(cxt context.Context) ([]TagCount, *TaskError)
+	UpdateTaskTags(cxt context.Context, update TagUpdate) (int64, *TaskError)
+	RenameTag(cxt context.Context, tag string, newName string) (int64, *TaskError)
+	MergeTags(cxt context.Context, tags []string, into string) (int64, *TaskError)
+	GetRevisions(cxt context.Context, taskID string) ([]TaskRevision, *TaskError)
+	DiffRevisions(cxt context.Context, taskID string, from int, to int) (RevisionDiff, *TaskError)
+	RevertTask(cxt context.Context, taskID string, revision int, authority User) (Task, *TaskError)
+	GetDeletedTasks(cxt context.Context) ([]Task, *TaskError)
+	RestoreDeletedTask(cxt context.Context, taskID string) (Task, *TaskError)
+	PurgeDeletedTasks(cxt context.Context, deletedBefore time.Time) (int, *TaskError)
+}
+
+// report use case interface
+type ReportUsecase interface {
+	GetBurndown(cxt context.Context, query ReportQuery) (Burndown, *TaskError)
+	GetVelocity(cxt context.Context, query ReportQuery) (Velocity, *TaskError)
+}
+
+// comment repository interface
+type CommentRepository interface {
+	FetchComments(cxt context.Context, taskID string) ([]Comment, *TaskError)
+	FetchCommentByID(cxt context.Context, commentID string) (Comment, *TaskError)
+	CountReplies(cxt context.Context, commentID string) (int64, *TaskError)
+	CreateComment(cxt context.Context, comment Comment) (string, *TaskError)
+	UpdateCommentBody(cxt context.Context, commentID string, body string, previous CommentRevision) (Comment, *TaskError)
+	MarkCommentDeleted(cxt context.Context, commentID string) (Comment, *TaskError)
+	DeleteComment(cxt context.Context, commentID string) *TaskError
+	ArchiveTaskComments(cxt context.Context, taskID string) *TaskError
+}
+
+// comment use case interface
+type CommentUsecase interface {
+	GetComments(cxt context.Context, taskID string) ([]Comment, *TaskError)
+	AddComment(cxt context.Context, comment Comment) (Comment, *TaskError)
+	EditComment(cxt context.Context, taskID string, commentID string, authorID string, body string) (Comment, *TaskError)
+	DeleteComment(cxt context.Context, taskID string, commentID string, authorID string) *TaskError
+	TaskDeleted(cxt context.Context, task Task) *TaskError
+}
+
+// time entry repository interface
+type TimeEntryRepository interface {
+	CreateTimeEntry(cxt context.Context, entry TimeEntry) (string, *TaskError)
+	FetchRunningEntry(cxt context.Context, userID string) (TimeEntry, *TaskError)
+	StopTimeEntry(cxt context.Context, entryID string, end time.Time) (TimeEntry, *TaskError)
+	StopTaskTimers(cxt context.Context, taskID string, end time.Time) *TaskError
+	FetchTimeEntries(cxt context.Context, query TimeQuery) ([]TimeEntry, *TaskError)
+	FetchTimeReport(cxt context.Context, query TimeQuery) ([]TimeReportRow, *TaskError)
+}
+
+// time use case interface
+type TimeUsecase interface {
+	StartTimer(cxt context.Context, taskID string, note string, user User) (TimeEntry, *TaskError)
+	StopTimer(cxt context.Context, user User) (TimeEntry, *TaskError)
+	GetRunningTimer(cxt context.Context, user User) (TimeEntry, *TaskError)
+	AddTimeEntry(cxt context.Context, entry TimeEntry, user User) (TimeEntry, *TaskError)
+	GetTaskTime(cxt context.Context, taskID string) (TimeSummary, *TaskError)
+	GetUserTime(cxt context.Context, query TimeQuery) (TimeSummary, *TaskError)
+	GetTimeReport(cxt context.Context, query TimeQuery) (TimeReport, *TaskError)
+	TaskDeleted(cxt context.Context, task Task) *TaskError
+}
+
+type RevisionRepository interface {
+	CreateRevision(cxt context.Context, revision TaskRevision) *TaskError
+	FetchRevisions(cxt context.Context, taskID string) ([]TaskRevision, *TaskError)
+	FetchRevision(cxt context.Context, taskID string, number int) (TaskRevision, *TaskError)
+	DeleteRevisions(cxt context.Context, taskID string) *TaskError
+}
+
+// attachment use case interface
+type AttachmentUsecase interface {
+	GetAttachments(cxt context.Context, taskID string) ([]Attachment, *TaskError)
+	UploadAttachment(cxt context.Context, taskID string, upload AttachmentUpload) (Attachment, *TaskError)
+	OpenAttachment(cxt context.Context, taskID string, attachmentID string) (Attachment, io.ReadCloser, *TaskError)
+	DeleteAttachment(cxt context.Context, taskID string, attachmentID string, authority User) *TaskError
+	TaskDeleted(cxt context.Context, task Task) *TaskError
+}
+
+// audit interfaces
+
+// records entries in the audit log, recording never fails the audited operation
+type AuditRecorder interface {
+	Record(cxt context.Context, entry AuditEntry)
+}
+
+type AuditRepository interface {
+	InsertEntry(cxt context.Context, entry AuditEntry) *TaskError
+	FetchEntries(cxt context.Context, query AuditQuery) ([]AuditEntry, *TaskError)
+}
+
+type AuditUsecase interface {
+	Record(cxt context.Context, entry AuditEntry)
+	GetEntries(cxt context.Context, query AuditQuery) ([]AuditEntry, *TaskError)
 }
 
 // users use case interface
@@ -71,9 +764,31 @@ type UserUsecase interface {
 	GetUserByID(cxt context.Context, userID string) (User, *UserError)
 	GetUserByUsername(cxt context.Context, username string) (User, *UserError)
 	CreateUser(cxt context.Context, newUser User) (string, *UserError)
+	// founds a new organization whose first user is the given admin
+	CreateOrganization(cxt context.Context, newAdmin User) (User, *UserError)
 	UpdateUser(cxt context.Context, userUpdate User) (User, *UserError)
 	DeleteUser(cxt context.Context, authority User, deleteID string) (User, *UserError)
-	LoginUser(cxt context.Context, loggingUser User) (string, *UserError)
+	LoginUser(cxt context.Context, loggingUser User) (Session, *UserError)
+	RefreshSession(cxt context.Context, refreshToken string) (Session, *UserError)
+	Logout(cxt context.Context, refreshToken string, accessToken string) *UserError
+	BeginOIDCLogin(cxt context.Context) (OIDCLogin, *UserError)
+	CompleteOIDCLogin(cxt context.Context, code string, state string, login OIDCLogin) (Session, *UserError)
+	// the second step of a login that handed out a two-factor token, code may also be a recovery code
+	CompleteTwoFactorLogin(cxt context.Context, twoFactorToken string, code string) (Session, *UserError)
+	// enrolls the user of a login that requires a second factor they do not have yet
+	EnrollTwoFactorAtLogin(cxt context.Context, twoFactorToken string) (TwoFactorEnrollment, *UserError)
+	GetTwoFactorStatus(cxt context.Context, userID string) (TwoFactorStatus, *UserError)
+	EnrollTwoFactor(cxt context.Context, userID string) (TwoFactorEnrollment, *UserError)
+	// enables the enrolled second factor with its first code and returns the recovery codes
+	ConfirmTwoFactor(cxt context.Context, userID string, code string) ([]string, *UserError)
+	DisableTwoFactor(cxt context.Context, userID string, code string) *UserError
+	RegenerateRecoveryCodes(cxt context.Context, userID string, code string) ([]string, *UserError)
+	ResetTwoFactor(cxt context.Context, authority User, userID string) *UserError
+	GetTwoFactorPolicy(cxt context.Context) (TwoFactorPolicy, *UserError)
+	UpdateTwoFactorPolicy(cxt context.Context, authority User, policy TwoFactorPolicy) (TwoFactorPolicy, *UserError)
+	GetDeletedUsers(cxt context.Context) ([]User, *UserError)
+	RestoreDeletedUser(cxt context.Context, userID string) (User, *UserError)
+	PurgeDeletedUsers(cxt context.Context, deletedBefore time.Time) (int, *UserError)
 }
 
 // task repository struct
@@ -85,4 +800,41 @@ type UserRepository interface {
 	CreateUser(cxt context.Context, newUser User) (string, *UserError)
 	UpdateUser(cxt context.Context, updateUser User) (User, *UserError)
 	DeleteUser(cxt context.Context, userID string) (User, *UserError)
+	FetchDeletedUsers(cxt context.Context, deletedBefore time.Time, limit int) ([]User, *UserError)
+	UndeleteUser(cxt context.Context, ID string) (User, *UserError)
+	PurgeUser(cxt context.Context, ID string, deletedBefore time.Time) *UserError
+	// 404 when no user has the identity or email
+	FetchUserByIdentity(cxt context.Context, issuer string, subject string) (User, *UserError)
+	FetchUserByEmail(cxt context.Context, email string) (User, *UserError)
+	// links the user to the identity, 404 when the user is missing or already linked to another one
+	LinkIdentity(cxt context.Context, ID string, issuer string, subject string) (User, *UserError)
+	// replaces the second factor of the user, nil removes it
+	UpdateTwoFactor(cxt context.Context, ID string, twoFactor *TwoFactor) *UserError
+	// records the step of an accepted code, 404 when a code of the step or a later one was accepted already
+	AcceptTwoFactorStep(cxt context.Context, ID string, step int64) *UserError
+	// removes the hash from the recovery codes, 404 when it is not one of them
+	ConsumeRecoveryCode(cxt context.Context, ID string, hash string) *UserError
+	RecordTwoFactorFailure(cxt context.Context, ID string, failedAt time.Time) *UserError
+}
+
+// one policy per organization, the organization of the context
+type TwoFactorPolicyRepository interface {
+	// an empty policy when the organization has none
+	FetchTwoFactorPolicy(cxt context.Context) (TwoFactorPolicy, *UserError)
+	UpdateTwoFactorPolicy(cxt context.Context, policy TwoFactorPolicy) (TwoFactorPolicy, *UserError)
+}
+
+// an OpenID Connect provider logging users in through the authorization code flow with PKCE
+type IdentityProvider interface {
+	AuthCodeURL(cxt context.Context, state string, nonce string, codeChallenge string) (string, error)
+	// redeems the code and validates the ID token it comes with, which must carry the nonce
+	Exchange(cxt context.Context, code string, codeVerifier string, nonce string) (ExternalIdentity, error)
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(cxt context.Context, token RefreshToken) *UserError
+	FetchRefreshToken(cxt context.Context, ID string) (RefreshToken, *UserError)
+	// marks the token used if it is unused, unrevoked and unexpired at usedAt, 404 otherwise
+	ConsumeRefreshToken(cxt context.Context, ID string, usedAt time.Time) (RefreshToken, *UserError)
+	RevokeTokenFamily(cxt context.Context, familyID string, revokedAt time.Time) *UserError
 }
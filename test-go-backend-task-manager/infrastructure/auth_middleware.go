@@ -9,7 +9,22 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// keys under which AuthMiddleWare stores the token claims, gin exposes them through context.Context.Value
+const (
+	CONTEXT_USERNAME = "username"
+	CONTEXT_ROLE     = "role"
+	// the organization of the caller, the repositories only reach the documents of that organization
+	CONTEXT_ORG = "org"
+	// the project a request is scoped to, set once the caller's membership has been checked
+	CONTEXT_PROJECT = "project"
+)
+
 func AuthMiddleWare(validRoles ...string) gin.HandlerFunc {
+	return AuthMiddleWareWithRevocations(nil, validRoles...)
+}
+
+// like AuthMiddleWare, and also refuses the tokens revoked in the store
+func AuthMiddleWareWithRevocations(revocations TokenRevocationStore, validRoles ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -36,6 +51,12 @@ func AuthMiddleWare(validRoles ...string) gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
+		// tokens with a purpose, such as those that complete a login, are not access tokens
+		if _, hasPurpose := claims["purpose"]; hasPurpose {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"Error": "Invalid token"})
+			ctx.Abort()
+			return
+		}
 		expirationDate, ok := claims["exp"].(float64)
 		if !ok {
 			ctx.JSON(http.StatusBadRequest, gin.H{"Error": "Invalid token, Token expiration date not found"})
@@ -73,6 +94,29 @@ func AuthMiddleWare(validRoles ...string) gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
+		username, hasUsername := claims["username"].(string)
+		if revocations != nil {
+			// tokens issued before token IDs existed have no jti
+			tokenID, _ := claims["jti"].(string)
+			revoked, err := revocations.IsRevoked(ctx, tokenID, username, time.Unix(int64(issuedDate), 0))
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"Error": "Could not check the token"})
+				ctx.Abort()
+				return
+			}
+			if revoked {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"Error": "Token revoked"})
+				ctx.Abort()
+				return
+			}
+		}
+		ctx.Set(CONTEXT_ROLE, retrivedRole)
+		if hasUsername {
+			ctx.Set(CONTEXT_USERNAME, username)
+		}
+		// tokens issued before organizations existed belong to the default organization
+		orgID, _ := claims["org"].(string)
+		ctx.Set(CONTEXT_ORG, orgID)
 		ctx.Next()
 	}
 }
@@ -10,6 +10,6 @@ import (
 type AuthService interface {
 	HashPassword(password string) (string, error)
 	ValidatePassword(hashedPassword, password string) error
-	CreateJWTToken(username string, role string, timeDuration time.Duration) (string, error)
+	CreateJWTToken(username string, role string, orgID string, timeDuration time.Duration) (string, error)
 	ParseJWTToken(token string) (*jwt.Token, error)
 }
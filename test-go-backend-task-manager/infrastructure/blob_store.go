@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// returned by Open when no blob is stored under the key
+var ErrBlobNotFound = errors.New("blob not found")
+
+// stores file contents under slash separated keys such as "taskID/attachmentID"
+type BlobStore interface {
+	Put(cxt context.Context, key string, content io.Reader) error
+	Open(cxt context.Context, key string) (io.ReadCloser, error)
+	// deleting a missing blob is not an error
+	Delete(cxt context.Context, key string) error
+}
+
+// keeps blobs as files below Root
+type LocalBlobStore struct {
+	Root string
+}
+
+func NewLocalBlobStore(root string) (LocalBlobStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return LocalBlobStore{}, err
+	}
+	return LocalBlobStore{Root: root}, nil
+}
+
+// writes the content to a temporary file first so readers never see a partial blob
+func (store LocalBlobStore) Put(cxt context.Context, key string, content io.Reader) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := io.Copy(file, contextReader{cxt: cxt, reader: content}); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (store LocalBlobStore) Open(cxt context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return file, err
+}
+
+// removes the blob and the directories it leaves empty
+func (store LocalBlobStore) Delete(cxt context.Context, key string) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	root := filepath.Clean(store.Root)
+	for dir := filepath.Dir(path); dir != root; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// the file of the key, keys may not escape the root
+func (store LocalBlobStore) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) || strings.Contains(key, `\`) {
+		return "", errors.New("invalid blob key " + key)
+	}
+	return filepath.Join(store.Root, filepath.FromSlash(key)), nil
+}
+
+// stops a copy once the context is done
+type contextReader struct {
+	cxt    context.Context
+	reader io.Reader
+}
+
+func (reader contextReader) Read(p []byte) (int, error) {
+	if err := reader.cxt.Err(); err != nil {
+		return 0, err
+	}
+	return reader.reader.Read(p)
+}
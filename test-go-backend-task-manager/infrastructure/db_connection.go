@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -39,3 +40,66 @@ func EstablisUniqueUsernameIndex(collection *mongo.Collection, index string) err
 	}
 	return nil
 }
+
+// a unique index that only covers the documents matching the partial filter
+func EstablisPartialUniqueIndex(collection *mongo.Collection, index string, partialFilter bson.D) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.M{index: 1},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(partialFilter),
+	}
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), indexModel)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func EstablisIndex(collection *mongo.Collection, index string) error {
+	indexModel := mongo.IndexModel{
+		Keys: bson.M{index: 1},
+	}
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), indexModel)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// documents are removed once the time in the field has passed
+func EstablisTTLIndex(collection *mongo.Collection, index string) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.M{index: 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), indexModel)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func EstablisTextIndex(collection *mongo.Collection, weights map[string]int) error {
+	fields := []string{}
+	for field := range weights {
+		fields = append(fields, field)
+	}
+	// a collection holds a single text index, keep its key order stable between restarts
+	sort.Strings(fields)
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+	}
+	indexModel := mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetWeights(weights),
+	}
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), indexModel)
+	if err != nil {
+		return err
+	}
+	return nil
+}
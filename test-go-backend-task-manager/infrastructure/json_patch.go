@@ -0,0 +1,306 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// operations of a JSON Patch document
+const (
+	PATCH_OP_ADD     = "add"
+	PATCH_OP_REMOVE  = "remove"
+	PATCH_OP_REPLACE = "replace"
+	PATCH_OP_MOVE    = "move"
+	PATCH_OP_COPY    = "copy"
+	PATCH_OP_TEST    = "test"
+)
+
+var (
+	// the patch document itself is malformed
+	ErrInvalidPatch = errors.New("invalid patch")
+	// a test operation did not match the document
+	ErrPatchTestFailed = errors.New("patch test failed")
+)
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applies an RFC 7396 JSON Merge Patch to the document, null members of the patch remove the member
+func MergePatch(document []byte, patch []byte) ([]byte, error) {
+	var target, patchValue interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+	return json.Marshal(mergeValue(target, patchValue))
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergeValue(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// applies an RFC 6902 JSON Patch to the document. the operations are applied in order and
+// nothing is returned unless all of them succeed.
+func JSONPatch(document []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	var operations []patchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: a JSON Patch must be an array of operations", ErrInvalidPatch)
+	}
+	for index, operation := range operations {
+		updated, err := applyOperation(target, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", index, err)
+		}
+		target = updated
+	}
+	return json.Marshal(target)
+}
+
+func applyOperation(target interface{}, operation patchOperation) (interface{}, error) {
+	if operation.Path == nil {
+		return nil, fmt.Errorf("%w: %q needs a path", ErrInvalidPatch, operation.Op)
+	}
+	path, err := parsePointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	switch operation.Op {
+	case PATCH_OP_ADD, PATCH_OP_REPLACE, PATCH_OP_TEST:
+		if operation.Value == nil {
+			return nil, fmt.Errorf("%w: %q needs a value", ErrInvalidPatch, operation.Op)
+		}
+		if err := json.Unmarshal(operation.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+		}
+	case PATCH_OP_MOVE, PATCH_OP_COPY:
+		if operation.From == nil {
+			return nil, fmt.Errorf("%w: %q needs a from path", ErrInvalidPatch, operation.Op)
+		}
+	}
+
+	switch operation.Op {
+	case PATCH_OP_ADD:
+		return addValue(target, path, value)
+	case PATCH_OP_REMOVE:
+		updated, _, err := removeValue(target, path)
+		return updated, err
+	case PATCH_OP_REPLACE:
+		updated, _, err := removeValue(target, path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(updated, path, value)
+	case PATCH_OP_MOVE:
+		from, err := parsePointer(*operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("%w: cannot move %s into one of its children", ErrInvalidPatch, *operation.From)
+		}
+		updated, moved, err := removeValue(target, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(updated, path, moved)
+	case PATCH_OP_COPY:
+		from, err := parsePointer(*operation.From)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := getValue(target, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(target, path, deepCopy(copied))
+	case PATCH_OP_TEST:
+		current, err := getValue(target, path)
+		if err != nil || !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("%w: %s", ErrPatchTestFailed, *operation.Path)
+		}
+		return target, nil
+	}
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, operation.Op)
+}
+
+// the reference tokens of an RFC 6901 JSON Pointer, the empty pointer is the whole document
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q is not a JSON Pointer", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for index, token := range tokens {
+		tokens[index] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func getValue(node interface{}, path []string) (interface{}, error) {
+	for index, token := range path {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			child, ok := container[token]
+			if !ok {
+				return nil, pathNotFound(path[:index+1])
+			}
+			node = child
+		case []interface{}:
+			position, ok := arrayIndex(token, len(container))
+			if !ok {
+				return nil, pathNotFound(path[:index+1])
+			}
+			node = container[position]
+		default:
+			return nil, pathNotFound(path[:index+1])
+		}
+	}
+	return node, nil
+}
+
+// the node with value added at the path, an array element is inserted before the one at its index
+func addValue(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token := path[0]
+	switch container := node.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			container[token] = value
+			return container, nil
+		}
+		child, ok := container[token]
+		if !ok {
+			return nil, pathNotFound(path[:1])
+		}
+		updated, err := addValue(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		if len(path) == 1 {
+			position := len(container)
+			if token != "-" {
+				var ok bool
+				if position, ok = arrayIndex(token, len(container)+1); !ok {
+					return nil, pathNotFound(path[:1])
+				}
+			}
+			return append(container[:position], append([]interface{}{value}, container[position:]...)...), nil
+		}
+		position, ok := arrayIndex(token, len(container))
+		if !ok {
+			return nil, pathNotFound(path[:1])
+		}
+		updated, err := addValue(container[position], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		container[position] = updated
+		return container, nil
+	}
+	return nil, pathNotFound(path[:1])
+}
+
+// the node without the value at the path, and the removed value
+func removeValue(node interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: the whole document cannot be removed", ErrInvalidPatch)
+	}
+	token := path[0]
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, nil, pathNotFound(path[:1])
+		}
+		if len(path) == 1 {
+			delete(container, token)
+			return container, child, nil
+		}
+		updated, removed, err := removeValue(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = updated
+		return container, removed, nil
+	case []interface{}:
+		position, ok := arrayIndex(token, len(container))
+		if !ok {
+			return nil, nil, pathNotFound(path[:1])
+		}
+		if len(path) == 1 {
+			removed := container[position]
+			return append(container[:position], container[position+1:]...), removed, nil
+		}
+		updated, removed, err := removeValue(container[position], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[position] = updated
+		return container, removed, nil
+	}
+	return nil, nil, pathNotFound(path[:1])
+}
+
+// the array index in the token when it is below limit, leading zeros are not allowed
+func arrayIndex(token string, limit int) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.ContainsAny(token, "+-") {
+		return 0, false
+	}
+	position, err := strconv.Atoi(token)
+	if err != nil || position >= limit {
+		return 0, false
+	}
+	return position, true
+}
+
+func deepCopy(value interface{}) interface{} {
+	encoded, _ := json.Marshal(value)
+	var copied interface{}
+	json.Unmarshal(encoded, &copied)
+	return copied
+}
+
+func pathNotFound(path []string) error {
+	escaped := make([]string, len(path))
+	for index, token := range path {
+		escaped[index] = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	}
+	return fmt.Errorf("path /%s does not exist", strings.Join(escaped, "/"))
+}
@@ -1,29 +1,136 @@
 package infrastructure
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// the keys tokens are signed with, tokens are signed with SIGNITURE_SECRET while there are none
+var (
+	keyManagerMutex sync.RWMutex
+	keyManager      *KeyManager
+)
+
+// signs and verifies tokens with the keys of the manager from now on, nil goes back to SIGNITURE_SECRET.
+// tokens signed with SIGNITURE_SECRET are refused while a manager is set.
+func SetKeyManager(manager *KeyManager) {
+	keyManagerMutex.Lock()
+	defer keyManagerMutex.Unlock()
+	keyManager = manager
+}
+
+func currentKeyManager() *KeyManager {
+	keyManagerMutex.RLock()
+	defer keyManagerMutex.RUnlock()
+	return keyManager
+}
+
 type UserClaim struct {
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	OrgID    string `json:"org,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func CreateJWTToken(username string, role string, timeDuration time.Duration) (string, error) {
+func CreateJWTToken(username string, role string, orgID string, timeDuration time.Duration) (string, error) {
 	claim := UserClaim{
 		Username: username,
 		Role:     role,
+		OrgID:    orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// the jti claim, through which the token can be revoked
+			ID:        newTokenID(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(timeDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
+	return signClaims(claim)
+}
+
+// the purpose of tokens that only complete a login, such tokens are refused by AuthMiddleWare
+const TOKEN_PURPOSE_TWO_FACTOR = "two_factor"
+
+// names the user whose password was checked and who still has to give their second factor
+type TwoFactorClaim struct {
+	UserID  string `json:"uid"`
+	OrgID   string `json:"org,omitempty"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+func CreateTwoFactorToken(userID string, orgID string, timeDuration time.Duration) (string, error) {
+	return signClaims(TwoFactorClaim{
+		UserID:  userID,
+		OrgID:   orgID,
+		Purpose: TOKEN_PURPOSE_TWO_FACTOR,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newTokenID(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(timeDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+}
+
+// the claims of a token made by CreateTwoFactorToken, access tokens are refused
+func ParseTwoFactorToken(token string) (TwoFactorClaim, error) {
+	parsed, err := ParseJWTToken(token)
+	if err != nil {
+		return TwoFactorClaim{}, err
+	}
+	claims, _ := parsed.Claims.(jwt.MapClaims)
+	purpose, _ := claims["purpose"].(string)
+	userID, _ := claims["uid"].(string)
+	if purpose != TOKEN_PURPOSE_TWO_FACTOR || userID == "" {
+		return TwoFactorClaim{}, fmt.Errorf("Not a two-factor token")
+	}
+	if _, hasExpiry := claims["exp"]; !hasExpiry {
+		return TwoFactorClaim{}, fmt.Errorf("Token expiration date not found")
+	}
+	orgID, _ := claims["org"].(string)
+	return TwoFactorClaim{UserID: userID, OrgID: orgID, Purpose: purpose}, nil
+}
+
+// answered by ParseAccessToken for a token that was valid but has expired
+var ErrTokenExpired = errors.New("token expired")
+
+// the claims of an access token made by CreateJWTToken, tokens with a purpose are refused
+func ParseAccessToken(token string) (UserClaim, error) {
+	parsed, err := ParseJWTToken(token)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return UserClaim{}, ErrTokenExpired
+	}
+	if err != nil {
+		return UserClaim{}, err
+	}
+	claims, _ := parsed.Claims.(jwt.MapClaims)
+	if _, hasPurpose := claims["purpose"]; hasPurpose {
+		return UserClaim{}, fmt.Errorf("Not an access token")
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return UserClaim{}, fmt.Errorf("Token expiration date not found")
+	}
+	username, _ := claims["username"].(string)
+	role, _ := claims["role"].(string)
+	orgID, _ := claims["org"].(string)
+	// tokens issued before token IDs existed have no jti
+	tokenID, _ := claims["jti"].(string)
+	return UserClaim{Username: username, Role: role, OrgID: orgID, RegisteredClaims: jwt.RegisteredClaims{ID: tokenID, ExpiresAt: expiresAt}}, nil
+}
+
+// signs with the active key, or with SIGNITURE_SECRET while there is none
+func signClaims(claims jwt.Claims) (string, error) {
+	if manager := currentKeyManager(); manager != nil {
+		return manager.Sign(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	jwtToken, err := token.SignedString([]byte(os.Getenv("SIGNITURE_SECRET")))
 	if err != nil {
 		return "", err
@@ -32,12 +139,18 @@ func CreateJWTToken(username string, role string, timeDuration time.Duration) (s
 }
 
 func ParseJWTToken(token string) (*jwt.Token, error) {
-	retrivedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", t.Header["alg"])
-		}
-		return []byte(os.Getenv("SIGNITURE_SECRET")), nil
-	})
+	var retrivedToken *jwt.Token
+	var err error
+	if manager := currentKeyManager(); manager != nil {
+		retrivedToken, err = manager.Parse(token)
+	} else {
+		retrivedToken, err = jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("Unexpected signing method: %v", t.Header["alg"])
+			}
+			return []byte(os.Getenv("SIGNITURE_SECRET")), nil
+		})
+	}
 
 	if err != nil {
 		return &jwt.Token{}, err
@@ -47,3 +160,9 @@ func ParseJWTToken(token string) (*jwt.Token, error) {
 	}
 	return retrivedToken, nil
 }
+
+func newTokenID() string {
+	ID := make([]byte, 16)
+	rand.Read(ID)
+	return hex.EncodeToString(ID)
+}
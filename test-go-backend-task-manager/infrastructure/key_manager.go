@@ -0,0 +1,197 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signing algorithms, named as in the alg header of a token
+const (
+	ALGORITHM_RS256 = "RS256"
+	ALGORITHM_EDDSA = "EdDSA"
+)
+
+// RSA keys shorter than this are refused
+const MIN_RSA_KEY_BITS = 2048
+
+// a key pair that signs or verifies tokens. keys loaded from a public key file only verify.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Private   crypto.PrivateKey
+	Public    crypto.PublicKey
+	// retired keys are neither published nor accepted, the tokens they signed are refused
+	Retired bool
+}
+
+// signs tokens with the active key and verifies them against every key that is not retired
+type KeyManager struct {
+	keys   map[string]SigningKey
+	active SigningKey
+}
+
+// a public key in the form of RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA keys
+	Modulus  string `json:"n,omitempty"`
+	Exponent string `json:"e,omitempty"`
+	// Ed25519 keys, and EC keys with Y
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeyManager(keys []SigningKey, activeID string) (*KeyManager, error) {
+	manager := &KeyManager{keys: map[string]SigningKey{}}
+	for _, key := range keys {
+		if _, duplicate := manager.keys[key.ID]; duplicate {
+			return nil, fmt.Errorf("Duplicate signing key %q", key.ID)
+		}
+		manager.keys[key.ID] = key
+	}
+	active, ok := manager.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("The active signing key %q is not loaded", activeID)
+	}
+	if active.Private == nil {
+		return nil, fmt.Errorf("The active signing key %q has no private key", activeID)
+	}
+	if active.Retired {
+		return nil, fmt.Errorf("The active signing key %q is retired", activeID)
+	}
+	manager.active = active
+	return manager, nil
+}
+
+// loads every .pem file of the directory, a key's ID is the name of its file without the extension
+func LoadKeyManager(dir string, activeID string, retiredIDs []string) (*KeyManager, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	retired := map[string]bool{}
+	for _, ID := range retiredIDs {
+		retired[strings.TrimSpace(ID)] = true
+	}
+	keys := []SigningKey{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key.Retired = retired[key.ID]
+		keys = append(keys, key)
+	}
+	return NewKeyManager(keys, activeID)
+}
+
+// reads an RSA or Ed25519 key from PEM, either a private key in PKCS #8 or PKCS #1 or a public key in PKIX
+func ParseSigningKey(ID string, content []byte) (SigningKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("No PEM block found")
+	}
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("Unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	key := SigningKey{ID: ID}
+	switch typed := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private, key.Public = ALGORITHM_RS256, typed, &typed.PublicKey
+	case *rsa.PublicKey:
+		key.Algorithm, key.Public = ALGORITHM_RS256, typed
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private, key.Public = ALGORITHM_EDDSA, typed, typed.Public()
+	case ed25519.PublicKey:
+		key.Algorithm, key.Public = ALGORITHM_EDDSA, typed
+	default:
+		return SigningKey{}, fmt.Errorf("Unsupported key type %T, only RSA and Ed25519 keys are supported", parsed)
+	}
+	if rsaKey, ok := key.Public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < MIN_RSA_KEY_BITS {
+		return SigningKey{}, fmt.Errorf("RSA keys must have at least %d bits", MIN_RSA_KEY_BITS)
+	}
+	return key, nil
+}
+
+// signs the claims with the active key and names it in the kid header
+func (manager *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(manager.active.Algorithm), claims)
+	token.Header["kid"] = manager.active.ID
+	return token.SignedString(manager.active.Private)
+}
+
+// the token when it is valid and signed by a key that is not retired, with the algorithm of that key
+func (manager *KeyManager) Parse(token string) (*jwt.Token, error) {
+	return jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		keyID, _ := t.Header["kid"].(string)
+		key, ok := manager.keys[keyID]
+		if !ok || key.Retired {
+			return nil, fmt.Errorf("Unknown signing key %q", keyID)
+		}
+		if t.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("Unexpected signing method: %v", t.Header["alg"])
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods([]string{ALGORITHM_RS256, ALGORITHM_EDDSA}))
+}
+
+// the public keys that verify tokens, ordered by ID
+func (manager *KeyManager) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range manager.keys {
+		if key.Retired {
+			continue
+		}
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
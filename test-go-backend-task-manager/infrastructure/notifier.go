@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// writes reminders to the standard logger
+type LogNotifier struct{}
+
+func NewLogNotifier() LogNotifier {
+	return LogNotifier{}
+}
+
+func (notifier LogNotifier) Notify(cxt context.Context, reminder domain.Reminder) error {
+	log.Printf("Reminder %s: task %q (%s) of user %s is due %s", reminder.Kind, reminder.Title, reminder.TaskID, reminder.UserID, reminder.DueDate.Format(time.RFC3339))
+	return nil
+}
+
+// emails reminders to the address in the user's reminder preference.
+// reminders for users without an address are logged and dropped.
+type SMTPNotifier struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+// username may be empty for servers that do not require authentication
+func NewSMTPNotifier(addr string, from string, username string, password string) SMTPNotifier {
+	notifier := SMTPNotifier{Addr: addr, From: from}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		notifier.Auth = smtp.PlainAuth("", username, password, host)
+	}
+	return notifier
+}
+
+func (notifier SMTPNotifier) Notify(cxt context.Context, reminder domain.Reminder) error {
+	if reminder.Email == "" {
+		log.Println("Reminder", reminder.ID, "dropped, user", reminder.UserID, "has no email address")
+		return nil
+	}
+	host, _, err := net.SplitHostPort(notifier.Addr)
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{}).DialContext(cxt, "tcp", notifier.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := cxt.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if notifier.Auth != nil {
+		if err = client.Auth(notifier.Auth); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(notifier.From); err != nil {
+		return err
+	}
+	if err = client.Rcpt(reminder.Email); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(reminderMessage(notifier.From, reminder)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func reminderMessage(from string, reminder domain.Reminder) []byte {
+	subject := fmt.Sprintf("Task due soon: %s", reminder.Title)
+	body := fmt.Sprintf("Your task %q is due %s.", reminder.Title, reminder.DueDate.Format(time.RFC1123))
+	if reminder.Kind == domain.REMINDER_OVERDUE {
+		subject = fmt.Sprintf("Task overdue: %s", reminder.Title)
+		body = fmt.Sprintf("Your task %q was due %s.", reminder.Title, reminder.DueDate.Format(time.RFC1123))
+	}
+	headers := []string{
+		"From: " + from,
+		"To: " + reminder.Email,
+		"Subject: " + strings.NewReplacer("\r", " ", "\n", " ").Replace(subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
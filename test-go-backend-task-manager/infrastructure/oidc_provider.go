@@ -0,0 +1,362 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const ALGORITHM_ES256 = "ES256"
+
+// a provider that takes longer than this to answer is treated as unreachable
+const OIDC_HTTP_TIMEOUT = 10 * time.Second
+
+// ID tokens are accepted this long past their expiry, for clocks that drift apart
+const OIDC_CLOCK_SKEW = time.Minute
+
+// a token naming an unknown key fetches the keys of the provider again, at most this often
+const OIDC_KEYS_REFRESH_INTERVAL = time.Minute
+
+// the largest response read from the provider
+const MAX_OIDC_RESPONSE_BYTES = 1 << 20
+
+// the provider could not be reached or answered with an error of its own. every other error of
+// an exchange means the provider or its ID token refused the login.
+var ErrOIDCUnavailable = errors.New("The identity provider is unavailable")
+
+type OIDCConfig struct {
+	// the metadata of the provider is read from /.well-known/openid-configuration below the issuer
+	Issuer   string
+	ClientID string
+	// confidential clients authenticate with HTTP basic, public clients leave it empty
+	ClientSecret string
+	RedirectURL  string
+	// openid is always requested, email and profile too when no scopes are given
+	Scopes []string
+}
+
+// an OpenID Connect provider reached over HTTP. its metadata is read on first use and its keys
+// whenever a token names a key that is not known yet.
+type OIDCProvider struct {
+	config        OIDCConfig
+	client        *http.Client
+	mutex         sync.Mutex
+	metadata      *oidcMetadata
+	keys          map[string]oidcKey
+	keysFetchedAt time.Time
+}
+
+type oidcMetadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+type oidcKey struct {
+	algorithm string
+	public    crypto.PublicKey
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type idTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	PreferredUsername string `json:"preferred_username"`
+	// a boolean, though some providers send the string "true"
+	EmailVerified interface{} `json:"email_verified"`
+	jwt.RegisteredClaims
+}
+
+func NewOIDCProvider(config OIDCConfig, client *http.Client) *OIDCProvider {
+	if client == nil {
+		client = &http.Client{Timeout: OIDC_HTTP_TIMEOUT}
+	}
+	scopes := []string{"openid"}
+	if len(config.Scopes) == 0 {
+		scopes = append(scopes, "email", "profile")
+	}
+	for _, scope := range config.Scopes {
+		if scope != "openid" && scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	config.Scopes = scopes
+	return &OIDCProvider{config: config, client: client}
+}
+
+// the page of the provider that logs the user in and redirects back with a code
+func (provider *OIDCProvider) AuthCodeURL(cxt context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	metadata, err := provider.discover(cxt)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOIDCUnavailable, err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.config.ClientID)
+	query.Set("redirect_uri", provider.config.RedirectURL)
+	query.Set("scope", strings.Join(provider.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+func (provider *OIDCProvider) Exchange(cxt context.Context, code string, codeVerifier string, nonce string) (domain.ExternalIdentity, error) {
+	metadata, err := provider.discover(cxt)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if provider.config.ClientSecret == "" {
+		form.Set("client_id", provider.config.ClientID)
+	}
+	request, err := http.NewRequestWithContext(cxt, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: %v", ErrOIDCUnavailable, err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if provider.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(provider.config.ClientID), url.QueryEscape(provider.config.ClientSecret))
+	}
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: %v", ErrOIDCUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	var tokens oidcTokenResponse
+	errDecode := json.NewDecoder(io.LimitReader(response.Body, MAX_OIDC_RESPONSE_BYTES)).Decode(&tokens)
+	switch {
+	case response.StatusCode == http.StatusBadRequest || response.StatusCode == http.StatusUnauthorized:
+		return domain.ExternalIdentity{}, fmt.Errorf("The identity provider refused the code: %s %s", tokens.Error, tokens.ErrorDescription)
+	case response.StatusCode != http.StatusOK:
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: the token endpoint answered %d", ErrOIDCUnavailable, response.StatusCode)
+	case errDecode != nil:
+		return domain.ExternalIdentity{}, fmt.Errorf("%w: %v", ErrOIDCUnavailable, errDecode)
+	case tokens.IDToken == "":
+		return domain.ExternalIdentity{}, fmt.Errorf("The identity provider returned no ID token")
+	}
+	return provider.validateIDToken(cxt, tokens.IDToken, nonce)
+}
+
+// checks the signature, issuer, audience, lifetime and nonce of the ID token as OpenID Connect Core 3.1.3.7 asks
+func (provider *OIDCProvider) validateIDToken(cxt context.Context, idToken string, nonce string) (domain.ExternalIdentity, error) {
+	claims := idTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		keyID, _ := t.Header["kid"].(string)
+		key, err := provider.key(cxt, keyID)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != key.algorithm {
+			return nil, fmt.Errorf("Unexpected signing method: %v", t.Header["alg"])
+		}
+		return key.public, nil
+	},
+		jwt.WithValidMethods([]string{ALGORITHM_RS256, ALGORITHM_ES256, ALGORITHM_EDDSA}),
+		jwt.WithIssuer(provider.config.Issuer),
+		jwt.WithAudience(provider.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(OIDC_CLOCK_SKEW),
+	)
+	if err != nil {
+		return domain.ExternalIdentity{}, fmt.Errorf("Invalid ID token: %w", err)
+	}
+	if claims.Subject == "" {
+		return domain.ExternalIdentity{}, fmt.Errorf("Invalid ID token: the subject is missing")
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return domain.ExternalIdentity{}, fmt.Errorf("Invalid ID token: the nonce does not match")
+	}
+	// a token meant for several clients must name us as the one it was issued to
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != provider.config.ClientID {
+		return domain.ExternalIdentity{}, fmt.Errorf("Invalid ID token: it was issued to %q", claims.AuthorizedParty)
+	}
+	return domain.ExternalIdentity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Username:      claims.PreferredUsername,
+	}, nil
+}
+
+func (provider *OIDCProvider) discover(cxt context.Context) (*oidcMetadata, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	if provider.metadata != nil {
+		return provider.metadata, nil
+	}
+	metadata := oidcMetadata{}
+	if err := provider.getJSON(cxt, strings.TrimSuffix(provider.config.Issuer, "/")+"/.well-known/openid-configuration", &metadata); err != nil {
+		return nil, err
+	}
+	if metadata.Issuer != provider.config.Issuer {
+		return nil, fmt.Errorf("%w: the provider names itself %q rather than %q", ErrOIDCUnavailable, metadata.Issuer, provider.config.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: the provider metadata lacks an endpoint", ErrOIDCUnavailable)
+	}
+	if len(metadata.CodeChallengeMethods) > 0 && !containsString(metadata.CodeChallengeMethods, "S256") {
+		return nil, fmt.Errorf("%w: the provider does not support PKCE with S256", ErrOIDCUnavailable)
+	}
+	provider.metadata = &metadata
+	return provider.metadata, nil
+}
+
+// the key of the provider with the ID. a provider with a single key may leave the ID out.
+func (provider *OIDCProvider) key(cxt context.Context, keyID string) (oidcKey, error) {
+	metadata, err := provider.discover(cxt)
+	if err != nil {
+		return oidcKey{}, err
+	}
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	if key, found := provider.lookupKey(keyID); found {
+		return key, nil
+	}
+	if provider.keys != nil && time.Since(provider.keysFetchedAt) < OIDC_KEYS_REFRESH_INTERVAL {
+		return oidcKey{}, fmt.Errorf("Unknown signing key %q", keyID)
+	}
+	set := JWKSet{}
+	if err := provider.getJSON(cxt, metadata.JWKSURI, &set); err != nil {
+		return oidcKey{}, err
+	}
+	keys := map[string]oidcKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys of other types or curves cannot verify the tokens we accept
+		if key, errKey := parseJWK(jwk); errKey == nil {
+			keys[jwk.KeyID] = key
+		}
+	}
+	provider.keys = keys
+	provider.keysFetchedAt = time.Now()
+	if key, found := provider.lookupKey(keyID); found {
+		return key, nil
+	}
+	return oidcKey{}, fmt.Errorf("Unknown signing key %q", keyID)
+}
+
+func (provider *OIDCProvider) lookupKey(keyID string) (oidcKey, bool) {
+	if keyID == "" && len(provider.keys) == 1 {
+		for _, key := range provider.keys {
+			return key, true
+		}
+	}
+	key, found := provider.keys[keyID]
+	return key, found
+}
+
+func (provider *OIDCProvider) getJSON(cxt context.Context, address string, target interface{}) error {
+	request, err := http.NewRequestWithContext(cxt, http.MethodGet, address, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCUnavailable, err)
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCUnavailable, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s answered %d", ErrOIDCUnavailable, address, response.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(response.Body, MAX_OIDC_RESPONSE_BYTES)).Decode(target); err != nil {
+		return fmt.Errorf("%w: %v", ErrOIDCUnavailable, err)
+	}
+	return nil
+}
+
+// an RSA, P-256 or Ed25519 public key, with the algorithm it signs with when the key leaves it out
+func parseJWK(jwk JWK) (oidcKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		modulus, errModulus := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+		exponent, errExponent := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+		if errModulus != nil || errExponent != nil || len(exponent) == 0 || len(exponent) > 4 {
+			return oidcKey{}, fmt.Errorf("Malformed RSA key %q", jwk.KeyID)
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(new(big.Int).SetBytes(exponent).Int64())}
+		if public.N.BitLen() < MIN_RSA_KEY_BITS {
+			return oidcKey{}, fmt.Errorf("RSA keys must have at least %d bits", MIN_RSA_KEY_BITS)
+		}
+		return oidcKey{algorithm: algorithmOr(jwk.Algorithm, ALGORITHM_RS256), public: public}, nil
+	case "EC":
+		x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+		y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if jwk.Curve != "P-256" || errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return oidcKey{}, fmt.Errorf("Unsupported EC key %q", jwk.KeyID)
+		}
+		// refuses points that are not on the curve
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return oidcKey{}, err
+		}
+		public := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return oidcKey{algorithm: algorithmOr(jwk.Algorithm, ALGORITHM_ES256), public: public}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if jwk.Curve != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return oidcKey{}, fmt.Errorf("Unsupported OKP key %q", jwk.KeyID)
+		}
+		return oidcKey{algorithm: algorithmOr(jwk.Algorithm, ALGORITHM_EDDSA), public: ed25519.PublicKey(x)}, nil
+	}
+	return oidcKey{}, fmt.Errorf("Unsupported key type %q", jwk.KeyType)
+}
+
+func algorithmOr(algorithm string, fallback string) string {
+	if algorithm == "" {
+		return fallback
+	}
+	return algorithm
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
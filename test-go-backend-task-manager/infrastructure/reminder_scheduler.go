@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// periodically asks the reminder use case to send the reminders that are owed
+type ReminderScheduler struct {
+	reminderUsecase domain.ReminderUsecase
+	interval        time.Duration
+	stop            chan struct{}
+	done            chan struct{}
+	stopOnce        sync.Once
+}
+
+func NewReminderScheduler(reminderUC domain.ReminderUsecase, interval time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{
+		reminderUsecase: reminderUC,
+		interval:        interval,
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+// scans right away and then every interval until Stop is called or the context is cancelled
+func (scheduler *ReminderScheduler) Start(cxt context.Context) {
+	go runPeriodically(cxt, scheduler.interval, scheduler.stop, scheduler.done, scheduler.scan)
+}
+
+// stops the scheduler and waits for the running scan to finish, the scheduler must have been started
+func (scheduler *ReminderScheduler) Stop() {
+	scheduler.stopOnce.Do(func() { close(scheduler.stop) })
+	<-scheduler.done
+}
+
+func (scheduler *ReminderScheduler) scan(cxt context.Context) {
+	sent, err := scheduler.reminderUsecase.ScanReminders(cxt, time.Now())
+	if err != nil {
+		log.Println("Error", "scanning reminders", err)
+	}
+	if len(sent) > 0 {
+		log.Println("Sent", len(sent), "reminders")
+	}
+}
+
+// runs job right away and then every interval until stop is closed or the context is cancelled,
+// then closes done. a run that already started is allowed to finish on shutdown.
+func runPeriodically(cxt context.Context, interval time.Duration, stop <-chan struct{}, done chan<- struct{}, job func(context.Context)) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job(context.WithoutCancel(cxt))
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		case <-cxt.Done():
+			return
+		}
+	}
+}
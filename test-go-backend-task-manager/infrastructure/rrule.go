@@ -0,0 +1,268 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// supported RRULE frequencies
+const (
+	FREQ_DAILY   = "DAILY"
+	FREQ_WEEKLY  = "WEEKLY"
+	FREQ_MONTHLY = "MONTHLY"
+	FREQ_YEARLY  = "YEARLY"
+)
+
+// number of periods walked before giving up on a rule that never matches
+const MAX_RRULE_PERIODS = 10000
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// a BYDAY entry, ordinal 0 means every such weekday of the period, negative ordinals count from the end
+type RRuleDay struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// the subset of an RFC 5545 recurrence rule the task manager understands
+type RRule struct {
+	Freq     string
+	Interval int
+	ByDay    []RRuleDay
+	Count    int
+	Until    time.Time
+}
+
+// parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10", an "RRULE:" prefix is allowed
+func ParseRRule(rule string) (RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	parsed := RRule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, found := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !found || value == "" {
+			return RRule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+		if seen[name] {
+			return RRule{}, fmt.Errorf("%s is given more than once", name)
+		}
+		seen[name] = true
+		switch name {
+		case "FREQ":
+			if value != FREQ_DAILY && value != FREQ_WEEKLY && value != FREQ_MONTHLY && value != FREQ_YEARLY {
+				return RRule{}, fmt.Errorf("unsupported FREQ %q", value)
+			}
+			parsed.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return RRule{}, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			parsed.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return RRule{}, fmt.Errorf("COUNT must be a positive integer")
+			}
+			parsed.Count = count
+		case "UNTIL":
+			until, err := parseRRuleTime(value)
+			if err != nil {
+				return RRule{}, err
+			}
+			parsed.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				byDay, err := parseRRuleDay(day)
+				if err != nil {
+					return RRule{}, err
+				}
+				parsed.ByDay = append(parsed.ByDay, byDay)
+			}
+		case "WKST":
+			if value != "MO" {
+				return RRule{}, fmt.Errorf("only WKST=MO is supported")
+			}
+		default:
+			return RRule{}, fmt.Errorf("unsupported rule part %s", name)
+		}
+	}
+	if parsed.Freq == "" {
+		return RRule{}, fmt.Errorf("FREQ is required")
+	}
+	if parsed.Count > 0 && !parsed.Until.IsZero() {
+		return RRule{}, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	for _, day := range parsed.ByDay {
+		if day.Ordinal != 0 && parsed.Freq != FREQ_MONTHLY && parsed.Freq != FREQ_YEARLY {
+			return RRule{}, fmt.Errorf("BYDAY ordinals are only allowed with MONTHLY or YEARLY")
+		}
+	}
+	return parsed, nil
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return until, nil
+		}
+	}
+	// a date without a time includes the whole day
+	if until, err := time.Parse("20060102", value); err == nil {
+		return until.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseRRuleDay(value string) (RRuleDay, error) {
+	if len(value) < 2 {
+		return RRuleDay{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	weekday, ok := rruleWeekdays[value[len(value)-2:]]
+	if !ok {
+		return RRuleDay{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+	day := RRuleDay{Weekday: weekday}
+	if prefix := value[:len(value)-2]; prefix != "" {
+		ordinal, err := strconv.Atoi(prefix)
+		if err != nil || ordinal == 0 || ordinal < -53 || ordinal > 53 {
+			return RRuleDay{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+		day.Ordinal = ordinal
+	}
+	return day, nil
+}
+
+// returns up to n occurrences of the series starting at start that fall strictly after the given time.
+// start is always the first occurrence and counts towards COUNT, as in RFC 5545.
+func (rule RRule) After(start time.Time, after time.Time, n int) []time.Time {
+	occurrences := []time.Time{}
+	if n <= 0 {
+		return occurrences
+	}
+	emitted := 0
+	// reports whether the expansion should stop
+	emit := func(occurrence time.Time) bool {
+		if !rule.Until.IsZero() && occurrence.After(rule.Until) {
+			return true
+		}
+		emitted++
+		if occurrence.After(after) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return len(occurrences) == n || (rule.Count > 0 && emitted == rule.Count)
+	}
+	if emit(start) {
+		return occurrences
+	}
+	for period := 0; period < MAX_RRULE_PERIODS; period++ {
+		for _, candidate := range rule.candidates(start, period) {
+			if !candidate.After(start) {
+				continue
+			}
+			if emit(candidate) {
+				return occurrences
+			}
+		}
+	}
+	return occurrences
+}
+
+// the sorted occurrences of the rule inside the given period, at the time of day of start
+func (rule RRule) candidates(start time.Time, period int) []time.Time {
+	step := period * rule.Interval
+	days := []time.Time{}
+	switch rule.Freq {
+	case FREQ_DAILY:
+		day := start.AddDate(0, 0, step)
+		if len(rule.ByDay) == 0 || rule.matchesWeekday(day.Weekday()) {
+			days = append(days, day)
+		}
+	case FREQ_WEEKLY:
+		monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*step)
+		for offset := 0; offset < 7; offset++ {
+			day := monday.AddDate(0, 0, offset)
+			if (len(rule.ByDay) == 0 && day.Weekday() == start.Weekday()) || rule.matchesWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+	case FREQ_MONTHLY:
+		first := time.Date(start.Year(), start.Month()+time.Month(step), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		last := first.AddDate(0, 1, -1)
+		if len(rule.ByDay) == 0 {
+			if start.Day() <= last.Day() {
+				days = append(days, first.AddDate(0, 0, start.Day()-1))
+			}
+		} else {
+			days = rule.daysBetween(first, last)
+		}
+	case FREQ_YEARLY:
+		year := start.Year() + step
+		if len(rule.ByDay) == 0 {
+			day := time.Date(year, start.Month(), start.Day(), start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+			// skips February 29th in years that do not have one
+			if day.Day() == start.Day() {
+				days = append(days, day)
+			}
+		} else {
+			first := time.Date(year, time.January, 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+			days = rule.daysBetween(first, first.AddDate(1, 0, -1))
+		}
+	}
+	return days
+}
+
+func (rule RRule) matchesWeekday(weekday time.Weekday) bool {
+	for _, day := range rule.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// the days between first and last, inclusive, selected by BYDAY
+func (rule RRule) daysBetween(first time.Time, last time.Time) []time.Time {
+	byWeekday := map[time.Weekday][]time.Time{}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], day)
+	}
+	selected := map[int64]time.Time{}
+	for _, byDay := range rule.ByDay {
+		matching := byWeekday[byDay.Weekday]
+		switch {
+		case byDay.Ordinal == 0:
+			for _, day := range matching {
+				selected[day.Unix()] = day
+			}
+		case byDay.Ordinal > 0 && byDay.Ordinal <= len(matching):
+			day := matching[byDay.Ordinal-1]
+			selected[day.Unix()] = day
+		case byDay.Ordinal < 0 && -byDay.Ordinal <= len(matching):
+			day := matching[len(matching)+byDay.Ordinal]
+			selected[day.Unix()] = day
+		}
+	}
+	days := []time.Time{}
+	for _, day := range selected {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
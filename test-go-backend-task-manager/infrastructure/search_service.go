@@ -0,0 +1,235 @@
+package infrastructure
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// weights shared by the task text index and the in-process search
+const (
+	TITLE_SEARCH_WEIGHT       = 2
+	DESCRIPTION_SEARCH_WEIGHT = 1
+	SNIPPET_RADIUS            = 60
+)
+
+// a search query in the syntax of the text index: tasks match any of the terms, must contain every
+// quoted phrase and none of the terms or phrases negated with a leading "-"
+type SearchQuery struct {
+	// lower cased, the words of the phrases included
+	Terms    []string
+	Phrases  []string
+	Excluded []string
+}
+
+func ParseSearchQuery(query string) SearchQuery {
+	parsed := SearchQuery{Terms: []string{}, Phrases: []string{}, Excluded: []string{}}
+	rest := strings.ToLower(query)
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			return parsed
+		}
+		negated := strings.HasPrefix(rest, "-")
+		if negated {
+			rest = rest[1:]
+		}
+		if strings.HasPrefix(rest, "\"") {
+			// an unterminated phrase runs to the end of the query
+			phrase, remainder, _ := strings.Cut(rest[1:], "\"")
+			rest = remainder
+			words := strings.FieldsFunc(phrase, isNotWordRune)
+			if len(words) == 0 {
+				continue
+			}
+			if negated {
+				parsed.Excluded = append(parsed.Excluded, strings.Join(words, " "))
+			} else {
+				parsed.Phrases = append(parsed.Phrases, strings.Join(words, " "))
+				parsed.Terms = append(parsed.Terms, words...)
+			}
+			continue
+		}
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		words := strings.FieldsFunc(rest[:end], isNotWordRune)
+		rest = rest[end:]
+		if negated {
+			parsed.Excluded = append(parsed.Excluded, words...)
+		} else {
+			parsed.Terms = append(parsed.Terms, words...)
+		}
+	}
+}
+
+// splits a search query into lower cased terms, negated terms ("-word") are dropped
+func SearchTerms(query string) []string {
+	return ParseSearchQuery(query).Terms
+}
+
+// whether the task contains every phrase of the query and none of its exclusions, in its title or description
+func (query SearchQuery) Admits(task domain.Task) bool {
+	title, description := searchableText(task.Title), searchableText(task.Description)
+	for _, phrase := range query.Phrases {
+		if !strings.Contains(title, phrase) && !strings.Contains(description, phrase) {
+			return false
+		}
+	}
+	for _, excluded := range query.Excluded {
+		if strings.Contains(excluded, " ") {
+			if strings.Contains(title, excluded) || strings.Contains(description, excluded) {
+				return false
+			}
+		} else if countMatches(task.Title, []string{excluded}) > 0 || countMatches(task.Description, []string{excluded}) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// the lower cased words of the text separated by single spaces, phrases are matched against it
+func searchableText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), isNotWordRune), " ")
+}
+
+// ranks tasks against the query the same way the text index weights them,
+// used when the text index is unavailable and by in-process TaskRepository implementations
+func SearchTasksInMemory(tasks []domain.Task, query string, limit int) []domain.TaskSearchResult {
+	parsed := ParseSearchQuery(query)
+	terms := parsed.Terms
+	results := []domain.TaskSearchResult{}
+	if len(terms) == 0 {
+		return results
+	}
+	for _, task := range tasks {
+		if !parsed.Admits(task) {
+			continue
+		}
+		score := float64(TITLE_SEARCH_WEIGHT*countMatches(task.Title, terms) + DESCRIPTION_SEARCH_WEIGHT*countMatches(task.Description, terms))
+		if score == 0 {
+			continue
+		}
+		results = append(results, domain.TaskSearchResult{Task: task, Score: score, Highlights: HighlightTask(task, terms)})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.ID < results[j].Task.ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// builds the highlighted snippets of the fields that contain a search term
+func HighlightTask(task domain.Task, terms []string) map[string]string {
+	highlights := map[string]string{}
+	if countMatches(task.Title, terms) > 0 {
+		highlights["title"] = HighlightText(task.Title, terms, 0)
+	}
+	if countMatches(task.Description, terms) > 0 {
+		highlights["description"] = HighlightText(task.Description, terms, SNIPPET_RADIUS)
+	}
+	return highlights
+}
+
+// wraps every word starting with a search term in <mark> tags, the rest of the text is HTML escaped.
+// a positive radius trims the text to about that many characters around the first match.
+func HighlightText(text string, terms []string, radius int) string {
+	runes := []rune(text)
+	words := wordSpans(runes)
+	matched := []wordSpan{}
+	for _, word := range words {
+		if matchesTerm(string(runes[word.start:word.end]), terms) {
+			matched = append(matched, word)
+		}
+	}
+
+	start, end := 0, len(runes)
+	if radius > 0 && len(matched) > 0 {
+		start = max(0, matched[0].start-radius)
+		end = min(len(runes), matched[0].end+radius)
+		for start > 0 && !isNotWordRune(runes[start-1]) {
+			start--
+		}
+		for end < len(runes) && !isNotWordRune(runes[end]) {
+			end++
+		}
+	}
+
+	var builder strings.Builder
+	if start > 0 {
+		builder.WriteString("…")
+	}
+	position := start
+	for _, word := range matched {
+		if word.start < start || word.end > end {
+			continue
+		}
+		builder.WriteString(html.EscapeString(string(runes[position:word.start])))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(string(runes[word.start:word.end])))
+		builder.WriteString("</mark>")
+		position = word.end
+	}
+	builder.WriteString(html.EscapeString(string(runes[position:end])))
+	if end < len(runes) {
+		builder.WriteString("…")
+	}
+	return builder.String()
+}
+
+type wordSpan struct {
+	start int
+	end   int
+}
+
+func wordSpans(runes []rune) []wordSpan {
+	spans := []wordSpan{}
+	start := -1
+	for i, r := range runes {
+		if isNotWordRune(r) {
+			if start >= 0 {
+				spans = append(spans, wordSpan{start: start, end: i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, wordSpan{start: start, end: len(runes)})
+	}
+	return spans
+}
+
+func countMatches(text string, terms []string) int {
+	count := 0
+	for _, word := range strings.FieldsFunc(text, isNotWordRune) {
+		if matchesTerm(word, terms) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchesTerm(word string, terms []string) bool {
+	word = strings.ToLower(word)
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,184 @@
+package infrastructure
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// the cache forgets every answer once it holds this many
+const MAX_REVOCATION_CACHE_ENTRIES = 10000
+
+// a token is revoked when its ID was revoked, or when the tokens of its user were revoked in or after
+// the second it was issued. token times only have a precision of one second, so a token issued in the
+// same second as a revocation of its user is revoked too.
+type TokenRevocationStore interface {
+	// the token can be forgotten after expiresAt, it is refused for having expired from then on
+	RevokeToken(cxt context.Context, tokenID string, expiresAt time.Time) error
+	RevokeUserTokens(cxt context.Context, username string, revokedAt time.Time) error
+	// tokens issued before token IDs existed have an empty tokenID and can only be revoked through their user
+	IsRevoked(cxt context.Context, tokenID string, username string, issuedAt time.Time) (bool, error)
+}
+
+// keeps revocations in the memory of a single server, they are lost when it stops
+type MemoryRevocationStore struct {
+	mutex  sync.RWMutex
+	tokens map[string]time.Time
+	users  map[string]time.Time
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens: map[string]time.Time{},
+		users:  map[string]time.Time{},
+	}
+}
+
+func (store *MemoryRevocationStore) RevokeToken(cxt context.Context, tokenID string, expiresAt time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	now := time.Now()
+	for revokedID, tokenExpiry := range store.tokens {
+		if tokenExpiry.Before(now) {
+			delete(store.tokens, revokedID)
+		}
+	}
+	store.tokens[tokenID] = expiresAt
+	return nil
+}
+
+func (store *MemoryRevocationStore) RevokeUserTokens(cxt context.Context, username string, revokedAt time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if revokedAt.After(store.users[username]) {
+		store.users[username] = revokedAt
+	}
+	return nil
+}
+
+func (store *MemoryRevocationStore) IsRevoked(cxt context.Context, tokenID string, username string, issuedAt time.Time) (bool, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	if _, revoked := store.tokens[tokenID]; revoked && tokenID != "" {
+		return true, nil
+	}
+	revokedAt, revoked := store.users[username]
+	return revoked && !revokedAt.Before(issuedAt), nil
+}
+
+// shares revocations between servers through a collection. revoked tokens are removed by the TTL
+// index on expires_at, the revocations of users are kept.
+type MongoRevocationStore struct {
+	Collection *mongo.Collection
+}
+
+func NewMongoRevocationStore(collection *mongo.Collection) MongoRevocationStore {
+	return MongoRevocationStore{
+		Collection: collection,
+	}
+}
+
+func (store MongoRevocationStore) RevokeToken(cxt context.Context, tokenID string, expiresAt time.Time) error {
+	update := bson.D{{"$set", bson.D{{"expires_at", expiresAt}}}}
+	_, err := store.Collection.UpdateOne(cxt, bson.D{{"_id", "token:" + tokenID}}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (store MongoRevocationStore) RevokeUserTokens(cxt context.Context, username string, revokedAt time.Time) error {
+	update := bson.D{{"$max", bson.D{{"revoked_at", revokedAt}}}}
+	_, err := store.Collection.UpdateOne(cxt, bson.D{{"_id", "user:" + username}}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (store MongoRevocationStore) IsRevoked(cxt context.Context, tokenID string, username string, issuedAt time.Time) (bool, error) {
+	revocations := bson.A{bson.D{{"_id", "user:" + username}, {"revoked_at", bson.D{{"$gte", issuedAt}}}}}
+	if tokenID != "" {
+		revocations = append(revocations, bson.D{{"_id", "token:" + tokenID}})
+	}
+	count, err := store.Collection.CountDocuments(cxt, bson.D{{"$or", revocations}}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// remembers the answers of a store for ttl so that most requests skip it. revocations made through
+// the cache take effect at once, those made by other servers once the cached answers run out.
+type CachedRevocationStore struct {
+	store   TokenRevocationStore
+	ttl     time.Duration
+	mutex   sync.Mutex
+	answers map[string]cachedRevocation
+	// bumped by every revocation, so that answers fetched before it are not cached after it
+	generation int
+}
+
+type cachedRevocation struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+func NewCachedRevocationStore(store TokenRevocationStore, ttl time.Duration) *CachedRevocationStore {
+	return &CachedRevocationStore{
+		store:   store,
+		ttl:     ttl,
+		answers: map[string]cachedRevocation{},
+	}
+}
+
+func (cache *CachedRevocationStore) RevokeToken(cxt context.Context, tokenID string, expiresAt time.Time) error {
+	defer cache.forget()
+	return cache.store.RevokeToken(cxt, tokenID, expiresAt)
+}
+
+func (cache *CachedRevocationStore) RevokeUserTokens(cxt context.Context, username string, revokedAt time.Time) error {
+	defer cache.forget()
+	return cache.store.RevokeUserTokens(cxt, username, revokedAt)
+}
+
+// failures of the store are not cached
+func (cache *CachedRevocationStore) IsRevoked(cxt context.Context, tokenID string, username string, issuedAt time.Time) (bool, error) {
+	key := tokenID + "\x00" + username + "\x00" + strconv.FormatInt(issuedAt.Unix(), 10)
+	now := time.Now()
+	cache.mutex.Lock()
+	answer, found := cache.answers[key]
+	generation := cache.generation
+	cache.mutex.Unlock()
+	if found && now.Before(answer.expiresAt) {
+		return answer.revoked, nil
+	}
+
+	revoked, err := cache.store.IsRevoked(cxt, tokenID, username, issuedAt)
+	if err != nil {
+		return false, err
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if generation != cache.generation {
+		return revoked, nil
+	}
+	if len(cache.answers) >= MAX_REVOCATION_CACHE_ENTRIES {
+		for cachedKey, cached := range cache.answers {
+			if !now.Before(cached.expiresAt) {
+				delete(cache.answers, cachedKey)
+			}
+		}
+		if len(cache.answers) >= MAX_REVOCATION_CACHE_ENTRIES {
+			cache.answers = map[string]cachedRevocation{}
+		}
+	}
+	cache.answers[key] = cachedRevocation{revoked: revoked, expiresAt: now.Add(cache.ttl)}
+	return revoked, nil
+}
+
+func (cache *CachedRevocationStore) forget() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.answers = map[string]cachedRevocation{}
+	cache.generation++
+}
@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// time-based one-time passwords of RFC 6238 with the defaults every authenticator app supports:
+// HMAC-SHA1, six digits and a new code every thirty seconds
+const (
+	TOTP_DIGITS = 6
+	TOTP_PERIOD = 30 * time.Second
+	// the codes of this many steps before and after the current one are accepted too, for clocks that drift
+	TOTP_SKEW_STEPS = 1
+	// 160 bits, the length of an HMAC-SHA1 key that RFC 4226 recommends
+	TOTP_SECRET_BYTES = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// a random secret in base32, as authenticator apps expect it
+func NewTOTPSecret() string {
+	secret := make([]byte, TOTP_SECRET_BYTES)
+	rand.Read(secret)
+	return totpEncoding.EncodeToString(secret)
+}
+
+// the otpauth URI that authenticator apps read from a QR code
+func TOTPURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(TOTP_DIGITS))
+	query.Set("period", strconv.Itoa(int(TOTP_PERIOD/time.Second)))
+	// some apps show a + in the issuer as it is, so spaces are escaped as in the path
+	return "otpauth://totp/" + url.PathEscape(issuer) + ":" + url.PathEscape(account) + "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// the number of periods since the Unix epoch at the time
+func TOTPStep(at time.Time) int64 {
+	return at.Unix() / int64(TOTP_PERIOD/time.Second)
+}
+
+// the code of the time step, as in RFC 4226 with the step as the counter
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("Invalid TOTP secret: %w", err)
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint32(1)
+	for i := 0; i < TOTP_DIGITS; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTP_DIGITS, value%modulus), nil
+}
+
+// the time step the code belongs to, when it is the code of a step around the time. callers
+// remember the step so that a code is never accepted twice.
+func MatchTOTP(secret string, code string, at time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != TOTP_DIGITS {
+		return 0, false
+	}
+	current := TOTPStep(at)
+	for step := current - TOTP_SKEW_STEPS; step <= current+TOTP_SKEW_STEPS; step++ {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
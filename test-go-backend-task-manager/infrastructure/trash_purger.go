@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// periodically purges the tasks and users that have been in the trash for longer than the retention
+type TrashPurger struct {
+	taskUsecase domain.TaskUsecase
+	userUsecase domain.UserUsecase
+	retention   time.Duration
+	interval    time.Duration
+	stop        chan struct{}
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewTrashPurger(taskUC domain.TaskUsecase, userUC domain.UserUsecase, retention time.Duration, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		taskUsecase: taskUC,
+		userUsecase: userUC,
+		retention:   retention,
+		interval:    interval,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// purges right away and then every interval until Stop is called or the context is cancelled
+func (purger *TrashPurger) Start(cxt context.Context) {
+	go runPeriodically(cxt, purger.interval, purger.stop, purger.done, purger.purge)
+}
+
+// stops the purger and waits for the running purge to finish, the purger must have been started
+func (purger *TrashPurger) Stop() {
+	purger.stopOnce.Do(func() { close(purger.stop) })
+	<-purger.done
+}
+
+func (purger *TrashPurger) purge(cxt context.Context) {
+	deletedBefore := time.Now().Add(-purger.retention)
+	purgedTasks, errTasks := purger.taskUsecase.PurgeDeletedTasks(cxt, deletedBefore)
+	if errTasks != nil {
+		log.Println("Error", "purging tasks", errTasks)
+	}
+	purgedUsers, errUsers := purger.userUsecase.PurgeDeletedUsers(cxt, deletedBefore)
+	if errUsers != nil {
+		log.Println("Error", "purging users", errUsers)
+	}
+	if purgedTasks+purgedUsers > 0 {
+		log.Println("Purged", purgedTasks, "tasks and", purgedUsers, "users from the trash")
+	}
+}
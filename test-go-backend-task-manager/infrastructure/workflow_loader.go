@@ -0,0 +1,21 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"os"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// reads a task workflow definition from a JSON file
+func LoadWorkflow(path string) (domain.Workflow, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return domain.Workflow{}, err
+	}
+	var workflow domain.Workflow
+	if err := json.Unmarshal(content, &workflow); err != nil {
+		return domain.Workflow{}, err
+	}
+	return workflow, nil
+}
@@ -0,0 +1,71 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// the audit log is append-only, there is deliberately no way to change or remove entries
+type AuditRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewAuditRepository(collection *mongo.Collection) AuditRepository {
+	// changed values are free form, decode nested documents as maps so they render as JSON objects
+	cloned, err := collection.Clone(options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true}))
+	if err == nil {
+		collection = cloned
+	}
+	return AuditRepository{
+		Collection: collection,
+	}
+}
+
+func (auditRepo *AuditRepository) InsertEntry(cxt context.Context, entry domain.AuditEntry) *domain.TaskError {
+	entry.ID = ""
+	entry.OrgID = stampOrg(cxt, entry.OrgID)
+	if _, err := auditRepo.Collection.InsertOne(cxt, entry); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+func (auditRepo *AuditRepository) FetchEntries(cxt context.Context, query domain.AuditQuery) ([]domain.AuditEntry, *domain.TaskError) {
+	filter := tenantScope(cxt, bson.D{})
+	if query.Actor != "" {
+		filter = append(filter, bson.E{"actor", query.Actor})
+	}
+	if query.TargetType != "" {
+		filter = append(filter, bson.E{"target_type", query.TargetType})
+	}
+	if query.TargetID != "" {
+		filter = append(filter, bson.E{"target", query.TargetID})
+	}
+	window := bson.D{}
+	if !query.Since.IsZero() {
+		window = append(window, bson.E{"$gte", query.Since})
+	}
+	if !query.Until.IsZero() {
+		window = append(window, bson.E{"$lt", query.Until})
+	}
+	if len(window) > 0 {
+		filter = append(filter, bson.E{"timestamp", window})
+	}
+	opts := options.Find().SetSort(bson.D{{"timestamp", -1}, {"_id", -1}}).SetLimit(int64(query.Limit))
+	cursor, err := auditRepo.Collection.Find(cxt, filter, opts)
+	if err != nil {
+		return []domain.AuditEntry{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	entries := []domain.AuditEntry{}
+	if err = cursor.All(cxt, &entries); err != nil {
+		return []domain.AuditEntry{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return entries, nil
+}
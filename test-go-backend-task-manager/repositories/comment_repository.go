@@ -0,0 +1,131 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewCommentRepository(collection *mongo.Collection) CommentRepository {
+	return CommentRepository{
+		Collection: collection,
+	}
+}
+
+// the comments of a task that have not been archived, oldest first
+func (commentRepo *CommentRepository) FetchComments(cxt context.Context, taskID string) ([]domain.Comment, *domain.TaskError) {
+	filter := bson.D{{"taskID", taskID}, {"archived", bson.D{{"$ne", true}}}}
+	opts := options.Find().SetSort(bson.D{{"created_at", 1}, {"_id", 1}})
+	cursor, err := commentRepo.Collection.Find(cxt, filter, opts)
+	if err != nil {
+		return []domain.Comment{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	comments := []domain.Comment{}
+	if err = cursor.All(cxt, &comments); err != nil {
+		return []domain.Comment{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return comments, nil
+}
+
+func (commentRepo *CommentRepository) FetchCommentByID(cxt context.Context, commentID string) (domain.Comment, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return domain.Comment{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	var comment domain.Comment
+	err = commentRepo.Collection.FindOne(cxt, bson.D{{"_id", objectID}, {"archived", bson.D{{"$ne", true}}}}).Decode(&comment)
+	if err == mongo.ErrNoDocuments {
+		return domain.Comment{}, &domain.TaskError{Message: "Comment not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.Comment{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return comment, nil
+}
+
+func (commentRepo *CommentRepository) CountReplies(cxt context.Context, commentID string) (int64, *domain.TaskError) {
+	count, err := commentRepo.Collection.CountDocuments(cxt, bson.D{{"parentID", commentID}})
+	if err != nil {
+		return 0, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return count, nil
+}
+
+func (commentRepo *CommentRepository) CreateComment(cxt context.Context, comment domain.Comment) (string, *domain.TaskError) {
+	comment.ID = ""
+	inserted, err := commentRepo.Collection.InsertOne(cxt, comment)
+	if err != nil {
+		return "", &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	objectID, ok := inserted.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", &domain.TaskError{Message: "Invalid inserted ID", Code: http.StatusInternalServerError}
+	}
+	return objectID.Hex(), nil
+}
+
+// replaces the body and appends the previous one to the history
+func (commentRepo *CommentRepository) UpdateCommentBody(cxt context.Context, commentID string, body string, previous domain.CommentRevision) (domain.Comment, *domain.TaskError) {
+	update := bson.D{
+		{"$set", bson.D{{"body", body}, {"updated_at", previous.EditedAt}}},
+		{"$push", bson.D{{"history", previous}}},
+	}
+	return commentRepo.updateComment(cxt, commentID, update)
+}
+
+// clears the body and history of a comment that has to stay in its thread
+func (commentRepo *CommentRepository) MarkCommentDeleted(cxt context.Context, commentID string) (domain.Comment, *domain.TaskError) {
+	update := bson.D{
+		{"$set", bson.D{{"body", ""}, {"deleted", true}, {"updated_at", time.Now()}}},
+		{"$unset", bson.D{{"history", ""}}},
+	}
+	return commentRepo.updateComment(cxt, commentID, update)
+}
+
+func (commentRepo *CommentRepository) DeleteComment(cxt context.Context, commentID string) *domain.TaskError {
+	objectID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	if _, err = commentRepo.Collection.DeleteOne(cxt, bson.D{{"_id", objectID}}); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+func (commentRepo *CommentRepository) ArchiveTaskComments(cxt context.Context, taskID string) *domain.TaskError {
+	update := bson.D{{"$set", bson.D{{"archived", true}}}}
+	if _, err := commentRepo.Collection.UpdateMany(cxt, bson.D{{"taskID", taskID}}, update); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+func (commentRepo *CommentRepository) updateComment(cxt context.Context, commentID string, update bson.D) (domain.Comment, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return domain.Comment{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var comment domain.Comment
+	err = commentRepo.Collection.FindOneAndUpdate(cxt, bson.D{{"_id", objectID}}, update, opts).Decode(&comment)
+	if err == mongo.ErrNoDocuments {
+		return domain.Comment{}, &domain.TaskError{Message: "Comment not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.Comment{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return comment, nil
+}
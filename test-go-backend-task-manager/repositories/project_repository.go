@@ -0,0 +1,139 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProjectRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewProjectRepository(collection *mongo.Collection) ProjectRepository {
+	return ProjectRepository{
+		Collection: collection,
+	}
+}
+
+// the projects the user is a member of by name, every project when memberID is empty
+func (projectRepo *ProjectRepository) FetchProjects(cxt context.Context, memberID string) ([]domain.Project, *domain.TaskError) {
+	filter := bson.D{}
+	if memberID != "" {
+		filter = bson.D{{"members.userID", memberID}}
+	}
+	opts := options.Find().SetSort(bson.D{{"name", 1}, {"_id", 1}})
+	cursor, err := projectRepo.Collection.Find(cxt, tenantScope(cxt, filter), opts)
+	if err != nil {
+		return []domain.Project{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	projects := []domain.Project{}
+	if err = cursor.All(cxt, &projects); err != nil {
+		return []domain.Project{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return projects, nil
+}
+
+func (projectRepo *ProjectRepository) FetchProjectByID(cxt context.Context, ID string) (domain.Project, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return domain.Project{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	var project domain.Project
+	err = projectRepo.Collection.FindOne(cxt, tenantScope(cxt, bson.D{{"_id", objectID}})).Decode(&project)
+	if err == mongo.ErrNoDocuments {
+		return domain.Project{}, &domain.TaskError{Message: "Project not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.Project{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return project, nil
+}
+
+func (projectRepo *ProjectRepository) CreateProject(cxt context.Context, project domain.Project) (string, *domain.TaskError) {
+	project.ID = ""
+	project.OrgID = stampOrg(cxt, project.OrgID)
+	inserted, err := projectRepo.Collection.InsertOne(cxt, project)
+	if err != nil {
+		return "", &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	objectID, ok := inserted.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", &domain.TaskError{Message: "Invalid inserted ID", Code: http.StatusInternalServerError}
+	}
+	return objectID.Hex(), nil
+}
+
+// replaces the name and description, the members are changed through SetMember and RemoveMember
+func (projectRepo *ProjectRepository) UpdateProject(cxt context.Context, project domain.Project) (domain.Project, *domain.TaskError) {
+	update := bson.D{{"$set", bson.D{
+		{"name", project.Name},
+		{"description", project.Description},
+		{"updated_at", time.Now()},
+	}}}
+	return projectRepo.updateProject(cxt, project.ID, bson.D{}, update)
+}
+
+// adds the member, or changes the role of the user when they already are one
+func (projectRepo *ProjectRepository) SetMember(cxt context.Context, projectID string, member domain.ProjectMember) (domain.Project, *domain.TaskError) {
+	update := bson.D{{"$set", bson.D{{"members.$.role", member.Role}, {"updated_at", time.Now()}}}}
+	project, errUpdate := projectRepo.updateProject(cxt, projectID, bson.D{{"members.userID", member.UserID}}, update)
+	if errUpdate == nil || errUpdate.Code != http.StatusNotFound {
+		return project, errUpdate
+	}
+	// the filter on members keeps a concurrent SetMember for the same user from adding them twice
+	update = bson.D{
+		{"$push", bson.D{{"members", member}}},
+		{"$set", bson.D{{"updated_at", time.Now()}}},
+	}
+	return projectRepo.updateProject(cxt, projectID, bson.D{{"members.userID", bson.D{{"$ne", member.UserID}}}}, update)
+}
+
+func (projectRepo *ProjectRepository) RemoveMember(cxt context.Context, projectID string, userID string) (domain.Project, *domain.TaskError) {
+	update := bson.D{
+		{"$pull", bson.D{{"members", bson.D{{"userID", userID}}}}},
+		{"$set", bson.D{{"updated_at", time.Now()}}},
+	}
+	return projectRepo.updateProject(cxt, projectID, bson.D{}, update)
+}
+
+func (projectRepo *ProjectRepository) DeleteProject(cxt context.Context, ID string) *domain.TaskError {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	result, err := projectRepo.Collection.DeleteOne(cxt, tenantScope(cxt, bson.D{{"_id", objectID}}))
+	if err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	if result.DeletedCount == 0 {
+		return &domain.TaskError{Message: "Project not found", Code: http.StatusNotFound}
+	}
+	return nil
+}
+
+// applies the update to the project when it also matches filter, and returns the updated project
+func (projectRepo *ProjectRepository) updateProject(cxt context.Context, projectID string, filter bson.D, update bson.D) (domain.Project, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return domain.Project{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var project domain.Project
+	err = projectRepo.Collection.FindOneAndUpdate(cxt, tenantScope(cxt, append(bson.D{{"_id", objectID}}, filter...)), update, opts).Decode(&project)
+	if err == mongo.ErrNoDocuments {
+		return domain.Project{}, &domain.TaskError{Message: "Project not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.Project{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return project, nil
+}
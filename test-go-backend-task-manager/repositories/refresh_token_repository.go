@@ -0,0 +1,70 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refresh tokens are presented without an access token, so they are looked up in every organization
+type RefreshTokenRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(collection *mongo.Collection) RefreshTokenRepository {
+	return RefreshTokenRepository{
+		Collection: collection,
+	}
+}
+
+func (tokenRepo *RefreshTokenRepository) CreateRefreshToken(cxt context.Context, token domain.RefreshToken) *domain.UserError {
+	if _, err := tokenRepo.Collection.InsertOne(cxt, token); err != nil {
+		return &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+func (tokenRepo *RefreshTokenRepository) FetchRefreshToken(cxt context.Context, ID string) (domain.RefreshToken, *domain.UserError) {
+	var token domain.RefreshToken
+	err := tokenRepo.Collection.FindOne(cxt, bson.D{{"_id", ID}}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return domain.RefreshToken{}, &domain.UserError{Message: "Refresh token not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.RefreshToken{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return token, nil
+}
+
+// of two requests presenting the same token only one can consume it, the other finds it used
+func (tokenRepo *RefreshTokenRepository) ConsumeRefreshToken(cxt context.Context, ID string, usedAt time.Time) (domain.RefreshToken, *domain.UserError) {
+	filter := bson.D{
+		{"_id", ID},
+		{"used_at", bson.D{{"$exists", false}}},
+		{"revoked_at", bson.D{{"$exists", false}}},
+		{"expires_at", bson.D{{"$gt", usedAt}}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var token domain.RefreshToken
+	err := tokenRepo.Collection.FindOneAndUpdate(cxt, filter, bson.D{{"$set", bson.D{{"used_at", usedAt}}}}, opts).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return domain.RefreshToken{}, &domain.UserError{Message: "Refresh token not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.RefreshToken{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return token, nil
+}
+
+func (tokenRepo *RefreshTokenRepository) RevokeTokenFamily(cxt context.Context, familyID string, revokedAt time.Time) *domain.UserError {
+	filter := bson.D{{"familyID", familyID}, {"revoked_at", bson.D{{"$exists", false}}}}
+	if _, err := tokenRepo.Collection.UpdateMany(cxt, filter, bson.D{{"$set", bson.D{{"revoked_at", revokedAt}}}}); err != nil {
+		return &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
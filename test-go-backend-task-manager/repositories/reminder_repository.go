@@ -0,0 +1,65 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReminderRepository struct {
+	Preferences *mongo.Collection
+	Reminders   *mongo.Collection
+}
+
+func NewReminderRepository(preferences *mongo.Collection, reminders *mongo.Collection) ReminderRepository {
+	return ReminderRepository{
+		Preferences: preferences,
+		Reminders:   reminders,
+	}
+}
+
+func (reminderRepo *ReminderRepository) FetchPreferences(cxt context.Context, userIDs []string) ([]domain.ReminderPreference, *domain.TaskError) {
+	cursor, err := reminderRepo.Preferences.Find(cxt, bson.D{{"_id", bson.D{{"$in", userIDs}}}})
+	if err != nil {
+		return []domain.ReminderPreference{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	preferences := []domain.ReminderPreference{}
+	if err = cursor.All(cxt, &preferences); err != nil {
+		return []domain.ReminderPreference{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return preferences, nil
+}
+
+func (reminderRepo *ReminderRepository) UpsertPreference(cxt context.Context, preference domain.ReminderPreference) (domain.ReminderPreference, *domain.TaskError) {
+	filter := bson.D{{"_id", preference.UserID}}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := reminderRepo.Preferences.ReplaceOne(cxt, filter, preference, opts); err != nil {
+		return domain.ReminderPreference{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return preference, nil
+}
+
+// records the reminder and reports whether it was new, the ID is unique so concurrent scans send it once
+func (reminderRepo *ReminderRepository) MarkReminderSent(cxt context.Context, reminder domain.Reminder) (bool, *domain.TaskError) {
+	_, err := reminderRepo.Reminders.InsertOne(cxt, reminder)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return true, nil
+}
+
+func (reminderRepo *ReminderRepository) UnmarkReminder(cxt context.Context, reminderID string) *domain.TaskError {
+	if _, err := reminderRepo.Reminders.DeleteOne(cxt, bson.D{{"_id", reminderID}}); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repositorie
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RevisionRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewRevisionRepository(collection *mongo.Collection) RevisionRepository {
+	return RevisionRepository{
+		Collection: collection,
+	}
+}
+
+// revisions are keyed by task and number so the same revision can never be stored twice
+func (revisionRepo *RevisionRepository) CreateRevision(cxt context.Context, revision domain.TaskRevision) *domain.TaskError {
+	revision.ID = revisionID(revision.TaskID, revision.Number)
+	revision.OrgID = stampOrg(cxt, revision.OrgID)
+	_, err := revisionRepo.Collection.InsertOne(cxt, revision)
+	if mongo.IsDuplicateKeyError(err) {
+		return &domain.TaskError{Message: fmt.Sprintf("Revision %d already exists", revision.Number), Code: http.StatusConflict}
+	}
+	if err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+// the revisions of the task, newest first
+func (revisionRepo *RevisionRepository) FetchRevisions(cxt context.Context, taskID string) ([]domain.TaskRevision, *domain.TaskError) {
+	opts := options.Find().SetSort(bson.D{{"revision", -1}})
+	cursor, err := revisionRepo.Collection.Find(cxt, tenantScope(cxt, bson.D{{"taskID", taskID}}), opts)
+	if err != nil {
+		return []domain.TaskRevision{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	revisions := []domain.TaskRevision{}
+	if err = cursor.All(cxt, &revisions); err != nil {
+		return []domain.TaskRevision{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return revisions, nil
+}
+
+func (revisionRepo *RevisionRepository) FetchRevision(cxt context.Context, taskID string, number int) (domain.TaskRevision, *domain.TaskError) {
+	var revision domain.TaskRevision
+	err := revisionRepo.Collection.FindOne(cxt, tenantScope(cxt, bson.D{{"_id", revisionID(taskID, number)}})).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return domain.TaskRevision{}, &domain.TaskError{Message: fmt.Sprintf("Revision %d not found", number), Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.TaskRevision{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return revision, nil
+}
+
+func (revisionRepo *RevisionRepository) DeleteRevisions(cxt context.Context, taskID string) *domain.TaskError {
+	if _, err := revisionRepo.Collection.DeleteMany(cxt, tenantScope(cxt, bson.D{{"taskID", taskID}})); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+func revisionID(taskID string, number int) string {
+	return fmt.Sprintf("%s:%d", taskID, number)
+}
@@ -2,9 +2,17 @@ package repositorie
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
+	"regexp"
+	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,12 +22,34 @@ import (
 // locally used types
 const (
 	CREATED_SUCCESSFULLY = "task created successfully"
+	INDEX_NOT_FOUND_CODE = 27
+	MAX_SUBTREE_DEPTH    = 64
 )
 
 type TaskRepository struct {
 	Collection *mongo.Collection
 }
 
+// cursor used for paging through FetchTasks results, handed to clients as an opaque token
+type taskCursor struct {
+	SortBy    string      `json:"s,omitempty"`
+	SortOrder int         `json:"o"`
+	Value     interface{} `json:"v"`
+	ID        string      `json:"id"`
+}
+
+// task decoded together with its text search score
+type scoredTask struct {
+	domain.Task `bson:",inline"`
+	Score       float64 `bson:"score"`
+}
+
+var taskTimeFields = map[string]bool{
+	"due_date":   true,
+	"created_at": true,
+	"updated_at": true,
+}
+
 // creating a task repository instance
 
 func NewTaskRepository(collection *mongo.Collection) TaskRepository {
@@ -27,7 +57,7 @@ func NewTaskRepository(collection *mongo.Collection) TaskRepository {
 }
 
 func (taskRepo *TaskRepository) FetchAllTasks(cxt context.Context) ([]domain.Task, *domain.TaskError) {
-	filter := bson.D{}
+	filter := taskScope(cxt, bson.D{})
 	cursor, err := taskRepo.Collection.Find(cxt, filter)
 	if err != nil {
 		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
@@ -42,23 +72,298 @@ func (taskRepo *TaskRepository) FetchAllTasks(cxt context.Context) ([]domain.Tas
 	return fetchedTasks, nil
 }
 
+func (taskRepo *TaskRepository) FetchTasks(cxt context.Context, query domain.TaskQuery) (domain.TaskPage, *domain.TaskError) {
+	filter, errFilter := buildTaskFilter(query)
+	if errFilter != nil {
+		return domain.TaskPage{}, errFilter
+	}
+	filter = taskScope(cxt, filter)
+	sort := bson.D{{"_id", query.SortOrder}}
+	if query.SortBy != "" {
+		sort = bson.D{{query.SortBy, query.SortOrder}, {"_id", query.SortOrder}}
+	}
+	opts := options.Find().SetSort(sort).SetLimit(int64(query.Limit + 1))
+	cursor, err := taskRepo.Collection.Find(cxt, filter, opts)
+	if err != nil {
+		return domain.TaskPage{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	fetchedTasks := []domain.Task{}
+	if err = cursor.All(cxt, &fetchedTasks); err != nil {
+		return domain.TaskPage{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+
+	page := domain.TaskPage{Tasks: fetchedTasks, Limit: query.Limit}
+	if len(fetchedTasks) > query.Limit {
+		page.Tasks = fetchedTasks[:query.Limit]
+		last := page.Tasks[query.Limit-1]
+		nextCursor, errCursor := encodeTaskCursor(taskCursor{
+			SortBy:    query.SortBy,
+			SortOrder: query.SortOrder,
+			Value:     taskSortValue(last, query.SortBy),
+			ID:        last.ID,
+		})
+		if errCursor != nil {
+			return domain.TaskPage{}, errCursor
+		}
+		page.NextCursor = nextCursor
+	}
+	return page, nil
+}
+
+func (taskRepo *TaskRepository) SearchTasks(cxt context.Context, query string, limit int) ([]domain.TaskSearchResult, *domain.TaskError) {
+	filter := taskScope(cxt, bson.D{{"$text", bson.D{{"$search", query}}}})
+	score := bson.D{{"score", bson.D{{"$meta", "textScore"}}}}
+	opts := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(limit))
+	cursor, err := taskRepo.Collection.Find(cxt, filter, opts)
+	if err != nil {
+		if isIndexNotFound(err) {
+			return taskRepo.searchTasksWithoutIndex(cxt, query, limit)
+		}
+		return []domain.TaskSearchResult{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	var fetchedTasks []scoredTask
+	if err = cursor.All(cxt, &fetchedTasks); err != nil {
+		if isIndexNotFound(err) {
+			return taskRepo.searchTasksWithoutIndex(cxt, query, limit)
+		}
+		return []domain.TaskSearchResult{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+
+	terms := infrastructure.SearchTerms(query)
+	results := []domain.TaskSearchResult{}
+	for _, fetched := range fetchedTasks {
+		results = append(results, domain.TaskSearchResult{
+			Task:       fetched.Task,
+			Score:      fetched.Score,
+			Highlights: infrastructure.HighlightTask(fetched.Task, terms),
+		})
+	}
+	return results, nil
+}
+
+// fallback for collections without the text index, finds the candidates with a regex scan for the terms.
+// the phrases and exclusions are applied in process along with the ranking.
+func (taskRepo *TaskRepository) searchTasksWithoutIndex(cxt context.Context, query string, limit int) ([]domain.TaskSearchResult, *domain.TaskError) {
+	terms := infrastructure.SearchTerms(query)
+	if len(terms) == 0 {
+		return []domain.TaskSearchResult{}, nil
+	}
+	matchers := bson.A{}
+	for _, term := range terms {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(term), Options: "i"}
+		matchers = append(matchers, bson.D{{"title", pattern}}, bson.D{{"description", pattern}})
+	}
+	cursor, err := taskRepo.Collection.Find(cxt, taskScope(cxt, bson.D{{"$or", matchers}}))
+	if err != nil {
+		return []domain.TaskSearchResult{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	var candidates []domain.Task
+	if err = cursor.All(cxt, &candidates); err != nil {
+		return []domain.TaskSearchResult{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return infrastructure.SearchTasksInMemory(candidates, query, limit), nil
+}
+
+func (taskRepo *TaskRepository) FetchChildTasks(cxt context.Context, parentID string) ([]domain.Task, *domain.TaskError) {
+	cursor, err := taskRepo.Collection.Find(cxt, taskScope(cxt, bson.D{{"parentID", parentID}}))
+	if err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	children := []domain.Task{}
+	if err = cursor.All(cxt, &children); err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return children, nil
+}
+
+// fetches the root task followed by all of its descendants, one query per level of the hierarchy
+func (taskRepo *TaskRepository) FetchSubtree(cxt context.Context, rootID string) ([]domain.Task, *domain.TaskError) {
+	root, errRoot := taskRepo.FetchTaskByID(cxt, rootID)
+	if errRoot != nil {
+		return []domain.Task{}, errRoot
+	}
+	subtree := []domain.Task{root}
+	visited := map[string]bool{root.ID: true}
+	frontier := []string{root.ID}
+	for depth := 0; len(frontier) > 0; depth++ {
+		if depth == MAX_SUBTREE_DEPTH {
+			return []domain.Task{}, &domain.TaskError{Message: "Task hierarchy is too deep", Code: http.StatusInternalServerError}
+		}
+		cursor, err := taskRepo.Collection.Find(cxt, taskScope(cxt, bson.D{{"parentID", bson.D{{"$in", frontier}}}}))
+		if err != nil {
+			return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+		}
+		var level []domain.Task
+		err = cursor.All(cxt, &level)
+		cursor.Close(cxt)
+		if err != nil {
+			return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+		}
+
+		frontier = []string{}
+		for _, task := range level {
+			if visited[task.ID] {
+				continue
+			}
+			visited[task.ID] = true
+			subtree = append(subtree, task)
+			frontier = append(frontier, task.ID)
+		}
+	}
+	return subtree, nil
+}
+
+func (taskRepo *TaskRepository) FetchTasksByIDs(cxt context.Context, IDs []string) ([]domain.Task, *domain.TaskError) {
+	objectIDs := bson.A{}
+	for _, ID := range IDs {
+		objectID, err := primitive.ObjectIDFromHex(ID)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	if len(objectIDs) == 0 {
+		return []domain.Task{}, nil
+	}
+	cursor, err := taskRepo.Collection.Find(cxt, taskScope(cxt, bson.D{{"_id", bson.D{{"$in", objectIDs}}}}))
+	if err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	fetchedTasks := []domain.Task{}
+	if err = cursor.All(cxt, &fetchedTasks); err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return fetchedTasks, nil
+}
+
+// the tasks with only their ID and blockedBy list, in any project of the organization and in the
+// trash too. cycles are checked on these, a task the caller cannot see may still close one.
+func (taskRepo *TaskRepository) FetchDependencies(cxt context.Context, IDs []string) ([]domain.Task, *domain.TaskError) {
+	objectIDs := bson.A{}
+	for _, ID := range IDs {
+		objectID, err := primitive.ObjectIDFromHex(ID)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	if len(objectIDs) == 0 {
+		return []domain.Task{}, nil
+	}
+	opts := options.Find().SetProjection(bson.D{{"blockedBy", 1}})
+	cursor, err := taskRepo.Collection.Find(cxt, tenantScope(cxt, bson.D{{"_id", bson.D{{"$in", objectIDs}}}}), opts)
+	if err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	fetchedTasks := []domain.Task{}
+	if err = cursor.All(cxt, &fetchedTasks); err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return fetchedTasks, nil
+}
+
+func (taskRepo *TaskRepository) AddDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$addToSet", bson.D{{"blockedBy", blockerID}}}})
+}
+
+func (taskRepo *TaskRepository) RemoveDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$pull", bson.D{{"blockedBy", blockerID}}}})
+}
+
+func (taskRepo *TaskRepository) AddAssignee(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$addToSet", bson.D{{"assignees", userID}}}})
+}
+
+func (taskRepo *TaskRepository) RemoveAssignee(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$pull", bson.D{{"assignees", userID}}}})
+}
+
+func (taskRepo *TaskRepository) AddWatcher(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$addToSet", bson.D{{"watchers", userID}}}})
+}
+
+func (taskRepo *TaskRepository) RemoveWatcher(cxt context.Context, taskID string, userID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$pull", bson.D{{"watchers", userID}}}})
+}
+
+func (taskRepo *TaskRepository) findAndUpdateTask(cxt context.Context, taskID string, update bson.D) (domain.Task, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	update = append(update, bson.E{"$inc", bson.D{{"version", 1}}})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var returnedTask domain.Task
+	err = taskRepo.Collection.FindOneAndUpdate(cxt, taskScope(cxt, bson.D{{"_id", objectID}}), update, opts).Decode(&returnedTask)
+	if err != nil {
+		return domain.Task{}, taskLookupError(err)
+	}
+	return returnedTask, nil
+}
+
+func (taskRepo *TaskRepository) AddAttachment(cxt context.Context, taskID string, attachment domain.Attachment) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$push", bson.D{{"attachments", attachment}}}})
+}
+
+func (taskRepo *TaskRepository) RemoveAttachment(cxt context.Context, taskID string, attachmentID string) (domain.Task, *domain.TaskError) {
+	return taskRepo.findAndUpdateTask(cxt, taskID, bson.D{{"$pull", bson.D{{"attachments", bson.D{{"id", attachmentID}}}}}})
+}
+
+// removes the blocker from every task that depends on it, including those in the trash
+func (taskRepo *TaskRepository) UnlinkDependents(cxt context.Context, blockerID string) *domain.TaskError {
+	filter := tenantScope(cxt, bson.D{{"blockedBy", blockerID}})
+	update := bson.D{{"$pull", bson.D{{"blockedBy", blockerID}}}, {"$inc", bson.D{{"version", 1}}}}
+	if _, err := taskRepo.Collection.UpdateMany(cxt, filter, update); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
 func (taskRepo *TaskRepository) FetchTaskByID(cxt context.Context, ID string) (domain.Task, *domain.TaskError) {
 	taskID, err := primitive.ObjectIDFromHex(ID)
 	if err != nil {
-		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
 	}
 
-	filter := bson.D{{"_id", taskID}}
+	filter := taskScope(cxt, bson.D{{"_id", taskID}})
 	var fetchedTask domain.Task
 	err = taskRepo.Collection.FindOne(cxt, filter).Decode(&fetchedTask)
 	if err != nil {
-		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+		return domain.Task{}, taskLookupError(err)
 	}
 	return fetchedTask, nil
 }
 
+// a task that is missing, in the trash or out of the request's scope is not found,
+// any other failure is the server's
+func taskLookupError(err error) *domain.TaskError {
+	if err == mongo.ErrNoDocuments {
+		return &domain.TaskError{Message: "Task not found", Code: http.StatusNotFound}
+	}
+	return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+}
+
 func (taskRepo *TaskRepository) CreateTask(cxt context.Context, newTask domain.Task) (string, *domain.TaskError) {
 	newTask.ID = ""
+	newTask.DeletedAt = time.Time{}
+	newTask.OrgID = stampOrg(cxt, newTask.OrgID)
+	if projectID := contextProject(cxt); projectID != "" {
+		newTask.ProjectID = projectID
+	}
+	newTask.Revision = 1
+	newTask.Version = 1
 	insertedTask, err := taskRepo.Collection.InsertOne(cxt, newTask)
 	if err != nil {
 		return "", &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
@@ -75,35 +380,570 @@ func (taskRepo *TaskRepository) UpdateTask(cxt context.Context, updateTask domai
 	if err != nil {
 		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
 	}
-	filter := bson.D{{"_id", objectID}}
+	filter := taskScope(cxt, bson.D{{"_id", objectID}})
+	// a task at any other version has been changed since the caller read it
+	if updateTask.Version != 0 {
+		filter = append(filter, bson.E{"version", updateTask.Version})
+	}
 	opts := options.FindOneAndUpdate().SetUpsert(false).SetReturnDocument(options.After)
 	inserteTask := domain.Task{
-		Title:       updateTask.Title,
-		Description: updateTask.Description,
-		Status:      updateTask.Status,
-		DueDate:     updateTask.DueDate,
-		UserID:      updateTask.UserID,
-		Priority:    updateTask.Priority,
-	}
-	update := bson.D{{"$set", inserteTask}}
+		Title:            updateTask.Title,
+		Description:      updateTask.Description,
+		Status:           updateTask.Status,
+		DueDate:          updateTask.DueDate,
+		UserID:           updateTask.UserID,
+		ParentID:         updateTask.ParentID,
+		Priority:         updateTask.Priority,
+		Recurrence:       updateTask.Recurrence,
+		RecurrenceStart:  updateTask.RecurrenceStart,
+		NextOccurrenceID: updateTask.NextOccurrenceID,
+		Estimate:         updateTask.Estimate,
+		EstimateUnit:     updateTask.EstimateUnit,
+		CompletedAt:      updateTask.CompletedAt,
+	}
+	update := bson.D{{"$set", inserteTask}, {"$inc", bson.D{{"revision", 1}, {"version", 1}}}}
+	// a status without a completion time is not a final one
+	if updateTask.Status != "" && updateTask.CompletedAt.IsZero() {
+		update = append(update, bson.E{"$unset", bson.D{{"completed_at", ""}}})
+	}
 	var returnedtask domain.Task
 	err = taskRepo.Collection.FindOneAndUpdate(cxt, filter, update, opts).Decode(&returnedtask)
+	if err == mongo.ErrNoDocuments && updateTask.Version != 0 {
+		return taskRepo.versionConflict(cxt, objectID)
+	}
 	if err != nil {
 		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
 	return returnedtask, nil
 }
 
-func (taskRepo *TaskRepository) DeleteTask(cxt context.Context, ID string) (domain.Task, *domain.TaskError) {
+// links the next occurrence from the task, only when the task has none yet. a task linked
+// already answers 409 Conflict.
+func (taskRepo *TaskRepository) LinkNextOccurrence(cxt context.Context, taskID string, nextID string) (domain.Task, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	filter := taskScope(cxt, bson.D{{"_id", objectID}, {"nextOccurrenceID", bson.D{{"$exists", false}}}})
+	update := bson.D{{"$set", bson.D{{"nextOccurrenceID", nextID}}}, {"$inc", bson.D{{"version", 1}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var linkedTask domain.Task
+	err = taskRepo.Collection.FindOneAndUpdate(cxt, filter, update, opts).Decode(&linkedTask)
+	if err == mongo.ErrNoDocuments {
+		return domain.Task{}, &domain.TaskError{Message: "Task already has a next occurrence", Code: http.StatusConflict}
+	}
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return linkedTask, nil
+}
+
+// overwrites the editable fields of the task with those of the given one, unlike UpdateTask
+// empty fields are cleared. the revision is incremented like on any other update.
+func (taskRepo *TaskRepository) RestoreTask(cxt context.Context, task domain.Task) (domain.Task, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(task.ID)
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	update, errFields := taskFieldsUpdate(task, []string{"userID", "title", "parentID", "tags", "description", "status", "priority", "due_date", "recurrence", "recurrence_start", "estimate", "estimate_unit", "completed_at"})
+	if errFields != nil {
+		return domain.Task{}, errFields
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var restoredTask domain.Task
+	err = taskRepo.Collection.FindOneAndUpdate(cxt, taskScope(cxt, bson.D{{"_id", objectID}}), update, opts).Decode(&restoredTask)
+	if err != nil {
+		return domain.Task{}, taskLookupError(err)
+	}
+	return restoredTask, nil
+}
+
+// writes only the named fields of the task, when the task has a version only if it is still at that version
+func (taskRepo *TaskRepository) PatchTask(cxt context.Context, task domain.Task, fields []string) (domain.Task, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(task.ID)
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	update, errFields := taskFieldsUpdate(task, fields)
+	if errFields != nil {
+		return domain.Task{}, errFields
+	}
+	filter := taskScope(cxt, bson.D{{"_id", objectID}})
+	if task.Version != 0 {
+		filter = append(filter, bson.E{"version", task.Version})
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var patchedTask domain.Task
+	err = taskRepo.Collection.FindOneAndUpdate(cxt, filter, update, opts).Decode(&patchedTask)
+	if err == mongo.ErrNoDocuments && task.Version != 0 {
+		return taskRepo.versionConflict(cxt, objectID)
+	}
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return patchedTask, nil
+}
+
+// an update that sets the named fields to their values on the task and unsets the empty ones,
+// the revision and version are incremented like on any other update
+func taskFieldsUpdate(task domain.Task, fields []string) (bson.D, *domain.TaskError) {
+	values := map[string]interface{}{
+		"userID":           task.UserID,
+		"parentID":         task.ParentID,
+		"tags":             task.Tags,
+		"title":            task.Title,
+		"description":      task.Description,
+		"status":           task.Status,
+		"priority":         task.Priority,
+		"due_date":         task.DueDate,
+		"recurrence":       task.Recurrence,
+		"recurrence_start": task.RecurrenceStart,
+		"estimate":         task.Estimate,
+		"estimate_unit":    task.EstimateUnit,
+		"completed_at":     task.CompletedAt,
+	}
+	set, unset := bson.D{}, bson.D{}
+	for _, field := range fields {
+		value, ok := values[field]
+		if !ok {
+			return nil, &domain.TaskError{Message: "Field " + field + " cannot be written", Code: http.StatusInternalServerError}
+		}
+		if reflect.ValueOf(value).IsZero() {
+			unset = append(unset, bson.E{field, ""})
+		} else {
+			set = append(set, bson.E{field, value})
+		}
+	}
+	update := bson.D{{"$inc", bson.D{{"revision", 1}, {"version", 1}}}}
+	if len(set) > 0 {
+		update = append(update, bson.E{"$set", set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{"$unset", unset})
+	}
+	return update, nil
+}
+
+// moves the task to the trash, when expectedVersion is not zero only if the task is still at that version
+func (taskRepo *TaskRepository) DeleteTask(cxt context.Context, ID string, expectedVersion int) (domain.Task, *domain.TaskError) {
 	taskID, err := primitive.ObjectIDFromHex(ID)
 	if err != nil {
 		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
-	filter := bson.D{{"_id", taskID}}
+	filter := taskScope(cxt, bson.D{{"_id", taskID}})
+	if expectedVersion != 0 {
+		filter = append(filter, bson.E{"version", expectedVersion})
+	}
+	update := bson.D{{"$set", bson.D{{"deleted_at", time.Now()}}}, {"$inc", bson.D{{"version", 1}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var deletedTask domain.Task
-	err = taskRepo.Collection.FindOneAndDelete(cxt, filter).Decode(&deletedTask)
+	err = taskRepo.Collection.FindOneAndUpdate(cxt, filter, update, opts).Decode(&deletedTask)
+	if err == mongo.ErrNoDocuments && expectedVersion != 0 {
+		return taskRepo.versionConflict(cxt, taskID)
+	}
 	if err != nil {
 		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
 	return deletedTask, nil
 }
+
+// called when a write conditional on the version matched nothing, the task is either gone
+// or has moved on to another version in which case it is returned as it is now
+func (taskRepo *TaskRepository) versionConflict(cxt context.Context, objectID primitive.ObjectID) (domain.Task, *domain.TaskError) {
+	var currentTask domain.Task
+	err := taskRepo.Collection.FindOne(cxt, taskScope(cxt, bson.D{{"_id", objectID}})).Decode(&currentTask)
+	if err != nil {
+		return domain.Task{}, taskLookupError(err)
+	}
+	return currentTask, &domain.TaskError{Message: fmt.Sprintf("Task has been modified, it is now at version %d", currentTask.Version), Code: http.StatusPreconditionFailed}
+}
+
+// the tasks in the trash, most recently deleted first. a zero deletedBefore lists the whole trash.
+func (taskRepo *TaskRepository) FetchDeletedTasks(cxt context.Context, deletedBefore time.Time, limit int) ([]domain.Task, *domain.TaskError) {
+	opts := options.Find().SetSort(bson.D{{"deleted_at", -1}}).SetLimit(int64(limit))
+	cursor, err := taskRepo.Collection.Find(cxt, deletedFilter(cxt, deletedBefore), opts)
+	if err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	deletedTasks := []domain.Task{}
+	if err = cursor.All(cxt, &deletedTasks); err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return deletedTasks, nil
+}
+
+// takes the task out of the trash
+func (taskRepo *TaskRepository) UndeleteTask(cxt context.Context, ID string) (domain.Task, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	filter := append(deletedFilter(cxt, time.Time{}), bson.E{"_id", objectID})
+	update := bson.D{{"$unset", bson.D{{"deleted_at", ""}}}, {"$inc", bson.D{{"version", 1}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var restoredTask domain.Task
+	err = taskRepo.Collection.FindOneAndUpdate(cxt, filter, update, opts).Decode(&restoredTask)
+	if err == mongo.ErrNoDocuments {
+		return domain.Task{}, &domain.TaskError{Message: "Task not found in the trash", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return restoredTask, nil
+}
+
+// removes the task for good if it has been in the trash since before deletedBefore
+func (taskRepo *TaskRepository) PurgeTask(cxt context.Context, ID string, deletedBefore time.Time) *domain.TaskError {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	result, err := taskRepo.Collection.DeleteOne(cxt, append(deletedFilter(cxt, deletedBefore), bson.E{"_id", objectID}))
+	if err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	if result.DeletedCount == 0 {
+		return &domain.TaskError{Message: "Task not found in the trash", Code: http.StatusNotFound}
+	}
+	return nil
+}
+
+// records in the trash are only returned by the trash's own queries
+func notDeleted(cxt context.Context, filter bson.D) bson.D {
+	return tenantScope(cxt, append(filter, bson.E{"deleted_at", bson.D{{"$exists", false}}}))
+}
+
+// the tasks a request may reach: none in the trash, only those of the project when the request is
+// scoped to one, and only those outside of every project for callers that are not admins. requests
+// without a role, such as those of the schedulers, reach every task.
+func taskScope(cxt context.Context, filter bson.D) bson.D {
+	filter = notDeleted(cxt, filter)
+	if projectID := contextProject(cxt); projectID != "" {
+		return append(filter, bson.E{"projectID", projectID})
+	}
+	if role, _ := cxt.Value(infrastructure.CONTEXT_ROLE).(string); role != "" && role != "admin" {
+		return append(filter, bson.E{"projectID", bson.D{{"$exists", false}}})
+	}
+	return filter
+}
+
+func contextProject(cxt context.Context) string {
+	projectID, _ := cxt.Value(infrastructure.CONTEXT_PROJECT).(string)
+	return projectID
+}
+
+// the records in the trash, when deletedBefore is not zero only those deleted before it
+func deletedFilter(cxt context.Context, deletedBefore time.Time) bson.D {
+	if deletedBefore.IsZero() {
+		return tenantScope(cxt, bson.D{{"deleted_at", bson.D{{"$exists", true}}}})
+	}
+	return tenantScope(cxt, bson.D{{"deleted_at", bson.D{{"$lt", deletedBefore}}}})
+}
+
+func isIndexNotFound(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(INDEX_NOT_FOUND_CODE)
+}
+
+// helpers for FetchTasks
+
+func buildTaskFilter(query domain.TaskQuery) (bson.D, *domain.TaskError) {
+	filter := bson.D{}
+	if query.ProjectID != "" {
+		filter = append(filter, bson.E{"projectID", query.ProjectID})
+	}
+	if query.Status != "" {
+		filter = append(filter, bson.E{"status", query.Status})
+	}
+	if query.Priority != "" {
+		filter = append(filter, bson.E{"priority", query.Priority})
+	}
+	if query.UserID != "" {
+		filter = append(filter, bson.E{"userID", query.UserID})
+	}
+	if query.AssigneeID != "" {
+		filter = append(filter, bson.E{"assignees", query.AssigneeID})
+	}
+	dueRange := bson.D{}
+	if !query.DueAfter.IsZero() {
+		dueRange = append(dueRange, bson.E{"$gte", query.DueAfter})
+	}
+	if !query.DueBefore.IsZero() {
+		dueRange = append(dueRange, bson.E{"$lte", query.DueBefore})
+	}
+	if len(dueRange) > 0 {
+		filter = append(filter, bson.E{"due_date", dueRange})
+	}
+	tagFilter := bson.D{}
+	if len(query.AnyTags) > 0 {
+		tagFilter = append(tagFilter, bson.E{"$in", query.AnyTags})
+	}
+	if len(query.AllTags) > 0 {
+		tagFilter = append(tagFilter, bson.E{"$all", query.AllTags})
+	}
+	if len(tagFilter) > 0 {
+		filter = append(filter, bson.E{"tags", tagFilter})
+	}
+	if query.Cursor == "" {
+		return filter, nil
+	}
+
+	cursor, errCursor := decodeTaskCursor(query.Cursor)
+	if errCursor != nil {
+		return nil, errCursor
+	}
+	if cursor.SortBy != query.SortBy || cursor.SortOrder != query.SortOrder {
+		return nil, &domain.TaskError{Message: "Cursor does not match the requested sort", Code: http.StatusBadRequest}
+	}
+	afterFilter, errAfter := taskCursorFilter(cursor)
+	if errAfter != nil {
+		return nil, errAfter
+	}
+	return append(filter, afterFilter...), nil
+}
+
+// taskCursorFilter selects the documents that come after the cursor in the (sort field, _id) ordering.
+// Missing fields compare as null, which sorts before every other value.
+func taskCursorFilter(cursor taskCursor) (bson.D, *domain.TaskError) {
+	lastID, err := primitive.ObjectIDFromHex(cursor.ID)
+	if err != nil {
+		return nil, &domain.TaskError{Message: "Invalid cursor", Code: http.StatusBadRequest}
+	}
+	compare := "$gt"
+	if cursor.SortOrder < 0 {
+		compare = "$lt"
+	}
+	if cursor.SortBy == "" {
+		return bson.D{{"_id", bson.D{{compare, lastID}}}}, nil
+	}
+
+	field := cursor.SortBy
+	if cursor.Value == nil {
+		sameValue := bson.D{{field, nil}, {"_id", bson.D{{compare, lastID}}}}
+		if cursor.SortOrder < 0 {
+			return sameValue, nil
+		}
+		return bson.D{{"$or", bson.A{sameValue, bson.D{{field, bson.D{{"$ne", nil}}}}}}}, nil
+	}
+
+	value := cursor.Value
+	if taskTimeFields[field] {
+		raw, ok := cursor.Value.(string)
+		if !ok {
+			return nil, &domain.TaskError{Message: "Invalid cursor", Code: http.StatusBadRequest}
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, &domain.TaskError{Message: "Invalid cursor", Code: http.StatusBadRequest}
+		}
+		value = parsed
+	}
+	branches := bson.A{
+		bson.D{{field, bson.D{{compare, value}}}},
+		bson.D{{field, value}, {"_id", bson.D{{compare, lastID}}}},
+	}
+	if cursor.SortOrder < 0 {
+		branches = append(branches, bson.D{{field, nil}})
+	}
+	return bson.D{{"$or", branches}}, nil
+}
+
+// taskSortValue returns the cursor value of a sort field, nil standing for a field omitted from the document
+func taskSortValue(task domain.Task, field string) interface{} {
+	switch field {
+	case "due_date":
+		return cursorTime(task.DueDate)
+	case "created_at":
+		return cursorTime(task.CreatedAt)
+	case "updated_at":
+		return cursorTime(task.UpdatedAt)
+	case "title":
+		return task.Title
+	case "status":
+		return cursorString(task.Status)
+	case "priority":
+		return cursorString(task.Priority)
+	}
+	return nil
+}
+
+func cursorTime(value time.Time) interface{} {
+	if value.IsZero() {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+func cursorString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func encodeTaskCursor(cursor taskCursor) (string, *domain.TaskError) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeTaskCursor(token string) (taskCursor, *domain.TaskError) {
+	var cursor taskCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return taskCursor{}, &domain.TaskError{Message: "Invalid cursor", Code: http.StatusBadRequest}
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return taskCursor{}, &domain.TaskError{Message: "Invalid cursor", Code: http.StatusBadRequest}
+	}
+	return cursor, nil
+}
+
+// tasks due in (dueAfter, dueBefore] that are not in one of the excluded statuses, earliest first
+func (taskRepo *TaskRepository) FetchDueTasks(cxt context.Context, dueAfter time.Time, dueBefore time.Time, excludeStatuses []string) ([]domain.Task, *domain.TaskError) {
+	if excludeStatuses == nil {
+		excludeStatuses = []string{}
+	}
+	filter := taskScope(cxt, bson.D{
+		{"due_date", bson.D{{"$gt", dueAfter}, {"$lte", dueBefore}}},
+		{"status", bson.D{{"$nin", excludeStatuses}}},
+	})
+	opts := options.Find().SetSort(bson.D{{"due_date", 1}})
+	cursor, err := taskRepo.Collection.Find(cxt, filter, opts)
+	if err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	tasks := []domain.Task{}
+	if err = cursor.All(cxt, &tasks); err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return tasks, nil
+}
+
+// the tasks estimated in the unit of the query that were created before its end and not completed
+// before its start. tasks without a creation time were created when their ID was issued, they are
+// returned with that time. tasks in a final status without a completion time were completed before
+// completion times were recorded and are left out.
+func (taskRepo *TaskRepository) FetchEstimatedTasks(cxt context.Context, query domain.ReportQuery, finalStatuses []string) ([]domain.Task, *domain.TaskError) {
+	if finalStatuses == nil {
+		finalStatuses = []string{}
+	}
+	filter := bson.D{
+		{"estimate", bson.D{{"$gt", 0}}},
+		{"estimate_unit", query.Unit},
+		{"_id", bson.D{{"$lt", primitive.NewObjectIDFromTimestamp(query.To)}}},
+		{"$or", bson.A{
+			bson.D{{"completed_at", bson.D{{"$gte", query.From}}}},
+			bson.D{{"completed_at", bson.D{{"$exists", false}}}, {"status", bson.D{{"$nin", finalStatuses}}}},
+		}},
+	}
+	if query.ProjectID != "" {
+		filter = append(filter, bson.E{"projectID", query.ProjectID})
+	}
+	if query.Tag != "" {
+		filter = append(filter, bson.E{"tags", query.Tag})
+	}
+	if query.AssigneeID != "" {
+		filter = append(filter, bson.E{"assignees", query.AssigneeID})
+	}
+	cursor, err := taskRepo.Collection.Find(cxt, taskScope(cxt, filter))
+	if err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	tasks := []domain.Task{}
+	if err = cursor.All(cxt, &tasks); err != nil {
+		return []domain.Task{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	for i := range tasks {
+		if !tasks[i].CreatedAt.IsZero() {
+			continue
+		}
+		if objectID, err := primitive.ObjectIDFromHex(tasks[i].ID); err == nil {
+			tasks[i].CreatedAt = objectID.Timestamp()
+		}
+	}
+	return tasks, nil
+}
+
+// every tag in use with the number of tasks carrying it, most used first
+func (taskRepo *TaskRepository) FetchTagCounts(cxt context.Context) ([]domain.TagCount, *domain.TaskError) {
+	pipeline := mongo.Pipeline{
+		{{"$match", taskScope(cxt, bson.D{})}},
+		{{"$unwind", "$tags"}},
+		{{"$group", bson.D{{"_id", "$tags"}, {"count", bson.D{{"$sum", 1}}}}}},
+		{{"$sort", bson.D{{"count", -1}, {"_id", 1}}}},
+	}
+	cursor, err := taskRepo.Collection.Aggregate(cxt, pipeline)
+	if err != nil {
+		return []domain.TagCount{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	counts := []domain.TagCount{}
+	if err = cursor.All(cxt, &counts); err != nil {
+		return []domain.TagCount{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return counts, nil
+}
+
+// adds and removes tags on the given tasks and returns the number of tasks found
+func (taskRepo *TaskRepository) UpdateTaskTags(cxt context.Context, taskIDs []string, add []string, remove []string) (int64, *domain.TaskError) {
+	objectIDs := []primitive.ObjectID{}
+	for _, taskID := range taskIDs {
+		objectID, err := primitive.ObjectIDFromHex(taskID)
+		if err != nil {
+			return 0, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	filter := taskScope(cxt, bson.D{{"_id", bson.D{{"$in", objectIDs}}}})
+	// $addToSet and $pull cannot touch the same field in one update
+	updates := []bson.D{}
+	if len(add) > 0 {
+		updates = append(updates, bson.D{{"$addToSet", bson.D{{"tags", bson.D{{"$each", add}}}}}})
+	}
+	if len(remove) > 0 {
+		updates = append(updates, bson.D{{"$pull", bson.D{{"tags", bson.D{{"$in", remove}}}}}})
+	}
+	var matched int64
+	for _, update := range updates {
+		update = append(update, bson.E{"$inc", bson.D{{"version", 1}}})
+		result, err := taskRepo.Collection.UpdateMany(cxt, filter, update)
+		if err != nil {
+			return 0, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+		}
+		matched = result.MatchedCount
+	}
+	return matched, nil
+}
+
+// replaces the tags in from with to on every task in a single update, keeping the tag order
+// and dropping the duplicates a merge leaves behind. returns the number of tasks changed.
+func (taskRepo *TaskRepository) ReplaceTags(cxt context.Context, from []string, to string) (int64, *domain.TaskError) {
+	renamed := bson.D{{"$cond", bson.A{bson.D{{"$in", bson.A{"$$this", from}}}, to, "$$this"}}}
+	pipeline := mongo.Pipeline{
+		{{"$set", bson.D{{"tags", bson.D{{"$reduce", bson.D{
+			{"input", "$tags"},
+			{"initialValue", bson.A{}},
+			{"in", bson.D{{"$cond", bson.A{
+				bson.D{{"$in", bson.A{renamed, "$$value"}}},
+				"$$value",
+				bson.D{{"$concatArrays", bson.A{"$$value", bson.A{renamed}}}},
+			}}}},
+		}}}}}}},
+		{{"$set", bson.D{{"version", bson.D{{"$add", bson.A{bson.D{{"$ifNull", bson.A{"$version", 0}}}, 1}}}}}}},
+	}
+	result, err := taskRepo.Collection.UpdateMany(cxt, taskScope(cxt, bson.D{{"tags", bson.D{{"$in", from}}}}), pipeline)
+	if err != nil {
+		return 0, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,36 @@
+package repositorie
+
+import (
+	"context"
+
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// limits the filter to the organization of the request. every task, user and project filter goes
+// through it by way of notDeleted, deletedFilter or taskScope. requests made with a token always
+// carry an organization, the default one being empty, and only reach the documents stamped with it.
+// requests without a token, such as logins and those of the schedulers, reach every organization.
+func tenantScope(cxt context.Context, filter bson.D) bson.D {
+	orgID, scoped := contextOrg(cxt)
+	if !scoped {
+		return filter
+	}
+	if orgID == "" {
+		return append(filter, bson.E{"orgID", bson.D{{"$exists", false}}})
+	}
+	return append(filter, bson.E{"orgID", orgID})
+}
+
+func contextOrg(cxt context.Context) (string, bool) {
+	orgID, scoped := cxt.Value(infrastructure.CONTEXT_ORG).(string)
+	return orgID, scoped
+}
+
+// the organization a new document belongs to, the one of the request when there is one
+func stampOrg(cxt context.Context, orgID string) string {
+	if contextOrg, scoped := contextOrg(cxt); scoped {
+		return contextOrg
+	}
+	return orgID
+}
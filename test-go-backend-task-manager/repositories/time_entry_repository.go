@@ -0,0 +1,167 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TimeEntryRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewTimeEntryRepository(collection *mongo.Collection) TimeEntryRepository {
+	return TimeEntryRepository{
+		Collection: collection,
+	}
+}
+
+// a second timer of the same user is refused by the unique index on the running entries of a user
+func (timeRepo *TimeEntryRepository) CreateTimeEntry(cxt context.Context, entry domain.TimeEntry) (string, *domain.TaskError) {
+	entry.ID = ""
+	entry.OrgID = stampOrg(cxt, entry.OrgID)
+	inserted, err := timeRepo.Collection.InsertOne(cxt, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return "", &domain.TaskError{Message: "A timer is already running", Code: http.StatusConflict}
+	}
+	if err != nil {
+		return "", &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	objectID, ok := inserted.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", &domain.TaskError{Message: "Invalid inserted ID", Code: http.StatusInternalServerError}
+	}
+	return objectID.Hex(), nil
+}
+
+func (timeRepo *TimeEntryRepository) FetchRunningEntry(cxt context.Context, userID string) (domain.TimeEntry, *domain.TaskError) {
+	var entry domain.TimeEntry
+	err := timeRepo.Collection.FindOne(cxt, tenantScope(cxt, bson.D{{"userID", userID}, {"running", true}})).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "No timer is running", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.TimeEntry{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return entry, nil
+}
+
+// ends the entry if it is still running
+func (timeRepo *TimeEntryRepository) StopTimeEntry(cxt context.Context, entryID string, end time.Time) (domain.TimeEntry, *domain.TaskError) {
+	objectID, err := primitive.ObjectIDFromHex(entryID)
+	if err != nil {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	filter := tenantScope(cxt, bson.D{{"_id", objectID}, {"running", true}})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var entry domain.TimeEntry
+	err = timeRepo.Collection.FindOneAndUpdate(cxt, filter, stopTimer(end), opts).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "No timer is running", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.TimeEntry{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return entry, nil
+}
+
+func (timeRepo *TimeEntryRepository) StopTaskTimers(cxt context.Context, taskID string, end time.Time) *domain.TaskError {
+	filter := tenantScope(cxt, bson.D{{"taskID", taskID}, {"running", true}})
+	if _, err := timeRepo.Collection.UpdateMany(cxt, filter, stopTimer(end)); err != nil {
+		return &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+// the matching entries, earliest first
+func (timeRepo *TimeEntryRepository) FetchTimeEntries(cxt context.Context, query domain.TimeQuery) ([]domain.TimeEntry, *domain.TaskError) {
+	opts := options.Find().SetSort(bson.D{{"start", 1}, {"_id", 1}})
+	cursor, err := timeRepo.Collection.Find(cxt, buildTimeFilter(cxt, query), opts)
+	if err != nil {
+		return []domain.TimeEntry{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	entries := []domain.TimeEntry{}
+	if err = cursor.All(cxt, &entries); err != nil {
+		return []domain.TimeEntry{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return entries, nil
+}
+
+// the seconds of the stopped matching entries summed by project, user and the day they started on
+func (timeRepo *TimeEntryRepository) FetchTimeReport(cxt context.Context, query domain.TimeQuery) ([]domain.TimeReportRow, *domain.TaskError) {
+	timeZone := query.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	filter := append(buildTimeFilter(cxt, query), bson.E{"running", bson.D{{"$ne", true}}})
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$group", bson.D{
+			{"_id", bson.D{
+				{"projectID", bson.D{{"$ifNull", bson.A{"$projectID", ""}}}},
+				{"userID", "$userID"},
+				{"day", bson.D{{"$dateToString", bson.D{{"format", "%Y-%m-%d"}, {"date", "$start"}, {"timezone", timeZone}}}}},
+			}},
+			// end - start is in milliseconds
+			{"milliseconds", bson.D{{"$sum", bson.D{{"$subtract", bson.A{"$end", "$start"}}}}}},
+		}}},
+		{{"$project", bson.D{
+			{"_id", 0},
+			{"projectID", "$_id.projectID"},
+			{"userID", "$_id.userID"},
+			{"day", "$_id.day"},
+			{"seconds", bson.D{{"$toLong", bson.D{{"$divide", bson.A{"$milliseconds", 1000}}}}}},
+		}}},
+		{{"$sort", bson.D{{"day", 1}, {"projectID", 1}, {"userID", 1}}}},
+	}
+	cursor, err := timeRepo.Collection.Aggregate(cxt, pipeline)
+	if err != nil {
+		return []domain.TimeReportRow{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	rows := []domain.TimeReportRow{}
+	if err = cursor.All(cxt, &rows); err != nil {
+		return []domain.TimeReportRow{}, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return rows, nil
+}
+
+func stopTimer(end time.Time) bson.D {
+	return bson.D{
+		{"$set", bson.D{{"end", end}}},
+		{"$unset", bson.D{{"running", ""}}},
+	}
+}
+
+func buildTimeFilter(cxt context.Context, query domain.TimeQuery) bson.D {
+	filter := bson.D{}
+	if query.TaskID != "" {
+		filter = append(filter, bson.E{"taskID", query.TaskID})
+	}
+	if query.UserID != "" {
+		filter = append(filter, bson.E{"userID", query.UserID})
+	}
+	if query.ProjectID != "" {
+		filter = append(filter, bson.E{"projectID", query.ProjectID})
+	}
+	start := bson.D{}
+	if !query.From.IsZero() {
+		start = append(start, bson.E{"$gte", query.From})
+	}
+	if !query.To.IsZero() {
+		start = append(start, bson.E{"$lt", query.To})
+	}
+	if len(start) > 0 {
+		filter = append(filter, bson.E{"start", start})
+	}
+	return tenantScope(cxt, filter)
+}
@@ -0,0 +1,41 @@
+package repositorie
+
+import (
+	"context"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// the policy of an organization is stored under its ID, the default organization's under the empty one
+type TwoFactorPolicyRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewTwoFactorPolicyRepository(collection *mongo.Collection) TwoFactorPolicyRepository {
+	return TwoFactorPolicyRepository{
+		Collection: collection,
+	}
+}
+
+func (policyRepo *TwoFactorPolicyRepository) FetchTwoFactorPolicy(cxt context.Context) (domain.TwoFactorPolicy, *domain.UserError) {
+	orgID, _ := contextOrg(cxt)
+	policy := domain.TwoFactorPolicy{OrgID: orgID, RequiredRoles: []string{}}
+	err := policyRepo.Collection.FindOne(cxt, bson.D{{"_id", orgID}}).Decode(&policy)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return domain.TwoFactorPolicy{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return policy, nil
+}
+
+func (policyRepo *TwoFactorPolicyRepository) UpdateTwoFactorPolicy(cxt context.Context, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, *domain.UserError) {
+	policy.OrgID, _ = contextOrg(cxt)
+	opts := options.Replace().SetUpsert(true)
+	if _, err := policyRepo.Collection.ReplaceOne(cxt, bson.D{{"_id", policy.OrgID}}, policy, opts); err != nil {
+		return domain.TwoFactorPolicy{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return policy, nil
+}
@@ -3,6 +3,7 @@ package repositorie
 import (
 	"context"
 	"net/http"
+	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	"go.mongodb.org/mongo-driver/bson"
@@ -22,7 +23,7 @@ func NewUserRepository(collection *mongo.Collection) UserRepository {
 }
 
 func (userRepo *UserRepository) FetchAllUsers(cxt context.Context) ([]domain.User, *domain.UserError) {
-	filter := bson.D{{}}
+	filter := notDeleted(cxt, bson.D{})
 	cursor, err := userRepo.Collection.Find(cxt, filter)
 	if err != nil {
 		return []domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
@@ -41,7 +42,7 @@ func (userRepo *UserRepository) FetchUserByID(cxt context.Context, ID string) (d
 	if err != nil {
 		return domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
-	filter := bson.D{{"_id", taskID}}
+	filter := notDeleted(cxt, bson.D{{"_id", taskID}})
 	var retrivedUser domain.User
 	err = userRepo.Collection.FindOne(cxt, filter).Decode(&retrivedUser)
 	if err != nil {
@@ -52,7 +53,7 @@ func (userRepo *UserRepository) FetchUserByID(cxt context.Context, ID string) (d
 }
 
 func (userRepo *UserRepository) FetchUserByUsername(cxt context.Context, username string) (domain.User, *domain.UserError) {
-	filter := bson.D{{"username", username}}
+	filter := notDeleted(cxt, bson.D{{"username", username}})
 	var retrivedUser domain.User
 	err := userRepo.Collection.FindOne(cxt, filter).Decode(&retrivedUser)
 	if err != nil {
@@ -63,7 +64,7 @@ func (userRepo *UserRepository) FetchUserByUsername(cxt context.Context, usernam
 }
 
 func (userRepo *UserRepository) FetchUserCount(cxt context.Context) (int, *domain.UserError) {
-	usersCount, err := userRepo.Collection.EstimatedDocumentCount(cxt)
+	usersCount, err := userRepo.Collection.CountDocuments(cxt, tenantScope(cxt, bson.D{}))
 	if err != nil {
 		return 0, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
@@ -71,7 +72,12 @@ func (userRepo *UserRepository) FetchUserCount(cxt context.Context) (int, *domai
 }
 
 func (userRepo *UserRepository) CreateUser(cxt context.Context, newUser domain.User) (string, *domain.UserError) {
+	newUser.DeletedAt = time.Time{}
+	newUser.OrgID = stampOrg(cxt, newUser.OrgID)
 	createdUser, err := userRepo.Collection.InsertOne(cxt, newUser)
+	if mongo.IsDuplicateKeyError(err) {
+		return "", &domain.UserError{Message: "User already exists", Code: http.StatusConflict}
+	}
 	if err != nil {
 		return "", &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
@@ -87,12 +93,13 @@ func (userRepo *UserRepository) UpdateUser(cxt context.Context, updateUser domai
 	if err != nil {
 		return domain.User{}, &domain.UserError{Message: "Invalid ID format", Code: http.StatusBadRequest}
 	}
-	filter := bson.D{{"_id", objectID}}
+	filter := notDeleted(cxt, bson.D{{"_id", objectID}})
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	inserteUser := domain.User{
 		Username: updateUser.Username,
 		Role:     updateUser.Role,
 		Password: updateUser.Password,
+		Email:    updateUser.Email,
 	}
 	var returnedUser domain.User
 	err = userRepo.Collection.FindOneAndUpdate(cxt, filter, bson.D{{"$set", inserteUser}}, opts).Decode(&returnedUser)
@@ -102,15 +109,158 @@ func (userRepo *UserRepository) UpdateUser(cxt context.Context, updateUser domai
 	return returnedUser, nil
 }
 
+// moves the user to the trash, the username stays taken until the user is purged
 func (userRepo *UserRepository) DeleteUser(cxt context.Context, ID string) (domain.User, *domain.UserError) {
 	taskID, err := primitive.ObjectIDFromHex(ID)
 	if err != nil {
 		return domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
+	update := bson.D{{"$set", bson.D{{"deleted_at", time.Now()}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var returnedUser domain.User
-	err = userRepo.Collection.FindOneAndDelete(cxt, bson.D{{"_id", taskID}}).Decode(&returnedUser)
+	err = userRepo.Collection.FindOneAndUpdate(cxt, notDeleted(cxt, bson.D{{"_id", taskID}}), update, opts).Decode(&returnedUser)
 	if err != nil {
 		return domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
 	return returnedUser, nil
 }
+
+// the users in the trash, most recently deleted first. a zero deletedBefore lists the whole trash.
+func (userRepo *UserRepository) FetchDeletedUsers(cxt context.Context, deletedBefore time.Time, limit int) ([]domain.User, *domain.UserError) {
+	opts := options.Find().SetSort(bson.D{{"deleted_at", -1}}).SetLimit(int64(limit))
+	cursor, err := userRepo.Collection.Find(cxt, deletedFilter(cxt, deletedBefore), opts)
+	if err != nil {
+		return []domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	defer cursor.Close(cxt)
+
+	deletedUsers := []domain.User{}
+	if err = cursor.All(cxt, &deletedUsers); err != nil {
+		return []domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return deletedUsers, nil
+}
+
+// takes the user out of the trash
+func (userRepo *UserRepository) UndeleteUser(cxt context.Context, ID string) (domain.User, *domain.UserError) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return domain.User{}, &domain.UserError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	filter := append(deletedFilter(cxt, time.Time{}), bson.E{"_id", objectID})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var restoredUser domain.User
+	err = userRepo.Collection.FindOneAndUpdate(cxt, filter, bson.D{{"$unset", bson.D{{"deleted_at", ""}}}}, opts).Decode(&restoredUser)
+	if err == mongo.ErrNoDocuments {
+		return domain.User{}, &domain.UserError{Message: "User not found in the trash", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return restoredUser, nil
+}
+
+// removes the user for good if it has been in the trash since before deletedBefore
+func (userRepo *UserRepository) PurgeUser(cxt context.Context, ID string, deletedBefore time.Time) *domain.UserError {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return &domain.UserError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	result, err := userRepo.Collection.DeleteOne(cxt, append(deletedFilter(cxt, deletedBefore), bson.E{"_id", objectID}))
+	if err != nil {
+		return &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	if result.DeletedCount == 0 {
+		return &domain.UserError{Message: "User not found in the trash", Code: http.StatusNotFound}
+	}
+	return nil
+}
+
+func (userRepo *UserRepository) FetchUserByIdentity(cxt context.Context, issuer string, subject string) (domain.User, *domain.UserError) {
+	return userRepo.fetchUser(cxt, bson.D{{"oidc_issuer", issuer}, {"oidc_subject", subject}})
+}
+
+func (userRepo *UserRepository) FetchUserByEmail(cxt context.Context, email string) (domain.User, *domain.UserError) {
+	return userRepo.fetchUser(cxt, bson.D{{"email", email}})
+}
+
+// only links users that are not linked yet, so an identity never replaces another
+func (userRepo *UserRepository) LinkIdentity(cxt context.Context, ID string, issuer string, subject string) (domain.User, *domain.UserError) {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return domain.User{}, &domain.UserError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	filter := notDeleted(cxt, bson.D{{"_id", objectID}, {"oidc_subject", bson.D{{"$exists", false}}}})
+	update := bson.D{{"$set", bson.D{{"oidc_issuer", issuer}, {"oidc_subject", subject}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var linkedUser domain.User
+	err = userRepo.Collection.FindOneAndUpdate(cxt, filter, update, opts).Decode(&linkedUser)
+	if err == mongo.ErrNoDocuments {
+		return domain.User{}, &domain.UserError{Message: "User not found or already linked", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return linkedUser, nil
+}
+
+func (userRepo *UserRepository) fetchUser(cxt context.Context, filter bson.D) (domain.User, *domain.UserError) {
+	var retrivedUser domain.User
+	err := userRepo.Collection.FindOne(cxt, notDeleted(cxt, filter)).Decode(&retrivedUser)
+	if err == mongo.ErrNoDocuments {
+		return domain.User{}, &domain.UserError{Message: "User not found", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.User{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return retrivedUser, nil
+}
+
+func (userRepo *UserRepository) UpdateTwoFactor(cxt context.Context, ID string, twoFactor *domain.TwoFactor) *domain.UserError {
+	update := bson.D{{"$unset", bson.D{{"two_factor", ""}}}}
+	if twoFactor != nil {
+		update = bson.D{{"$set", bson.D{{"two_factor", twoFactor}}}}
+	}
+	return userRepo.updateTwoFactor(cxt, ID, bson.D{}, update)
+}
+
+func (userRepo *UserRepository) AcceptTwoFactorStep(cxt context.Context, ID string, step int64) *domain.UserError {
+	// the filter makes the check and the update one atomic operation, so two logins racing with the same code cannot both succeed
+	filter := bson.D{{"two_factor.last_step", bson.D{{"$lt", step}}}}
+	update := bson.D{{"$set", bson.D{{"two_factor.last_step", step}, {"two_factor.failures", 0}}}}
+	return userRepo.updateTwoFactor(cxt, ID, filter, update)
+}
+
+func (userRepo *UserRepository) ConsumeRecoveryCode(cxt context.Context, ID string, hash string) *domain.UserError {
+	filter := bson.D{{"two_factor.recovery_codes", hash}}
+	update := bson.D{
+		{"$pull", bson.D{{"two_factor.recovery_codes", hash}}},
+		{"$set", bson.D{{"two_factor.failures", 0}}},
+	}
+	return userRepo.updateTwoFactor(cxt, ID, filter, update)
+}
+
+func (userRepo *UserRepository) RecordTwoFactorFailure(cxt context.Context, ID string, failedAt time.Time) *domain.UserError {
+	filter := bson.D{{"two_factor", bson.D{{"$exists", true}}}}
+	update := bson.D{
+		{"$inc", bson.D{{"two_factor.failures", 1}}},
+		{"$set", bson.D{{"two_factor.failed_at", failedAt}}},
+	}
+	return userRepo.updateTwoFactor(cxt, ID, filter, update)
+}
+
+func (userRepo *UserRepository) updateTwoFactor(cxt context.Context, ID string, filter bson.D, update bson.D) *domain.UserError {
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return &domain.UserError{Message: "Invalid ID format", Code: http.StatusBadRequest}
+	}
+	filter = notDeleted(cxt, append(bson.D{{"_id", objectID}}, filter...))
+	result, err := userRepo.Collection.UpdateOne(cxt, filter, update)
+	if err != nil {
+		return &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	if result.MatchedCount == 0 {
+		return &domain.UserError{Message: "User not found", Code: http.StatusNotFound}
+	}
+	return nil
+}
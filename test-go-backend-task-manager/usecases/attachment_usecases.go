@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+const (
+	// largest file that can be attached, 10 MiB
+	MAX_ATTACHMENT_SIZE     = 10 << 20
+	MAX_TASK_ATTACHMENTS    = 20
+	MAX_ATTACHMENT_FILENAME = 255
+	// number of leading bytes looked at to detect the content type
+	CONTENT_SNIFF_LENGTH = 512
+)
+
+type attachmentUseCase struct {
+	taskRepository domain.TaskRepository
+	blobStore      infrastructure.BlobStore
+	contextTimeout time.Duration
+}
+
+func NewAttachmentUsecase(taskRepo domain.TaskRepository, blobStore infrastructure.BlobStore, timeout time.Duration) attachmentUseCase {
+	return attachmentUseCase{
+		taskRepository: taskRepo,
+		blobStore:      blobStore,
+		contextTimeout: timeout,
+	}
+}
+
+func (attachmentUC *attachmentUseCase) GetAttachments(cxt context.Context, taskID string) ([]domain.Attachment, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, attachmentUC.contextTimeout)
+	defer cancel()
+
+	task, errFetch := attachmentUC.taskRepository.FetchTaskByID(context, taskID)
+	if errFetch != nil {
+		return []domain.Attachment{}, errFetch
+	}
+	if task.Attachments == nil {
+		return []domain.Attachment{}, nil
+	}
+	return task.Attachments, nil
+}
+
+// stores the uploaded file and records it on the task. the content type is detected from
+// the content rather than trusted from the client and the checksum is taken while storing.
+func (attachmentUC *attachmentUseCase) UploadAttachment(cxt context.Context, taskID string, upload domain.AttachmentUpload) (domain.Attachment, *domain.TaskError) {
+	filename, errName := cleanFilename(upload.Filename)
+	if errName != nil {
+		return domain.Attachment{}, errName
+	}
+	if upload.UploadedBy == "" {
+		return domain.Attachment{}, &domain.TaskError{Message: "Uploader is required", Code: http.StatusBadRequest}
+	}
+	fetchContext, cancelFetch := context.WithTimeout(cxt, attachmentUC.contextTimeout)
+	task, errFetch := attachmentUC.taskRepository.FetchTaskByID(fetchContext, taskID)
+	cancelFetch()
+	if errFetch != nil {
+		return domain.Attachment{}, errFetch
+	}
+	if len(task.Attachments) >= MAX_TASK_ATTACHMENTS {
+		return domain.Attachment{}, &domain.TaskError{Message: fmt.Sprintf("Tasks can have at most %d attachments", MAX_TASK_ATTACHMENTS), Code: http.StatusBadRequest}
+	}
+
+	content := bufio.NewReaderSize(upload.Content, CONTENT_SNIFF_LENGTH)
+	head, err := content.Peek(CONTENT_SNIFF_LENGTH)
+	if err != nil && err != io.EOF {
+		return domain.Attachment{}, &domain.TaskError{Message: "Could not store the attachment: " + err.Error(), Code: http.StatusInternalServerError}
+	}
+	if len(head) == 0 {
+		return domain.Attachment{}, &domain.TaskError{Message: "Attachment is empty", Code: http.StatusBadRequest}
+	}
+	attachment := domain.Attachment{
+		ID:          newAttachmentID(),
+		Filename:    filename,
+		ContentType: http.DetectContentType(head),
+		UploadedBy:  upload.UploadedBy,
+		UploadedAt:  time.Now(),
+	}
+	key := attachmentKey(taskID, attachment.ID)
+	checksum := sha256.New()
+	var size byteCounter
+	stored := io.TeeReader(io.LimitReader(content, MAX_ATTACHMENT_SIZE+1), io.MultiWriter(checksum, &size))
+	// streaming the upload is bounded by the caller's context, the use case timeout only covers the database
+	if err := attachmentUC.blobStore.Put(cxt, key, stored); err != nil {
+		return domain.Attachment{}, &domain.TaskError{Message: "Could not store the attachment: " + err.Error(), Code: http.StatusInternalServerError}
+	}
+	context, cancel := context.WithTimeout(cxt, attachmentUC.contextTimeout)
+	defer cancel()
+	if size > MAX_ATTACHMENT_SIZE {
+		attachmentUC.removeBlob(context, key)
+		return domain.Attachment{}, &domain.TaskError{Message: fmt.Sprintf("Attachments must be at most %d MiB", MAX_ATTACHMENT_SIZE>>20), Code: http.StatusRequestEntityTooLarge}
+	}
+	attachment.Size = int64(size)
+	attachment.SHA256 = hex.EncodeToString(checksum.Sum(nil))
+
+	if _, errAdd := attachmentUC.taskRepository.AddAttachment(context, taskID, attachment); errAdd != nil {
+		attachmentUC.removeBlob(context, key)
+		return domain.Attachment{}, errAdd
+	}
+	return attachment, nil
+}
+
+// the attachment metadata and its content, the caller closes the content
+func (attachmentUC *attachmentUseCase) OpenAttachment(cxt context.Context, taskID string, attachmentID string) (domain.Attachment, io.ReadCloser, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, attachmentUC.contextTimeout)
+	defer cancel()
+
+	attachment, errFetch := attachmentUC.findAttachment(context, taskID, attachmentID)
+	if errFetch != nil {
+		return domain.Attachment{}, nil, errFetch
+	}
+	content, err := attachmentUC.blobStore.Open(context, attachmentKey(taskID, attachmentID))
+	if errors.Is(err, infrastructure.ErrBlobNotFound) {
+		return domain.Attachment{}, nil, &domain.TaskError{Message: "Attachment content is missing", Code: http.StatusNotFound}
+	}
+	if err != nil {
+		return domain.Attachment{}, nil, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return attachment, content, nil
+}
+
+// removes the attachment, only its uploader or an admin may do so
+func (attachmentUC *attachmentUseCase) DeleteAttachment(cxt context.Context, taskID string, attachmentID string, authority domain.User) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, attachmentUC.contextTimeout)
+	defer cancel()
+
+	attachment, errFetch := attachmentUC.findAttachment(context, taskID, attachmentID)
+	if errFetch != nil {
+		return errFetch
+	}
+	if attachment.UploadedBy != authority.ID && authority.Role != "admin" {
+		return &domain.TaskError{Message: "Only the uploader or an admin can delete this attachment", Code: http.StatusForbidden}
+	}
+	if _, errRemove := attachmentUC.taskRepository.RemoveAttachment(context, taskID, attachmentID); errRemove != nil {
+		return errRemove
+	}
+	attachmentUC.removeBlob(context, attachmentKey(taskID, attachmentID))
+	return nil
+}
+
+// deletes the files of a deleted task, registered as a TaskDeleteListener of the task use case
+func (attachmentUC *attachmentUseCase) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, attachmentUC.contextTimeout)
+	defer cancel()
+
+	var errDelete *domain.TaskError
+	for _, attachment := range task.Attachments {
+		if err := attachmentUC.blobStore.Delete(context, attachmentKey(task.ID, attachment.ID)); err != nil {
+			errDelete = &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+		}
+	}
+	return errDelete
+}
+
+func (attachmentUC *attachmentUseCase) findAttachment(cxt context.Context, taskID string, attachmentID string) (domain.Attachment, *domain.TaskError) {
+	task, errFetch := attachmentUC.taskRepository.FetchTaskByID(cxt, taskID)
+	if errFetch != nil {
+		return domain.Attachment{}, errFetch
+	}
+	for _, attachment := range task.Attachments {
+		if attachment.ID == attachmentID {
+			return attachment, nil
+		}
+	}
+	return domain.Attachment{}, &domain.TaskError{Message: "Attachment not found", Code: http.StatusNotFound}
+}
+
+// an orphaned blob is harmless, failing to remove one is only logged
+func (attachmentUC *attachmentUseCase) removeBlob(cxt context.Context, key string) {
+	if err := attachmentUC.blobStore.Delete(cxt, key); err != nil {
+		log.Println("Error", "deleting attachment", key, err)
+	}
+}
+
+func attachmentKey(taskID string, attachmentID string) string {
+	return taskID + "/" + attachmentID
+}
+
+func newAttachmentID() string {
+	ID := make([]byte, 12)
+	rand.Read(ID)
+	return hex.EncodeToString(ID)
+}
+
+// the base name of the uploaded file without control characters
+func cleanFilename(filename string) (string, *domain.TaskError) {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename))
+	if filename == "" || filename == "." || filename == "/" {
+		return "", &domain.TaskError{Message: "Filename is required", Code: http.StatusBadRequest}
+	}
+	if len([]rune(filename)) > MAX_ATTACHMENT_FILENAME {
+		return "", &domain.TaskError{Message: fmt.Sprintf("Filenames must be at most %d characters", MAX_ATTACHMENT_FILENAME), Code: http.StatusBadRequest}
+	}
+	return filename, nil
+}
+
+type byteCounter int64
+
+func (counter *byteCounter) Write(p []byte) (int, error) {
+	*counter += byteCounter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+const (
+	DEFAULT_AUDIT_PAGE_LIMIT = 50
+	MAX_AUDIT_PAGE_LIMIT     = 500
+	// shown in place of secret values in recorded changes
+	REDACTED_VALUE = "[redacted]"
+)
+
+type auditUseCase struct {
+	auditRepository domain.AuditRepository
+	contextTimeout  time.Duration
+}
+
+func NewAuditUsecase(auditRepo domain.AuditRepository, timeout time.Duration) auditUseCase {
+	return auditUseCase{
+		auditRepository: auditRepo,
+		contextTimeout:  timeout,
+	}
+}
+
+// appends the entry to the audit log. the entry is written even when the caller's context has
+// been cancelled since the change it describes has already happened, failures are only logged.
+func (auditUC *auditUseCase) Record(cxt context.Context, entry domain.AuditEntry) {
+	context, cancel := context.WithTimeout(context.WithoutCancel(cxt), auditUC.contextTimeout)
+	defer cancel()
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if errInsert := auditUC.auditRepository.InsertEntry(context, entry); errInsert != nil {
+		log.Println("Error", "recording audit entry", entry.Action, entry.TargetType, entry.TargetID, errInsert)
+	}
+}
+
+func (auditUC *auditUseCase) GetEntries(cxt context.Context, query domain.AuditQuery) ([]domain.AuditEntry, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, auditUC.contextTimeout)
+	defer cancel()
+
+	if query.Limit <= 0 {
+		query.Limit = DEFAULT_AUDIT_PAGE_LIMIT
+	} else if query.Limit > MAX_AUDIT_PAGE_LIMIT {
+		query.Limit = MAX_AUDIT_PAGE_LIMIT
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && !query.Since.Before(query.Until) {
+		return []domain.AuditEntry{}, &domain.TaskError{Message: "Since must be before until", Code: http.StatusBadRequest}
+	}
+	return auditUC.auditRepository.FetchEntries(context, query)
+}
+
+// an audit entry for a change of the target made by the user in the context. the entry belongs to
+// the organization of the context, so requests without a token scope it to the target's organization.
+// before is nil for created records and after is nil for deleted ones.
+func newAuditEntry(cxt context.Context, action string, targetType string, targetID string, before interface{}, after interface{}, redacted ...string) domain.AuditEntry {
+	actor, _ := cxt.Value(infrastructure.CONTEXT_USERNAME).(string)
+	orgID, _ := cxt.Value(infrastructure.CONTEXT_ORG).(string)
+	return domain.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Timestamp:  time.Now(),
+		Changes:    diffFields(before, after, redacted...),
+		OrgID:      orgID,
+	}
+}
+
+// the fields that differ between the JSON forms of before and after in field order,
+// the values of redacted fields are replaced so that only the fact they changed is kept
+func diffFields(before interface{}, after interface{}, redacted ...string) []domain.FieldChange {
+	beforeFields, afterFields := jsonFields(before), jsonFields(after)
+	names := []string{}
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []domain.FieldChange{}
+	for _, name := range names {
+		change := domain.FieldChange{Field: name, Before: beforeFields[name], After: afterFields[name]}
+		if reflect.DeepEqual(change.Before, change.After) {
+			continue
+		}
+		if slices.Contains(redacted, name) {
+			if change.Before != nil {
+				change.Before = REDACTED_VALUE
+			}
+			if change.After != nil {
+				change.After = REDACTED_VALUE
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// the record as a map of its JSON fields, nil records have no fields
+func jsonFields(record interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if record == nil {
+		return fields
+	}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(encoded, &fields)
+	return fields
+}
@@ -0,0 +1,209 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+const MAX_COMMENT_LENGTH = 5000
+
+type commentUseCase struct {
+	commentRepository domain.CommentRepository
+	taskRepository    domain.TaskRepository
+	contextTimeout    time.Duration
+}
+
+func NewCommentUsecase(commentRepo domain.CommentRepository, taskRepo domain.TaskRepository, timeout time.Duration) commentUseCase {
+	return commentUseCase{
+		commentRepository: commentRepo,
+		taskRepository:    taskRepo,
+		contextTimeout:    timeout,
+	}
+}
+
+// the comments of the task as threads, replies are nested under the comment they answer
+func (commentUC *commentUseCase) GetComments(cxt context.Context, taskID string) ([]domain.Comment, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, commentUC.contextTimeout)
+	defer cancel()
+
+	if _, errFetch := commentUC.taskRepository.FetchTaskByID(context, taskID); errFetch != nil {
+		return []domain.Comment{}, errFetch
+	}
+	comments, errFetch := commentUC.commentRepository.FetchComments(context, taskID)
+	if errFetch != nil {
+		return []domain.Comment{}, errFetch
+	}
+	known := map[string]bool{}
+	for _, comment := range comments {
+		known[comment.ID] = true
+	}
+	replies := map[string][]domain.Comment{}
+	roots := []domain.Comment{}
+	for _, comment := range comments {
+		if comment.ParentID != "" && known[comment.ParentID] {
+			replies[comment.ParentID] = append(replies[comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+	threads := []domain.Comment{}
+	for _, root := range roots {
+		threads = append(threads, buildThread(root, replies))
+	}
+	return threads, nil
+}
+
+func (commentUC *commentUseCase) AddComment(cxt context.Context, comment domain.Comment) (domain.Comment, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, commentUC.contextTimeout)
+	defer cancel()
+
+	body, errBody := checkCommentBody(comment.Body)
+	if errBody != nil {
+		return domain.Comment{}, errBody
+	}
+	if comment.AuthorID == "" {
+		return domain.Comment{}, &domain.TaskError{Message: "Author is required", Code: http.StatusBadRequest}
+	}
+	if _, errFetch := commentUC.taskRepository.FetchTaskByID(context, comment.TaskID); errFetch != nil {
+		return domain.Comment{}, errFetch
+	}
+	if comment.ParentID != "" {
+		parent, errFetch := commentUC.commentRepository.FetchCommentByID(context, comment.ParentID)
+		if errFetch != nil || parent.TaskID != comment.TaskID {
+			return domain.Comment{}, &domain.TaskError{Message: "Parent comment not found", Code: http.StatusBadRequest}
+		}
+		if parent.Deleted {
+			return domain.Comment{}, &domain.TaskError{Message: "Cannot reply to a deleted comment", Code: http.StatusBadRequest}
+		}
+	}
+
+	newComment := domain.Comment{
+		TaskID:    comment.TaskID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+	commentID, errCreate := commentUC.commentRepository.CreateComment(context, newComment)
+	if errCreate != nil {
+		return domain.Comment{}, errCreate
+	}
+	newComment.ID = commentID
+	return newComment, nil
+}
+
+// replaces the body of the comment, the previous body is kept in its history
+func (commentUC *commentUseCase) EditComment(cxt context.Context, taskID string, commentID string, authorID string, body string) (domain.Comment, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, commentUC.contextTimeout)
+	defer cancel()
+
+	body, errBody := checkCommentBody(body)
+	if errBody != nil {
+		return domain.Comment{}, errBody
+	}
+	comment, errFetch := commentUC.authoredComment(context, taskID, commentID, authorID)
+	if errFetch != nil {
+		return domain.Comment{}, errFetch
+	}
+	if comment.Deleted {
+		return domain.Comment{}, &domain.TaskError{Message: "Cannot edit a deleted comment", Code: http.StatusBadRequest}
+	}
+	if comment.Body == body {
+		return comment, nil
+	}
+	previous := domain.CommentRevision{Body: comment.Body, EditedAt: time.Now()}
+	return commentUC.commentRepository.UpdateCommentBody(context, commentID, body, previous)
+}
+
+// removes the comment, a comment with replies is blanked instead so the thread stays readable
+func (commentUC *commentUseCase) DeleteComment(cxt context.Context, taskID string, commentID string, authorID string) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, commentUC.contextTimeout)
+	defer cancel()
+
+	comment, errFetch := commentUC.authoredComment(context, taskID, commentID, authorID)
+	if errFetch != nil {
+		return errFetch
+	}
+	replies, errCount := commentUC.commentRepository.CountReplies(context, commentID)
+	if errCount != nil {
+		return errCount
+	}
+	if replies > 0 {
+		_, errMark := commentUC.commentRepository.MarkCommentDeleted(context, commentID)
+		return errMark
+	}
+	if errDelete := commentUC.commentRepository.DeleteComment(context, commentID); errDelete != nil {
+		return errDelete
+	}
+	return commentUC.removeEmptyTombstones(context, comment.ParentID)
+}
+
+// archives the comments of a deleted task, registered as a TaskDeleteListener of the task use case
+func (commentUC *commentUseCase) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, commentUC.contextTimeout)
+	defer cancel()
+	return commentUC.commentRepository.ArchiveTaskComments(context, task.ID)
+}
+
+// the comment when it belongs to the task and was written by the author
+func (commentUC *commentUseCase) authoredComment(cxt context.Context, taskID string, commentID string, authorID string) (domain.Comment, *domain.TaskError) {
+	comment, errFetch := commentUC.commentRepository.FetchCommentByID(cxt, commentID)
+	if errFetch != nil {
+		return domain.Comment{}, errFetch
+	}
+	if comment.TaskID != taskID {
+		return domain.Comment{}, &domain.TaskError{Message: "Comment not found", Code: http.StatusNotFound}
+	}
+	if comment.AuthorID != authorID {
+		return domain.Comment{}, &domain.TaskError{Message: "Only the author can change this comment", Code: http.StatusForbidden}
+	}
+	return comment, nil
+}
+
+// deletes blanked ancestors whose last reply has just been removed
+func (commentUC *commentUseCase) removeEmptyTombstones(cxt context.Context, commentID string) *domain.TaskError {
+	for commentID != "" {
+		comment, errFetch := commentUC.commentRepository.FetchCommentByID(cxt, commentID)
+		if errFetch != nil {
+			if errFetch.Code == http.StatusNotFound {
+				return nil
+			}
+			return errFetch
+		}
+		if !comment.Deleted {
+			return nil
+		}
+		replies, errCount := commentUC.commentRepository.CountReplies(cxt, commentID)
+		if errCount != nil || replies > 0 {
+			return errCount
+		}
+		if errDelete := commentUC.commentRepository.DeleteComment(cxt, commentID); errDelete != nil {
+			return errDelete
+		}
+		commentID = comment.ParentID
+	}
+	return nil
+}
+
+func buildThread(comment domain.Comment, replies map[string][]domain.Comment) domain.Comment {
+	for _, reply := range replies[comment.ID] {
+		comment.Replies = append(comment.Replies, buildThread(reply, replies))
+	}
+	return comment
+}
+
+func checkCommentBody(body string) (string, *domain.TaskError) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", &domain.TaskError{Message: "Comment body is required", Code: http.StatusBadRequest}
+	}
+	if len([]rune(body)) > MAX_COMMENT_LENGTH {
+		return "", &domain.TaskError{Message: fmt.Sprintf("Comments must be at most %d characters", MAX_COMMENT_LENGTH), Code: http.StatusBadRequest}
+	}
+	return body, nil
+}
@@ -0,0 +1,199 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+const MAX_PROJECT_NAME_LENGTH = 200
+
+// a member may do what their role and every role ranked below it may do
+var projectRoleRanks = map[string]int{
+	domain.PROJECT_ROLE_VIEWER: 1,
+	domain.PROJECT_ROLE_EDITOR: 2,
+	domain.PROJECT_ROLE_OWNER:  3,
+}
+
+type projectUseCase struct {
+	projectRepository domain.ProjectRepository
+	taskRepository    domain.TaskRepository
+	userRepository    domain.UserRepository
+	contextTimeout    time.Duration
+}
+
+func NewProjectUsecase(projectRepo domain.ProjectRepository, taskRepo domain.TaskRepository, userRepo domain.UserRepository, timeout time.Duration) projectUseCase {
+	return projectUseCase{
+		projectRepository: projectRepo,
+		taskRepository:    taskRepo,
+		userRepository:    userRepo,
+		contextTimeout:    timeout,
+	}
+}
+
+// the projects the user is a member of, admins get every project
+func (projectUC *projectUseCase) GetProjects(cxt context.Context, user domain.User) ([]domain.Project, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	if user.Role == "admin" {
+		return projectUC.projectRepository.FetchProjects(context, "")
+	}
+	return projectUC.projectRepository.FetchProjects(context, user.ID)
+}
+
+func (projectUC *projectUseCase) GetProject(cxt context.Context, projectID string, user domain.User) (domain.Project, *domain.TaskError) {
+	return projectUC.Authorize(cxt, projectID, user, domain.PROJECT_ROLE_VIEWER)
+}
+
+// creates the project with the user as its owner and only member
+func (projectUC *projectUseCase) CreateProject(cxt context.Context, project domain.Project, owner domain.User) (domain.Project, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	name, errName := checkProjectName(project.Name)
+	if errName != nil {
+		return domain.Project{}, errName
+	}
+	newProject := domain.Project{
+		Name:        name,
+		Description: project.Description,
+		OwnerID:     owner.ID,
+		Members:     []domain.ProjectMember{{UserID: owner.ID, Role: domain.PROJECT_ROLE_OWNER}},
+		CreatedAt:   time.Now(),
+	}
+	projectID, errCreate := projectUC.projectRepository.CreateProject(context, newProject)
+	if errCreate != nil {
+		return domain.Project{}, errCreate
+	}
+	newProject.ID = projectID
+	return newProject, nil
+}
+
+func (projectUC *projectUseCase) UpdateProject(cxt context.Context, project domain.Project, user domain.User) (domain.Project, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	name, errName := checkProjectName(project.Name)
+	if errName != nil {
+		return domain.Project{}, errName
+	}
+	if _, errAuthorize := projectUC.Authorize(context, project.ID, user, domain.PROJECT_ROLE_OWNER); errAuthorize != nil {
+		return domain.Project{}, errAuthorize
+	}
+	project.Name = name
+	return projectUC.projectRepository.UpdateProject(context, project)
+}
+
+// only empty projects can be deleted, their tasks have to be deleted or moved first
+func (projectUC *projectUseCase) DeleteProject(cxt context.Context, projectID string, user domain.User) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	if _, errAuthorize := projectUC.Authorize(context, projectID, user, domain.PROJECT_ROLE_OWNER); errAuthorize != nil {
+		return errAuthorize
+	}
+	page, errFetch := projectUC.taskRepository.FetchTasks(withProject(context, projectID), domain.TaskQuery{ProjectID: projectID, SortOrder: 1, Limit: 1})
+	if errFetch != nil {
+		return errFetch
+	}
+	if len(page.Tasks) > 0 {
+		return &domain.TaskError{Message: "Project still has tasks", Code: http.StatusConflict}
+	}
+	return projectUC.projectRepository.DeleteProject(context, projectID)
+}
+
+// adds the user to the project or changes their role, only owners manage members
+func (projectUC *projectUseCase) SetMember(cxt context.Context, projectID string, member domain.ProjectMember, user domain.User) (domain.Project, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	if _, ok := projectRoleRanks[member.Role]; !ok {
+		return domain.Project{}, &domain.TaskError{Message: "Role must be one of viewer, editor or owner", Code: http.StatusBadRequest}
+	}
+	project, errAuthorize := projectUC.Authorize(context, projectID, user, domain.PROJECT_ROLE_OWNER)
+	if errAuthorize != nil {
+		return domain.Project{}, errAuthorize
+	}
+	if member.UserID == project.OwnerID && member.Role != domain.PROJECT_ROLE_OWNER {
+		return domain.Project{}, &domain.TaskError{Message: "The project owner cannot be given another role", Code: http.StatusConflict}
+	}
+	if _, errUser := projectUC.userRepository.FetchUserByID(context, member.UserID); errUser != nil {
+		return domain.Project{}, &domain.TaskError{Message: errUser.Error(), Code: errUser.Code}
+	}
+	return projectUC.projectRepository.SetMember(context, projectID, member)
+}
+
+// owners can remove any member but the project owner, other members can only leave
+func (projectUC *projectUseCase) RemoveMember(cxt context.Context, projectID string, memberID string, user domain.User) (domain.Project, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	role := domain.PROJECT_ROLE_OWNER
+	if memberID == user.ID {
+		role = domain.PROJECT_ROLE_VIEWER
+	}
+	project, errAuthorize := projectUC.Authorize(context, projectID, user, role)
+	if errAuthorize != nil {
+		return domain.Project{}, errAuthorize
+	}
+	if memberID == project.OwnerID {
+		return domain.Project{}, &domain.TaskError{Message: "The project owner cannot be removed", Code: http.StatusConflict}
+	}
+	if _, isMember := projectMemberRole(project, memberID); !isMember {
+		return domain.Project{}, &domain.TaskError{Message: "User is not a member of the project", Code: http.StatusNotFound}
+	}
+	return projectUC.projectRepository.RemoveMember(context, projectID, memberID)
+}
+
+// the project when the user has at least the given role in it. admins may do anything in every project,
+// a project the user is not a member of is reported as not found so its existence is not revealed.
+func (projectUC *projectUseCase) Authorize(cxt context.Context, projectID string, user domain.User, role string) (domain.Project, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, projectUC.contextTimeout)
+	defer cancel()
+
+	project, errFetch := projectUC.projectRepository.FetchProjectByID(context, projectID)
+	if errFetch != nil {
+		return domain.Project{}, errFetch
+	}
+	if user.Role == "admin" {
+		return project, nil
+	}
+	memberRole, isMember := projectMemberRole(project, user.ID)
+	if !isMember {
+		return domain.Project{}, &domain.TaskError{Message: "Project not found", Code: http.StatusNotFound}
+	}
+	if projectRoleRanks[memberRole] < projectRoleRanks[role] {
+		return domain.Project{}, &domain.TaskError{Message: "This requires the " + role + " role in the project", Code: http.StatusForbidden}
+	}
+	return project, nil
+}
+
+// scopes the task queries made with the context to the project, like requests under /projects/:pid
+func withProject(cxt context.Context, projectID string) context.Context {
+	return context.WithValue(cxt, infrastructure.CONTEXT_PROJECT, projectID)
+}
+
+func projectMemberRole(project domain.Project, userID string) (string, bool) {
+	for _, member := range project.Members {
+		if member.UserID == userID {
+			return member.Role, true
+		}
+	}
+	return "", false
+}
+
+func checkProjectName(name string) (string, *domain.TaskError) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", &domain.TaskError{Message: "Project name is required", Code: http.StatusBadRequest}
+	}
+	if len(name) > MAX_PROJECT_NAME_LENGTH {
+		return "", &domain.TaskError{Message: "Project name is too long", Code: http.StatusBadRequest}
+	}
+	return name, nil
+}
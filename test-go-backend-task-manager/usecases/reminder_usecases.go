@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+const (
+	DEFAULT_REMINDER_LEAD_MINUTES = 60
+	// longest lead time a user can ask for, one week
+	MAX_REMINDER_LEAD_MINUTES = 7 * 24 * 60
+	// tasks that have been overdue for longer than this are not reminded about
+	OVERDUE_REMINDER_WINDOW = 7 * 24 * time.Hour
+)
+
+type reminderUseCase struct {
+	taskRepository     domain.TaskRepository
+	reminderRepository domain.ReminderRepository
+	notifier           domain.Notifier
+	contextTimeout     time.Duration
+	workflow           domain.Workflow
+}
+
+func NewReminderUsecase(taskRepo domain.TaskRepository, reminderRepo domain.ReminderRepository, notifier domain.Notifier, timeout time.Duration, workflow domain.Workflow) reminderUseCase {
+	return reminderUseCase{
+		taskRepository:     taskRepo,
+		reminderRepository: reminderRepo,
+		notifier:           notifier,
+		contextTimeout:     timeout,
+		workflow:           workflow,
+	}
+}
+
+// the preference used for users that never saved one
+func DefaultReminderPreference(userID string) domain.ReminderPreference {
+	return domain.ReminderPreference{
+		UserID:      userID,
+		Enabled:     true,
+		LeadMinutes: DEFAULT_REMINDER_LEAD_MINUTES,
+		Overdue:     true,
+	}
+}
+
+func (reminderUC *reminderUseCase) GetPreference(cxt context.Context, userID string) (domain.ReminderPreference, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, reminderUC.contextTimeout)
+	defer cancel()
+
+	preferences, errFetch := reminderUC.preferencesOf(context, []string{userID})
+	if errFetch != nil {
+		return domain.ReminderPreference{}, errFetch
+	}
+	return preferences[userID], nil
+}
+
+func (reminderUC *reminderUseCase) UpdatePreference(cxt context.Context, preference domain.ReminderPreference) (domain.ReminderPreference, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, reminderUC.contextTimeout)
+	defer cancel()
+
+	if preference.UserID == "" {
+		return domain.ReminderPreference{}, &domain.TaskError{Message: "User ID is required", Code: http.StatusBadRequest}
+	}
+	if preference.LeadMinutes < 0 || preference.LeadMinutes > MAX_REMINDER_LEAD_MINUTES {
+		return domain.ReminderPreference{}, &domain.TaskError{Message: fmt.Sprintf("Lead minutes must be between 0 and %d", MAX_REMINDER_LEAD_MINUTES), Code: http.StatusBadRequest}
+	}
+	// malformed addresses are refused here rather than when the scheduler mails the reminder,
+	// only the bare address is kept
+	if preference.Email != "" {
+		address, err := mail.ParseAddress(preference.Email)
+		if err != nil {
+			return domain.ReminderPreference{}, &domain.TaskError{Message: "Invalid email address: " + err.Error(), Code: http.StatusBadRequest}
+		}
+		preference.Email = address.Address
+	}
+	return reminderUC.reminderRepository.UpsertPreference(context, preference)
+}
+
+// sends the due soon and overdue reminders that are owed at the given time and returns the ones sent.
+// a reminder that fails to deliver is released so the next scan retries it.
+func (reminderUC *reminderUseCase) ScanReminders(cxt context.Context, now time.Time) ([]domain.Reminder, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, reminderUC.contextTimeout)
+	defer cancel()
+
+	horizon := now.Add(MAX_REMINDER_LEAD_MINUTES * time.Minute)
+	dueTasks, errFetch := reminderUC.taskRepository.FetchDueTasks(context, now.Add(-OVERDUE_REMINDER_WINDOW), horizon, reminderUC.workflow.FinalStatuses)
+	if errFetch != nil {
+		return []domain.Reminder{}, errFetch
+	}
+	userIDs := []string{}
+	for _, task := range dueTasks {
+		userIDs = append(userIDs, task.UserID)
+	}
+	preferences, errFetch := reminderUC.preferencesOf(context, uniqueIDs(userIDs))
+	if errFetch != nil {
+		return []domain.Reminder{}, errFetch
+	}
+
+	sent := []domain.Reminder{}
+	for _, task := range dueTasks {
+		preference, ok := preferences[task.UserID]
+		if !ok || !preference.Enabled {
+			continue
+		}
+		kind := reminderKind(task, preference, now)
+		if kind == "" {
+			continue
+		}
+		reminder := domain.Reminder{
+			ID:      fmt.Sprintf("%s:%s:%d", task.ID, kind, task.DueDate.Unix()),
+			TaskID:  task.ID,
+			UserID:  task.UserID,
+			Kind:    kind,
+			Title:   task.Title,
+			DueDate: task.DueDate,
+			Email:   preference.Email,
+			SentAt:  now,
+		}
+		fresh, errMark := reminderUC.reminderRepository.MarkReminderSent(context, reminder)
+		if errMark != nil {
+			return sent, errMark
+		}
+		if !fresh {
+			continue
+		}
+		if err := reminderUC.notifier.Notify(context, reminder); err != nil {
+			log.Println("Error", "sending reminder", reminder.ID, err)
+			if errUnmark := reminderUC.reminderRepository.UnmarkReminder(context, reminder.ID); errUnmark != nil {
+				return sent, errUnmark
+			}
+			continue
+		}
+		sent = append(sent, reminder)
+	}
+	return sent, nil
+}
+
+// the saved preferences of the users keyed by user ID, users without one get the default
+func (reminderUC *reminderUseCase) preferencesOf(cxt context.Context, userIDs []string) (map[string]domain.ReminderPreference, *domain.TaskError) {
+	byUser := map[string]domain.ReminderPreference{}
+	if len(userIDs) == 0 {
+		return byUser, nil
+	}
+	saved, errFetch := reminderUC.reminderRepository.FetchPreferences(cxt, userIDs)
+	if errFetch != nil {
+		return byUser, errFetch
+	}
+	for _, userID := range userIDs {
+		byUser[userID] = DefaultReminderPreference(userID)
+	}
+	for _, preference := range saved {
+		byUser[preference.UserID] = preference
+	}
+	return byUser, nil
+}
+
+func reminderKind(task domain.Task, preference domain.ReminderPreference, now time.Time) string {
+	if !task.DueDate.After(now) {
+		if preference.Overdue {
+			return domain.REMINDER_OVERDUE
+		}
+		return ""
+	}
+	if !task.DueDate.After(now.Add(time.Duration(preference.LeadMinutes) * time.Minute)) {
+		return domain.REMINDER_DUE_SOON
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// report ranges, in days unless stated otherwise
+const (
+	DEFAULT_BURNDOWN_DAYS  = 14
+	MAX_REPORT_DAYS        = 366
+	DEFAULT_ITERATION_DAYS = 14
+	DEFAULT_ITERATIONS     = 6
+	MAX_ITERATIONS         = 52
+)
+
+type reportUseCase struct {
+	taskRepository domain.TaskRepository
+	contextTimeout time.Duration
+	workflow       domain.Workflow
+}
+
+func NewReportUsecase(taskRepo domain.TaskRepository, timeout time.Duration, workflow domain.Workflow) reportUseCase {
+	return reportUseCase{
+		taskRepository: taskRepo,
+		contextTimeout: timeout,
+		workflow:       workflow,
+	}
+}
+
+// the remaining and completed estimates at the end of every day of the range, which defaults
+// to the two weeks up to today. days run from midnight to midnight UTC.
+func (reportUC *reportUseCase) GetBurndown(cxt context.Context, query domain.ReportQuery) (domain.Burndown, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, reportUC.contextTimeout)
+	defer cancel()
+
+	if errUnit := checkReportUnit(&query); errUnit != nil {
+		return domain.Burndown{}, errUnit
+	}
+	if query.To.IsZero() {
+		query.To = reportToday().AddDate(0, 0, 1)
+	}
+	if query.From.IsZero() {
+		query.From = query.To.AddDate(0, 0, -DEFAULT_BURNDOWN_DAYS)
+	}
+	days := int(query.To.Sub(query.From) / (24 * time.Hour))
+	if days < 1 {
+		return domain.Burndown{}, &domain.TaskError{Message: "from must be before to", Code: http.StatusBadRequest}
+	}
+	if days > MAX_REPORT_DAYS {
+		return domain.Burndown{}, &domain.TaskError{Message: fmt.Sprintf("A burndown cannot cover more than %d days", MAX_REPORT_DAYS), Code: http.StatusBadRequest}
+	}
+	tasks, errFetch := reportUC.taskRepository.FetchEstimatedTasks(context, query, reportUC.workflow.FinalStatuses)
+	if errFetch != nil {
+		return domain.Burndown{}, errFetch
+	}
+
+	initial := 0.0
+	for _, task := range tasks {
+		if task.CreatedAt.Before(query.From) {
+			initial += task.Estimate
+		}
+	}
+	burndown := domain.Burndown{
+		From:   query.From.Format(domain.REPORT_DAY_FORMAT),
+		To:     query.To.AddDate(0, 0, -1).Format(domain.REPORT_DAY_FORMAT),
+		Unit:   query.Unit,
+		Series: []domain.BurndownPoint{},
+	}
+	for day := 0; day < days; day++ {
+		start := query.From.AddDate(0, 0, day)
+		end := start.AddDate(0, 0, 1)
+		point := domain.BurndownPoint{
+			Date:  start.Format(domain.REPORT_DAY_FORMAT),
+			Ideal: initial * float64(days-day-1) / float64(days),
+		}
+		for _, task := range tasks {
+			if !task.CreatedAt.Before(end) {
+				continue
+			}
+			point.Scope += task.Estimate
+			if !task.CompletedAt.IsZero() && task.CompletedAt.Before(end) {
+				point.Completed += task.Estimate
+			} else {
+				point.Remaining += task.Estimate
+			}
+		}
+		burndown.Series = append(burndown.Series, point)
+	}
+	return burndown, nil
+}
+
+// the estimates completed in each of the iterations up to To, which defaults to the end of today
+func (reportUC *reportUseCase) GetVelocity(cxt context.Context, query domain.ReportQuery) (domain.Velocity, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, reportUC.contextTimeout)
+	defer cancel()
+
+	if errUnit := checkReportUnit(&query); errUnit != nil {
+		return domain.Velocity{}, errUnit
+	}
+	if query.IterationDays == 0 {
+		query.IterationDays = DEFAULT_ITERATION_DAYS
+	}
+	if query.Iterations == 0 {
+		query.Iterations = DEFAULT_ITERATIONS
+	}
+	if query.IterationDays < 1 || query.IterationDays > MAX_REPORT_DAYS {
+		return domain.Velocity{}, &domain.TaskError{Message: fmt.Sprintf("iteration_days must be between 1 and %d", MAX_REPORT_DAYS), Code: http.StatusBadRequest}
+	}
+	if query.Iterations < 1 || query.Iterations > MAX_ITERATIONS {
+		return domain.Velocity{}, &domain.TaskError{Message: fmt.Sprintf("iterations must be between 1 and %d", MAX_ITERATIONS), Code: http.StatusBadRequest}
+	}
+	if query.To.IsZero() {
+		query.To = reportToday().AddDate(0, 0, 1)
+	}
+	query.From = query.To.AddDate(0, 0, -query.IterationDays*query.Iterations)
+	tasks, errFetch := reportUC.taskRepository.FetchEstimatedTasks(context, query, reportUC.workflow.FinalStatuses)
+	if errFetch != nil {
+		return domain.Velocity{}, errFetch
+	}
+
+	velocity := domain.Velocity{Unit: query.Unit, IterationDays: query.IterationDays, Iterations: []domain.VelocityIteration{}}
+	total := 0.0
+	for iteration := 0; iteration < query.Iterations; iteration++ {
+		start := query.From.AddDate(0, 0, iteration*query.IterationDays)
+		end := start.AddDate(0, 0, query.IterationDays)
+		completed := domain.VelocityIteration{
+			Start: start.Format(domain.REPORT_DAY_FORMAT),
+			End:   end.AddDate(0, 0, -1).Format(domain.REPORT_DAY_FORMAT),
+		}
+		for _, task := range tasks {
+			if !task.CompletedAt.Before(start) && task.CompletedAt.Before(end) {
+				completed.Completed += task.Estimate
+				completed.Tasks++
+			}
+		}
+		total += completed.Completed
+		velocity.Iterations = append(velocity.Iterations, completed)
+	}
+	velocity.Average = total / float64(query.Iterations)
+	return velocity, nil
+}
+
+func checkReportUnit(query *domain.ReportQuery) *domain.TaskError {
+	switch query.Unit {
+	case "":
+		query.Unit = domain.ESTIMATE_POINTS
+	case domain.ESTIMATE_POINTS, domain.ESTIMATE_HOURS:
+	default:
+		return &domain.TaskError{Message: "unit must be points or hours", Code: http.StatusBadRequest}
+	}
+	return nil
+}
+
+// midnight UTC of the current day
+func reportToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// adds the user to the assignees of the task, only those who may edit the task assign it
+func (taskUC *taskUseCase) AssignTask(cxt context.Context, taskID string, assigneeID string, authority domain.User) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if assigneeID == "" {
+		return domain.Task{}, &domain.TaskError{Message: "userID is required", Code: http.StatusBadRequest}
+	}
+	currentTask, errAuthorize := taskUC.authorizeEdit(context, taskID, authority)
+	if errAuthorize != nil {
+		return domain.Task{}, errAuthorize
+	}
+	if slices.Contains(currentTask.Assignees, assigneeID) {
+		return currentTask, nil
+	}
+	updatedTask, errUpdate := taskUC.taskRepository.AddAssignee(context, taskID, assigneeID)
+	if errUpdate != nil {
+		return domain.Task{}, errUpdate
+	}
+	taskUC.audit(context, domain.AUDIT_TASK_UPDATE, taskID, currentTask, updatedTask)
+	return updatedTask, nil
+}
+
+// removes the user from the assignees of the task, assignees can also unassign themselves
+func (taskUC *taskUseCase) UnassignTask(cxt context.Context, taskID string, assigneeID string, authority domain.User) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	currentTask, errAuthorize := taskUC.authorizeEdit(context, taskID, authority)
+	if errAuthorize != nil {
+		return domain.Task{}, errAuthorize
+	}
+	if !slices.Contains(currentTask.Assignees, assigneeID) {
+		return domain.Task{}, &domain.TaskError{Message: "User is not assigned to the task", Code: http.StatusNotFound}
+	}
+	updatedTask, errUpdate := taskUC.taskRepository.RemoveAssignee(context, taskID, assigneeID)
+	if errUpdate != nil {
+		return domain.Task{}, errUpdate
+	}
+	taskUC.audit(context, domain.AUDIT_TASK_UPDATE, taskID, currentTask, updatedTask)
+	return updatedTask, nil
+}
+
+// anyone who can see the task can watch it
+func (taskUC *taskUseCase) WatchTask(cxt context.Context, taskID string, watcherID string) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	currentTask, errFetch := taskUC.taskRepository.FetchTaskByID(context, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if slices.Contains(currentTask.Watchers, watcherID) {
+		return currentTask, nil
+	}
+	return taskUC.taskRepository.AddWatcher(context, taskID, watcherID)
+}
+
+func (taskUC *taskUseCase) UnwatchTask(cxt context.Context, taskID string, watcherID string) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	currentTask, errFetch := taskUC.taskRepository.FetchTaskByID(context, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if !slices.Contains(currentTask.Watchers, watcherID) {
+		return currentTask, nil
+	}
+	return taskUC.taskRepository.RemoveWatcher(context, taskID, watcherID)
+}
+
+// the task when the user may edit it
+func (taskUC *taskUseCase) authorizeEdit(cxt context.Context, taskID string, authority domain.User) (domain.Task, *domain.TaskError) {
+	currentTask, errFetch := taskUC.taskRepository.FetchTaskByID(cxt, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if !canEditTask(cxt, currentTask, authority) {
+		return domain.Task{}, &domain.TaskError{Message: "You are not authorized to update this task", Code: http.StatusForbidden}
+	}
+	return currentTask, nil
+}
+
+// the owner, the assignees and admins may edit and delete a task. requests scoped to a project
+// have already been checked for the editor role in it, which is enough for every task of the project.
+func canEditTask(cxt context.Context, task domain.Task, authority domain.User) bool {
+	if authority.Role == "admin" || authority.ID == task.UserID || slices.Contains(task.Assignees, authority.ID) {
+		return true
+	}
+	projectID, _ := cxt.Value(infrastructure.CONTEXT_PROJECT).(string)
+	return projectID != "" && projectID == task.ProjectID
+}
+
+// only the owner and admins may hand a task over to another user, assignees and project editors
+// edit it for the owner
+func checkOwnerChange(task domain.Task, userID string, authority domain.User) *domain.TaskError {
+	if userID == task.UserID || authority.Role == "admin" || authority.ID == task.UserID {
+		return nil
+	}
+	return &domain.TaskError{Message: "Only the owner of the task or an admin can change its owner", Code: http.StatusForbidden}
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+func (taskUC *taskUseCase) AddDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if taskID == blockerID {
+		return domain.Task{}, &domain.TaskError{Message: "A task cannot block itself", Code: http.StatusBadRequest}
+	}
+	fetchedTasks, errFetch := taskUC.taskRepository.FetchTasksByIDs(context, []string{taskID, blockerID})
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if len(fetchedTasks) != 2 {
+		return domain.Task{}, &domain.TaskError{Message: "Task not found", Code: http.StatusNotFound}
+	}
+	if errCycle := taskUC.checkDependencyCycle(context, taskID, blockerID); errCycle != nil {
+		return domain.Task{}, errCycle
+	}
+
+	updatedTask, errUpdate := taskUC.taskRepository.AddDependency(context, taskID, blockerID)
+	if errUpdate != nil {
+		return domain.Task{}, errUpdate
+	}
+	return taskUC.markBlockedTask(context, updatedTask)
+}
+
+func (taskUC *taskUseCase) RemoveDependency(cxt context.Context, taskID string, blockerID string) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	updatedTask, errUpdate := taskUC.taskRepository.RemoveDependency(context, taskID, blockerID)
+	if errUpdate != nil {
+		return domain.Task{}, errUpdate
+	}
+	return taskUC.markBlockedTask(context, updatedTask)
+}
+
+// orders the tasks so that every task comes after the tasks in the set that block it,
+// ties keep the order the tasks were requested in
+func (taskUC *taskUseCase) PlanTasks(cxt context.Context, taskIDs []string) ([]domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	taskIDs = uniqueIDs(taskIDs)
+	if len(taskIDs) == 0 {
+		return []domain.Task{}, &domain.TaskError{Message: "At least one task ID is required", Code: http.StatusBadRequest}
+	}
+	if len(taskIDs) > MAX_TASK_PAGE_LIMIT {
+		return []domain.Task{}, &domain.TaskError{Message: "Too many tasks to plan", Code: http.StatusBadRequest}
+	}
+	fetchedTasks, errFetch := taskUC.taskRepository.FetchTasksByIDs(context, taskIDs)
+	if errFetch != nil {
+		return []domain.Task{}, errFetch
+	}
+	byID := map[string]domain.Task{}
+	for _, task := range fetchedTasks {
+		byID[task.ID] = task
+	}
+	for _, taskID := range taskIDs {
+		if _, ok := byID[taskID]; !ok {
+			return []domain.Task{}, &domain.TaskError{Message: "Task not found: " + taskID, Code: http.StatusNotFound}
+		}
+	}
+
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, taskID := range taskIDs {
+		for _, blockerID := range uniqueIDs(byID[taskID].BlockedBy) {
+			if _, inSet := byID[blockerID]; inSet {
+				pending[taskID]++
+				dependents[blockerID] = append(dependents[blockerID], taskID)
+			}
+		}
+	}
+	plan := []domain.Task{}
+	planned := map[string]bool{}
+	for len(plan) < len(taskIDs) {
+		next := ""
+		for _, taskID := range taskIDs {
+			if !planned[taskID] && pending[taskID] == 0 {
+				next = taskID
+				break
+			}
+		}
+		if next == "" {
+			return []domain.Task{}, &domain.TaskError{Message: "Task dependencies contain a cycle", Code: http.StatusConflict}
+		}
+		planned[next] = true
+		plan = append(plan, byID[next])
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return taskUC.markBlocked(context, plan)
+}
+
+// rejects blockerID -> taskID when taskID already blocks blockerID, directly or transitively.
+// the chain is followed through every task of the organization, not only those the caller sees.
+func (taskUC *taskUseCase) checkDependencyCycle(cxt context.Context, taskID string, blockerID string) *domain.TaskError {
+	visited := map[string]bool{blockerID: true}
+	frontier := []string{blockerID}
+	for len(frontier) > 0 {
+		fetchedTasks, errFetch := taskUC.taskRepository.FetchDependencies(cxt, frontier)
+		if errFetch != nil {
+			return errFetch
+		}
+		frontier = []string{}
+		for _, task := range fetchedTasks {
+			for _, upstreamID := range task.BlockedBy {
+				if upstreamID == taskID {
+					return &domain.TaskError{Message: "Dependency would create a cycle", Code: http.StatusConflict}
+				}
+				if !visited[upstreamID] {
+					visited[upstreamID] = true
+					frontier = append(frontier, upstreamID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (taskUC *taskUseCase) markBlockedTask(cxt context.Context, task domain.Task) (domain.Task, *domain.TaskError) {
+	marked, errMark := taskUC.markBlocked(cxt, []domain.Task{task})
+	if errMark != nil {
+		return domain.Task{}, errMark
+	}
+	return marked[0], nil
+}
+
+// a task is blocked while any of its existing blockers is not in a final status
+func (taskUC *taskUseCase) markBlocked(cxt context.Context, tasks []domain.Task) ([]domain.Task, *domain.TaskError) {
+	blockerIDs := []string{}
+	for _, task := range tasks {
+		blockerIDs = append(blockerIDs, task.BlockedBy...)
+	}
+	blockerIDs = uniqueIDs(blockerIDs)
+	if len(blockerIDs) == 0 {
+		return tasks, nil
+	}
+	blockers, errFetch := taskUC.taskRepository.FetchTasksByIDs(cxt, blockerIDs)
+	if errFetch != nil {
+		return tasks, errFetch
+	}
+	open := map[string]bool{}
+	for _, blocker := range blockers {
+		open[blocker.ID] = !isFinalStatus(taskUC.workflow, blocker.Status)
+	}
+	for i := range tasks {
+		tasks[i].Blocked = false
+		for _, blockerID := range tasks[i].BlockedBy {
+			if open[blockerID] {
+				tasks[i].Blocked = true
+				break
+			}
+		}
+	}
+	return tasks, nil
+}
+
+func uniqueIDs(IDs []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, ID := range IDs {
+		if ID != "" && !seen[ID] {
+			seen[ID] = true
+			unique = append(unique, ID)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// validates the estimate of the task, an estimate without a unit is in story points
+func checkEstimate(task *domain.Task) *domain.TaskError {
+	if task.Estimate < 0 {
+		return &domain.TaskError{Message: "estimate cannot be negative", Code: http.StatusBadRequest}
+	}
+	switch task.EstimateUnit {
+	case "":
+		if task.Estimate > 0 {
+			task.EstimateUnit = domain.ESTIMATE_POINTS
+		}
+	case domain.ESTIMATE_POINTS, domain.ESTIMATE_HOURS:
+	default:
+		return &domain.TaskError{Message: "estimate_unit must be points or hours", Code: http.StatusBadRequest}
+	}
+	return nil
+}
+
+// when a task moving from the current task's status to the given one was completed.
+// a task completed before keeps its completion time, a task leaving the final statuses has none.
+func completionTime(workflow domain.Workflow, current domain.Task, status string, now time.Time) time.Time {
+	if !isFinalStatus(workflow, status) {
+		return time.Time{}
+	}
+	if isFinalStatus(workflow, current.Status) && !current.CompletedAt.IsZero() {
+		return current.CompletedAt
+	}
+	return now
+}
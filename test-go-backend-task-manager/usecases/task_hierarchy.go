@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+// maximum number of ancestors walked when validating a parent
+const MAX_TASK_DEPTH = 64
+
+func (taskUC *taskUseCase) GetChildTasks(cxt context.Context, taskID string) ([]domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if _, errFetch := taskUC.taskRepository.FetchTaskByID(context, taskID); errFetch != nil {
+		return []domain.Task{}, errFetch
+	}
+	return taskUC.taskRepository.FetchChildTasks(context, taskID)
+}
+
+func (taskUC *taskUseCase) GetTaskTree(cxt context.Context, taskID string) (domain.TaskNode, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	subtree, errFetch := taskUC.taskRepository.FetchSubtree(context, taskID)
+	if errFetch != nil {
+		return domain.TaskNode{}, errFetch
+	}
+	if len(subtree) == 0 {
+		return domain.TaskNode{}, &domain.TaskError{Message: "Task not found", Code: http.StatusNotFound}
+	}
+	children := map[string][]domain.Task{}
+	for _, task := range subtree[1:] {
+		children[task.ParentID] = append(children[task.ParentID], task)
+	}
+	return taskUC.buildTaskNode(subtree[0], children), nil
+}
+
+// a leaf is complete once it reaches a final status, a parent is the average of its children
+func (taskUC *taskUseCase) buildTaskNode(task domain.Task, children map[string][]domain.Task) domain.TaskNode {
+	node := domain.TaskNode{Task: task, Children: []domain.TaskNode{}}
+	for _, child := range children[task.ID] {
+		node.Children = append(node.Children, taskUC.buildTaskNode(child, children))
+	}
+	if len(node.Children) == 0 {
+		if isFinalStatus(taskUC.workflow, task.Status) {
+			node.PercentComplete = 100
+		}
+		return node
+	}
+	total := 0.0
+	for _, child := range node.Children {
+		total += child.PercentComplete
+	}
+	node.PercentComplete = total / float64(len(node.Children))
+	return node
+}
+
+// checks that the parent exists and that attaching taskID under it does not create a cycle
+func (taskUC *taskUseCase) checkParent(cxt context.Context, taskID string, parentID string) *domain.TaskError {
+	ancestorID := parentID
+	for depth := 0; ancestorID != ""; depth++ {
+		if ancestorID == taskID {
+			return &domain.TaskError{Message: "A task cannot be nested under itself or its subtasks", Code: http.StatusBadRequest}
+		}
+		if depth == MAX_TASK_DEPTH {
+			return &domain.TaskError{Message: "Task hierarchy is too deep", Code: http.StatusBadRequest}
+		}
+		ancestor, errFetch := taskUC.taskRepository.FetchTaskByID(cxt, ancestorID)
+		if errFetch != nil {
+			if ancestorID == parentID {
+				return &domain.TaskError{Message: "Parent task not found", Code: http.StatusBadRequest}
+			}
+			return errFetch
+		}
+		ancestorID = ancestor.ParentID
+	}
+	return nil
+}
+
+func (taskUC *taskUseCase) checkChildrenClosed(cxt context.Context, taskID string) *domain.TaskError {
+	children, errFetch := taskUC.taskRepository.FetchChildTasks(cxt, taskID)
+	if errFetch != nil {
+		return errFetch
+	}
+	for _, child := range children {
+		if !isFinalStatus(taskUC.workflow, child.Status) {
+			return &domain.TaskError{Message: "Task has open subtasks", Code: http.StatusConflict}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// attempts at applying a patch without an expected version when the task keeps changing underneath it
+const MAX_PATCH_ATTEMPTS = 3
+
+// the task fields a patch may change, the others are managed by their own endpoints
+var patchableTaskFields = []string{"userID", "parentID", "tags", "title", "description", "status", "priority", "due_date", "recurrence", "recurrence_start", "estimate", "estimate_unit"}
+
+// applies the patch to the stored task and writes only the fields it changed. the change is
+// validated like a full update. a patch without an expected version is applied to the task as
+// it is when written, if it changes in between the patch is applied again.
+func (taskUC *taskUseCase) PatchTask(cxt context.Context, taskID string, patch domain.TaskPatch, authority domain.User) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		patchedTask, errPatch := taskUC.patchTask(context, taskID, patch, authority)
+		if errPatch == nil || errPatch.Code != http.StatusPreconditionFailed || patch.Version != 0 || attempt == MAX_PATCH_ATTEMPTS {
+			return patchedTask, errPatch
+		}
+	}
+}
+
+func (taskUC *taskUseCase) patchTask(cxt context.Context, taskID string, patch domain.TaskPatch, authority domain.User) (domain.Task, *domain.TaskError) {
+	currentTask, errFetch := taskUC.taskRepository.FetchTaskByID(cxt, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if !canEditTask(cxt, currentTask, authority) {
+		return domain.Task{}, &domain.TaskError{Message: "You are not authorized to update this task", Code: http.StatusForbidden}
+	}
+	if errVersion := checkVersion(currentTask, patch.Version); errVersion != nil {
+		return currentTask, errVersion
+	}
+	patchedTask, fields, errApply := applyTaskPatch(currentTask, patch)
+	if errApply != nil {
+		return domain.Task{}, errApply
+	}
+	if len(fields) == 0 {
+		return currentTask, nil
+	}
+
+	if patchedTask.UserID == "" || patchedTask.Title == "" {
+		return domain.Task{}, &domain.TaskError{Message: "userID and title cannot be removed", Code: http.StatusBadRequest}
+	}
+	if errOwner := checkOwnerChange(currentTask, patchedTask.UserID, authority); errOwner != nil {
+		return domain.Task{}, errOwner
+	}
+	if slices.Contains(fields, "tags") && len(patchedTask.Tags) > 0 {
+		tags, errTags := checkTaskTags(patchedTask.Tags)
+		if errTags != nil {
+			return domain.Task{}, errTags
+		}
+		patchedTask.Tags = tags
+	}
+	if slices.Contains(fields, "estimate") || slices.Contains(fields, "estimate_unit") {
+		unit := patchedTask.EstimateUnit
+		if errEstimate := checkEstimate(&patchedTask); errEstimate != nil {
+			return domain.Task{}, errEstimate
+		}
+		if patchedTask.EstimateUnit != unit && !slices.Contains(fields, "estimate_unit") {
+			fields = append(fields, "estimate_unit")
+		}
+	}
+	if patchedTask.Recurrence != "" && (slices.Contains(fields, "recurrence") || slices.Contains(fields, "recurrence_start")) {
+		if errRecurrence := checkRecurrence(&patchedTask, domain.Task{}); errRecurrence != nil {
+			return domain.Task{}, errRecurrence
+		}
+		if !slices.Contains(fields, "recurrence_start") {
+			fields = append(fields, "recurrence_start")
+		}
+	}
+	completing := false
+	if slices.Contains(fields, "status") {
+		if patchedTask.Status == "" {
+			return domain.Task{}, &domain.TaskError{Message: "status cannot be removed", Code: http.StatusBadRequest}
+		}
+		if errTransition := taskUC.checkTransition(cxt, currentTask.Status, patchedTask.Status); errTransition != nil {
+			return domain.Task{}, errTransition
+		}
+		completing = isFinalStatus(taskUC.workflow, patchedTask.Status) && !isFinalStatus(taskUC.workflow, currentTask.Status)
+		if completing {
+			if errChildren := taskUC.checkChildrenClosed(cxt, taskID); errChildren != nil {
+				return domain.Task{}, errChildren
+			}
+		}
+		patchedTask.CompletedAt = completionTime(taskUC.workflow, currentTask, patchedTask.Status, time.Now())
+		if !patchedTask.CompletedAt.Equal(currentTask.CompletedAt) {
+			fields = append(fields, "completed_at")
+		}
+	}
+	if slices.Contains(fields, "parentID") && patchedTask.ParentID != "" {
+		if errParent := taskUC.checkParent(cxt, taskID, patchedTask.ParentID); errParent != nil {
+			return domain.Task{}, errParent
+		}
+	}
+
+	// the patch was applied to this version, a task changed since then would be overwritten
+	patchedTask.Version = currentTask.Version
+	updatedTask, errUpdate := taskUC.taskRepository.PatchTask(cxt, patchedTask, fields)
+	if errUpdate != nil {
+		return updatedTask, errUpdate
+	}
+	// a recurring task spawns its next occurrence once, the first time it is completed
+	if completing && currentTask.NextOccurrenceID == "" && updatedTask.Recurrence != "" {
+		updatedTask = taskUC.spawnNextOccurrence(cxt, updatedTask)
+	}
+	taskUC.recordRevision(cxt, domain.REVISION_UPDATE, updatedTask, 0)
+	taskUC.audit(cxt, domain.AUDIT_TASK_UPDATE, taskID, currentTask, updatedTask)
+	return updatedTask, nil
+}
+
+// the task with the patch applied and the patchable fields that changed, in field order.
+// changing any other field is rejected.
+func applyTaskPatch(currentTask domain.Task, patch domain.TaskPatch) (domain.Task, []string, *domain.TaskError) {
+	document, err := json.Marshal(currentTask)
+	if err != nil {
+		return domain.Task{}, nil, &domain.TaskError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	var patched []byte
+	switch patch.Format {
+	case domain.PATCH_MERGE:
+		patched, err = infrastructure.MergePatch(document, patch.Patch)
+	case domain.PATCH_JSON:
+		patched, err = infrastructure.JSONPatch(document, patch.Patch)
+	default:
+		return domain.Task{}, nil, &domain.TaskError{Message: "Unsupported patch format: " + patch.Format, Code: http.StatusUnsupportedMediaType}
+	}
+	if errors.Is(err, infrastructure.ErrPatchTestFailed) {
+		return domain.Task{}, nil, &domain.TaskError{Message: err.Error(), Code: http.StatusConflict}
+	}
+	if errors.Is(err, infrastructure.ErrInvalidPatch) {
+		return domain.Task{}, nil, &domain.TaskError{Message: err.Error(), Code: http.StatusBadRequest}
+	}
+	if err != nil {
+		return domain.Task{}, nil, &domain.TaskError{Message: "Patch cannot be applied: " + err.Error(), Code: http.StatusUnprocessableEntity}
+	}
+
+	var patchedFields map[string]interface{}
+	var patchedTask domain.Task
+	if json.Unmarshal(patched, &patchedFields) != nil || json.Unmarshal(patched, &patchedTask) != nil {
+		return domain.Task{}, nil, &domain.TaskError{Message: "The patched document is not a valid task", Code: http.StatusUnprocessableEntity}
+	}
+	currentFields := jsonFields(currentTask)
+	for _, change := range diffFields(currentFields, patchedFields) {
+		if !slices.Contains(patchableTaskFields, change.Field) {
+			return domain.Task{}, nil, &domain.TaskError{Message: "Field " + change.Field + " cannot be patched", Code: http.StatusUnprocessableEntity}
+		}
+	}
+	patchedTask.ID = currentTask.ID
+
+	// compared through the task so that removed fields and their zero values are the same
+	fields := []string{}
+	normalizedFields := jsonFields(patchedTask)
+	for _, field := range patchableTaskFields {
+		if !reflect.DeepEqual(currentFields[field], normalizedFields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	return patchedTask, fields, nil
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// number of occurrences previewed when no count is given
+const DEFAULT_OCCURRENCE_PREVIEW = 5
+
+func (taskUC *taskUseCase) PreviewOccurrences(cxt context.Context, taskID string, count int) ([]time.Time, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if count == 0 {
+		count = DEFAULT_OCCURRENCE_PREVIEW
+	}
+	if count < 0 || count > MAX_TASK_PAGE_LIMIT {
+		return []time.Time{}, &domain.TaskError{Message: "Count must be between 1 and 100", Code: http.StatusBadRequest}
+	}
+	task, errFetch := taskUC.taskRepository.FetchTaskByID(context, taskID)
+	if errFetch != nil {
+		return []time.Time{}, errFetch
+	}
+	if task.Recurrence == "" {
+		return []time.Time{}, &domain.TaskError{Message: "Task does not recur", Code: http.StatusBadRequest}
+	}
+	rule, err := infrastructure.ParseRRule(task.Recurrence)
+	if err != nil {
+		return []time.Time{}, &domain.TaskError{Message: "Invalid recurrence: " + err.Error(), Code: http.StatusBadRequest}
+	}
+	return rule.After(recurrenceStart(task), task.DueDate, count), nil
+}
+
+// validates the recurrence rule of the task and anchors the series to its due date.
+// current holds the stored task on updates and is empty on creation.
+func checkRecurrence(task *domain.Task, current domain.Task) *domain.TaskError {
+	if _, err := infrastructure.ParseRRule(task.Recurrence); err != nil {
+		return &domain.TaskError{Message: "Invalid recurrence: " + err.Error(), Code: http.StatusBadRequest}
+	}
+	if task.RecurrenceStart.IsZero() {
+		task.RecurrenceStart = current.RecurrenceStart
+	}
+	if task.RecurrenceStart.IsZero() {
+		task.RecurrenceStart = task.DueDate
+	}
+	if task.RecurrenceStart.IsZero() {
+		task.RecurrenceStart = current.DueDate
+	}
+	if task.RecurrenceStart.IsZero() {
+		return &domain.TaskError{Message: "Recurring tasks need a due date", Code: http.StatusBadRequest}
+	}
+	return nil
+}
+
+// creates the occurrence that follows the completed task like any other task, so it gets its
+// first revision and an audit entry, and returns its ID. the ID is empty when the series has ended.
+func (taskUC *taskUseCase) createNextOccurrence(cxt context.Context, completed domain.Task) (string, *domain.TaskError) {
+	rule, err := infrastructure.ParseRRule(completed.Recurrence)
+	if err != nil {
+		return "", &domain.TaskError{Message: "Invalid recurrence: " + err.Error(), Code: http.StatusBadRequest}
+	}
+	next := rule.After(recurrenceStart(completed), completed.DueDate, 1)
+	if len(next) == 0 {
+		return "", nil
+	}
+	occurrence := domain.Task{
+		UserID:          completed.UserID,
+		OrgID:           completed.OrgID,
+		ProjectID:       completed.ProjectID,
+		Assignees:       completed.Assignees,
+		Watchers:        completed.Watchers,
+		ParentID:        completed.ParentID,
+		Tags:            completed.Tags,
+		Title:           completed.Title,
+		Description:     completed.Description,
+		Status:          taskUC.workflow.InitialStatus,
+		Priority:        completed.Priority,
+		Estimate:        completed.Estimate,
+		EstimateUnit:    completed.EstimateUnit,
+		DueDate:         next[0],
+		Recurrence:      completed.Recurrence,
+		RecurrenceStart: recurrenceStart(completed),
+		CreatedAt:       time.Now(),
+	}
+	return taskUC.CreateTask(cxt, occurrence)
+}
+
+// spawns the next occurrence of a task that was just completed and links it from the task.
+// it runs after the completing write, so a write that fails or conflicts leaves nothing behind.
+// the link is only set when no other completion of the task set one first, otherwise the new
+// occurrence is discarded. the completion stands either way, failures are only logged.
+func (taskUC *taskUseCase) spawnNextOccurrence(cxt context.Context, completed domain.Task) domain.Task {
+	nextID, errNext := taskUC.createNextOccurrence(cxt, completed)
+	if errNext != nil {
+		log.Println("Error", "creating the next occurrence of task", completed.ID, errNext)
+		return completed
+	}
+	if nextID == "" {
+		return completed
+	}
+	linkedTask, errLink := taskUC.taskRepository.LinkNextOccurrence(cxt, completed.ID, nextID)
+	if errLink != nil {
+		log.Println("Error", "linking occurrence", nextID, "to task", completed.ID, errLink)
+		taskUC.discardOccurrence(cxt, nextID)
+		return completed
+	}
+	return linkedTask
+}
+
+func (taskUC *taskUseCase) discardOccurrence(cxt context.Context, taskID string) {
+	if _, errDelete := taskUC.taskRepository.DeleteTask(cxt, taskID, 0); errDelete != nil {
+		log.Println("Error", "discarding occurrence", taskID, errDelete)
+		return
+	}
+	if errPurge := taskUC.taskRepository.PurgeTask(cxt, taskID, time.Time{}); errPurge != nil {
+		log.Println("Error", "discarding occurrence", taskID, errPurge)
+	}
+}
+
+// tasks stored before the series was anchored start at their due date
+func recurrenceStart(task domain.Task) time.Time {
+	if task.RecurrenceStart.IsZero() {
+		return task.DueDate
+	}
+	return task.RecurrenceStart
+}
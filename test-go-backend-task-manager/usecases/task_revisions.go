@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// keeps a numbered snapshot of the task after every create, update and revert
+func (taskUC *taskUseCase) SetRevisionRepository(revisionRepo domain.RevisionRepository) {
+	taskUC.revisionRepository = revisionRepo
+}
+
+func (taskUC *taskUseCase) GetRevisions(cxt context.Context, taskID string) ([]domain.TaskRevision, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if errEnabled := taskUC.checkRevisionsEnabled(); errEnabled != nil {
+		return []domain.TaskRevision{}, errEnabled
+	}
+	return taskUC.revisionRepository.FetchRevisions(context, taskID)
+}
+
+// the fields that changed from one revision of the task to another, either may be the older one
+func (taskUC *taskUseCase) DiffRevisions(cxt context.Context, taskID string, from int, to int) (domain.RevisionDiff, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if errEnabled := taskUC.checkRevisionsEnabled(); errEnabled != nil {
+		return domain.RevisionDiff{}, errEnabled
+	}
+	fromRevision, errFetch := taskUC.revisionRepository.FetchRevision(context, taskID, from)
+	if errFetch != nil {
+		return domain.RevisionDiff{}, errFetch
+	}
+	toRevision, errFetch := taskUC.revisionRepository.FetchRevision(context, taskID, to)
+	if errFetch != nil {
+		return domain.RevisionDiff{}, errFetch
+	}
+	// the revision and version numbers differ between any two snapshots and are not part of the content
+	fromRevision.Task.Revision, toRevision.Task.Revision = 0, 0
+	fromRevision.Task.Version, toRevision.Task.Version = 0, 0
+	return domain.RevisionDiff{From: from, To: to, Changes: diffFields(fromRevision.Task, toRevision.Task)}, nil
+}
+
+// restores the editable fields of the task to what they were at the given revision, checked like
+// an update by the same user. the revert is stored as a new revision, the revisions after the
+// restored one are kept.
+func (taskUC *taskUseCase) RevertTask(cxt context.Context, taskID string, revision int, authority domain.User) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if errEnabled := taskUC.checkRevisionsEnabled(); errEnabled != nil {
+		return domain.Task{}, errEnabled
+	}
+	restored, errFetch := taskUC.revisionRepository.FetchRevision(context, taskID, revision)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	currentTask, errFetch := taskUC.taskRepository.FetchTaskByID(context, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if !canEditTask(context, currentTask, authority) {
+		return domain.Task{}, &domain.TaskError{Message: "You are not authorized to update this task", Code: http.StatusForbidden}
+	}
+	if errOwner := checkOwnerChange(currentTask, restored.Task.UserID, authority); errOwner != nil {
+		return domain.Task{}, errOwner
+	}
+	// the workflow and the task hierarchy may have changed since the revision was taken
+	if restored.Task.Status != "" {
+		if !hasStatus(taskUC.workflow, restored.Task.Status) {
+			return domain.Task{}, &domain.TaskError{Message: "Unknown status: " + restored.Task.Status, Code: http.StatusConflict}
+		}
+		if errTransition := taskUC.checkTransition(context, currentTask.Status, restored.Task.Status); errTransition != nil {
+			return domain.Task{}, errTransition
+		}
+		if isFinalStatus(taskUC.workflow, restored.Task.Status) && !isFinalStatus(taskUC.workflow, currentTask.Status) {
+			if errChildren := taskUC.checkChildrenClosed(context, taskID); errChildren != nil {
+				return domain.Task{}, errChildren
+			}
+		}
+	}
+	if restored.Task.ParentID != "" && restored.Task.ParentID != currentTask.ParentID {
+		if errParent := taskUC.checkParent(context, taskID, restored.Task.ParentID); errParent != nil {
+			return domain.Task{}, errParent
+		}
+	}
+
+	restored.Task.ID = taskID
+	restored.Task.CompletedAt = completionTime(taskUC.workflow, currentTask, restored.Task.Status, time.Now())
+	revertedTask, errRestore := taskUC.taskRepository.RestoreTask(context, restored.Task)
+	if errRestore != nil {
+		return domain.Task{}, errRestore
+	}
+	taskUC.recordRevision(context, domain.REVISION_REVERT, revertedTask, revision)
+	taskUC.audit(context, domain.AUDIT_TASK_REVERT, taskID, currentTask, revertedTask)
+	return revertedTask, nil
+}
+
+// stores the task as it is now, a failure is only logged since the change itself has been made
+func (taskUC *taskUseCase) recordRevision(cxt context.Context, action string, task domain.Task, revertedFrom int) {
+	if taskUC.revisionRepository == nil {
+		return
+	}
+	if task.Revision == 0 {
+		log.Println("Error", "task", task.ID, "has no revision number, not recording", action)
+		return
+	}
+	author, _ := cxt.Value(infrastructure.CONTEXT_USERNAME).(string)
+	revision := domain.TaskRevision{
+		TaskID:       task.ID,
+		Number:       task.Revision,
+		Action:       action,
+		RevertedFrom: revertedFrom,
+		Author:       author,
+		CreatedAt:    time.Now(),
+		Task:         task,
+		OrgID:        task.OrgID,
+	}
+	if errCreate := taskUC.revisionRepository.CreateRevision(cxt, revision); errCreate != nil {
+		log.Println("Error", fmt.Sprintf("recording revision %d of task %s", task.Revision, task.ID), errCreate)
+	}
+}
+
+func (taskUC *taskUseCase) checkRevisionsEnabled() *domain.TaskError {
+	if taskUC.revisionRepository == nil {
+		return &domain.TaskError{Message: "Revision history is not enabled", Code: http.StatusNotImplemented}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+const (
+	MAX_TAG_LENGTH = 50
+	MAX_TASK_TAGS  = 20
+)
+
+func (taskUC *taskUseCase) GetTags(cxt context.Context) ([]domain.TagCount, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+	return taskUC.taskRepository.FetchTagCounts(context)
+}
+
+func (taskUC *taskUseCase) UpdateTaskTags(cxt context.Context, update domain.TagUpdate) (int64, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	taskIDs := uniqueIDs(update.TaskIDs)
+	if len(taskIDs) == 0 {
+		return 0, &domain.TaskError{Message: "At least one task ID is required", Code: http.StatusBadRequest}
+	}
+	if len(taskIDs) > MAX_TASK_PAGE_LIMIT {
+		return 0, &domain.TaskError{Message: "Too many tasks to tag", Code: http.StatusBadRequest}
+	}
+	add, errTags := normalizeTags(update.Add)
+	if errTags != nil {
+		return 0, errTags
+	}
+	remove, errTags := normalizeTags(update.Remove)
+	if errTags != nil {
+		return 0, errTags
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, &domain.TaskError{Message: "No tags to add or remove", Code: http.StatusBadRequest}
+	}
+	for _, tag := range add {
+		if containsTag(remove, tag) {
+			return 0, &domain.TaskError{Message: "Tag is both added and removed: " + tag, Code: http.StatusBadRequest}
+		}
+	}
+	return taskUC.taskRepository.UpdateTaskTags(context, taskIDs, add, remove)
+}
+
+func (taskUC *taskUseCase) RenameTag(cxt context.Context, tag string, newName string) (int64, *domain.TaskError) {
+	return taskUC.MergeTags(cxt, []string{tag}, newName)
+}
+
+// replaces every tag in tags with into on all tasks, tasks that end up with into twice keep one
+func (taskUC *taskUseCase) MergeTags(cxt context.Context, tags []string, into string) (int64, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	from, errTags := normalizeTags(tags)
+	if errTags != nil {
+		return 0, errTags
+	}
+	target, errTags := normalizeTags([]string{into})
+	if errTags != nil {
+		return 0, errTags
+	}
+	if len(from) == 0 || len(target) == 0 {
+		return 0, &domain.TaskError{Message: "Source and target tags are required", Code: http.StatusBadRequest}
+	}
+	return taskUC.taskRepository.ReplaceTags(context, from, target[0])
+}
+
+// the normalized tags of a single task
+func checkTaskTags(tags []string) ([]string, *domain.TaskError) {
+	normalized, errTags := normalizeTags(tags)
+	if errTags != nil {
+		return nil, errTags
+	}
+	if len(normalized) > MAX_TASK_TAGS {
+		return nil, &domain.TaskError{Message: fmt.Sprintf("A task can have at most %d tags", MAX_TASK_TAGS), Code: http.StatusBadRequest}
+	}
+	return normalized, nil
+}
+
+// trims, lower cases and deduplicates tags
+func normalizeTags(tags []string) ([]string, *domain.TaskError) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len([]rune(tag)) > MAX_TAG_LENGTH {
+			return nil, &domain.TaskError{Message: fmt.Sprintf("Tags must be at most %d characters", MAX_TAG_LENGTH), Code: http.StatusBadRequest}
+		}
+		if !containsTag(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+const (
+	// most recently deleted records listed from the trash
+	TRASH_LIST_LIMIT = 500
+	// records purged at most by one purge, the rest is left for the next one
+	PURGE_BATCH_SIZE = 100
+)
+
+func (taskUC *taskUseCase) GetDeletedTasks(cxt context.Context) ([]domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+	return taskUC.taskRepository.FetchDeletedTasks(context, time.Time{}, TRASH_LIST_LIMIT)
+}
+
+// takes the task out of the trash together with everything that belongs to it
+func (taskUC *taskUseCase) RestoreDeletedTask(cxt context.Context, taskID string) (domain.Task, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	restoredTask, errRestore := taskUC.taskRepository.UndeleteTask(context, taskID)
+	if errRestore != nil {
+		return domain.Task{}, errRestore
+	}
+	taskUC.audit(context, domain.AUDIT_TASK_RESTORE, taskID, nil, restoredTask)
+	return restoredTask, nil
+}
+
+// removes the tasks that were moved to the trash before deletedBefore for good, together with
+// their revisions and whatever the delete listeners clean up. returns the number of purged tasks,
+// a task that fails is skipped and the first failure is returned after the others are purged.
+func (taskUC *taskUseCase) PurgeDeletedTasks(cxt context.Context, deletedBefore time.Time) (int, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	expiredTasks, errFetch := taskUC.taskRepository.FetchDeletedTasks(context, deletedBefore, PURGE_BATCH_SIZE)
+	cancel()
+	if errFetch != nil {
+		return 0, errFetch
+	}
+
+	purged := 0
+	var firstErr *domain.TaskError
+	for _, task := range expiredTasks {
+		if errPurge := taskUC.purgeTask(cxt, task, deletedBefore); errPurge != nil {
+			if firstErr == nil {
+				firstErr = errPurge
+			}
+			continue
+		}
+		purged++
+	}
+	return purged, firstErr
+}
+
+// the task itself is removed last so that cleanup failing part way is retried by the next purge
+func (taskUC *taskUseCase) purgeTask(cxt context.Context, task domain.Task, deletedBefore time.Time) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if taskUC.revisionRepository != nil {
+		if errRevisions := taskUC.revisionRepository.DeleteRevisions(context, task.ID); errRevisions != nil {
+			return errRevisions
+		}
+	}
+	// tasks that were waiting on the purged task are no longer blocked by it
+	if errUnlink := taskUC.taskRepository.UnlinkDependents(context, task.ID); errUnlink != nil {
+		return errUnlink
+	}
+	for _, listener := range taskUC.deleteListeners {
+		if errListener := listener.TaskDeleted(context, task); errListener != nil {
+			return errListener
+		}
+	}
+	if errPurge := taskUC.taskRepository.PurgeTask(context, task.ID, deletedBefore); errPurge != nil {
+		return errPurge
+	}
+	taskUC.audit(withOrg(context, task.OrgID), domain.AUDIT_TASK_PURGE, task.ID, task, nil)
+	return nil
+}
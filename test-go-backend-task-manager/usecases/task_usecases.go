@@ -2,20 +2,47 @@ package usecases
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 )
 
+// paging limits and sortable fields for GetTasks
+const (
+	DEFAULT_TASK_PAGE_LIMIT = 20
+	MAX_TASK_PAGE_LIMIT     = 100
+)
+
+var sortableTaskFields = map[string]bool{
+	"due_date":   true,
+	"created_at": true,
+	"updated_at": true,
+	"priority":   true,
+	"status":     true,
+	"title":      true,
+}
+
 type taskUseCase struct {
-	taskRepository domain.TaskRepository
-	contextTimeout time.Duration
+	taskRepository     domain.TaskRepository
+	contextTimeout     time.Duration
+	workflow           domain.Workflow
+	deleteListeners    []domain.TaskDeleteListener
+	auditLog           domain.AuditRecorder
+	revisionRepository domain.RevisionRepository
 }
 
 func NewTaskUsecase(taskRepo domain.TaskRepository, timeout time.Duration) taskUseCase {
+	return NewTaskUsecaseWithWorkflow(taskRepo, timeout, DefaultWorkflow())
+}
+
+func NewTaskUsecaseWithWorkflow(taskRepo domain.TaskRepository, timeout time.Duration, workflow domain.Workflow) taskUseCase {
 	return taskUseCase{
 		taskRepository: taskRepo,
 		contextTimeout: timeout,
+		workflow:       workflow,
 	}
 }
 
@@ -27,11 +54,79 @@ func (taskUC *taskUseCase) GetAllTasks(cxt context.Context) ([]domain.Task, *dom
 	return taskUC.taskRepository.FetchAllTasks(context)
 }
 
+func (taskUC *taskUseCase) GetTasks(cxt context.Context, query domain.TaskQuery) (domain.TaskPage, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	if query.Limit <= 0 {
+		query.Limit = DEFAULT_TASK_PAGE_LIMIT
+	} else if query.Limit > MAX_TASK_PAGE_LIMIT {
+		query.Limit = MAX_TASK_PAGE_LIMIT
+	}
+	if query.SortBy != "" && !sortableTaskFields[query.SortBy] {
+		return domain.TaskPage{}, &domain.TaskError{Message: "Invalid sort field: " + query.SortBy, Code: http.StatusBadRequest}
+	}
+	switch query.SortOrder {
+	case 0:
+		query.SortOrder = 1
+	case 1, -1:
+	default:
+		return domain.TaskPage{}, &domain.TaskError{Message: "Invalid sort order", Code: http.StatusBadRequest}
+	}
+	if !query.DueAfter.IsZero() && !query.DueBefore.IsZero() && query.DueAfter.After(query.DueBefore) {
+		return domain.TaskPage{}, &domain.TaskError{Message: "due_after must not be later than due_before", Code: http.StatusBadRequest}
+	}
+	if len(query.AnyTags) > 0 {
+		anyTags, errTags := normalizeTags(query.AnyTags)
+		if errTags != nil {
+			return domain.TaskPage{}, errTags
+		}
+		query.AnyTags = anyTags
+	}
+	if len(query.AllTags) > 0 {
+		allTags, errTags := normalizeTags(query.AllTags)
+		if errTags != nil {
+			return domain.TaskPage{}, errTags
+		}
+		query.AllTags = allTags
+	}
+
+	page, errFetch := taskUC.taskRepository.FetchTasks(context, query)
+	if errFetch != nil {
+		return domain.TaskPage{}, errFetch
+	}
+	page.Tasks, errFetch = taskUC.markBlocked(context, page.Tasks)
+	if errFetch != nil {
+		return domain.TaskPage{}, errFetch
+	}
+	return page, nil
+}
+
+func (taskUC *taskUseCase) SearchTasks(cxt context.Context, query string, limit int) ([]domain.TaskSearchResult, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
+	defer cancel()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []domain.TaskSearchResult{}, &domain.TaskError{Message: "Search query is required", Code: http.StatusBadRequest}
+	}
+	if limit <= 0 {
+		limit = DEFAULT_TASK_PAGE_LIMIT
+	} else if limit > MAX_TASK_PAGE_LIMIT {
+		limit = MAX_TASK_PAGE_LIMIT
+	}
+	return taskUC.taskRepository.SearchTasks(context, query, limit)
+}
+
 func (taskUC taskUseCase) GetTaskByID(cxt context.Context, taskID string) (domain.Task, *domain.TaskError) {
 	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
 	defer cancel()
 
-	return taskUC.taskRepository.FetchTaskByID(context, taskID)
+	fetchedTask, errFetch := taskUC.taskRepository.FetchTaskByID(context, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	return taskUC.markBlockedTask(context, fetchedTask)
 }
 
 func (taskUC *taskUseCase) CreateTask(cxt context.Context, newTask domain.Task) (string, *domain.TaskError) {
@@ -40,18 +135,143 @@ func (taskUC *taskUseCase) CreateTask(cxt context.Context, newTask domain.Task)
 	if newTask.Title == "" {
 		return "", &domain.TaskError{Message: "Title is required", Code: 400}
 	}
+	// attachments only come in through UploadAttachment and the next occurrence is
+	// spawned when the task is completed, neither can be given by the client
+	newTask.Attachments = nil
+	newTask.NextOccurrenceID = ""
+	if newTask.Status == "" {
+		newTask.Status = taskUC.workflow.InitialStatus
+	} else if !hasStatus(taskUC.workflow, newTask.Status) {
+		return "", &domain.TaskError{Message: "Unknown status: " + newTask.Status, Code: http.StatusBadRequest}
+	}
+	if errEstimate := checkEstimate(&newTask); errEstimate != nil {
+		return "", errEstimate
+	}
+	newTask.CompletedAt = completionTime(taskUC.workflow, domain.Task{}, newTask.Status, time.Now())
+	if newTask.ParentID != "" {
+		if errParent := taskUC.checkParent(context, "", newTask.ParentID); errParent != nil {
+			return "", errParent
+		}
+	}
+	if newTask.Recurrence != "" {
+		if errRecurrence := checkRecurrence(&newTask, domain.Task{}); errRecurrence != nil {
+			return "", errRecurrence
+		}
+	}
+	if len(newTask.Tags) > 0 {
+		tags, errTags := checkTaskTags(newTask.Tags)
+		if errTags != nil {
+			return "", errTags
+		}
+		newTask.Tags = tags
+	}
+	if len(newTask.Assignees) > 0 {
+		newTask.Assignees = uniqueIDs(newTask.Assignees)
+	}
+	if len(newTask.Watchers) > 0 {
+		newTask.Watchers = uniqueIDs(newTask.Watchers)
+	}
+	if len(newTask.BlockedBy) > 0 {
+		newTask.BlockedBy = uniqueIDs(newTask.BlockedBy)
+		blockers, errFetch := taskUC.taskRepository.FetchTasksByIDs(context, newTask.BlockedBy)
+		if errFetch != nil {
+			return "", errFetch
+		}
+		if len(blockers) != len(newTask.BlockedBy) {
+			return "", &domain.TaskError{Message: "Blocking task not found", Code: http.StatusBadRequest}
+		}
+	}
 
-	return taskUC.taskRepository.CreateTask(context, newTask)
+	taskID, errCreate := taskUC.taskRepository.CreateTask(context, newTask)
+	if errCreate != nil {
+		return "", errCreate
+	}
+	newTask.ID = taskID
+	// the repository starts every task at its first revision
+	newTask.Revision = 1
+	taskUC.recordRevision(context, domain.REVISION_CREATE, newTask, 0)
+	taskUC.audit(context, domain.AUDIT_TASK_CREATE, taskID, nil, newTask)
+	return taskID, nil
 }
 
-func (taskUC *taskUseCase) UpdateTask(cxt context.Context, updateTask domain.Task) (domain.Task, *domain.TaskError) {
+func (taskUC *taskUseCase) UpdateTask(cxt context.Context, updateTask domain.Task, authority domain.User) (domain.Task, *domain.TaskError) {
 	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
 	defer cancel()
 
-	return taskUC.taskRepository.UpdateTask(context, updateTask)
+	currentTask, errFetch := taskUC.taskRepository.FetchTaskByID(context, updateTask.ID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if !canEditTask(context, currentTask, authority) {
+		return domain.Task{}, &domain.TaskError{Message: "You are not authorized to update this task", Code: http.StatusForbidden}
+	}
+	if errOwner := checkOwnerChange(currentTask, updateTask.UserID, authority); errOwner != nil {
+		return domain.Task{}, errOwner
+	}
+	// fails before the update is validated, the repository checks the version again when writing
+	if errVersion := checkVersion(currentTask, updateTask.Version); errVersion != nil {
+		return currentTask, errVersion
+	}
+	if updateTask.Recurrence != "" {
+		if errRecurrence := checkRecurrence(&updateTask, currentTask); errRecurrence != nil {
+			return domain.Task{}, errRecurrence
+		}
+	}
+	if len(updateTask.Tags) > 0 {
+		tags, errTags := checkTaskTags(updateTask.Tags)
+		if errTags != nil {
+			return domain.Task{}, errTags
+		}
+		updateTask.Tags = tags
+	}
+	// an estimate given without a unit stays in the unit of the current one
+	if updateTask.EstimateUnit == "" {
+		updateTask.EstimateUnit = currentTask.EstimateUnit
+	}
+	if errEstimate := checkEstimate(&updateTask); errEstimate != nil {
+		return domain.Task{}, errEstimate
+	}
+	completing := false
+	// the completion time follows the status, it cannot be set directly
+	updateTask.CompletedAt = time.Time{}
+	// an empty status leaves the current one untouched
+	if updateTask.Status != "" {
+		if errTransition := taskUC.checkTransition(context, currentTask.Status, updateTask.Status); errTransition != nil {
+			return domain.Task{}, errTransition
+		}
+		updateTask.CompletedAt = completionTime(taskUC.workflow, currentTask, updateTask.Status, time.Now())
+		completing = isFinalStatus(taskUC.workflow, updateTask.Status) && !isFinalStatus(taskUC.workflow, currentTask.Status)
+		if completing {
+			if errChildren := taskUC.checkChildrenClosed(context, updateTask.ID); errChildren != nil {
+				return domain.Task{}, errChildren
+			}
+		}
+	}
+	if updateTask.ParentID != "" {
+		if errParent := taskUC.checkParent(context, updateTask.ID, updateTask.ParentID); errParent != nil {
+			return domain.Task{}, errParent
+		}
+	}
+	// only set when the next occurrence is spawned
+	updateTask.NextOccurrenceID = ""
+
+	updatedTask, errUpdate := taskUC.taskRepository.UpdateTask(context, updateTask)
+	if errUpdate != nil {
+		// on a version conflict the repository hands back the task as it is now
+		return updatedTask, errUpdate
+	}
+	// a recurring task spawns its next occurrence once, the first time it is completed
+	if completing && currentTask.NextOccurrenceID == "" && updatedTask.Recurrence != "" {
+		updatedTask = taskUC.spawnNextOccurrence(context, updatedTask)
+	}
+	taskUC.recordRevision(context, domain.REVISION_UPDATE, updatedTask, 0)
+	taskUC.audit(context, domain.AUDIT_TASK_UPDATE, updatedTask.ID, currentTask, updatedTask)
+	return updatedTask, nil
 }
 
-func (taskUC *taskUseCase) DeleteTask(cxt context.Context, taskID string, authorityID string) (domain.Task, *domain.TaskError) {
+// moves the task to the trash, when expectedVersion is not zero only if the task is still at that version.
+// what belongs to the task is kept until it is purged.
+func (taskUC *taskUseCase) DeleteTask(cxt context.Context, taskID string, authority domain.User, expectedVersion int) (domain.Task, *domain.TaskError) {
 	context, cancel := context.WithTimeout(cxt, taskUC.contextTimeout)
 	defer cancel()
 
@@ -60,9 +280,41 @@ func (taskUC *taskUseCase) DeleteTask(cxt context.Context, taskID string, author
 		return domain.Task{}, errFetch
 	}
 
-	if authorityID != fetchedTask.UserID {
+	if !canEditTask(context, fetchedTask, authority) {
 		return domain.Task{}, &domain.TaskError{Message: "You are not authorized to delete this task", Code: 403}
 	}
+	if errVersion := checkVersion(fetchedTask, expectedVersion); errVersion != nil {
+		return fetchedTask, errVersion
+	}
+
+	deletedTask, errDelete := taskUC.taskRepository.DeleteTask(context, taskID, expectedVersion)
+	if errDelete != nil {
+		return deletedTask, errDelete
+	}
+	taskUC.audit(context, domain.AUDIT_TASK_DELETE, taskID, fetchedTask, nil)
+	return deletedTask, nil
+}
+
+// a zero expected version skips the check
+func checkVersion(task domain.Task, expectedVersion int) *domain.TaskError {
+	if expectedVersion != 0 && task.Version != expectedVersion {
+		return &domain.TaskError{Message: fmt.Sprintf("Task has been modified, it is now at version %d", task.Version), Code: http.StatusPreconditionFailed}
+	}
+	return nil
+}
+
+// records every create, update and delete of a task in the audit log
+func (taskUC *taskUseCase) SetAuditLog(auditLog domain.AuditRecorder) {
+	taskUC.auditLog = auditLog
+}
+
+func (taskUC *taskUseCase) audit(cxt context.Context, action string, taskID string, before interface{}, after interface{}) {
+	if taskUC.auditLog != nil {
+		taskUC.auditLog.Record(cxt, newAuditEntry(cxt, action, domain.AUDIT_TARGET_TASK, taskID, before, after))
+	}
+}
 
-	return taskUC.taskRepository.DeleteTask(context, taskID)
+// registers cleanup that runs when a task is purged from the trash, in registration order
+func (taskUC *taskUseCase) AddDeleteListener(listener domain.TaskDeleteListener) {
+	taskUC.deleteListeners = append(taskUC.deleteListeners, listener)
 }
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+const (
+	MAX_TIME_NOTE_LENGTH = 1000
+	// manual entries longer than this are most likely typos
+	MAX_TIME_ENTRY_DURATION = 24 * time.Hour
+)
+
+type timeUseCase struct {
+	timeRepository domain.TimeEntryRepository
+	taskRepository domain.TaskRepository
+	contextTimeout time.Duration
+}
+
+func NewTimeUsecase(timeRepo domain.TimeEntryRepository, taskRepo domain.TaskRepository, timeout time.Duration) timeUseCase {
+	return timeUseCase{
+		timeRepository: timeRepo,
+		taskRepository: taskRepo,
+		contextTimeout: timeout,
+	}
+}
+
+// starts a timer on the task for the user, who may not have another one running
+func (timeUC *timeUseCase) StartTimer(cxt context.Context, taskID string, note string, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+
+	note, errNote := checkTimeNote(note)
+	if errNote != nil {
+		return domain.TimeEntry{}, errNote
+	}
+	task, errTask := timeUC.trackableTask(context, taskID, user)
+	if errTask != nil {
+		return domain.TimeEntry{}, errTask
+	}
+	_, errRunning := timeUC.timeRepository.FetchRunningEntry(context, user.ID)
+	if errRunning == nil {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "A timer is already running", Code: http.StatusConflict}
+	}
+	if errRunning.Code != http.StatusNotFound {
+		return domain.TimeEntry{}, errRunning
+	}
+	now := time.Now()
+	entry := domain.TimeEntry{
+		UserID:    user.ID,
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		OrgID:     task.OrgID,
+		Start:     now,
+		Note:      note,
+		Running:   true,
+		CreatedAt: now,
+	}
+	insertedID, errCreate := timeUC.timeRepository.CreateTimeEntry(context, entry)
+	if errCreate != nil {
+		return domain.TimeEntry{}, errCreate
+	}
+	entry.ID = insertedID
+	return entry, nil
+}
+
+func (timeUC *timeUseCase) StopTimer(cxt context.Context, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+
+	running, errFetch := timeUC.timeRepository.FetchRunningEntry(context, user.ID)
+	if errFetch != nil {
+		return domain.TimeEntry{}, errFetch
+	}
+	stopped, errStop := timeUC.timeRepository.StopTimeEntry(context, running.ID, time.Now())
+	if errStop != nil {
+		return domain.TimeEntry{}, errStop
+	}
+	return withSeconds(stopped), nil
+}
+
+func (timeUC *timeUseCase) GetRunningTimer(cxt context.Context, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+	return timeUC.timeRepository.FetchRunningEntry(context, user.ID)
+}
+
+// records time the user spent on a task without running a timer
+func (timeUC *timeUseCase) AddTimeEntry(cxt context.Context, entry domain.TimeEntry, user domain.User) (domain.TimeEntry, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+
+	note, errNote := checkTimeNote(entry.Note)
+	if errNote != nil {
+		return domain.TimeEntry{}, errNote
+	}
+	if entry.Start.IsZero() || entry.End.IsZero() {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "start and end are required", Code: http.StatusBadRequest}
+	}
+	if !entry.End.After(entry.Start) {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "end must be after start", Code: http.StatusBadRequest}
+	}
+	if entry.End.After(time.Now()) {
+		return domain.TimeEntry{}, &domain.TaskError{Message: "end cannot be in the future", Code: http.StatusBadRequest}
+	}
+	if entry.End.Sub(entry.Start) > MAX_TIME_ENTRY_DURATION {
+		return domain.TimeEntry{}, &domain.TaskError{Message: fmt.Sprintf("An entry cannot be longer than %v", MAX_TIME_ENTRY_DURATION), Code: http.StatusBadRequest}
+	}
+	task, errTask := timeUC.trackableTask(context, entry.TaskID, user)
+	if errTask != nil {
+		return domain.TimeEntry{}, errTask
+	}
+	newEntry := domain.TimeEntry{
+		UserID:    user.ID,
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		OrgID:     task.OrgID,
+		Start:     entry.Start,
+		End:       entry.End,
+		Note:      note,
+		Manual:    true,
+		CreatedAt: time.Now(),
+	}
+	insertedID, errCreate := timeUC.timeRepository.CreateTimeEntry(context, newEntry)
+	if errCreate != nil {
+		return domain.TimeEntry{}, errCreate
+	}
+	newEntry.ID = insertedID
+	return withSeconds(newEntry), nil
+}
+
+// every entry of the task, by anyone
+func (timeUC *timeUseCase) GetTaskTime(cxt context.Context, taskID string) (domain.TimeSummary, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+
+	if _, errFetch := timeUC.taskRepository.FetchTaskByID(context, taskID); errFetch != nil {
+		return domain.TimeSummary{}, errFetch
+	}
+	return timeUC.summarize(context, domain.TimeQuery{TaskID: taskID})
+}
+
+func (timeUC *timeUseCase) GetUserTime(cxt context.Context, query domain.TimeQuery) (domain.TimeSummary, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+
+	if query.UserID == "" {
+		return domain.TimeSummary{}, &domain.TaskError{Message: "userID is required", Code: http.StatusBadRequest}
+	}
+	if errRange := checkTimeRange(query); errRange != nil {
+		return domain.TimeSummary{}, errRange
+	}
+	return timeUC.summarize(context, domain.TimeQuery{UserID: query.UserID, From: query.From, To: query.To})
+}
+
+// the stopped entries in the range summed by project, user and day
+func (timeUC *timeUseCase) GetTimeReport(cxt context.Context, query domain.TimeQuery) (domain.TimeReport, *domain.TaskError) {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+
+	if errRange := checkTimeRange(query); errRange != nil {
+		return domain.TimeReport{}, errRange
+	}
+	if query.TimeZone == "" {
+		query.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(query.TimeZone); err != nil {
+		return domain.TimeReport{}, &domain.TaskError{Message: "Unknown time zone " + query.TimeZone, Code: http.StatusBadRequest}
+	}
+	rows, errFetch := timeUC.timeRepository.FetchTimeReport(context, query)
+	if errFetch != nil {
+		return domain.TimeReport{}, errFetch
+	}
+	report := domain.TimeReport{From: query.From, To: query.To, TimeZone: query.TimeZone, Rows: rows}
+	for _, row := range rows {
+		report.Seconds += row.Seconds
+	}
+	return report, nil
+}
+
+// the entries stay for billing, only the timers still running on the task are stopped
+func (timeUC *timeUseCase) TaskDeleted(cxt context.Context, task domain.Task) *domain.TaskError {
+	context, cancel := context.WithTimeout(cxt, timeUC.contextTimeout)
+	defer cancel()
+	return timeUC.timeRepository.StopTaskTimers(context, task.ID, time.Now())
+}
+
+// the task when the user may track time on it, which is when they may edit it
+func (timeUC *timeUseCase) trackableTask(cxt context.Context, taskID string, user domain.User) (domain.Task, *domain.TaskError) {
+	task, errFetch := timeUC.taskRepository.FetchTaskByID(cxt, taskID)
+	if errFetch != nil {
+		return domain.Task{}, errFetch
+	}
+	if !canEditTask(cxt, task, user) {
+		return domain.Task{}, &domain.TaskError{Message: "You are not authorized to track time on this task", Code: http.StatusForbidden}
+	}
+	return task, nil
+}
+
+func (timeUC *timeUseCase) summarize(cxt context.Context, query domain.TimeQuery) (domain.TimeSummary, *domain.TaskError) {
+	entries, errFetch := timeUC.timeRepository.FetchTimeEntries(cxt, query)
+	if errFetch != nil {
+		return domain.TimeSummary{}, errFetch
+	}
+	summary := domain.TimeSummary{Entries: entries}
+	for i := range summary.Entries {
+		summary.Entries[i] = withSeconds(summary.Entries[i])
+		summary.Seconds += summary.Entries[i].Seconds
+	}
+	return summary, nil
+}
+
+func withSeconds(entry domain.TimeEntry) domain.TimeEntry {
+	if !entry.Running && !entry.End.IsZero() {
+		entry.Seconds = int64(entry.End.Sub(entry.Start) / time.Second)
+	}
+	return entry
+}
+
+func checkTimeNote(note string) (string, *domain.TaskError) {
+	note = strings.TrimSpace(note)
+	if len(note) > MAX_TIME_NOTE_LENGTH {
+		return "", &domain.TaskError{Message: fmt.Sprintf("A note cannot be longer than %d characters", MAX_TIME_NOTE_LENGTH), Code: http.StatusBadRequest}
+	}
+	return note, nil
+}
+
+func checkTimeRange(query domain.TimeQuery) *domain.TaskError {
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return &domain.TaskError{Message: "from must be before to", Code: http.StatusBadRequest}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// logs users in through the identity provider. the users it finds, links and provisions belong
+// to the organization orgID, an empty orgID being the default organization.
+func (userUC *userUsercase) SetIdentityProvider(identities domain.IdentityProvider, orgID string) {
+	userUC.identities = identities
+	userUC.identityOrg = orgID
+}
+
+// a new login at the identity provider with its own state, nonce and PKCE code verifier
+func (userUC userUsercase) BeginOIDCLogin(cxt context.Context) (domain.OIDCLogin, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	if userUC.identities == nil {
+		return domain.OIDCLogin{}, &domain.UserError{Message: "Single sign-on is not enabled", Code: http.StatusNotImplemented}
+	}
+	login := domain.OIDCLogin{State: newOIDCSecret(), Nonce: newOIDCSecret(), CodeVerifier: newOIDCSecret()}
+	URL, err := userUC.identities.AuthCodeURL(context, login.State, login.Nonce, codeChallenge(login.CodeVerifier))
+	if err != nil {
+		return domain.OIDCLogin{}, identityError(err)
+	}
+	login.URL = URL
+	return login, nil
+}
+
+// redeems the code the provider redirected back with for a session. state is the one the provider
+// sent back, it has to be the one of the login so that nobody can slip their own code to the user.
+func (userUC userUsercase) CompleteOIDCLogin(cxt context.Context, code string, state string, login domain.OIDCLogin) (domain.Session, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	if userUC.identities == nil {
+		return domain.Session{}, &domain.UserError{Message: "Single sign-on is not enabled", Code: http.StatusNotImplemented}
+	}
+	if login.State == "" || subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 {
+		return domain.Session{}, &domain.UserError{Message: "The login state does not match, please log in again", Code: http.StatusUnauthorized}
+	}
+	identity, err := userUC.identities.Exchange(context, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return domain.Session{}, identityError(err)
+	}
+	user, errUser := userUC.identityUser(withOrg(context, userUC.identityOrg), identity)
+	if errUser != nil {
+		return domain.Session{}, errUser
+	}
+	return userUC.loginSession(context, user)
+}
+
+// the user linked to the identity. on its first login the identity is linked to the user with its
+// email, when the provider verified it, and otherwise a new user is provisioned for it.
+func (userUC userUsercase) identityUser(cxt context.Context, identity domain.ExternalIdentity) (domain.User, *domain.UserError) {
+	user, errFetch := userUC.userRepository.FetchUserByIdentity(cxt, identity.Issuer, identity.Subject)
+	if errFetch == nil || errFetch.Code != http.StatusNotFound {
+		return user, errFetch
+	}
+	email := ""
+	if identity.EmailVerified {
+		email = normalizeEmail(identity.Email)
+	}
+	if email != "" {
+		existing, errEmail := userUC.userRepository.FetchUserByEmail(cxt, email)
+		if errEmail == nil {
+			linked, errLink := userUC.userRepository.LinkIdentity(cxt, existing.ID, identity.Issuer, identity.Subject)
+			if errLink != nil && errLink.Code == http.StatusNotFound {
+				return domain.User{}, &domain.UserError{Message: "The user with this email is linked to another identity", Code: http.StatusConflict}
+			}
+			if errLink != nil {
+				return domain.User{}, errLink
+			}
+			userUC.audit(cxt, domain.AUDIT_USER_LINK, linked.ID, linked.Username, existing, linked)
+			return linked, nil
+		}
+		if errEmail.Code != http.StatusNotFound {
+			return domain.User{}, errEmail
+		}
+	}
+	return userUC.provisionUser(cxt, identity, email)
+}
+
+// creates a user for the identity, named after the first of its preferred username, its email
+// and its subject that is free. the first user of the configured organization becomes its admin.
+func (userUC userUsercase) provisionUser(cxt context.Context, identity domain.ExternalIdentity, email string) (domain.User, *domain.UserError) {
+	documentCount, errCount := userUC.userRepository.FetchUserCount(cxt)
+	if errCount != nil {
+		return domain.User{}, errCount
+	}
+	newUser := domain.User{
+		Role:            "user",
+		OrgID:           userUC.identityOrg,
+		Email:           email,
+		IdentityIssuer:  identity.Issuer,
+		IdentitySubject: identity.Subject,
+	}
+	if documentCount == 0 {
+		newUser.Role = "admin"
+	}
+	subjectHash := sha256.Sum256([]byte(identity.Issuer + "\x00" + identity.Subject))
+	candidates := []string{identity.Username, email, "oidc_" + hex.EncodeToString(subjectHash[:8])}
+	for _, username := range candidates {
+		if username == "" {
+			continue
+		}
+		newUser.Username = username
+		inserted, errCreate := userUC.userRepository.CreateUser(cxt, newUser)
+		if errCreate == nil {
+			newUser.ID = inserted
+			userUC.audit(cxt, domain.AUDIT_USER_CREATE, inserted, username, nil, newUser)
+			return newUser, nil
+		}
+		if errCreate.Code != http.StatusConflict {
+			return domain.User{}, errCreate
+		}
+		// another login of the same identity may have provisioned it in the meantime
+		if user, errFetch := userUC.userRepository.FetchUserByIdentity(cxt, identity.Issuer, identity.Subject); errFetch == nil {
+			return user, nil
+		}
+	}
+	return domain.User{}, &domain.UserError{Message: "No free username for the identity, or its user is in the trash", Code: http.StatusConflict}
+}
+
+// failures to reach the provider are its fault, every other failure means the login was refused
+func identityError(err error) *domain.UserError {
+	if errors.Is(err, infrastructure.ErrOIDCUnavailable) {
+		return &domain.UserError{Message: "Single sign-on failed: " + err.Error(), Code: http.StatusBadGateway}
+	}
+	return &domain.UserError{Message: "Single sign-on failed: " + err.Error(), Code: http.StatusUnauthorized}
+}
+
+// emails are compared without case
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// 256 random bits, which also makes a valid PKCE code verifier
+func newOIDCSecret() string {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	return base64.RawURLEncoding.EncodeToString(secret)
+}
+
+// the S256 code challenge of RFC 7636
+func codeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
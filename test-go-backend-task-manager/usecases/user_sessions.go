@@ -0,0 +1,229 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// how long a refresh token can be exchanged when REFRESH_TOKEN_DURATION is unset
+const DEFAULT_REFRESH_TOKEN_DURATION = 30 * 24 * time.Hour
+
+// hands out a refresh token with every access token, without it logins only issue access tokens
+func (userUC *userUsercase) SetRefreshTokenRepository(refreshTokens domain.RefreshTokenRepository) {
+	userUC.refreshTokens = refreshTokens
+}
+
+// exchanges the refresh token for a new session in the same family. a refresh token can only be
+// exchanged once, presenting it again revokes every token of the family so that a stolen token
+// stops working for the thief and the owner alike.
+func (userUC userUsercase) RefreshSession(cxt context.Context, refreshToken string) (domain.Session, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	if userUC.refreshTokens == nil {
+		return domain.Session{}, &domain.UserError{Message: "Refresh tokens are not enabled", Code: http.StatusNotImplemented}
+	}
+	now := time.Now()
+	tokenID := hashRefreshToken(refreshToken)
+	token, errFetch := userUC.fetchRefreshToken(context, tokenID)
+	if errFetch != nil {
+		return domain.Session{}, errFetch
+	}
+	if errRejected := userUC.rejectRefreshToken(context, token, now); errRejected != nil {
+		return domain.Session{}, errRejected
+	}
+	// the user is read again so that the new access token carries their current role
+	user, errUser := userUC.userRepository.FetchUserByID(withOrg(context, token.OrgID), token.UserID)
+	if errUser != nil {
+		return domain.Session{}, errUser
+	}
+	// sessions that began before the policy required a second factor of the user end with their access token
+	required, errPolicy := userUC.twoFactorRequired(context, user)
+	if errPolicy != nil {
+		return domain.Session{}, errPolicy
+	}
+	if required && !twoFactorEnabled(user) {
+		return domain.Session{}, &domain.UserError{Message: "Two-factor authentication is required, please log in again", Code: http.StatusUnauthorized}
+	}
+	if _, errConsume := userUC.refreshTokens.ConsumeRefreshToken(context, tokenID, now); errConsume != nil {
+		if errConsume.Code != http.StatusNotFound {
+			return domain.Session{}, errConsume
+		}
+		// another request exchanged, revoked or outlived the token in the meantime
+		token, errFetch = userUC.fetchRefreshToken(context, tokenID)
+		if errFetch != nil {
+			return domain.Session{}, errFetch
+		}
+		if errRejected := userUC.rejectRefreshToken(context, token, now); errRejected != nil {
+			return domain.Session{}, errRejected
+		}
+		return domain.Session{}, &domain.UserError{Message: "Invalid refresh token", Code: http.StatusUnauthorized}
+	}
+	return userUC.newSession(context, user, token.FamilyID)
+}
+
+// revokes every refresh token of the session and, when given, the access token the logout was made with.
+// other access tokens already handed out stay valid until they expire.
+func (userUC userUsercase) Logout(cxt context.Context, refreshToken string, accessToken string) *domain.UserError {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	if userUC.refreshTokens == nil {
+		return &domain.UserError{Message: "Refresh tokens are not enabled", Code: http.StatusNotImplemented}
+	}
+	token, errFetch := userUC.fetchRefreshToken(context, hashRefreshToken(refreshToken))
+	if errFetch != nil {
+		return errFetch
+	}
+	accessClaims, errAccess := userUC.sessionAccessToken(context, token, accessToken)
+	if errAccess != nil {
+		return errAccess
+	}
+	if errRevoke := userUC.refreshTokens.RevokeTokenFamily(context, token.FamilyID, time.Now()); errRevoke != nil {
+		return errRevoke
+	}
+	if accessClaims.ID != "" && userUC.revocations != nil {
+		if err := userUC.revocations.RevokeToken(context, accessClaims.ID, accessClaims.ExpiresAt.Time); err != nil {
+			return &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+		}
+	}
+	userUC.auditToken(context, domain.AUDIT_USER_LOGOUT, token)
+	return nil
+}
+
+// the claims of the access token presented with the refresh token, which has to belong to the same
+// user. access tokens that have expired already need no revocation and give empty claims.
+func (userUC userUsercase) sessionAccessToken(cxt context.Context, token domain.RefreshToken, accessToken string) (infrastructure.UserClaim, *domain.UserError) {
+	if accessToken == "" {
+		return infrastructure.UserClaim{}, nil
+	}
+	claims, err := infrastructure.ParseAccessToken(accessToken)
+	if errors.Is(err, infrastructure.ErrTokenExpired) {
+		return infrastructure.UserClaim{}, nil
+	}
+	if err != nil {
+		return infrastructure.UserClaim{}, &domain.UserError{Message: "Invalid access token", Code: http.StatusUnauthorized}
+	}
+	user, errFetch := userUC.userRepository.FetchUserByID(withOrg(cxt, token.OrgID), token.UserID)
+	if errFetch != nil {
+		return infrastructure.UserClaim{}, errFetch
+	}
+	if claims.Username != user.Username {
+		return infrastructure.UserClaim{}, &domain.UserError{Message: "Access token does not belong to the session", Code: http.StatusUnauthorized}
+	}
+	return claims, nil
+}
+
+// an access token for the user and, when refresh tokens are enabled, a refresh token in the family.
+// an empty family starts a new one named after its first token.
+func (userUC userUsercase) newSession(cxt context.Context, user domain.User, familyID string) (domain.Session, *domain.UserError) {
+	accessDuration, errDuration := durationSecondsFromEnv("SIGNITURE_TIME_DURATION", 0)
+	if errDuration != nil {
+		return domain.Session{}, errDuration
+	}
+	accessToken, errToken := infrastructure.CreateJWTToken(user.Username, user.Role, user.OrgID, accessDuration)
+	if errToken != nil {
+		return domain.Session{}, &domain.UserError{Message: errToken.Error(), Code: http.StatusInternalServerError}
+	}
+	session := domain.Session{Token: accessToken, ExpiresIn: int64(accessDuration / time.Second)}
+	if userUC.refreshTokens == nil {
+		return session, nil
+	}
+
+	refreshDuration, errDuration := durationSecondsFromEnv("REFRESH_TOKEN_DURATION", DEFAULT_REFRESH_TOKEN_DURATION)
+	if errDuration != nil {
+		return domain.Session{}, errDuration
+	}
+	refreshToken := newRefreshToken()
+	tokenID := hashRefreshToken(refreshToken)
+	if familyID == "" {
+		familyID = tokenID
+	}
+	now := time.Now()
+	errCreate := userUC.refreshTokens.CreateRefreshToken(cxt, domain.RefreshToken{
+		ID:        tokenID,
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		OrgID:     user.OrgID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(refreshDuration),
+	})
+	if errCreate != nil {
+		return domain.Session{}, errCreate
+	}
+	session.RefreshToken = refreshToken
+	return session, nil
+}
+
+func (userUC userUsercase) fetchRefreshToken(cxt context.Context, tokenID string) (domain.RefreshToken, *domain.UserError) {
+	token, errFetch := userUC.refreshTokens.FetchRefreshToken(cxt, tokenID)
+	if errFetch != nil && errFetch.Code == http.StatusNotFound {
+		return domain.RefreshToken{}, &domain.UserError{Message: "Invalid refresh token", Code: http.StatusUnauthorized}
+	}
+	return token, errFetch
+}
+
+// the reason the token cannot be exchanged, nil when it can. a token that was already
+// exchanged is being reused, which revokes its family.
+func (userUC userUsercase) rejectRefreshToken(cxt context.Context, token domain.RefreshToken, now time.Time) *domain.UserError {
+	switch {
+	case !token.RevokedAt.IsZero():
+		return &domain.UserError{Message: "Refresh token has been revoked", Code: http.StatusUnauthorized}
+	case !token.UsedAt.IsZero():
+		if errRevoke := userUC.refreshTokens.RevokeTokenFamily(cxt, token.FamilyID, now); errRevoke != nil {
+			return errRevoke
+		}
+		userUC.auditToken(cxt, domain.AUDIT_USER_TOKEN_REUSE, token)
+		return &domain.UserError{Message: "Refresh token has already been used, please log in again", Code: http.StatusUnauthorized}
+	case !token.ExpiresAt.After(now):
+		return &domain.UserError{Message: "Refresh token has expired", Code: http.StatusUnauthorized}
+	}
+	return nil
+}
+
+// refresh tokens are presented without an access token, so the owner of the token is the actor
+func (userUC userUsercase) auditToken(cxt context.Context, action string, token domain.RefreshToken) {
+	if userUC.auditLog == nil {
+		return
+	}
+	username := ""
+	if user, errFetch := userUC.userRepository.FetchUserByID(withOrg(cxt, token.OrgID), token.UserID); errFetch == nil {
+		username = user.Username
+	}
+	userUC.audit(withOrg(cxt, token.OrgID), action, token.UserID, username, nil, nil)
+}
+
+// the number of seconds in the environment variable, fallback when it is unset
+func durationSecondsFromEnv(name string, fallback time.Duration) (time.Duration, *domain.UserError) {
+	value := os.Getenv(name)
+	if value == "" && fallback > 0 {
+		return fallback, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// 256 random bits, only their hash is stored
+func newRefreshToken() string {
+	token := make([]byte, 32)
+	rand.Read(token)
+	return hex.EncodeToString(token)
+}
+
+func hashRefreshToken(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+)
+
+func (userUC userUsercase) GetDeletedUsers(cxt context.Context) ([]domain.User, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+	return userUC.userRepository.FetchDeletedUsers(context, time.Time{}, TRASH_LIST_LIMIT)
+}
+
+func (userUC userUsercase) RestoreDeletedUser(cxt context.Context, userID string) (domain.User, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	restoredUser, errRestore := userUC.userRepository.UndeleteUser(context, userID)
+	if errRestore != nil {
+		return domain.User{}, errRestore
+	}
+	userUC.audit(context, domain.AUDIT_USER_RESTORE, userID, "", nil, restoredUser)
+	return restoredUser, nil
+}
+
+// removes the users that were moved to the trash before deletedBefore for good and frees their
+// usernames. returns the number of purged users, a user that fails is skipped and the first
+// failure is returned after the others are purged.
+func (userUC userUsercase) PurgeDeletedUsers(cxt context.Context, deletedBefore time.Time) (int, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	expiredUsers, errFetch := userUC.userRepository.FetchDeletedUsers(context, deletedBefore, PURGE_BATCH_SIZE)
+	if errFetch != nil {
+		return 0, errFetch
+	}
+	purged := 0
+	var firstErr *domain.UserError
+	for _, user := range expiredUsers {
+		if errPurge := userUC.userRepository.PurgeUser(context, user.ID, deletedBefore); errPurge != nil {
+			if firstErr == nil {
+				firstErr = errPurge
+			}
+			continue
+		}
+		userUC.audit(withOrg(context, user.OrgID), domain.AUDIT_USER_PURGE, user.ID, "", user, nil)
+		purged++
+	}
+	return purged, firstErr
+}
@@ -0,0 +1,405 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+const (
+	// how long a login waits for its second factor
+	TWO_FACTOR_TOKEN_DURATION = 5 * time.Minute
+	// wrong codes in a row after which the second factor of the user is locked
+	MAX_TWO_FACTOR_FAILURES = 5
+	// how long the second factor stays locked, every wrong code after it locks it again
+	TWO_FACTOR_LOCKOUT  = 15 * time.Minute
+	RECOVERY_CODE_COUNT = 10
+	// the name authenticator apps show next to the codes when TOTP_ISSUER is unset
+	DEFAULT_TOTP_ISSUER = "Task Manager"
+)
+
+// requires a second factor of the users whose role the policy of their organization names,
+// without it only the users who enrolled one are asked for it
+func (userUC *userUsercase) SetTwoFactorPolicyRepository(policies domain.TwoFactorPolicyRepository) {
+	userUC.policies = policies
+}
+
+// the session of a user whose password or identity was verified. users with a second factor,
+// or whose role requires one, get a two-factor token to complete the login with instead.
+func (userUC userUsercase) loginSession(cxt context.Context, user domain.User) (domain.Session, *domain.UserError) {
+	// logins are made without a token, their audit entries belong to the user's organization
+	cxt = withOrg(cxt, user.OrgID)
+	required, errPolicy := userUC.twoFactorRequired(cxt, user)
+	if errPolicy != nil {
+		return domain.Session{}, errPolicy
+	}
+	enabled := twoFactorEnabled(user)
+	if !enabled && !required {
+		session, errSession := userUC.newSession(cxt, user, "")
+		if errSession != nil {
+			return domain.Session{}, errSession
+		}
+		userUC.audit(cxt, domain.AUDIT_USER_LOGIN, user.ID, user.Username, nil, nil)
+		return session, nil
+	}
+	token, err := infrastructure.CreateTwoFactorToken(user.ID, user.OrgID, TWO_FACTOR_TOKEN_DURATION)
+	if err != nil {
+		return domain.Session{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return domain.Session{
+		TwoFactorToken:              token,
+		ExpiresIn:                   int64(TWO_FACTOR_TOKEN_DURATION / time.Second),
+		TwoFactorEnrollmentRequired: !enabled,
+	}, nil
+}
+
+// completes the login with a one-time password or a recovery code. a login that required an
+// enrollment completes with the first code of the enrollment, which enables it.
+func (userUC userUsercase) CompleteTwoFactorLogin(cxt context.Context, twoFactorToken string, code string) (domain.Session, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.twoFactorLoginUser(context, twoFactorToken)
+	if errUser != nil {
+		return domain.Session{}, errUser
+	}
+	// the login is made without a token, what it changes and records belongs to the user's organization
+	context = withOrg(context, user.OrgID)
+	if user.TwoFactor == nil {
+		return domain.Session{}, &domain.UserError{Message: "Two-factor authentication is not enrolled", Code: http.StatusConflict}
+	}
+	var recoveryCodes []string
+	if user.TwoFactor.Enabled {
+		if _, errVerify := userUC.verifySecondFactor(context, user, code, true); errVerify != nil {
+			return domain.Session{}, errVerify
+		}
+	} else {
+		step, errVerify := userUC.verifySecondFactor(context, user, code, false)
+		if errVerify != nil {
+			return domain.Session{}, errVerify
+		}
+		codes, errEnable := userUC.enableTwoFactor(context, user, step)
+		if errEnable != nil {
+			return domain.Session{}, errEnable
+		}
+		recoveryCodes = codes
+	}
+	session, errSession := userUC.newSession(context, user, "")
+	if errSession != nil {
+		return domain.Session{}, errSession
+	}
+	session.RecoveryCodes = recoveryCodes
+	userUC.audit(context, domain.AUDIT_USER_LOGIN, user.ID, user.Username, nil, nil)
+	return session, nil
+}
+
+func (userUC userUsercase) EnrollTwoFactorAtLogin(cxt context.Context, twoFactorToken string) (domain.TwoFactorEnrollment, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.twoFactorLoginUser(context, twoFactorToken)
+	if errUser != nil {
+		return domain.TwoFactorEnrollment{}, errUser
+	}
+	context = withOrg(context, user.OrgID)
+	return userUC.enrollTwoFactor(context, user)
+}
+
+func (userUC userUsercase) GetTwoFactorStatus(cxt context.Context, userID string) (domain.TwoFactorStatus, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.userRepository.FetchUserByID(context, userID)
+	if errUser != nil {
+		return domain.TwoFactorStatus{}, errUser
+	}
+	required, errPolicy := userUC.twoFactorRequired(context, user)
+	if errPolicy != nil {
+		return domain.TwoFactorStatus{}, errPolicy
+	}
+	status := domain.TwoFactorStatus{Enabled: twoFactorEnabled(user), Required: required}
+	if status.Enabled {
+		status.RecoveryCodesRemaining = len(user.TwoFactor.RecoveryCodes)
+	}
+	return status, nil
+}
+
+// a new secret for the user, which only takes effect once ConfirmTwoFactor receives its first code
+func (userUC userUsercase) EnrollTwoFactor(cxt context.Context, userID string) (domain.TwoFactorEnrollment, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.userRepository.FetchUserByID(context, userID)
+	if errUser != nil {
+		return domain.TwoFactorEnrollment{}, errUser
+	}
+	return userUC.enrollTwoFactor(context, user)
+}
+
+func (userUC userUsercase) ConfirmTwoFactor(cxt context.Context, userID string, code string) ([]string, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.userRepository.FetchUserByID(context, userID)
+	if errUser != nil {
+		return nil, errUser
+	}
+	if user.TwoFactor == nil {
+		return nil, &domain.UserError{Message: "Two-factor authentication is not enrolled", Code: http.StatusConflict}
+	}
+	if user.TwoFactor.Enabled {
+		return nil, &domain.UserError{Message: "Two-factor authentication is already enabled", Code: http.StatusConflict}
+	}
+	step, errVerify := userUC.verifySecondFactor(context, user, code, false)
+	if errVerify != nil {
+		return nil, errVerify
+	}
+	return userUC.enableTwoFactor(context, user, step)
+}
+
+// removes the second factor of the user, which takes a valid code unless the enrollment was never confirmed
+func (userUC userUsercase) DisableTwoFactor(cxt context.Context, userID string, code string) *domain.UserError {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.userRepository.FetchUserByID(context, userID)
+	if errUser != nil {
+		return errUser
+	}
+	if user.TwoFactor == nil {
+		return &domain.UserError{Message: "Two-factor authentication is not enabled", Code: http.StatusConflict}
+	}
+	if user.TwoFactor.Enabled {
+		required, errPolicy := userUC.twoFactorRequired(context, user)
+		if errPolicy != nil {
+			return errPolicy
+		}
+		if required {
+			return &domain.UserError{Message: "Two-factor authentication is required for your role", Code: http.StatusForbidden}
+		}
+		if _, errVerify := userUC.verifySecondFactor(context, user, code, true); errVerify != nil {
+			return errVerify
+		}
+	}
+	if errUpdate := userUC.userRepository.UpdateTwoFactor(context, user.ID, nil); errUpdate != nil {
+		return errUpdate
+	}
+	if user.TwoFactor.Enabled {
+		userUC.audit(context, domain.AUDIT_USER_2FA_DISABLE, user.ID, user.Username, nil, nil)
+	}
+	return nil
+}
+
+// replaces the recovery codes of the user with new ones, which takes a one-time password
+func (userUC userUsercase) RegenerateRecoveryCodes(cxt context.Context, userID string, code string) ([]string, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	user, errUser := userUC.userRepository.FetchUserByID(context, userID)
+	if errUser != nil {
+		return nil, errUser
+	}
+	if !twoFactorEnabled(user) {
+		return nil, &domain.UserError{Message: "Two-factor authentication is not enabled", Code: http.StatusConflict}
+	}
+	step, errVerify := userUC.verifySecondFactor(context, user, code, false)
+	if errVerify != nil {
+		return nil, errVerify
+	}
+	codes, hashes := newRecoveryCodes()
+	twoFactor := *user.TwoFactor
+	twoFactor.LastStep = step
+	twoFactor.Failures = 0
+	twoFactor.RecoveryCodes = hashes
+	if errUpdate := userUC.userRepository.UpdateTwoFactor(context, user.ID, &twoFactor); errUpdate != nil {
+		return nil, errUpdate
+	}
+	userUC.audit(context, domain.AUDIT_USER_2FA_RECOVERY_CODES, user.ID, user.Username, nil, nil)
+	return codes, nil
+}
+
+// removes the second factor of a user who lost it, a user whose role requires one enrolls again at their next login
+func (userUC userUsercase) ResetTwoFactor(cxt context.Context, authority domain.User, userID string) *domain.UserError {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	fetchedAuthority, err := userUC.userRepository.FetchUserByID(context, authority.ID)
+	if err != nil {
+		return err
+	}
+	if fetchedAuthority.Role != "admin" {
+		return &domain.UserError{Message: "Unauthorized to make this update", Code: http.StatusUnauthorized}
+	}
+	if errUpdate := userUC.userRepository.UpdateTwoFactor(context, userID, nil); errUpdate != nil {
+		return errUpdate
+	}
+	userUC.audit(context, domain.AUDIT_USER_2FA_DISABLE, userID, fetchedAuthority.Username, nil, nil)
+	return nil
+}
+
+func (userUC userUsercase) GetTwoFactorPolicy(cxt context.Context) (domain.TwoFactorPolicy, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	if userUC.policies == nil {
+		return domain.TwoFactorPolicy{}, &domain.UserError{Message: "Two-factor policies are not enabled", Code: http.StatusNotImplemented}
+	}
+	return userUC.policies.FetchTwoFactorPolicy(context)
+}
+
+func (userUC userUsercase) UpdateTwoFactorPolicy(cxt context.Context, authority domain.User, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+
+	if userUC.policies == nil {
+		return domain.TwoFactorPolicy{}, &domain.UserError{Message: "Two-factor policies are not enabled", Code: http.StatusNotImplemented}
+	}
+	roles := []string{}
+	for _, role := range policy.RequiredRoles {
+		if role != "admin" && role != "user" {
+			return domain.TwoFactorPolicy{}, &domain.UserError{Message: "Unknown role: " + role, Code: http.StatusBadRequest}
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	policy.RequiredRoles = roles
+
+	fetchedAuthority, err := userUC.userRepository.FetchUserByID(context, authority.ID)
+	if err != nil {
+		return domain.TwoFactorPolicy{}, err
+	}
+	if fetchedAuthority.Role != "admin" {
+		return domain.TwoFactorPolicy{}, &domain.UserError{Message: "Unauthorized to make this update", Code: http.StatusUnauthorized}
+	}
+	previous, errFetch := userUC.policies.FetchTwoFactorPolicy(context)
+	if errFetch != nil {
+		return domain.TwoFactorPolicy{}, errFetch
+	}
+	updated, errUpdate := userUC.policies.UpdateTwoFactorPolicy(context, policy)
+	if errUpdate != nil {
+		return domain.TwoFactorPolicy{}, errUpdate
+	}
+	if userUC.auditLog != nil {
+		userUC.auditLog.Record(context, newAuditEntry(context, domain.AUDIT_TWO_FACTOR_POLICY, domain.AUDIT_TARGET_TWO_FACTOR_POLICY, updated.OrgID, previous, updated))
+	}
+	return updated, nil
+}
+
+// the user the two-factor token was issued to
+func (userUC userUsercase) twoFactorLoginUser(cxt context.Context, twoFactorToken string) (domain.User, *domain.UserError) {
+	claims, err := infrastructure.ParseTwoFactorToken(twoFactorToken)
+	if err != nil {
+		return domain.User{}, &domain.UserError{Message: "Invalid two-factor token, please log in again", Code: http.StatusUnauthorized}
+	}
+	return userUC.userRepository.FetchUserByID(withOrg(cxt, claims.OrgID), claims.UserID)
+}
+
+// whether the policy of the user's organization requires a second factor for their role
+func (userUC userUsercase) twoFactorRequired(cxt context.Context, user domain.User) (bool, *domain.UserError) {
+	if userUC.policies == nil {
+		return false, nil
+	}
+	policy, errFetch := userUC.policies.FetchTwoFactorPolicy(withOrg(cxt, user.OrgID))
+	if errFetch != nil {
+		return false, errFetch
+	}
+	return slices.Contains(policy.RequiredRoles, user.Role), nil
+}
+
+func (userUC userUsercase) enrollTwoFactor(cxt context.Context, user domain.User) (domain.TwoFactorEnrollment, *domain.UserError) {
+	if twoFactorEnabled(user) {
+		return domain.TwoFactorEnrollment{}, &domain.UserError{Message: "Two-factor authentication is already enabled", Code: http.StatusConflict}
+	}
+	secret := infrastructure.NewTOTPSecret()
+	if errUpdate := userUC.userRepository.UpdateTwoFactor(cxt, user.ID, &domain.TwoFactor{Secret: secret}); errUpdate != nil {
+		return domain.TwoFactorEnrollment{}, errUpdate
+	}
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = DEFAULT_TOTP_ISSUER
+	}
+	return domain.TwoFactorEnrollment{Secret: secret, URI: infrastructure.TOTPURI(issuer, user.Username, secret)}, nil
+}
+
+// enables the enrollment the code of the step confirmed and returns its recovery codes
+func (userUC userUsercase) enableTwoFactor(cxt context.Context, user domain.User, step int64) ([]string, *domain.UserError) {
+	codes, hashes := newRecoveryCodes()
+	twoFactor := *user.TwoFactor
+	twoFactor.Enabled = true
+	twoFactor.LastStep = step
+	twoFactor.Failures = 0
+	twoFactor.RecoveryCodes = hashes
+	if errUpdate := userUC.userRepository.UpdateTwoFactor(cxt, user.ID, &twoFactor); errUpdate != nil {
+		return nil, errUpdate
+	}
+	userUC.audit(cxt, domain.AUDIT_USER_2FA_ENABLE, user.ID, user.Username, nil, nil)
+	return codes, nil
+}
+
+// checks the one-time password, or when allowRecovery is set the recovery code, of the user and
+// returns the time step of the accepted password. every password and recovery code is accepted once.
+func (userUC userUsercase) verifySecondFactor(cxt context.Context, user domain.User, code string, allowRecovery bool) (int64, *domain.UserError) {
+	twoFactor := user.TwoFactor
+	now := time.Now()
+	if twoFactor.Failures >= MAX_TWO_FACTOR_FAILURES && now.Sub(twoFactor.FailedAt) < TWO_FACTOR_LOCKOUT {
+		return 0, &domain.UserError{Message: "Too many invalid codes, please try again later", Code: http.StatusTooManyRequests}
+	}
+	if step, matched := infrastructure.MatchTOTP(twoFactor.Secret, code, now); matched {
+		errAccept := userUC.userRepository.AcceptTwoFactorStep(cxt, user.ID, step)
+		if errAccept == nil {
+			return step, nil
+		}
+		if errAccept.Code != http.StatusNotFound {
+			return 0, errAccept
+		}
+		// the code was used already, which is as good as a wrong one
+	} else if allowRecovery {
+		errConsume := userUC.userRepository.ConsumeRecoveryCode(cxt, user.ID, hashRecoveryCode(code))
+		if errConsume == nil {
+			userUC.audit(cxt, domain.AUDIT_USER_2FA_RECOVERY, user.ID, user.Username, nil, nil)
+			return twoFactor.LastStep, nil
+		}
+		if errConsume.Code != http.StatusNotFound {
+			return 0, errConsume
+		}
+	}
+	if errRecord := userUC.userRepository.RecordTwoFactorFailure(cxt, user.ID, now); errRecord != nil {
+		return 0, errRecord
+	}
+	return 0, &domain.UserError{Message: "Invalid two-factor code", Code: http.StatusUnauthorized}
+}
+
+func twoFactorEnabled(user domain.User) bool {
+	return user.TwoFactor != nil && user.TwoFactor.Enabled
+}
+
+// recovery codes of 40 random bits in two groups of five hex digits, and the hashes they are stored as
+func newRecoveryCodes() ([]string, []string) {
+	codes := make([]string, RECOVERY_CODE_COUNT)
+	hashes := make([]string, RECOVERY_CODE_COUNT)
+	for i := range codes {
+		code := make([]byte, 5)
+		rand.Read(code)
+		digits := hex.EncodeToString(code)
+		codes[i] = digits[:5] + "-" + digits[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes
+}
+
+// recovery codes are compared without their case, spaces and dashes
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
@@ -2,19 +2,24 @@ package usecases
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
-	"os"
-	"strconv"
 	"time"
 
 	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
 	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type userUsercase struct {
 	userRepository domain.UserRepository
 	timeout        time.Duration
+	auditLog       domain.AuditRecorder
+	refreshTokens  domain.RefreshTokenRepository
+	revocations    infrastructure.TokenRevocationStore
+	identities     domain.IdentityProvider
+	identityOrg    string
+	policies       domain.TwoFactorPolicyRepository
 }
 
 func NewUserUsecase(userRepo domain.UserRepository, timeout time.Duration) userUsercase {
@@ -47,36 +52,90 @@ func (userUC userUsercase) GetUserByUsername(cxt context.Context, username strin
 func (userUC userUsercase) CreateUser(cxt context.Context, newUser domain.User) (string, *domain.UserError) {
 	context, cancel := context.WithTimeout(cxt, userUC.timeout)
 	defer cancel()
-	documentCount, err := userUC.userRepository.FetchUserCount(context)
+	// users created by an admin join the admin's organization. self-registration joins the default
+	// organization, the organization never comes from the request.
+	newUser.Role = "user"
+	if _, scoped := context.Value(infrastructure.CONTEXT_ORG).(string); !scoped {
+		// only the first user of the deployment becomes an admin, the admins of the other
+		// organizations are created with them
+		documentCount, err := userUC.userRepository.FetchUserCount(context)
+		if err != nil {
+			return "", &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+		}
+		if documentCount == 0 {
+			newUser.Role = "admin"
+		}
+		context = withOrg(context, "")
+	}
+	return userUC.insertUser(context, newUser)
+}
+
+// founds a new organization with its first admin, only admins of the default organization may
+func (userUC userUsercase) CreateOrganization(cxt context.Context, newAdmin domain.User) (domain.User, *domain.UserError) {
+	context, cancel := context.WithTimeout(cxt, userUC.timeout)
+	defer cancel()
+	if orgID, _ := context.Value(infrastructure.CONTEXT_ORG).(string); orgID != "" {
+		return domain.User{}, &domain.UserError{Message: "Only admins of the default organization can create organizations", Code: http.StatusForbidden}
+	}
+	orgID := newOrgID()
+	newAdmin.Role = "admin"
+	inserted, err := userUC.insertUser(withOrg(context, orgID), newAdmin)
 	if err != nil {
-		return "", &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+		return domain.User{}, err
 	}
+	return domain.User{ID: inserted, Username: newAdmin.Username, Role: newAdmin.Role, OrgID: orgID}, nil
+}
+
+// inserts the user into the organization of the context with the role already decided
+func (userUC userUsercase) insertUser(cxt context.Context, newUser domain.User) (string, *domain.UserError) {
 	newUser.ID = ""
-	if documentCount == 0 {
-		newUser.Role = "admin"
-	} else {
-		newUser.Role = "user"
-	}
+	newUser.OrgID = ""
 	hashed, errhash := infrastructure.HashPassword(newUser.Password)
 	if errhash != nil {
 		return "", &domain.UserError{Message: errhash.Error(), Code: http.StatusInternalServerError}
 	}
 	newUser.Password = hashed
-	inserted, err := userUC.userRepository.CreateUser(context, newUser)
+	// emails given at registration are not verified, linking the identity provider by them would
+	// hand the account to whoever registered first
+	newUser.Email = ""
+	inserted, err := userUC.userRepository.CreateUser(cxt, newUser)
 	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
+		if err.Code == http.StatusConflict {
 			return "", &domain.UserError{Message: "Username already exists", Code: http.StatusConflict}
 		} else {
 			return "", &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 		}
 	}
+	newUser.ID = inserted
+	// users registering themselves are not logged in yet
+	userUC.audit(cxt, domain.AUDIT_USER_CREATE, inserted, newUser.Username, nil, newUser)
 	return inserted, nil
 }
 
 func (userUC userUsercase) UpdateUser(cxt context.Context, updateUser domain.User) (domain.User, *domain.UserError) {
 	context, cancel := context.WithTimeout(cxt, userUC.timeout)
 	defer cancel()
-	return userUC.userRepository.UpdateUser(context, updateUser)
+	updateUser.Email = normalizeEmail(updateUser.Email)
+	var currentUser domain.User
+	if userUC.auditLog != nil || userUC.revocations != nil {
+		fetchedUser, errFetch := userUC.userRepository.FetchUserByID(context, updateUser.ID)
+		if errFetch != nil {
+			return domain.User{}, errFetch
+		}
+		currentUser = fetchedUser
+	}
+	// the tokens still carry the old role. they are revoked first, so a failure leaves the role as it was.
+	if updateUser.Role != currentUser.Role {
+		if errRevoke := userUC.revokeTokens(context, currentUser); errRevoke != nil {
+			return domain.User{}, errRevoke
+		}
+	}
+	updatedUser, errUpdate := userUC.userRepository.UpdateUser(context, updateUser)
+	if errUpdate != nil {
+		return domain.User{}, errUpdate
+	}
+	userUC.audit(context, domain.AUDIT_USER_UPDATE, updatedUser.ID, "", currentUser, updatedUser)
+	return updatedUser, nil
 }
 
 func (userUC userUsercase) DeleteUser(cxt context.Context, authority domain.User, deleteID string) (domain.User, *domain.UserError) {
@@ -92,30 +151,84 @@ func (userUC userUsercase) DeleteUser(cxt context.Context, authority domain.User
 	if fetchedAuthority.ID == deleteID {
 		return domain.User{}, &domain.UserError{Message: "Unauthorized to delete yourself", Code: http.StatusUnauthorized}
 	}
-	return userUC.userRepository.DeleteUser(context, deleteID)
+	if userUC.revocations != nil {
+		deletingUser, errFetch := userUC.userRepository.FetchUserByID(context, deleteID)
+		if errFetch != nil {
+			return domain.User{}, errFetch
+		}
+		if errRevoke := userUC.revokeTokens(context, deletingUser); errRevoke != nil {
+			return domain.User{}, errRevoke
+		}
+	}
+	deletedUser, errDelete := userUC.userRepository.DeleteUser(context, deleteID)
+	if errDelete != nil {
+		return domain.User{}, errDelete
+	}
+	userUC.audit(context, domain.AUDIT_USER_DELETE, deleteID, fetchedAuthority.Username, deletedUser, nil)
+	return deletedUser, nil
 }
 
-func (userUC userUsercase) LoginUser(cxt context.Context, loggingUser domain.User) (string, *domain.UserError) {
+func (userUC userUsercase) LoginUser(cxt context.Context, loggingUser domain.User) (domain.Session, *domain.UserError) {
 	context, cancel := context.WithTimeout(cxt, userUC.timeout)
 	defer cancel()
 	result, err := userUC.userRepository.FetchUserByUsername(context, loggingUser.Username)
 	if err != nil {
-		return "", &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+		userUC.audit(context, domain.AUDIT_USER_LOGIN_FAILED, "", loggingUser.Username, nil, nil)
+		return domain.Session{}, &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
 	}
 	if err := infrastructure.ValidatePassword(result.Password, loggingUser.Password); err != nil {
-		return "", &domain.UserError{Message: "Password validation failed", Code: http.StatusUnauthorized}
+		userUC.audit(withOrg(context, result.OrgID), domain.AUDIT_USER_LOGIN_FAILED, result.ID, loggingUser.Username, nil, nil)
+		return domain.Session{}, &domain.UserError{Message: "Password validation failed", Code: http.StatusUnauthorized}
 	}
 
 	if result.Role != loggingUser.Role {
-		return "", &domain.UserError{Message: "Role mismatch", Code: http.StatusUnauthorized}
+		userUC.audit(withOrg(context, result.OrgID), domain.AUDIT_USER_LOGIN_FAILED, result.ID, loggingUser.Username, nil, nil)
+		return domain.Session{}, &domain.UserError{Message: "Role mismatch", Code: http.StatusUnauthorized}
 	}
-	timeDurationEnv, errDuration := strconv.ParseInt(os.Getenv("SIGNITURE_TIME_DURATION"), 10, 64)
-	if errDuration != nil {
-		return "", &domain.UserError{Message: errDuration.Error(), Code: http.StatusInternalServerError}
+	return userUC.loginSession(context, result)
+}
+
+// 128 random bits, organization IDs are never taken from clients
+func newOrgID() string {
+	orgID := make([]byte, 16)
+	rand.Read(orgID)
+	return hex.EncodeToString(orgID)
+}
+
+// scopes the queries made with the context to the organization, like requests made with a token
+func withOrg(cxt context.Context, orgID string) context.Context {
+	return context.WithValue(cxt, infrastructure.CONTEXT_ORG, orgID)
+}
+
+// records every create, update and delete of a user and every login attempt in the audit log
+func (userUC *userUsercase) SetAuditLog(auditLog domain.AuditRecorder) {
+	userUC.auditLog = auditLog
+}
+
+// refuses the tokens of a user once their role changes or they are deleted
+func (userUC *userUsercase) SetTokenRevocationStore(revocations infrastructure.TokenRevocationStore) {
+	userUC.revocations = revocations
+}
+
+// revokes every token issued to the user so far
+func (userUC userUsercase) revokeTokens(cxt context.Context, user domain.User) *domain.UserError {
+	if userUC.revocations == nil {
+		return nil
+	}
+	if err := userUC.revocations.RevokeUserTokens(cxt, user.Username, time.Now()); err != nil {
+		return &domain.UserError{Message: err.Error(), Code: http.StatusInternalServerError}
+	}
+	return nil
+}
+
+// the actor is the logged in user, or fallbackActor for requests made without a token
+func (userUC userUsercase) audit(cxt context.Context, action string, userID string, fallbackActor string, before interface{}, after interface{}) {
+	if userUC.auditLog == nil {
+		return
 	}
-	token, errToken := infrastructure.CreateJWTToken(result.Username, result.Role, time.Duration(timeDurationEnv)*time.Second)
-	if errToken != nil {
-		return "", &domain.UserError{Message: errToken.Error(), Code: http.StatusInternalServerError}
+	entry := newAuditEntry(cxt, action, domain.AUDIT_TARGET_USER, userID, before, after, "password")
+	if entry.Actor == "" {
+		entry.Actor = fallbackActor
 	}
-	return token, nil
+	userUC.auditLog.Record(cxt, entry)
 }
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	domain "github.com/amha-mersha/go_tasks/test-go-backend-task-manager/domains"
+	"github.com/amha-mersha/go_tasks/test-go-backend-task-manager/infrastructure"
+)
+
+// statuses of the default workflow
+const (
+	STATUS_TODO        = "todo"
+	STATUS_IN_PROGRESS = "in_progress"
+	STATUS_REVIEW      = "review"
+	STATUS_DONE        = "done"
+)
+
+// todo -> in_progress -> review -> done, only admins close or reopen a task
+func DefaultWorkflow() domain.Workflow {
+	return domain.Workflow{
+		InitialStatus: STATUS_TODO,
+		FinalStatuses: []string{STATUS_DONE},
+		Statuses:      []string{STATUS_TODO, STATUS_IN_PROGRESS, STATUS_REVIEW, STATUS_DONE},
+		Transitions: []domain.WorkflowTransition{
+			{From: STATUS_TODO, To: STATUS_IN_PROGRESS},
+			{From: STATUS_IN_PROGRESS, To: STATUS_TODO},
+			{From: STATUS_IN_PROGRESS, To: STATUS_REVIEW},
+			{From: STATUS_REVIEW, To: STATUS_IN_PROGRESS},
+			{From: STATUS_REVIEW, To: STATUS_DONE, Roles: []string{"admin"}},
+			{From: STATUS_DONE, To: STATUS_IN_PROGRESS, Roles: []string{"admin"}},
+		},
+	}
+}
+
+// checks that every status referenced by the workflow is declared
+func ValidateWorkflow(workflow domain.Workflow) error {
+	if len(workflow.Statuses) == 0 {
+		return fmt.Errorf("workflow has no statuses")
+	}
+	if !hasStatus(workflow, workflow.InitialStatus) {
+		return fmt.Errorf("initial status %q is not a workflow status", workflow.InitialStatus)
+	}
+	if len(workflow.FinalStatuses) == 0 {
+		return fmt.Errorf("workflow has no final statuses")
+	}
+	for _, final := range workflow.FinalStatuses {
+		if !hasStatus(workflow, final) {
+			return fmt.Errorf("final status %q is not a workflow status", final)
+		}
+	}
+	for _, transition := range workflow.Transitions {
+		if !hasStatus(workflow, transition.From) || !hasStatus(workflow, transition.To) {
+			return fmt.Errorf("transition %q -> %q uses an unknown status", transition.From, transition.To)
+		}
+	}
+	return nil
+}
+
+func (taskUC *taskUseCase) GetWorkflow() domain.Workflow {
+	return taskUC.workflow
+}
+
+func (taskUC *taskUseCase) NextStatuses(cxt context.Context, status string) ([]string, *domain.TaskError) {
+	if !hasStatus(taskUC.workflow, status) {
+		return []string{}, &domain.TaskError{Message: "Unknown status: " + status, Code: http.StatusBadRequest}
+	}
+	role, _ := cxt.Value(infrastructure.CONTEXT_ROLE).(string)
+	next := []string{}
+	for _, transition := range taskUC.workflow.Transitions {
+		if transition.From == status && transitionAllows(transition, role) {
+			next = append(next, transition.To)
+		}
+	}
+	return next, nil
+}
+
+// validates moving a task from its current status to the requested one for the role in the context.
+// tasks whose status predates the workflow may move to any workflow status.
+func (taskUC *taskUseCase) checkTransition(cxt context.Context, from string, to string) *domain.TaskError {
+	if !hasStatus(taskUC.workflow, to) {
+		return &domain.TaskError{Message: "Unknown status: " + to, Code: http.StatusBadRequest}
+	}
+	if from == to || !hasStatus(taskUC.workflow, from) {
+		return nil
+	}
+	role, _ := cxt.Value(infrastructure.CONTEXT_ROLE).(string)
+	for _, transition := range taskUC.workflow.Transitions {
+		if transition.From == from && transition.To == to && transitionAllows(transition, role) {
+			return nil
+		}
+	}
+	return &domain.TaskError{Message: fmt.Sprintf("Transition from %s to %s is not allowed", from, to), Code: http.StatusConflict}
+}
+
+func transitionAllows(transition domain.WorkflowTransition, role string) bool {
+	if len(transition.Roles) == 0 {
+		return true
+	}
+	for _, allowed := range transition.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+func hasStatus(workflow domain.Workflow, status string) bool {
+	for _, known := range workflow.Statuses {
+		if known == status {
+			return true
+		}
+	}
+	return false
+}
+
+func isFinalStatus(workflow domain.Workflow, status string) bool {
+	for _, final := range workflow.FinalStatuses {
+		if final == status {
+			return true
+		}
+	}
+	return false
+}